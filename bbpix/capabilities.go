@@ -0,0 +1,116 @@
+package bbpix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/internal/apierror"
+	httpclient "github.com/pericles-luz/go-bb-pix/internal/http"
+	"github.com/pericles-luz/go-bb-pix/pix"
+)
+
+// Capabilities reports which optional endpoints are enabled for the
+// credential and convênio of the client
+type Capabilities struct {
+	// CobV is true when charges with due date (/cobv) are enabled
+	CobV bool
+	// LoteCobV is true when batches of charges with due date (/lotecobv)
+	// are enabled
+	LoteCobV bool
+	// Webhook is true when webhook configuration (/webhook) is enabled
+	Webhook bool
+	// CobR is true when PIX Automático recurring charges (/cobr) are enabled
+	CobR bool
+
+	// CheckedAt is when the endpoints were probed
+	CheckedAt time.Time
+}
+
+// capabilityProbes maps each probed listing endpoint to the field it sets
+var capabilityProbes = []struct {
+	path string
+	set  func(*Capabilities, bool)
+}{
+	{path: "/cobv", set: func(c *Capabilities, ok bool) { c.CobV = ok }},
+	{path: "/lotecobv", set: func(c *Capabilities, ok bool) { c.LoteCobV = ok }},
+	{path: "/webhook", set: func(c *Capabilities, ok bool) { c.Webhook = ok }},
+	{path: "/cobr", set: func(c *Capabilities, ok bool) { c.CobR = ok }},
+}
+
+// Capabilities probes which optional endpoints are enabled, so callers can
+// feature-flag flows instead of discovering 403/404 responses at runtime.
+// Each endpoint is probed with a one-item listing of the last minute; 403,
+// 404, 405 and 501 responses mark it as disabled. The result is cached for
+// the lifetime of the client; use RefreshCapabilities to probe again.
+// Other errors are returned and not cached.
+func (c *Client) Capabilities(ctx context.Context) (Capabilities, error) {
+	c.mu.Lock()
+	cached := c.capabilities
+	c.mu.Unlock()
+
+	if cached != nil {
+		return *cached, nil
+	}
+
+	return c.RefreshCapabilities(ctx)
+}
+
+// RefreshCapabilities probes the optional endpoints again and replaces the
+// cached result
+func (c *Client) RefreshCapabilities(ctx context.Context) (Capabilities, error) {
+	opts := []httpclient.Option{httpclient.WithPaginationStyle(c.pagination)}
+	if c.config.Convenio != "" {
+		opts = append(opts, httpclient.WithListParam(pix.ConvenioParam, c.config.Convenio))
+	}
+	client := httpclient.NewClient(c.httpClient, c.apiURL, opts...)
+
+	end := time.Now()
+	start := end.Add(-time.Minute)
+
+	var caps Capabilities
+	for _, probe := range capabilityProbes {
+		enabled, err := probeEndpoint(ctx, client, probe.path, start, end)
+		if err != nil {
+			return Capabilities{}, fmt.Errorf("failed to probe %s: %w", probe.path, err)
+		}
+		probe.set(&caps, enabled)
+	}
+	caps.CheckedAt = end
+
+	c.mu.Lock()
+	c.capabilities = &caps
+	c.mu.Unlock()
+
+	return caps, nil
+}
+
+// probeEndpoint lists a single item of path and reports whether the
+// endpoint is enabled
+func probeEndpoint(ctx context.Context, client *httpclient.Client, path string, start, end time.Time) (bool, error) {
+	httpReq, err := client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	q := httpReq.URL.Query()
+	q.Set("inicio", start.Format("2006-01-02T15:04:05Z07:00"))
+	q.Set("fim", end.Format("2006-01-02T15:04:05Z07:00"))
+	client.SetPagination(q, 0, 1)
+	httpReq.URL.RawQuery = q.Encode()
+
+	err = client.Do(httpReq, nil)
+	if err == nil {
+		return true, nil
+	}
+
+	if apiErr, asErr := apierror.As(err); asErr == nil {
+		switch apiErr.StatusCode {
+		case http.StatusForbidden, http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			return false, nil
+		}
+	}
+
+	return false, err
+}
@@ -0,0 +1,102 @@
+package bbpix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_Capabilities(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		query := r.URL.Query()
+		if query.Get("paginacao.itensPorPagina") != "1" || query.Get("inicio") == "" || query.Get("fim") == "" {
+			t.Errorf("probe query = %s, want a one-item window", r.URL.RawQuery)
+		}
+		if query.Get("numeroConvenio") != "1234567" {
+			t.Errorf("numeroConvenio = %s, want 1234567", query.Get("numeroConvenio"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/cobv", "/webhook":
+			w.Write([]byte(`{}`))
+		case "/lotecobv":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"detail":"acesso negado"}`))
+		case "/cobr":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			t.Errorf("unexpected probe %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		config:     Config{Convenio: "1234567"},
+		httpClient: server.Client(),
+		apiURL:     server.URL,
+	}
+
+	caps, err := client.Capabilities(context.Background())
+	if err != nil {
+		t.Fatalf("Capabilities() error = %v", err)
+	}
+	if !caps.CobV || !caps.Webhook {
+		t.Errorf("caps = %+v, want cobv and webhook enabled", caps)
+	}
+	if caps.LoteCobV || caps.CobR {
+		t.Errorf("caps = %+v, want lotecobv and cobr disabled", caps)
+	}
+	if caps.CheckedAt.IsZero() {
+		t.Error("CheckedAt should be set")
+	}
+
+	// The result is cached
+	if _, err := client.Capabilities(context.Background()); err != nil {
+		t.Fatalf("Capabilities() error = %v", err)
+	}
+	if got := calls.Load(); got != 4 {
+		t.Errorf("probes = %d, want 4 (cached on the second call)", got)
+	}
+
+	if _, err := client.RefreshCapabilities(context.Background()); err != nil {
+		t.Fatalf("RefreshCapabilities() error = %v", err)
+	}
+	if got := calls.Load(); got != 8 {
+		t.Errorf("probes = %d, want 8 after refresh", got)
+	}
+}
+
+func TestClient_Capabilities_ErrorNotCached(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: server.Client(), apiURL: server.URL}
+
+	if _, err := client.Capabilities(context.Background()); err == nil {
+		t.Fatal("Capabilities() error = nil, want error on 503")
+	}
+
+	failing.Store(false)
+	caps, err := client.Capabilities(context.Background())
+	if err != nil {
+		t.Fatalf("Capabilities() error = %v", err)
+	}
+	if !caps.CobV || !caps.LoteCobV || !caps.Webhook || !caps.CobR {
+		t.Errorf("caps = %+v, want everything enabled", caps)
+	}
+}
@@ -0,0 +1,150 @@
+package bbpix
+
+import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/internal/pkcs12"
+)
+
+// ErrIncorrectPassword is returned by LoadPKCS12 when the password does not
+// open the bundle
+var ErrIncorrectPassword = pkcs12.ErrIncorrectPassword
+
+// LoadPKCS12 reads a PKCS#12 bundle (.pfx/.p12), the format BB distributes
+// client certificates in, into a certificate for WithClientCertificate
+// The chain starts with the certificate of the private key, followed by the
+// other certificates of the bundle.
+func LoadPKCS12(path, password string) (tls.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to read certificate bundle: %w", err)
+	}
+
+	cert, err := parsePKCS12(data, password)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to load certificate bundle %s: %w", path, err)
+	}
+	return cert, nil
+}
+
+// parsePKCS12 decodes a PKCS#12 bundle into a TLS certificate
+func parsePKCS12(data []byte, password string) (tls.Certificate, error) {
+	key, certs, err := pkcs12.Decode(data, password)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return tls.Certificate{}, errors.New("bundle has no private key")
+	}
+	public, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok {
+		return tls.Certificate{}, fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	var (
+		leaf  *x509.Certificate
+		chain [][]byte
+	)
+	for _, cert := range certs {
+		if leaf == nil && public.Equal(cert.PublicKey) {
+			leaf = cert
+			continue
+		}
+		chain = append(chain, cert.Raw)
+	}
+	if leaf == nil {
+		return tls.Certificate{}, errors.New("bundle has no certificate for its private key")
+	}
+
+	return tls.Certificate{
+		Certificate: append([][]byte{leaf.Raw}, chain...),
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}, nil
+}
+
+// CertificateReloader serves a client certificate from a PKCS#12 bundle and
+// reloads it when the file changes, so a rotated certificate is picked up
+// without restarting the process
+// Pass its GetClientCertificate method to WithGetClientCertificate. The file
+// is checked at most once per interval, during the TLS handshakes of new
+// connections; if the new bundle cannot be loaded, the previous certificate
+// is kept and loading is retried on the next check.
+type CertificateReloader struct {
+	path     string
+	password string
+	interval time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+	size    int64
+	checked time.Time
+}
+
+// NewCertificateReloader loads the bundle at path and returns a reloader
+// that checks it for changes at most once per interval
+func NewCertificateReloader(path, password string, interval time.Duration) (*CertificateReloader, error) {
+	r := &CertificateReloader{
+		path:     path,
+		password: password,
+		interval: interval,
+		now:      time.Now,
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload loads the bundle again, regardless of the check interval
+func (r *CertificateReloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.reload()
+}
+
+// GetClientCertificate returns the current certificate, reloading the
+// bundle first if it changed since the last check
+func (r *CertificateReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now := r.now(); now.Sub(r.checked) >= r.interval {
+		r.checked = now
+		if info, err := os.Stat(r.path); err == nil && (!info.ModTime().Equal(r.modTime) || info.Size() != r.size) {
+			_ = r.reload()
+		}
+	}
+
+	return r.cert, nil
+}
+
+// reload loads the bundle; r.mu must be held
+func (r *CertificateReloader) reload() error {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return fmt.Errorf("failed to read certificate bundle: %w", err)
+	}
+
+	cert, err := LoadPKCS12(r.path, r.password)
+	if err != nil {
+		return err
+	}
+
+	r.cert = &cert
+	r.modTime, r.size = info.ModTime(), info.Size()
+	r.checked = r.now()
+	return nil
+}
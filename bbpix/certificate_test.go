@@ -0,0 +1,182 @@
+package bbpix
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadPKCS12(t *testing.T) {
+	cert, err := LoadPKCS12("testdata/client.p12", "senha123")
+	if err != nil {
+		t.Fatalf("LoadPKCS12() error = %v", err)
+	}
+
+	if cert.Leaf == nil {
+		t.Fatal("Leaf is nil")
+	}
+	if len(cert.Certificate) == 0 || string(cert.Certificate[0]) != string(cert.Leaf.Raw) {
+		t.Error("chain does not start with the leaf certificate")
+	}
+	if cert.PrivateKey == nil {
+		t.Error("PrivateKey is nil")
+	}
+}
+
+func TestLoadPKCS12_Errors(t *testing.T) {
+	t.Run("incorrect password", func(t *testing.T) {
+		_, err := LoadPKCS12("testdata/client.p12", "errada")
+		if !errors.Is(err, ErrIncorrectPassword) {
+			t.Errorf("error = %v, want ErrIncorrectPassword", err)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := LoadPKCS12("testdata/missing.p12", "senha123"); err == nil {
+			t.Error("expected error for a missing file")
+		}
+	})
+}
+
+func TestWithClientCertificate(t *testing.T) {
+	cert, err := LoadPKCS12("testdata/client.p12", "senha123")
+	if err != nil {
+		t.Fatalf("LoadPKCS12() error = %v", err)
+	}
+
+	t.Run("default transport", func(t *testing.T) {
+		transport, err := customizeTransport(http.DefaultTransport, &clientOptions{clientCertificate: &cert})
+		if err != nil {
+			t.Fatalf("customizeTransport() error = %v", err)
+		}
+
+		tlsConfig := transport.(*http.Transport).TLSClientConfig
+		if tlsConfig == nil || len(tlsConfig.Certificates) != 1 {
+			t.Fatalf("TLSClientConfig = %+v, want the client certificate", tlsConfig)
+		}
+		if c := http.DefaultTransport.(*http.Transport).TLSClientConfig; c != nil && len(c.Certificates) != 0 {
+			t.Error("http.DefaultTransport was modified")
+		}
+	})
+
+	t.Run("keeps custom TLS settings", func(t *testing.T) {
+		base := &http.Transport{TLSClientConfig: &tls.Config{ServerName: "pix.bb.com.br"}}
+
+		transport, err := customizeTransport(base, &clientOptions{clientCertificate: &cert})
+		if err != nil {
+			t.Fatalf("customizeTransport() error = %v", err)
+		}
+
+		tlsConfig := transport.(*http.Transport).TLSClientConfig
+		if tlsConfig.ServerName != "pix.bb.com.br" || len(tlsConfig.Certificates) != 1 {
+			t.Errorf("TLSClientConfig = %+v, want ServerName kept and the certificate added", tlsConfig)
+		}
+		if len(base.TLSClientConfig.Certificates) != 0 {
+			t.Error("base transport was modified")
+		}
+	})
+
+	t.Run("certificate func", func(t *testing.T) {
+		get := func(*tls.CertificateRequestInfo) (*tls.Certificate, error) { return &cert, nil }
+
+		transport, err := customizeTransport(http.DefaultTransport, &clientOptions{getClientCertificate: get})
+		if err != nil {
+			t.Fatalf("customizeTransport() error = %v", err)
+		}
+
+		if transport.(*http.Transport).TLSClientConfig.GetClientCertificate == nil {
+			t.Error("GetClientCertificate was not set")
+		}
+	})
+
+	t.Run("unsupported transport", func(t *testing.T) {
+		config := Config{
+			Environment:     EnvironmentSandbox,
+			ClientID:        "test-client-id",
+			ClientSecret:    "test-client-secret",
+			DeveloperAppKey: "test-app-key",
+		}
+		httpClient := &http.Client{Transport: roundTripperFunc(http.DefaultTransport.RoundTrip)}
+
+		if _, err := New(config, WithHTTPClient(httpClient), WithClientCertificate(cert)); err == nil {
+			t.Error("expected error for a transport that is not an *http.Transport")
+		}
+	})
+}
+
+func TestCertificateReloader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "client.p12")
+	copyFile(t, "testdata/client.p12", path)
+
+	reloader, err := NewCertificateReloader(path, "senha123", time.Minute)
+	if err != nil {
+		t.Fatalf("NewCertificateReloader() error = %v", err)
+	}
+	now := time.Now()
+	reloader.now = func() time.Time { return now }
+
+	subject := func() string {
+		t.Helper()
+		cert, err := reloader.GetClientCertificate(nil)
+		if err != nil {
+			t.Fatalf("GetClientCertificate() error = %v", err)
+		}
+		return cert.Leaf.Subject.CommonName
+	}
+
+	if got := subject(); got != "go-bb-pix test client" {
+		t.Fatalf("subject = %q, want the initial certificate", got)
+	}
+
+	copyFile(t, "testdata/rotated.p12", path)
+	if got := subject(); got != "go-bb-pix test client" {
+		t.Errorf("subject = %q before the interval, want the initial certificate", got)
+	}
+
+	now = now.Add(time.Minute)
+	if got := subject(); got != "ec client" {
+		t.Errorf("subject = %q after the interval, want the rotated certificate", got)
+	}
+
+	// A broken bundle keeps the current certificate
+	if err := os.WriteFile(path, []byte("partial"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+	if got := subject(); got != "ec client" {
+		t.Errorf("subject = %q with a broken bundle, want the rotated certificate", got)
+	}
+	if err := reloader.Reload(); err == nil {
+		t.Error("Reload() expected error for a broken bundle")
+	}
+}
+
+func TestNewCertificateReloader_Error(t *testing.T) {
+	if _, err := NewCertificateReloader("testdata/client.p12", "errada", time.Minute); !errors.Is(err, ErrIncorrectPassword) {
+		t.Errorf("error = %v, want ErrIncorrectPassword", err)
+	}
+}
+
+// copyFile copies src to dst
+func copyFile(t *testing.T, src, dst string) {
+	t.Helper()
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
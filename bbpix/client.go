@@ -1,60 +1,112 @@
 package bbpix
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/pericles-luz/go-bb-pix/internal/auth"
 	"github.com/pericles-luz/go-bb-pix/internal/transport"
 	"github.com/pericles-luz/go-bb-pix/pix"
 	"github.com/pericles-luz/go-bb-pix/pixauto"
+	"github.com/pericles-luz/go-bb-pix/redact"
 )
 
 // Client is the main client for the Banco do Brasil PIX API
 type Client struct {
 	config     Config
+	preset     Preset
 	httpClient *http.Client
 	apiURL     string
 	oauthURL   string
+	auditFunc  AuditFunc
+	hooks      Hooks
+	redactor   *redact.Redactor
+	pagination pix.PaginationStyle
+	timeouts   pix.Timeouts
 
-	// Lazy-initialized clients
+	// authTransport injects the OAuth2 token and tracks its expiry drift
+	authTransport *transport.AuthTransport
+
+	// oauthProvider is the built-in token provider, unless
+	// WithTokenProvider was given
+	oauthProvider *auth.OAuth2Provider
+
+	// refresher renews the access token in the background (see
+	// RunTokenRefresh)
+	refresher *auth.Refresher
+
+	// breakerTransport fails fast while the API is unavailable
+	breakerTransport *transport.CircuitBreakerTransport
+
+	// stats counts the requests for Stats
+	stats *transport.StatsRecorder
+
+	// Lazy-initialized clients and cached capabilities
 	pixClient     *pix.Client
 	pixAutoClient *pixauto.Client
+	capabilities  *Capabilities
 	mu            sync.Mutex
 }
 
 // New creates a new Banco do Brasil PIX client
 func New(config Config, opts ...Option) (*Client, error) {
-	// Validate config
-	if err := config.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
-	}
-
-	// Apply options
+	// Apply options, with environment overrides between defaults and options
 	options := defaultClientOptions()
+	if err := applyEnvOptions(options); err != nil {
+		return nil, fmt.Errorf("invalid environment: %w", err)
+	}
 	for _, opt := range opts {
 		opt(options)
 	}
 
-	// Get environment URLs
-	oauthURL, apiURL := config.Environment.URLs()
+	// Validate config; OAuth2 credentials are only needed by the built-in
+	// token provider
+	if err := config.validate(options.tokenProvider == nil); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	// Get environment defaults
+	preset := config.preset()
 
 	// Create client
 	client := &Client{
-		config:   config,
-		apiURL:   apiURL,
-		oauthURL: oauthURL,
+		config:     config,
+		preset:     preset,
+		apiURL:     preset.APIURL,
+		oauthURL:   preset.OAuthURL,
+		auditFunc:  options.auditFunc,
+		hooks:      options.hooks,
+		redactor:   options.redactor,
+		pagination: options.paginationStyle,
+		timeouts:   options.operationTimeouts,
+	}
+
+	// Log with the logger of the request context, if any (see LoggerContext)
+	if options.logger != nil {
+		options.logger = slog.New(transport.ContextHandler(options.logger.Handler()))
+	}
+
+	// Redact logs regardless of the order WithLogger and WithRedactor were given
+	if options.redactor != nil && options.logger != nil {
+		options.logger = slog.New(options.redactor.Handler(options.logger.Handler()))
 	}
 
 	// Build HTTP client with transport chain
-	client.httpClient = client.buildHTTPClient(options)
+	httpClient, err := client.buildHTTPClient(options)
+	if err != nil {
+		return nil, err
+	}
+	client.httpClient = httpClient
 
 	return client, nil
 }
 
 // buildHTTPClient builds an HTTP client with the transport chain
-func (c *Client) buildHTTPClient(opts *clientOptions) *http.Client {
+func (c *Client) buildHTTPClient(opts *clientOptions) (*http.Client, error) {
 	// Start with base transport or custom HTTP client
 	var baseTransport http.RoundTripper
 	if opts.httpClient != nil {
@@ -65,49 +117,148 @@ func (c *Client) buildHTTPClient(opts *clientOptions) *http.Client {
 	} else {
 		baseTransport = http.DefaultTransport
 	}
+	if opts.transport != nil {
+		baseTransport = opts.transport
+	}
 
-	// Create OAuth2 token provider
-	tokenProvider := auth.NewOAuth2Provider(c.oauthURL, c.config.ClientID, c.config.ClientSecret)
+	// Present the client certificate for mutual TLS and use the proxy
+	if opts.needsCustomTransport() {
+		var err error
+		if baseTransport, err = customizeTransport(baseTransport, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create OAuth2 token provider, unless one was given; tokens are
+	// requested through the base transport, so the client certificate,
+	// proxy and TLS settings apply to them too
+	tokenProvider := opts.tokenProvider
+	if tokenProvider == nil {
+		c.oauthProvider = auth.NewOAuth2Provider(c.oauthURL, c.config.ClientID, c.config.ClientSecret,
+			auth.WithScopes(c.preset.Scopes...),
+			auth.WithTransport(baseTransport),
+			auth.WithTimeout(opts.timeout),
+			auth.WithTokenStore(opts.tokenStore),
+			auth.WithLogger(opts.logger),
+		)
+		tokenProvider = c.oauthProvider
+	}
+	c.refresher = auth.NewRefresher(tokenProvider)
+	c.refresher.SetLogger(opts.logger)
 
 	// Build transport chain (innermost to outermost):
-	// 1. Base transport
-	// 2. Circuit breaker (fail-fast protection)
-	// 3. Retry (exponential backoff)
-	// 4. Auth (inject OAuth2 token)
-	// 5. Logging (log requests/responses)
+	// 1. Base transport, with debug body logging when enabled, so every
+	//    attempt is logged with the headers actually sent
+	// 2. Rate limit (token bucket, when enabled)
+	// 3. Circuit breaker (fail-fast protection, unless disabled)
+	// 4. Retry (exponential backoff, unless disabled)
+	// 5. User middlewares
+	// 6. Auth (inject OAuth2 token)
+	// 7. Static headers (User-Agent and WithHeader)
+	// 8. Metrics (client statistics and WithMetrics)
+	// 9. Logging (log requests/responses)
+	// 10. Tracing (when enabled, so the logs are within the span)
+	// 11. Request ID (when enabled, so the logs report it)
+	var currentTransport http.RoundTripper = baseTransport
+
+	c.stats = transport.NewStatsRecorder()
+	metrics := opts.metricsRecorder(c.stats)
+
+	// Apply debug body logging
+	if opts.debugBodies {
+		debugTransport := transport.NewDebugTransport(currentTransport, opts.logger, opts.debugBodyLimit)
+		debugTransport.RedactHeaders(c.preset.AppKeyHeader)
+		currentTransport = debugTransport
+	}
+
+	// Apply rate limit, so every attempt, including retries, takes a token
+	if opts.rateLimit > 0 {
+		currentTransport = transport.NewRateLimitTransport(currentTransport, opts.rateLimit, opts.rateBurst)
+	}
 
 	// Apply circuit breaker
-	var currentTransport http.RoundTripper = transport.NewCircuitBreakerTransport(
-		baseTransport,
-		opts.circuitBreakerMaxFailures,
-		opts.circuitBreakerResetTimeout,
-	)
+	if !opts.circuitBreakerDisabled {
+		breakerTransport := transport.NewCircuitBreakerTransport(
+			currentTransport,
+			opts.circuitBreakerMaxFailures,
+			opts.circuitBreakerResetTimeout,
+		)
+		breakerTransport.SetMaxHalfOpenProbes(opts.circuitBreakerProbes)
+		breakerTransport.OnStateChange(opts.circuitStateChange(metrics))
+		c.breakerTransport = breakerTransport
+		currentTransport = breakerTransport
+	}
 
 	// Apply retry
-	currentTransport = transport.NewRetryTransport(
-		currentTransport,
-		opts.maxRetries,
-		opts.initialBackoff,
-	)
+	if !opts.retryDisabled {
+		retryTransport := transport.NewRetryTransport(
+			currentTransport,
+			opts.maxRetries,
+			opts.initialBackoff,
+		)
+		retryTransport.SetPolicy(opts.retryPolicy)
+		retryTransport.SetLogger(opts.logger)
+		retryTransport.OnRetry(opts.retryFunc)
+		currentTransport = retryTransport
+	}
+
+	// Apply middlewares, the first one outermost
+	for i := len(opts.middlewares) - 1; i >= 0; i-- {
+		currentTransport = opts.middlewares[i](currentTransport)
+	}
 
 	// Apply auth
-	currentTransport = transport.NewAuthTransport(
+	authTransport := transport.NewAuthTransport(
 		currentTransport,
 		tokenProvider,
 		c.config.DeveloperAppKey,
 	)
+	authTransport.SetAppKeyHeader(c.preset.AppKeyHeader)
+	authTransport.SetLogger(opts.logger)
+	authTransport.OnTokenRejected(opts.tokenRejectedFunc)
+	c.authTransport = authTransport
+	currentTransport = authTransport
+
+	// Apply static headers
+	header, err := opts.staticHeader()
+	if err != nil {
+		return nil, err
+	}
+	if len(header) > 0 {
+		currentTransport = transport.NewHeaderTransport(currentTransport, header)
+	}
+
+	// Apply metrics
+	currentTransport = transport.NewMetricsTransport(currentTransport, metrics)
 
 	// Apply logging
-	currentTransport = transport.NewLoggingTransport(
+	loggingTransport := transport.NewLoggingTransport(
 		currentTransport,
 		opts.logger,
 	)
+	loggingTransport.SetLevels(opts.logLevels)
+	loggingTransport.SetSampling(opts.logSampling)
+	loggingTransport.SetSlowThreshold(opts.slowThreshold)
+	currentTransport = loggingTransport
+
+	// Apply tracing
+	if opts.tracer != nil {
+		currentTransport = transport.NewTracingTransport(currentTransport, opts.tracer)
+	}
+
+	// Apply request ID
+	if opts.requestIDHeader != "" {
+		if !isToken(opts.requestIDHeader) {
+			return nil, fmt.Errorf("invalid request ID header %q", opts.requestIDHeader)
+		}
+		currentTransport = transport.NewRequestIDTransport(currentTransport, opts.requestIDHeader)
+	}
 
 	// Create HTTP client with configured transport and timeout
 	return &http.Client{
 		Transport: currentTransport,
 		Timeout:   opts.timeout,
-	}
+	}, nil
 }
 
 // PIX returns the PIX client
@@ -117,7 +268,14 @@ func (c *Client) PIX() *pix.Client {
 	defer c.mu.Unlock()
 
 	if c.pixClient == nil {
-		c.pixClient = pix.NewClient(c.httpClient, c.apiURL)
+		c.pixClient = pix.NewClient(c.httpClient, c.apiURL,
+			pix.WithAuditHook(c.auditFunc),
+			pix.WithHooks(c.hooks),
+			pix.WithConvenio(c.config.Convenio),
+			pix.WithRedactor(c.redactor),
+			pix.WithPaginationStyle(c.pagination),
+			pix.WithTimeouts(c.timeouts),
+		)
 	}
 
 	return c.pixClient
@@ -130,8 +288,117 @@ func (c *Client) PIXAuto() *pixauto.Client {
 	defer c.mu.Unlock()
 
 	if c.pixAutoClient == nil {
-		c.pixAutoClient = pixauto.NewClient(c.httpClient, c.apiURL)
+		c.pixAutoClient = pixauto.NewClient(c.httpClient, c.apiURL,
+			pixauto.WithAuditHook(c.auditFunc),
+			pixauto.WithHooks(c.hooks),
+			pixauto.WithConvenio(c.config.Convenio),
+			pixauto.WithRedactor(c.redactor),
+			pixauto.WithPaginationStyle(c.pagination),
+		)
 	}
 
 	return c.pixAutoClient
 }
+
+// ResetSandbox removes the charges and webhooks created by a test run,
+// identified by the prefixes in params (see pix.Client.Cleanup)
+// It refuses to run against the production environment.
+func (c *Client) ResetSandbox(ctx context.Context, params pix.CleanupParams) (*pix.CleanupResult, error) {
+	if c.config.Environment == EnvironmentProducao {
+		return nil, ErrResetInProduction
+	}
+
+	return c.PIX().Cleanup(ctx, params)
+}
+
+// TokenDrift returns the statistics of the OAuth2 tokens rejected with 401
+// before their declared expiry. A growing AverageDrift usually means clock
+// drift or early revocation by the gateway.
+func (c *Client) TokenDrift() TokenDriftStats {
+	if c.authTransport == nil {
+		return TokenDriftStats{}
+	}
+	return c.authTransport.TokenDrift()
+}
+
+// RunTokenRefresh renews the access token in the background shortly before
+// it expires, until ctx is done, so the first request after an idle period
+// does not wait for a token fetch; failed renewals are logged and retried
+// It blocks and returns ctx.Err(), so it is meant to run in its own
+// goroutine, at most one per client:
+//
+//	go client.RunTokenRefresh(ctx)
+func (c *Client) RunTokenRefresh(ctx context.Context) error {
+	if c.refresher == nil {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return c.refresher.Run(ctx)
+}
+
+// Stats is a snapshot of the counters of a client
+type Stats struct {
+	// Requests counts the finished API requests, retries included in a
+	// single request, and Errors those that failed without a response
+	Requests uint64
+	Errors   uint64
+
+	// Retries counts the attempts after the first one
+	Retries uint64
+
+	// CircuitBreakerOpens counts the times the circuit breaker opened
+	CircuitBreakerOpens uint64
+
+	// TokenRefreshes counts the access tokens obtained, the first one
+	// included
+	TokenRefreshes uint64
+
+	// TokenFetches and TokenFetchFailures count the requests to the OAuth2
+	// token endpoint, concurrent refreshes counted once, and
+	// TokenFetchDuration is their total time; they are zero with
+	// WithTokenProvider
+	TokenFetches       uint64
+	TokenFetchFailures uint64
+	TokenFetchDuration time.Duration
+
+	// Endpoints are the counters of each endpoint, sorted by endpoint and
+	// method
+	Endpoints []EndpointStats
+}
+
+// Stats returns a snapshot of the counters of the client: requests by
+// endpoint and status, retries, circuit breaker openings, access tokens
+// obtained and token requests, for visibility without a metrics system
+func (c *Client) Stats() Stats {
+	if c.stats == nil {
+		return Stats{}
+	}
+
+	s := c.stats.Snapshot()
+	stats := Stats{
+		Requests:            s.Requests,
+		Errors:              s.Errors,
+		Retries:             s.Retries,
+		CircuitBreakerOpens: s.CircuitBreakerOpens,
+		Endpoints:           s.Endpoints,
+	}
+	if c.authTransport != nil {
+		stats.TokenRefreshes = c.authTransport.TokenRefreshes()
+	}
+	if c.oauthProvider != nil {
+		fetch := c.oauthProvider.Stats()
+		stats.TokenFetches = fetch.Fetches
+		stats.TokenFetchFailures = fetch.Failures
+		stats.TokenFetchDuration = fetch.Duration
+	}
+	return stats
+}
+
+// CircuitBreaker returns the current state of the circuit breaker, e.g. to
+// report it in a health endpoint; a disabled breaker is reported closed
+func (c *Client) CircuitBreaker() CircuitBreakerStats {
+	if c.breakerTransport == nil {
+		return CircuitBreakerStats{}
+	}
+	return c.breakerTransport.Stats()
+}
@@ -1,11 +1,20 @@
 package bbpix
 
 import (
+	"context"
+	"errors"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/pericles-luz/go-bb-pix/pix"
 )
 
 func TestNew_ValidConfig(t *testing.T) {
@@ -89,6 +98,122 @@ func TestNew_BuildsHTTPClientWithTransportChain(t *testing.T) {
 	}
 }
 
+func TestClient_TokenDrift(t *testing.T) {
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		ClientID:        "test-client-id",
+		ClientSecret:    "test-client-secret",
+		DeveloperAppKey: "test-app-key",
+	}
+
+	client, err := New(config, WithTokenRejectedHook(func(ctx context.Context, r TokenRejection) {}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if client.authTransport == nil {
+		t.Fatal("auth transport not kept by the client")
+	}
+	if stats := client.TokenDrift(); stats.Rejections != 0 {
+		t.Errorf("TokenDrift().Rejections = %d, want 0", stats.Rejections)
+	}
+
+	// Clients built without New report no drift instead of panicking
+	if stats := (&Client{}).TokenDrift(); stats != (TokenDriftStats{}) {
+		t.Errorf("TokenDrift() = %+v, want zero", stats)
+	}
+}
+
+func TestClient_RunTokenRefresh(t *testing.T) {
+	var tokenRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/oauth/token" {
+			tokenRequests.Add(1)
+			w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+			return
+		}
+		w.Write([]byte(`{"txid":"abc"}`))
+	}))
+	defer server.Close()
+
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		ClientID:        "test-client-id",
+		ClientSecret:    "test-client-secret",
+		DeveloperAppKey: "test-app-key",
+		OAuthURL:        server.URL + "/oauth/token",
+		APIURL:          server.URL + "/pix-bb/v1",
+	}
+	client, err := New(config)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- client.RunTokenRefresh(ctx) }()
+
+	deadline := time.Now().Add(time.Second)
+	for tokenRequests.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := tokenRequests.Load(); n != 1 {
+		t.Fatalf("token requests = %d, want 1 fetched in the background", n)
+	}
+
+	if _, err := client.PIX().GetQRCode(context.Background(), "abc"); err != nil {
+		t.Fatalf("GetQRCode() error = %v", err)
+	}
+	if n := tokenRequests.Load(); n != 1 {
+		t.Errorf("token requests = %d, want the background token reused", n)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("RunTokenRefresh() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestNew_WithTokenStore(t *testing.T) {
+	var tokenRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/oauth/token" {
+			tokenRequests.Add(1)
+			w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+			return
+		}
+		w.Write([]byte(`{"txid":"abc"}`))
+	}))
+	defer server.Close()
+
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		ClientID:        "test-client-id",
+		ClientSecret:    "test-client-secret",
+		DeveloperAppKey: "test-app-key",
+		OAuthURL:        server.URL + "/oauth/token",
+		APIURL:          server.URL + "/pix-bb/v1",
+	}
+	store := NewFileTokenStore(t.TempDir())
+
+	// Each client stands for a worker process sharing the store
+	for i := 0; i < 3; i++ {
+		client, err := New(config, WithTokenStore(store))
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if _, err := client.PIX().GetQRCode(context.Background(), "abc"); err != nil {
+			t.Fatalf("GetQRCode() error = %v", err)
+		}
+	}
+
+	if n := tokenRequests.Load(); n != 1 {
+		t.Errorf("token requests = %d, want 1 shared through the store", n)
+	}
+}
+
 func TestNew_CustomHTTPClient(t *testing.T) {
 	config := Config{
 		Environment:     EnvironmentSandbox,
@@ -224,3 +349,570 @@ func TestClient_Singleton_PIXAuto(t *testing.T) {
 		t.Error("PIXAuto() should return singleton instance")
 	}
 }
+
+func TestClient_ResetSandbox_RefusesProduction(t *testing.T) {
+	config := Config{
+		Environment:     EnvironmentProducao,
+		ClientID:        "test-client-id",
+		ClientSecret:    "test-client-secret",
+		DeveloperAppKey: "test-app-key",
+	}
+
+	client, err := New(config)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	_, err = client.ResetSandbox(context.Background(), pix.CleanupParams{TxIDPrefix: "ci"})
+	if !errors.Is(err, ErrResetInProduction) {
+		t.Errorf("ResetSandbox() error = %v, want ErrResetInProduction", err)
+	}
+}
+
+func TestNew_URLOverrides(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/oauth/token" {
+			w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+			return
+		}
+		gotPath = r.URL.Path
+		w.Write([]byte(`{"txid":"abc"}`))
+	}))
+	defer server.Close()
+
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		ClientID:        "test-client-id",
+		ClientSecret:    "test-client-secret",
+		DeveloperAppKey: "test-app-key",
+		OAuthURL:        server.URL + "/oauth/token",
+		APIURL:          server.URL + "/pix-bb/v1",
+	}
+
+	client, err := New(config, WithRetry(0, time.Millisecond))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := client.PIX().GetQRCode(context.Background(), "abc"); err != nil {
+		t.Fatalf("GetQRCode() error = %v", err)
+	}
+	if gotPath != "/pix-bb/v1/cob/abc" {
+		t.Errorf("Path = %s, want /pix-bb/v1/cob/abc", gotPath)
+	}
+}
+
+func TestNew_Scopes(t *testing.T) {
+	tests := []struct {
+		name      string
+		scopes    []string
+		wantScope string
+	}{
+		{name: "default sends no scope", wantScope: ""},
+		{name: "configured", scopes: []string{"cob.read", "rec.read"}, wantScope: "cob.read rec.read"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotScope []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Path == "/oauth/token" {
+					r.ParseForm()
+					gotScope = r.PostForm["scope"]
+					w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+					return
+				}
+				w.Write([]byte(`{"txid":"abc"}`))
+			}))
+			defer server.Close()
+
+			config := Config{
+				Environment:     EnvironmentSandbox,
+				ClientID:        "test-client-id",
+				ClientSecret:    "test-client-secret",
+				DeveloperAppKey: "test-app-key",
+				Scopes:          tt.scopes,
+				OAuthURL:        server.URL + "/oauth/token",
+				APIURL:          server.URL,
+			}
+
+			client, err := New(config, WithRetry(0, time.Millisecond))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if _, err := client.PIX().GetQRCode(context.Background(), "abc"); err != nil {
+				t.Fatalf("GetQRCode() error = %v", err)
+			}
+
+			if tt.wantScope == "" {
+				if gotScope != nil {
+					t.Errorf("scope = %v, want none", gotScope)
+				}
+				return
+			}
+			if len(gotScope) != 1 || gotScope[0] != tt.wantScope {
+				t.Errorf("scope = %v, want %q", gotScope, tt.wantScope)
+			}
+		})
+	}
+}
+
+func TestNew_AppKeyHeader(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        Environment
+		override   string
+		wantHeader string
+	}{
+		{name: "sandbox", env: EnvironmentSandbox, wantHeader: "gw-dev-app-key"},
+		{name: "producao", env: EnvironmentProducao, wantHeader: "gw-app-key"},
+		{name: "override", env: EnvironmentProducao, override: "x-app-key", wantHeader: "x-app-key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got http.Header
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Path == "/oauth/token" {
+					w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+					return
+				}
+				got = r.Header.Clone()
+				w.Write([]byte(`{"txid":"abc"}`))
+			}))
+			defer server.Close()
+
+			config := Config{
+				Environment:     tt.env,
+				ClientID:        "test-client-id",
+				ClientSecret:    "test-client-secret",
+				DeveloperAppKey: "test-app-key",
+				AppKeyHeader:    tt.override,
+				OAuthURL:        server.URL + "/oauth/token",
+				APIURL:          server.URL,
+			}
+
+			client, err := New(config, WithRetry(0, time.Millisecond))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if _, err := client.PIX().GetQRCode(context.Background(), "abc"); err != nil {
+				t.Fatalf("GetQRCode() error = %v", err)
+			}
+
+			if v := got.Get(tt.wantHeader); v != "test-app-key" {
+				t.Errorf("%s header = %q, want test-app-key", tt.wantHeader, v)
+			}
+			for _, other := range []string{"gw-dev-app-key", "gw-app-key", "x-app-key"} {
+				if other != tt.wantHeader && got.Get(other) != "" {
+					t.Errorf("unexpected %s header", other)
+				}
+			}
+		})
+	}
+}
+
+// staticTokenProvider serves a fixed token and counts invalidations
+type staticTokenProvider struct {
+	token       string
+	invalidated int
+}
+
+func (p *staticTokenProvider) GetToken(ctx context.Context) (*Token, error) {
+	return &Token{AccessToken: p.token, TokenType: "Bearer", ExpiresIn: 3600, IssuedAt: time.Now()}, nil
+}
+
+func (p *staticTokenProvider) Invalidate() {
+	p.invalidated++
+}
+
+func TestNew_WithTokenProvider(t *testing.T) {
+	var gotAuth string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			t.Error("built-in OAuth2 provider should not be used")
+		}
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(`{"txid":"abc"}`))
+	}))
+	defer server.Close()
+
+	// No client credentials: the provider replaces them
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		DeveloperAppKey: "test-app-key",
+		OAuthURL:        server.URL + "/oauth/token",
+		APIURL:          server.URL,
+	}
+	provider := &staticTokenProvider{token: "vault-token"}
+
+	client, err := New(config, WithTokenProvider(provider), WithRetry(0, time.Millisecond))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := client.PIX().GetQRCode(context.Background(), "abc"); err != nil {
+		t.Fatalf("GetQRCode() error = %v", err)
+	}
+	if gotAuth != "Bearer vault-token" {
+		t.Errorf("Authorization = %q, want Bearer vault-token", gotAuth)
+	}
+
+	status = http.StatusUnauthorized
+	client.PIX().GetQRCode(context.Background(), "abc")
+	if provider.invalidated != 1 {
+		t.Errorf("Invalidate called %d times, want 1", provider.invalidated)
+	}
+}
+
+func TestNew_CredentialsRequiredWithoutTokenProvider(t *testing.T) {
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		DeveloperAppKey: "test-app-key",
+	}
+
+	if _, err := New(config); err == nil {
+		t.Error("expected error without client credentials")
+	}
+}
+
+func TestNew_WithTransportAndMiddleware(t *testing.T) {
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		DeveloperAppKey: "test-app-key",
+	}
+
+	// The base transport answers the API itself, failing the first attempt
+	attempts := 0
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		status := http.StatusOK
+		if attempts == 1 {
+			status = http.StatusServiceUnavailable
+		}
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"txid":"abc"}`)),
+			Request:    req,
+		}, nil
+	})
+
+	var calls []string
+	middleware := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+":"+req.Header.Get("Authorization"))
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	client, err := New(config,
+		WithTokenProvider(&staticTokenProvider{token: "token"}),
+		WithTransport(base),
+		WithRetry(1, time.Millisecond),
+		WithMiddleware(middleware("outer")),
+		WithMiddleware(middleware("inner")),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := client.PIX().GetQRCode(context.Background(), "abc"); err != nil {
+		t.Fatalf("GetQRCode() error = %v", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("base transport attempts = %d, want 2", attempts)
+	}
+	if got := strings.Join(calls, " "); got != "outer:Bearer token inner:Bearer token" {
+		t.Errorf("middleware calls = %q, want each once, outer first, with the auth header", got)
+	}
+}
+
+func TestNew_CircuitBreakerHook(t *testing.T) {
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		DeveloperAppKey: "test-app-key",
+	}
+
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+			Request:    req,
+		}, nil
+	})
+
+	var changes []string
+	client, err := New(config,
+		WithTokenProvider(&staticTokenProvider{token: "token"}),
+		WithTransport(base),
+		WithRetry(0, time.Millisecond),
+		WithCircuitBreaker(2, time.Minute),
+		WithCircuitBreakerHook(func(from, to CircuitState, reason string) {
+			changes = append(changes, from.String()+"->"+to.String())
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for range 3 {
+		client.PIX().GetQRCode(context.Background(), "abc")
+	}
+
+	if got := strings.Join(changes, " "); got != "closed->open" {
+		t.Errorf("state changes = %q, want closed->open", got)
+	}
+
+	if stats := client.CircuitBreaker(); stats.State != CircuitOpen || stats.Failures != 2 || stats.UntilHalfOpen <= 0 {
+		t.Errorf("CircuitBreaker() = %+v, want open after 2 failures", stats)
+	}
+}
+
+func TestNew_ResilienceDisabled(t *testing.T) {
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		DeveloperAppKey: "test-app-key",
+	}
+
+	tests := []struct {
+		name         string
+		opts         []Option
+		requests     int
+		wantAttempts int
+	}{
+		// The first attempt of the second request opens the circuit, which
+		// then fails its retry fast
+		{name: "enabled", requests: 2, wantAttempts: 3},
+		{name: "retry disabled", opts: []Option{WithRetryDisabled()}, requests: 2, wantAttempts: 2},
+		{name: "circuit breaker disabled", opts: []Option{WithCircuitBreakerDisabled()}, requests: 2, wantAttempts: 4},
+		{name: "both disabled", opts: []Option{WithRetryDisabled(), WithCircuitBreakerDisabled()}, requests: 2, wantAttempts: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				attempts++
+				return &http.Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Header:     make(http.Header),
+					Body:       io.NopCloser(strings.NewReader(`{}`)),
+					Request:    req,
+				}, nil
+			})
+
+			opts := append([]Option{
+				WithTokenProvider(&staticTokenProvider{token: "token"}),
+				WithTransport(base),
+				WithRetry(1, time.Millisecond),
+				WithCircuitBreaker(3, time.Minute),
+			}, tt.opts...)
+			client, err := New(config, opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			for range tt.requests {
+				client.PIX().GetQRCode(context.Background(), "abc")
+			}
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("base transport attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestNew_WithRetryHook(t *testing.T) {
+	attempts := 0
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		status := http.StatusTooManyRequests
+		if attempts == 3 {
+			status = http.StatusOK
+		}
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"txid":"abc"}`)),
+			Request:    req,
+		}, nil
+	})
+
+	var retries []RetryAttempt
+	client, err := New(Config{Environment: EnvironmentSandbox, DeveloperAppKey: "test-app-key"},
+		WithTokenProvider(&staticTokenProvider{token: "token"}),
+		WithTransport(base),
+		WithRetry(3, time.Millisecond),
+		WithRetryHook(func(ctx context.Context, a RetryAttempt) {
+			retries = append(retries, a)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := client.PIX().GetQRCode(context.Background(), "7978c0c97ea847e78e8849634473c1f1"); err != nil {
+		t.Fatalf("GetQRCode() error = %v", err)
+	}
+
+	if len(retries) != 2 {
+		t.Fatalf("retries = %d, want 2", len(retries))
+	}
+	for i, a := range retries {
+		if a.Attempt != i+1 || a.StatusCode != http.StatusTooManyRequests || a.Endpoint != "/pix-bb/v1/cob/{id}" {
+			t.Errorf("retry %d = %+v", i, a)
+		}
+	}
+}
+
+func TestNew_WithHooks(t *testing.T) {
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+			Request:    req,
+		}, nil
+	})
+
+	var calls []Call
+	client, err := New(Config{Environment: EnvironmentSandbox, DeveloperAppKey: "test-app-key"},
+		WithTokenProvider(&staticTokenProvider{token: "token"}),
+		WithTransport(base),
+		WithHooks(Hooks{
+			OnResponse: func(ctx context.Context, call Call) {
+				calls = append(calls, call)
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := client.PIX().GetQRCode(context.Background(), "7978c0c97ea847e78e8849634473c1f1"); err != nil {
+		t.Fatalf("GetQRCode() error = %v", err)
+	}
+	if _, err := client.PIXAuto().GetCobR(context.Background(), "7978c0c97ea847e78e8849634473c1f1"); err != nil {
+		t.Fatalf("GetCobR() error = %v", err)
+	}
+
+	if len(calls) != 2 {
+		t.Fatalf("OnResponse calls = %d, want 2", len(calls))
+	}
+	if got := Endpoint(calls[0].Path); got != "/pix-bb/v1/cob/{id}" {
+		t.Errorf("PIX call endpoint = %s, want /pix-bb/v1/cob/{id}", got)
+	}
+	if got := Endpoint(calls[1].Path); got != "/pix-bb/v1/cobr/{id}" {
+		t.Errorf("PIX Automático call endpoint = %s, want /pix-bb/v1/cobr/{id}", got)
+	}
+}
+
+func TestClient_Stats(t *testing.T) {
+	attempts := 0
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		status := http.StatusOK
+		switch {
+		case attempts == 1:
+			status = http.StatusServiceUnavailable
+		case strings.HasSuffix(req.URL.Path, "/missing"):
+			status = http.StatusNotFound
+		}
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+			Request:    req,
+		}, nil
+	})
+
+	client, err := New(Config{Environment: EnvironmentSandbox, DeveloperAppKey: "test-app-key"},
+		WithTokenProvider(&staticTokenProvider{token: "token"}),
+		WithTransport(base),
+		WithRetry(1, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := context.Background()
+	client.PIX().GetQRCode(ctx, "7978c0c97ea847e78e8849634473c1f1")
+	client.PIX().GetQRCode(ctx, "8978c0c97ea847e78e8849634473c1f1")
+	client.PIX().GetQRCode(ctx, "missing")
+
+	stats := client.Stats()
+	if stats.Requests != 3 || stats.Retries != 1 || stats.Errors != 0 || stats.TokenRefreshes != 1 {
+		t.Errorf("Stats() = %+v, want 3 requests, 1 retry and 1 token", stats)
+	}
+	if len(stats.Endpoints) != 2 {
+		t.Fatalf("Stats().Endpoints = %+v, want 2 endpoints", stats.Endpoints)
+	}
+	cob := stats.Endpoints[1]
+	if cob.Method != http.MethodGet || cob.Endpoint != "/pix-bb/v1/cob/{id}" || cob.StatusCodes[http.StatusOK] != 2 || cob.Retries != 1 {
+		t.Errorf("Stats().Endpoints[1] = %+v", cob)
+	}
+	if missing := stats.Endpoints[0]; missing.Endpoint != "/pix-bb/v1/cob/missing" || missing.StatusCodes[http.StatusNotFound] != 1 {
+		t.Errorf("Stats().Endpoints[0] = %+v", missing)
+	}
+}
+
+func TestClient_Stats_TokenFetches(t *testing.T) {
+	var tokenRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/oauth/token" {
+			tokenRequests.Add(1)
+			time.Sleep(20 * time.Millisecond)
+			w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+			return
+		}
+		w.Write([]byte(`{"txid":"abc"}`))
+	}))
+	defer server.Close()
+
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		ClientID:        "test-client-id",
+		ClientSecret:    "test-client-secret",
+		DeveloperAppKey: "test-app-key",
+		OAuthURL:        server.URL + "/oauth/token",
+		APIURL:          server.URL + "/pix-bb/v1",
+	}
+	client, err := New(config)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.PIX().GetQRCode(context.Background(), "abc"); err != nil {
+				t.Errorf("GetQRCode() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := client.Stats()
+	if n := tokenRequests.Load(); n != 1 || stats.TokenFetches != 1 {
+		t.Errorf("token requests = %d, TokenFetches = %d, want 1", n, stats.TokenFetches)
+	}
+	if stats.TokenFetchFailures != 0 || stats.TokenFetchDuration < 20*time.Millisecond {
+		t.Errorf("Stats() = %+v, want a successful fetch of at least 20ms", stats)
+	}
+}
@@ -3,8 +3,10 @@ package bbpix
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"strings"
+	"unicode"
 )
 
 // Environment represents the API environment
@@ -26,23 +28,56 @@ func (e Environment) String() string {
 	return string(e)
 }
 
-// URLs returns the OAuth and API URLs for the environment
-func (e Environment) URLs() (oauthURL, apiURL string) {
+// Preset holds the defaults that differ between environments
+type Preset struct {
+	// OAuthURL is the OAuth2 token endpoint
+	OAuthURL string
+
+	// APIURL is the base URL of the PIX API
+	APIURL string
+
+	// AppKeyHeader is the header that carries the developer application key
+	AppKeyHeader string
+
+	// Scopes are the OAuth2 scopes requested with each token
+	// When empty, no scope is sent and BB grants every scope registered for
+	// the application, PIX Automático ones included.
+	Scopes []string
+}
+
+// Preset returns the defaults for the environment
+// The second return value is false for unknown environments
+func (e Environment) Preset() (Preset, bool) {
 	switch e {
 	case EnvironmentSandbox:
-		return "https://oauth.sandbox.bb.com.br/oauth/token",
-			"https://api.sandbox.bb.com.br/pix-bb/v1"
+		return Preset{
+			OAuthURL:     "https://oauth.sandbox.bb.com.br/oauth/token",
+			APIURL:       "https://api.sandbox.bb.com.br/pix-bb/v1",
+			AppKeyHeader: "gw-dev-app-key",
+		}, true
 	case EnvironmentHomologacao:
-		return "https://oauth.hm.bb.com.br/oauth/token",
-			"https://api.hm.bb.com.br/pix-bb/v1"
+		return Preset{
+			OAuthURL:     "https://oauth.hm.bb.com.br/oauth/token",
+			APIURL:       "https://api.hm.bb.com.br/pix-bb/v1",
+			AppKeyHeader: "gw-dev-app-key",
+		}, true
 	case EnvironmentProducao:
-		return "https://oauth.bb.com.br/oauth/token",
-			"https://api.bb.com.br/pix-bb/v1"
+		return Preset{
+			OAuthURL:     "https://oauth.bb.com.br/oauth/token",
+			APIURL:       "https://api.bb.com.br/pix-bb/v1",
+			AppKeyHeader: "gw-app-key",
+		}, true
 	default:
-		return "", ""
+		return Preset{}, false
 	}
 }
 
+// URLs returns the OAuth and API URLs for the environment
+func (e Environment) URLs() (oauthURL, apiURL string) {
+	preset, _ := e.Preset()
+	return preset.OAuthURL, preset.APIURL
+}
+
 // ParseEnvironment parses a string into an Environment
 func ParseEnvironment(s string) (Environment, error) {
 	switch strings.ToLower(s) {
@@ -71,19 +106,47 @@ type Config struct {
 	// DeveloperAppKey is the developer application key
 	// (gw-dev-app-key for sandbox, gw-app-key for production)
 	DeveloperAppKey string
+
+	// AppKeyHeader overrides the header that carries DeveloperAppKey, which
+	// otherwise follows the environment (gw-dev-app-key in sandbox and
+	// homologação, gw-app-key in production)
+	AppKeyHeader string
+
+	// Scopes are the OAuth2 scopes requested with each token. When empty,
+	// BB grants every scope registered for the application.
+	Scopes []string
+
+	// Convenio is the BB agreement (convênio) number. When set, it is sent
+	// as the numeroConvenio parameter of the PIX and PIX Automático
+	// listings; requests on a single resource do not take it.
+	Convenio string
+
+	// OAuthURL overrides the OAuth2 token endpoint of the environment preset,
+	// e.g. to route through an internal API gateway or a mock server
+	OAuthURL string
+
+	// APIURL overrides the base URL of the environment preset. Its path is
+	// kept as the prefix of every request path.
+	APIURL string
 }
 
 // Validate checks if the configuration is valid
 func (c Config) Validate() error {
+	return c.validate(true)
+}
+
+// validate checks the configuration, requiring the OAuth2 client
+// credentials only when credentials is true
+func (c Config) validate(credentials bool) error {
 	if c.Environment == "" {
 		return errors.New("environment is required")
 	}
 
-	if c.ClientID == "" {
+	if credentials && c.ClientID == "" {
 		return errors.New("client_id is required")
 	}
 
-	if c.ClientSecret == "" {
+	if credentials && c.ClientSecret == "" {
 		return errors.New("client_secret is required")
 	}
 
@@ -91,15 +154,74 @@ func (c Config) Validate() error {
 		return errors.New("developer_application_key is required")
 	}
 
+	if c.Convenio != "" && strings.Trim(c.Convenio, "0123456789") != "" {
+		return errors.New("convenio must contain only digits")
+	}
+
 	// Validate environment URLs
 	oauthURL, apiURL := c.Environment.URLs()
 	if oauthURL == "" || apiURL == "" {
 		return fmt.Errorf("invalid environment: %s", c.Environment)
 	}
 
+	if c.AppKeyHeader != "" && !isToken(c.AppKeyHeader) {
+		return fmt.Errorf("invalid app_key_header: %q is not a valid header name", c.AppKeyHeader)
+	}
+
+	if err := validateURL(c.OAuthURL); err != nil {
+		return fmt.Errorf("invalid oauth_url: %w", err)
+	}
+	if err := validateURL(c.APIURL); err != nil {
+		return fmt.Errorf("invalid api_url: %w", err)
+	}
+
 	return nil
 }
 
+// isToken reports whether s is a valid HTTP header name
+func isToken(s string) bool {
+	for _, r := range s {
+		if r >= unicode.MaxASCII || r <= ' ' || strings.ContainsRune("()<>@,;:\\\"/[]?={}", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateURL checks that an optional URL override is an absolute HTTP(S) URL
+func validateURL(s string) error {
+	if s == "" {
+		return nil
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q must be an absolute http or https URL", s)
+	}
+	return nil
+}
+
+// preset returns the environment preset with the config overrides applied
+func (c Config) preset() Preset {
+	preset, _ := c.Environment.Preset()
+	if len(c.Scopes) > 0 {
+		preset.Scopes = append([]string(nil), c.Scopes...)
+	}
+	if c.OAuthURL != "" {
+		preset.OAuthURL = c.OAuthURL
+	}
+	if c.APIURL != "" {
+		preset.APIURL = c.APIURL
+	}
+	if c.AppKeyHeader != "" {
+		preset.AppKeyHeader = c.AppKeyHeader
+	}
+	return preset
+}
+
 // NewConfig creates and validates a new Config
 func NewConfig(cfg Config) error {
 	return cfg.Validate()
@@ -111,6 +233,10 @@ func NewConfig(cfg Config) error {
 //   - BB_CLIENT_ID: OAuth2 client ID
 //   - BB_CLIENT_SECRET: OAuth2 client secret
 //   - BB_DEV_APP_KEY: Developer application key
+//   - BB_CONVENIO: Agreement (convênio) number (optional)
+//   - BB_OAUTH_URL: OAuth2 token endpoint override (optional)
+//   - BB_API_URL: API base URL override (optional)
+//   - BB_APP_KEY_HEADER: App key header name override (optional)
 func LoadConfigFromEnv() (Config, error) {
 	envStr := os.Getenv("BB_ENVIRONMENT")
 	if envStr == "" {
@@ -142,6 +268,10 @@ func LoadConfigFromEnv() (Config, error) {
 		ClientID:        clientID,
 		ClientSecret:    clientSecret,
 		DeveloperAppKey: appKey,
+		Convenio:        os.Getenv("BB_CONVENIO"),
+		OAuthURL:        os.Getenv("BB_OAUTH_URL"),
+		APIURL:          os.Getenv("BB_API_URL"),
+		AppKeyHeader:    os.Getenv("BB_APP_KEY_HEADER"),
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -0,0 +1,338 @@
+package bbpix
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fileConfig is the layout of a configuration file
+type fileConfig struct {
+	Environment     string   `json:"environment"`
+	ClientID        string   `json:"client_id"`
+	ClientSecret    string   `json:"client_secret"`
+	DeveloperAppKey string   `json:"developer_app_key"`
+	AppKeyHeader    string   `json:"app_key_header"`
+	Scopes          []string `json:"scopes"`
+	Convenio        string   `json:"convenio"`
+	OAuthURL        string   `json:"oauth_url"`
+	APIURL          string   `json:"api_url"`
+
+	Timeout fileDuration `json:"timeout"`
+
+	Retry struct {
+		MaxRetries     fileInt      `json:"max_retries"`
+		InitialBackoff fileDuration `json:"initial_backoff"`
+		StatusCodes    []fileInt    `json:"status_codes"`
+	} `json:"retry"`
+
+	CircuitBreaker struct {
+		MaxFailures  fileInt      `json:"max_failures"`
+		ResetTimeout fileDuration `json:"reset_timeout"`
+	} `json:"circuit_breaker"`
+
+	Certificate struct {
+		Path           string       `json:"path"`
+		Password       string       `json:"password"`
+		ReloadInterval fileDuration `json:"reload_interval"`
+	} `json:"certificate"`
+}
+
+// fileDuration is a duration such as "30s"; an empty or null value leaves
+// it unset
+type fileDuration struct {
+	value time.Duration
+	set   bool
+}
+
+func (d *fileDuration) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\", got %s", data)
+	}
+	if s == "" {
+		return nil
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil || v < 0 {
+		return fmt.Errorf("invalid duration %q", s)
+	}
+	d.value, d.set = v, true
+	return nil
+}
+
+// fileInt is a non-negative integer given as a number or a string; an empty
+// or null value leaves it unset
+type fileInt struct {
+	value int
+	set   bool
+}
+
+func (n *fileInt) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		if s == "" {
+			return nil
+		}
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 0 {
+		return fmt.Errorf("invalid non-negative integer %s", data)
+	}
+	n.value, n.set = v, true
+	return nil
+}
+
+// LoadConfigFromFile loads the configuration and client options from a
+// JSON (.json) or YAML (.yaml, .yml) file
+// Besides the Config fields, the file may set the timeout, retry and
+// circuit breaker settings and a PKCS#12 client certificate, returned as
+// options for New:
+//
+//	environment: producao
+//	client_id: ${BB_CLIENT_ID}
+//	client_secret: ${BB_CLIENT_SECRET}
+//	developer_app_key: ${BB_DEV_APP_KEY}
+//	timeout: 30s
+//	retry:
+//	  max_retries: 3
+//	  initial_backoff: 100ms
+//	  status_codes: [429, 500, 502, 503, 504]
+//	certificate:
+//	  path: certificado.pfx
+//	  password: ${BB_CERT_PASSWORD}
+//	  reload_interval: 1m
+//
+// String values may reference environment variables as ${NAME}, or
+// ${NAME:-default} to fall back when NAME is unset; a reference to an unset
+// variable without default is an error and $$ is a literal $. A relative
+// certificate path is relative to the directory of the file. Only a subset
+// of YAML is supported: mappings, sequences of scalars and comments.
+func LoadConfigFromFile(path string) (Config, []Option, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc any
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.Unmarshal(data, &doc)
+	case ".yaml", ".yml":
+		doc, err = parseYAML(data)
+	default:
+		return Config{}, nil, fmt.Errorf("unsupported config file extension %q (must be .json, .yaml or .yml)", ext)
+	}
+	if err != nil {
+		return Config{}, nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if doc, err = expandEnvValues(doc); err != nil {
+		return Config{}, nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	var file fileConfig
+	if err := decodeFileConfig(doc, &file); err != nil {
+		return Config{}, nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	env, err := ParseEnvironment(file.Environment)
+	if err != nil {
+		return Config{}, nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	cfg := Config{
+		Environment:     env,
+		ClientID:        file.ClientID,
+		ClientSecret:    file.ClientSecret,
+		DeveloperAppKey: file.DeveloperAppKey,
+		AppKeyHeader:    file.AppKeyHeader,
+		Scopes:          file.Scopes,
+		Convenio:        file.Convenio,
+		OAuthURL:        file.OAuthURL,
+		APIURL:          file.APIURL,
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	opts, err := file.options(filepath.Dir(path))
+	if err != nil {
+		return Config{}, nil, err
+	}
+
+	return cfg, opts, nil
+}
+
+// decodeFileConfig decodes a parsed document into file, rejecting unknown
+// keys so typos do not go unnoticed
+func decodeFileConfig(doc any, file *fileConfig) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(file)
+}
+
+// options returns the client options set by the file
+func (f *fileConfig) options(dir string) ([]Option, error) {
+	var opts []Option
+
+	if f.Timeout.set {
+		opts = append(opts, WithTimeout(f.Timeout.value))
+	}
+
+	retry, breaker := f.Retry, f.CircuitBreaker
+	if retry.MaxRetries.set || retry.InitialBackoff.set || breaker.MaxFailures.set || breaker.ResetTimeout.set {
+		opts = append(opts, func(o *clientOptions) {
+			if retry.MaxRetries.set {
+				o.maxRetries = retry.MaxRetries.value
+			}
+			if retry.InitialBackoff.set {
+				o.initialBackoff = retry.InitialBackoff.value
+			}
+			if breaker.MaxFailures.set {
+				o.circuitBreakerMaxFailures = breaker.MaxFailures.value
+			}
+			if breaker.ResetTimeout.set {
+				o.circuitBreakerResetTimeout = breaker.ResetTimeout.value
+			}
+		})
+	}
+
+	if retry.StatusCodes != nil {
+		codes := make([]int, 0, len(retry.StatusCodes))
+		for _, code := range retry.StatusCodes {
+			if !code.set || !validStatusCode(code.value) {
+				return nil, fmt.Errorf("invalid configuration: retry status code %d is not an HTTP status code", code.value)
+			}
+			codes = append(codes, code.value)
+		}
+		opts = append(opts, WithRetryableStatusCodes(codes...))
+	}
+
+	cert := f.Certificate
+	if cert.Path == "" {
+		if cert.Password != "" || cert.ReloadInterval.set {
+			return nil, errors.New("invalid configuration: certificate path is required")
+		}
+		return opts, nil
+	}
+
+	path := cert.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	if cert.ReloadInterval.set {
+		reloader, err := NewCertificateReloader(path, cert.Password, cert.ReloadInterval.value)
+		if err != nil {
+			return nil, err
+		}
+		return append(opts, WithGetClientCertificate(reloader.GetClientCertificate)), nil
+	}
+
+	tlsCert, err := LoadPKCS12(path, cert.Password)
+	if err != nil {
+		return nil, err
+	}
+	return append(opts, WithClientCertificate(tlsCert)), nil
+}
+
+// expandEnvValues expands the environment references of every string in a
+// parsed document
+func expandEnvValues(v any) (any, error) {
+	switch v := v.(type) {
+	case string:
+		return expandEnv(v)
+
+	case []any:
+		for i, item := range v {
+			expanded, err := expandEnvValues(item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = expanded
+		}
+
+	case map[string]any:
+		for key, item := range v {
+			expanded, err := expandEnvValues(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			v[key] = expanded
+		}
+	}
+	return v, nil
+}
+
+// expandEnv replaces ${NAME} and ${NAME:-default} with the value of the
+// environment variable NAME, and $$ with $
+func expandEnv(s string) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(s, '$')
+		if i < 0 || i == len(s)-1 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		b.WriteString(s[:i])
+
+		switch s[i+1] {
+		case '$':
+			b.WriteByte('$')
+			s = s[i+2:]
+
+		case '{':
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated variable reference in %q", s)
+			}
+			ref := s[i+2 : i+end]
+			name, def, hasDefault := strings.Cut(ref, ":-")
+			if name == "" {
+				return "", fmt.Errorf("empty variable reference in %q", s)
+			}
+
+			value, ok := os.LookupEnv(name)
+			switch {
+			case !ok && hasDefault:
+				value = def
+			case !ok:
+				return "", fmt.Errorf("environment variable %s is not set", name)
+			}
+			b.WriteString(value)
+			s = s[i+end+1:]
+
+		default:
+			b.WriteByte('$')
+			s = s[i+1:]
+		}
+	}
+}
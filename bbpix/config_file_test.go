@@ -0,0 +1,193 @@
+package bbpix
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfigFromFile(t *testing.T) {
+	t.Setenv("TEST_BB_CLIENT_ID", "file-client-id")
+	t.Setenv("TEST_BB_CLIENT_SECRET", "file-client-secret")
+	t.Setenv("TEST_BB_CERT_PASSWORD", "senha123")
+
+	tests := []struct {
+		name       string
+		path       string
+		wantEnv    Environment
+		wantAPIURL string
+		wantConv   string
+		wantCertFn bool
+	}{
+		{
+			name:       "yaml",
+			path:       "testdata/config.yaml",
+			wantEnv:    EnvironmentProducao,
+			wantAPIURL: "https://gateway.example.com/pix-bb/v1",
+			wantConv:   "0123",
+		},
+		{
+			name:       "json",
+			path:       "testdata/config.json",
+			wantEnv:    EnvironmentSandbox,
+			wantCertFn: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, opts, err := LoadConfigFromFile(tt.path)
+			if err != nil {
+				t.Fatalf("LoadConfigFromFile() error = %v", err)
+			}
+
+			if cfg.Environment != tt.wantEnv {
+				t.Errorf("Environment = %q, want %q", cfg.Environment, tt.wantEnv)
+			}
+			if cfg.ClientID != "file-client-id" || cfg.ClientSecret != "file-client-secret" {
+				t.Errorf("credentials = %q, %q, want the interpolated values", cfg.ClientID, cfg.ClientSecret)
+			}
+			if cfg.DeveloperAppKey != "app-key" {
+				t.Errorf("DeveloperAppKey = %q, want app-key", cfg.DeveloperAppKey)
+			}
+			if cfg.APIURL != tt.wantAPIURL {
+				t.Errorf("APIURL = %q, want %q", cfg.APIURL, tt.wantAPIURL)
+			}
+			if cfg.Convenio != tt.wantConv {
+				t.Errorf("Convenio = %q, want %q", cfg.Convenio, tt.wantConv)
+			}
+			if strings.Join(cfg.Scopes, " ") != "cob.read pix.read" {
+				t.Errorf("Scopes = %v, want [cob.read pix.read]", cfg.Scopes)
+			}
+
+			options := defaultClientOptions()
+			for _, opt := range opts {
+				opt(options)
+			}
+			if options.timeout != 45*time.Second {
+				t.Errorf("timeout = %v, want 45s", options.timeout)
+			}
+			if options.maxRetries != 5 || options.initialBackoff != 250*time.Millisecond {
+				t.Errorf("retry = %d, %v, want 5, 250ms", options.maxRetries, options.initialBackoff)
+			}
+			if !slices.Equal(options.retryPolicy.RetryableStatusCodes, []int{429, 500, 503}) {
+				t.Errorf("RetryableStatusCodes = %v, want [429 500 503]", options.retryPolicy.RetryableStatusCodes)
+			}
+			if options.circuitBreakerMaxFailures != 5 || options.circuitBreakerResetTimeout != 2*time.Minute {
+				t.Errorf("circuit breaker = %d, %v, want the default failures and 2m", options.circuitBreakerMaxFailures, options.circuitBreakerResetTimeout)
+			}
+			if tt.wantCertFn {
+				if options.getClientCertificate == nil || options.clientCertificate != nil {
+					t.Error("want the certificate served by a reloader")
+				}
+			} else if options.clientCertificate == nil || options.clientCertificate.Leaf == nil {
+				t.Error("want the certificate loaded from the file directory")
+			}
+		})
+	}
+}
+
+func TestLoadConfigFromFile_Errors(t *testing.T) {
+	t.Setenv("TEST_BB_SET", "value")
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "unsupported extension",
+			file:    "config.toml",
+			content: `environment = "sandbox"`,
+			wantErr: "unsupported config file extension",
+		},
+		{
+			name:    "unset variable",
+			file:    "config.yaml",
+			content: "environment: sandbox\nclient_id: ${TEST_BB_UNSET}\n",
+			wantErr: "TEST_BB_UNSET is not set",
+		},
+		{
+			name:    "unknown key",
+			file:    "config.json",
+			content: `{"environment": "sandbox", "client_secrt": "x"}`,
+			wantErr: "unknown field",
+		},
+		{
+			name:    "invalid duration",
+			file:    "config.yaml",
+			content: "environment: sandbox\nclient_id: ${TEST_BB_SET}\nclient_secret: s\ndeveloper_app_key: k\ntimeout: 30\n",
+			wantErr: "invalid duration",
+		},
+		{
+			name:    "invalid configuration",
+			file:    "config.yaml",
+			content: "environment: sandbox\nclient_id: ${TEST_BB_SET}\n",
+			wantErr: "invalid configuration",
+		},
+		{
+			name:    "invalid retry status code",
+			file:    "config.yaml",
+			content: "environment: sandbox\nclient_id: id\nclient_secret: s\ndeveloper_app_key: k\nretry:\n  status_codes: [500, 600]\n",
+			wantErr: "retry status code 600",
+		},
+		{
+			name:    "certificate without path",
+			file:    "config.yaml",
+			content: "environment: sandbox\nclient_id: id\nclient_secret: s\ndeveloper_app_key: k\ncertificate:\n  password: x\n",
+			wantErr: "certificate path is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			_, _, err := LoadConfigFromFile(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadConfigFromFile() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("TEST_BB_SET", "value")
+	t.Setenv("TEST_BB_EMPTY", "")
+
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "plain", want: "plain"},
+		{in: "${TEST_BB_SET}", want: "value"},
+		{in: "a-${TEST_BB_SET}-b", want: "a-value-b"},
+		{in: "${TEST_BB_UNSET:-fallback}", want: "fallback"},
+		{in: "${TEST_BB_EMPTY:-fallback}", want: ""},
+		{in: "${TEST_BB_SET:-fallback}", want: "value"},
+		{in: "cost $$5 and $x", want: "cost $5 and $x"},
+		{in: "trailing $", want: "trailing $"},
+		{in: "${TEST_BB_UNSET}", wantErr: true},
+		{in: "${TEST_BB_SET", wantErr: true},
+		{in: "${}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := expandEnv(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("expandEnv(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("expandEnv(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
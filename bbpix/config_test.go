@@ -194,6 +194,29 @@ func TestNewConfig(t *testing.T) {
 			wantErr: true,
 			errMsg:  "environment is required",
 		},
+		{
+			name: "valid convenio",
+			config: Config{
+				Environment:     EnvironmentSandbox,
+				ClientID:        "client-id",
+				ClientSecret:    "client-secret",
+				DeveloperAppKey: "app-key",
+				Convenio:        "1234567",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid convenio",
+			config: Config{
+				Environment:     EnvironmentSandbox,
+				ClientID:        "client-id",
+				ClientSecret:    "client-secret",
+				DeveloperAppKey: "app-key",
+				Convenio:        "12-34",
+			},
+			wantErr: true,
+			errMsg:  "convenio must contain only digits",
+		},
 	}
 
 	for _, tt := range tests {
@@ -235,6 +258,62 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "URL overrides",
+			config: Config{
+				Environment:     EnvironmentSandbox,
+				ClientID:        "id",
+				ClientSecret:    "secret",
+				DeveloperAppKey: "key",
+				OAuthURL:        "http://localhost:8080/oauth/token",
+				APIURL:          "https://gateway.example.com/pix-bb/v1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "app key header override",
+			config: Config{
+				Environment:     EnvironmentSandbox,
+				ClientID:        "id",
+				ClientSecret:    "secret",
+				DeveloperAppKey: "key",
+				AppKeyHeader:    "x-app-key",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid app key header",
+			config: Config{
+				Environment:     EnvironmentSandbox,
+				ClientID:        "id",
+				ClientSecret:    "secret",
+				DeveloperAppKey: "key",
+				AppKeyHeader:    "app key:",
+			},
+			wantErr: true,
+		},
+		{
+			name: "relative API URL",
+			config: Config{
+				Environment:     EnvironmentSandbox,
+				ClientID:        "id",
+				ClientSecret:    "secret",
+				DeveloperAppKey: "key",
+				APIURL:          "/pix-bb/v1",
+			},
+			wantErr: true,
+		},
+		{
+			name: "OAuth URL without scheme",
+			config: Config{
+				Environment:     EnvironmentSandbox,
+				ClientID:        "id",
+				ClientSecret:    "secret",
+				DeveloperAppKey: "key",
+				OAuthURL:        "oauth.example.com/token",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -343,3 +422,83 @@ func TestLoadConfigFromEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvironment_Preset(t *testing.T) {
+	tests := []struct {
+		name             string
+		env              Environment
+		wantOK           bool
+		wantAppKeyHeader string
+	}{
+		{name: "sandbox", env: EnvironmentSandbox, wantOK: true, wantAppKeyHeader: "gw-dev-app-key"},
+		{name: "homologacao", env: EnvironmentHomologacao, wantOK: true, wantAppKeyHeader: "gw-dev-app-key"},
+		{name: "producao", env: EnvironmentProducao, wantOK: true, wantAppKeyHeader: "gw-app-key"},
+		{name: "unknown", env: Environment("invalid"), wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preset, ok := tt.env.Preset()
+			if ok != tt.wantOK {
+				t.Fatalf("Preset() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+
+			if preset.AppKeyHeader != tt.wantAppKeyHeader {
+				t.Errorf("AppKeyHeader = %q, want %q", preset.AppKeyHeader, tt.wantAppKeyHeader)
+			}
+			if len(preset.Scopes) != 0 {
+				t.Errorf("Scopes = %v, want none so BB grants the registered scopes", preset.Scopes)
+			}
+
+			oauthURL, apiURL := tt.env.URLs()
+			if preset.OAuthURL != oauthURL || preset.APIURL != apiURL {
+				t.Error("Preset() URLs should match URLs()")
+			}
+		})
+	}
+}
+
+func TestConfig_PresetScopesOverride(t *testing.T) {
+	cfg := Config{
+		Environment: EnvironmentSandbox,
+		Scopes:      []string{"cob.read"},
+	}
+
+	preset := cfg.preset()
+	if len(preset.Scopes) != 1 || preset.Scopes[0] != "cob.read" {
+		t.Errorf("Scopes = %v, want [cob.read]", preset.Scopes)
+	}
+
+	// The override must not leak into the shared defaults
+	defaults, _ := EnvironmentSandbox.Preset()
+	if len(defaults.Scopes) != 0 {
+		t.Errorf("environment preset Scopes = %v, want none", defaults.Scopes)
+	}
+}
+
+func TestConfig_PresetURLOverrides(t *testing.T) {
+	cfg := Config{
+		Environment: EnvironmentProducao,
+		OAuthURL:    "https://gateway.example.com/oauth/token",
+		APIURL:      "https://gateway.example.com/pix-bb/v1",
+	}
+
+	preset := cfg.preset()
+	if preset.OAuthURL != cfg.OAuthURL || preset.APIURL != cfg.APIURL {
+		t.Errorf("URLs = %s, %s, want the overrides", preset.OAuthURL, preset.APIURL)
+	}
+	if preset.AppKeyHeader != "gw-app-key" {
+		t.Errorf("AppKeyHeader = %q, want the environment default", preset.AppKeyHeader)
+	}
+}
+
+func TestConfig_PresetAppKeyHeaderOverride(t *testing.T) {
+	cfg := Config{Environment: EnvironmentProducao, AppKeyHeader: "x-app-key"}
+
+	if got := cfg.preset().AppKeyHeader; got != "x-app-key" {
+		t.Errorf("AppKeyHeader = %q, want x-app-key", got)
+	}
+}
@@ -1,7 +1,10 @@
 package bbpix
 
 import (
+	"errors"
+
 	"github.com/pericles-luz/go-bb-pix/internal/apierror"
+	"github.com/pericles-luz/go-bb-pix/internal/auth"
 )
 
 // Re-export types from internal/apierror for public API
@@ -11,8 +14,29 @@ type (
 
 	// APIError represents an error returned by the Banco do Brasil API
 	APIError = apierror.APIError
+
+	// ScopeError is returned for a 403 caused by a scope missing from the
+	// access token; it wraps the APIError of the response
+	ScopeError = apierror.ScopeError
+
+	// AuthError is returned when the OAuth2 token endpoint rejects a token
+	// request, e.g. with invalid_client or a 5xx
+	AuthError = auth.AuthError
 )
 
+// OAuth2 error codes reported in AuthError.Code
+const (
+	AuthErrorInvalidRequest       = auth.ErrorInvalidRequest
+	AuthErrorInvalidClient        = auth.ErrorInvalidClient
+	AuthErrorInvalidGrant         = auth.ErrorInvalidGrant
+	AuthErrorUnauthorizedClient   = auth.ErrorUnauthorizedClient
+	AuthErrorUnsupportedGrantType = auth.ErrorUnsupportedGrantType
+	AuthErrorInvalidScope         = auth.ErrorInvalidScope
+)
+
+// ErrResetInProduction is returned by ResetSandbox in the production environment
+var ErrResetInProduction = errors.New("sandbox reset is not allowed in production")
+
 // NewAPIError creates a new APIError
 func NewAPIError(statusCode int, message string, details ...ErrorDetail) *APIError {
 	return apierror.New(statusCode, message, details...)
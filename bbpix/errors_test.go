@@ -1,9 +1,13 @@
 package bbpix
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestAPIError_Error(t *testing.T) {
@@ -244,3 +248,76 @@ func TestErrorDetail_String(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_InsufficientScope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":"insufficient_scope","error_description":"Token sem o escopo necessário","scope":"cob.read"}`))
+	}))
+	defer server.Close()
+
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		DeveloperAppKey: "test-app-key",
+		APIURL:          server.URL + "/pix-bb/v1",
+	}
+	client, err := New(config,
+		WithTokenProvider(&staticTokenProvider{token: "token"}),
+		WithCircuitBreaker(2, time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err = client.PIX().GetQRCode(context.Background(), "7978c0c97ea847e78e8849634473c1f1")
+
+		var scopeErr *ScopeError
+		if !errors.As(err, &scopeErr) {
+			t.Fatalf("GetQRCode() error = %v, want a ScopeError", err)
+		}
+		if len(scopeErr.Scopes) != 1 || scopeErr.Scopes[0] != "cob.read" {
+			t.Errorf("Scopes = %v, want [cob.read]", scopeErr.Scopes)
+		}
+		if !IsAPIError(err) {
+			t.Errorf("IsAPIError(%v) = false, want true", err)
+		}
+	}
+
+	if state := client.CircuitBreaker().State; state != CircuitClosed {
+		t.Errorf("circuit breaker state = %v, want closed", state)
+	}
+}
+
+func TestClient_AuthError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"invalid_client","error_description":"Client authentication failed"}`))
+	}))
+	defer server.Close()
+
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		ClientID:        "wrong-client-id",
+		ClientSecret:    "wrong-client-secret",
+		DeveloperAppKey: "test-app-key",
+		OAuthURL:        server.URL + "/oauth/token",
+		APIURL:          server.URL + "/pix-bb/v1",
+	}
+	client, err := New(config)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	_, err = client.PIX().GetQRCode(context.Background(), "7978c0c97ea847e78e8849634473c1f1")
+
+	var authErr *AuthError
+	if !errors.As(err, &authErr) {
+		t.Fatalf("GetQRCode() error = %v, want an AuthError", err)
+	}
+	if authErr.StatusCode != http.StatusUnauthorized || authErr.Code != AuthErrorInvalidClient || authErr.Temporary() {
+		t.Errorf("AuthError = %+v, want a permanent 401 invalid_client", authErr)
+	}
+}
@@ -1,15 +1,149 @@
 package bbpix
 
 import (
+	"context"
+	"crypto/tls"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/pericles-luz/go-bb-pix/internal/auth"
+	"github.com/pericles-luz/go-bb-pix/internal/transport"
+	"github.com/pericles-luz/go-bb-pix/pix"
+	"github.com/pericles-luz/go-bb-pix/redact"
+)
+
+// Re-export audit types from the pix package for public API
+type (
+	// AuditRecord describes a mutating request and its outcome as sent and
+	// received on the wire
+	AuditRecord = pix.AuditRecord
+
+	// AuditFunc receives an AuditRecord after every mutating request
+	AuditFunc = pix.AuditFunc
+
+	// Hooks are functions called around every API call (see WithHooks)
+	Hooks = pix.Hooks
+
+	// Call describes an API call passed to the Hooks
+	Call = pix.Call
+
+	// TokenRejection describes a 401 response received for a token the
+	// client still considered valid
+	TokenRejection = transport.TokenRejection
+
+	// TokenRejectedFunc is called for every rejection of a still valid token
+	TokenRejectedFunc = transport.TokenRejectedFunc
+
+	// TokenDriftStats summarizes the token rejections observed by the client
+	TokenDriftStats = transport.TokenDriftStats
+
+	// PaginationStyle selects the names of the paging query parameters
+	PaginationStyle = pix.PaginationStyle
+
+	// OperationTimeouts are the default durations of the read, write and
+	// list calls of the PIX client
+	OperationTimeouts = pix.Timeouts
+
+	// RetryPolicy customizes which failed requests are retried and how long
+	// the client waits between attempts (see WithRetryPolicy)
+	RetryPolicy = transport.RetryPolicy
+
+	// EndpointStats are the counters of the requests to an endpoint (see
+	// Client.Stats)
+	EndpointStats = transport.EndpointStats
+
+	// RetryAttempt describes a retry about to be attempted (see
+	// WithRetryHook)
+	RetryAttempt = transport.RetryAttempt
+
+	// RetryFunc is called before every retry
+	RetryFunc = transport.RetryFunc
+
+	// LogLevels are the levels requests are logged at, by outcome (see
+	// WithLogLevels)
+	LogLevels = transport.LogLevels
+
+	// Jitter selects how the wait between retries is randomized (see
+	// RetryPolicy.Jitter)
+	Jitter = transport.Jitter
+
+	// RetryBudget limits the retries of the client to a share of its
+	// requests (see RetryPolicy.Budget)
+	RetryBudget = transport.RetryBudget
+
+	// CircuitState is the state of the circuit breaker
+	CircuitState = transport.CircuitState
+
+	// CircuitBreakerStats describes the current state of the circuit
+	// breaker (see Client.CircuitBreaker)
+	CircuitBreakerStats = transport.CircuitBreakerStats
+
+	// CircuitStateChangeFunc is called when the circuit breaker changes
+	// state (see WithCircuitBreakerHook)
+	CircuitStateChangeFunc = transport.CircuitStateChangeFunc
+
+	// MetricsRecorder receives the metrics of the requests and of the
+	// circuit breaker (see WithMetrics)
+	MetricsRecorder = transport.MetricsRecorder
+
+	// RequestMetrics describes a finished API request, retries included
+	RequestMetrics = transport.RequestMetrics
+
+	// RequestTracer traces the API requests (see WithTracer)
+	RequestTracer = transport.RequestTracer
+
+	// Token is an OAuth2 access token
+	Token = auth.Token
+
+	// TokenProvider supplies the access tokens sent by the client (see
+	// WithTokenProvider)
+	TokenProvider = auth.TokenProvider
+
+	// TokenInvalidator is implemented by the TokenProviders that can
+	// invalidate the rejected token only, keeping a newer one
+	TokenInvalidator = auth.TokenInvalidator
+
+	// TokenStore shares the access tokens between processes (see
+	// WithTokenStore)
+	TokenStore = auth.TokenStore
+
+	// FileTokenStore is a TokenStore that keeps the tokens in files
+	FileTokenStore = auth.FileTokenStore
+)
+
+// Jitter strategies
+const (
+	JitterProportional = transport.JitterProportional
+	JitterNone         = transport.JitterNone
+	JitterFull         = transport.JitterFull
+	JitterEqual        = transport.JitterEqual
+)
+
+// Circuit breaker states
+const (
+	CircuitClosed   = transport.CircuitClosed
+	CircuitOpen     = transport.CircuitOpen
+	CircuitHalfOpen = transport.CircuitHalfOpen
+)
+
+// Pagination styles
+const (
+	PaginationNested = pix.PaginationNested
+	PaginationFlat   = pix.PaginationFlat
 )
 
 // Option is a functional option for configuring the client
 type Option func(*clientOptions)
 
+// Middleware wraps the transport of the client (see WithMiddleware)
+type Middleware func(http.RoundTripper) http.RoundTripper
+
 // clientOptions holds all configurable options for the client
 type clientOptions struct {
 	logger                       *slog.Logger
@@ -19,7 +153,37 @@ type clientOptions struct {
 	initialBackoff               time.Duration
 	circuitBreakerMaxFailures    int
 	circuitBreakerResetTimeout   time.Duration
+	circuitBreakerProbes         int
+	circuitBreakerDisabled       bool
+	retryDisabled                bool
 	userAgent                    string
+	header                       http.Header
+	requestIDHeader              string
+	metrics                      MetricsRecorder
+	tracer                       RequestTracer
+	debugBodies                  bool
+	debugBodyLimit               int
+	auditFunc                    AuditFunc
+	hooks                        Hooks
+	redactor                     *redact.Redactor
+	tokenRejectedFunc            TokenRejectedFunc
+	circuitStateChangeFunc       CircuitStateChangeFunc
+	paginationStyle              pix.PaginationStyle
+	operationTimeouts            pix.Timeouts
+	clientCertificate            *tls.Certificate
+	getClientCertificate         func(*tls.CertificateRequestInfo) (*tls.Certificate, error)
+	tokenProvider                TokenProvider
+	tokenStore                   TokenStore
+	transport                    http.RoundTripper
+	middlewares                  []Middleware
+	proxy                        *url.URL
+	rateLimit                    float64
+	rateBurst                    int
+	retryPolicy                  RetryPolicy
+	logLevels                    LogLevels
+	logSampling                  int
+	slowThreshold                time.Duration
+	retryFunc                    RetryFunc
 }
 
 // defaultClientOptions returns the default client options
@@ -31,10 +195,81 @@ func defaultClientOptions() *clientOptions {
 		initialBackoff:             100 * time.Millisecond,
 		circuitBreakerMaxFailures:  5,
 		circuitBreakerResetTimeout: 60 * time.Second,
+		circuitBreakerProbes:       1,
 		userAgent:                  "go-bb-pix/1.0.0",
+		logLevels:                  transport.DefaultLogLevels,
 	}
 }
 
+// Environment variables that override the default resilience options
+const (
+	EnvRetryCount     = "BB_RETRY_COUNT"
+	EnvRetryDelayMS   = "BB_RETRY_DELAY_MS"
+	EnvTimeoutSeconds = "BB_TIMEOUT_SECONDS"
+
+	// EnvRetryStatusCodes is a comma-separated list of the retried response
+	// statuses, such as "429,500,502,503,504"
+	EnvRetryStatusCodes = "BB_RETRY_STATUS_CODES"
+)
+
+// applyEnvOptions overrides the defaults with the values of EnvRetryCount,
+// EnvRetryDelayMS, EnvTimeoutSeconds and EnvRetryStatusCodes when they are
+// set
+// Options passed to New still take precedence over the environment.
+func applyEnvOptions(opts *clientOptions) error {
+	if v := os.Getenv(EnvRetryCount); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid %s %q: must be a non-negative integer", EnvRetryCount, v)
+		}
+		opts.maxRetries = n
+	}
+
+	if v := os.Getenv(EnvRetryDelayMS); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid %s %q: must be a non-negative integer", EnvRetryDelayMS, v)
+		}
+		opts.initialBackoff = time.Duration(n) * time.Millisecond
+	}
+
+	if v := os.Getenv(EnvTimeoutSeconds); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid %s %q: must be a positive integer", EnvTimeoutSeconds, v)
+		}
+		opts.timeout = time.Duration(n) * time.Second
+	}
+
+	if v := os.Getenv(EnvRetryStatusCodes); v != "" {
+		codes, err := parseStatusCodes(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", EnvRetryStatusCodes, v, err)
+		}
+		opts.retryPolicy.RetryableStatusCodes = codes
+	}
+
+	return nil
+}
+
+// parseStatusCodes parses a comma-separated list of HTTP status codes
+func parseStatusCodes(s string) ([]int, error) {
+	var codes []int
+	for field := range strings.SplitSeq(s, ",") {
+		code, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || !validStatusCode(code) {
+			return nil, fmt.Errorf("%q is not an HTTP status code", strings.TrimSpace(field))
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// validStatusCode reports whether code is in the HTTP status code range
+func validStatusCode(code int) bool {
+	return code >= 100 && code <= 599
+}
+
 // WithLogger sets a custom logger for the client
 func WithLogger(logger *slog.Logger) Option {
 	return func(opts *clientOptions) {
@@ -50,6 +285,39 @@ func WithHTTPClient(client *http.Client) Option {
 	}
 }
 
+// WithTransport sets the innermost transport, which sends the requests once
+// the rest of the chain is applied, as well as the OAuth2 token requests; it
+// takes precedence over the transport of WithHTTPClient
+// Default: http.DefaultTransport
+func WithTransport(rt http.RoundTripper) Option {
+	return func(opts *clientOptions) {
+		opts.transport = rt
+	}
+}
+
+// WithMiddleware adds transport wrappers between the auth and retry layers
+// of the chain, so they see the request with its auth headers and run once
+// per request, outside the retries
+// The first middleware is the outermost; calls accumulate.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(opts *clientOptions) {
+		opts.middlewares = append(opts.middlewares, mw...)
+	}
+}
+
+// WithRateLimit limits the requests sent to the API to rps per second on
+// average, with bursts of up to burst requests, waiting for the rate when
+// needed; a wait that would outlast the request deadline fails at once
+// Retries take from the same budget, so they never push the client over the
+// quota. A rps of 0 disables the limit.
+// Default: disabled
+func WithRateLimit(rps float64, burst int) Option {
+	return func(opts *clientOptions) {
+		opts.rateLimit = rps
+		opts.rateBurst = burst
+	}
+}
+
 // WithTimeout sets the timeout for HTTP requests
 // Default: 30 seconds
 func WithTimeout(timeout time.Duration) Option {
@@ -58,6 +326,17 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithOperationTimeouts sets per-operation deadlines for the PIX client
+// calls: Read for single lookups, Write for creations, updates and removals
+// and List for each page of a listing, retries included; zero durations
+// leave only WithTimeout, which bounds every attempt
+// Default: no per-operation deadline
+func WithOperationTimeouts(t OperationTimeouts) Option {
+	return func(opts *clientOptions) {
+		opts.operationTimeouts = t
+	}
+}
+
 // WithRetry configures the retry behavior
 // maxRetries: maximum number of retry attempts (default: 3)
 // initialBackoff: initial backoff duration (default: 100ms)
@@ -69,6 +348,103 @@ func WithRetry(maxRetries int, initialBackoff time.Duration) Option {
 	}
 }
 
+// WithRetryDisabled removes the retry layer, e.g. when a service mesh
+// already retries the requests; every request is sent once
+func WithRetryDisabled() Option {
+	return func(opts *clientOptions) {
+		opts.retryDisabled = true
+	}
+}
+
+// WithRetryPolicy customizes the retries configured by WithRetry: a custom
+// predicate or set of retryable status codes, a cap on the wait between
+// attempts and a limit on the total time spent on a request
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(opts *clientOptions) {
+		opts.retryPolicy = p
+	}
+}
+
+// WithRetryableStatusCodes sets the response statuses retried, besides
+// network errors, replacing the default 429, 502, 503 and 504; include 500
+// to retry the transient errors of the BB gateway
+// Calling it without codes retries network errors only. It changes only the
+// status codes of the policy set by WithRetryPolicy.
+func WithRetryableStatusCodes(codes ...int) Option {
+	return func(opts *clientOptions) {
+		opts.retryPolicy.RetryableStatusCodes = append([]int{}, codes...)
+	}
+}
+
+// IdempotentContext returns a context whose requests carry key in the
+// Idempotency-Key header and are retried like idempotent ones, so POST and
+// PATCH operations can be retried safely when the API deduplicates them by
+// key (see pix.IdempotencyKey for deterministic keys)
+// An empty key leaves the context unchanged.
+func IdempotentContext(ctx context.Context, key string) context.Context {
+	return transport.WithIdempotencyKey(ctx, key)
+}
+
+// WithRequestID sends a request ID in header with every API request and adds
+// it to the request logs as request_id, so the logs can be matched with BB
+// support tickets; an empty header means X-Request-Id
+// The ID is taken from the context (see RequestIDContext) or generated.
+// Retries of a request share its ID.
+func WithRequestID(header string) Option {
+	return func(opts *clientOptions) {
+		if header == "" {
+			header = transport.DefaultRequestIDHeader
+		}
+		opts.requestIDHeader = header
+	}
+}
+
+// LoggerContext returns a context whose requests are logged with logger
+// instead of the client logger, e.g. a logger tagged with the tenant or
+// order being processed
+// The client redaction rules still apply. A nil logger leaves the context
+// unchanged.
+func LoggerContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return transport.WithLogger(ctx, logger)
+}
+
+// LogAttrsContext returns a context whose request logs carry attrs, besides
+// those already attached to ctx
+func LogAttrsContext(ctx context.Context, attrs ...slog.Attr) context.Context {
+	return transport.WithLogAttrs(ctx, attrs...)
+}
+
+// RequestIDContext returns a context whose requests are sent with id as their
+// request ID, e.g. the correlation ID of the incoming request being served,
+// when WithRequestID is enabled
+// An empty id leaves the context unchanged.
+func RequestIDContext(ctx context.Context, id string) context.Context {
+	return transport.WithRequestID(ctx, id)
+}
+
+// WithMetrics reports the metrics of every API request (endpoint, status,
+// duration and retries) and the circuit breaker state changes to r, e.g.
+// the Prometheus collector of the contrib/bbprom module
+func WithMetrics(r MetricsRecorder) Option {
+	return func(opts *clientOptions) {
+		opts.metrics = r
+	}
+}
+
+// WithTracer traces every API request with t, as a single span including its
+// retries, e.g. with the OpenTelemetry tracer of the contrib/bbotel module
+func WithTracer(t RequestTracer) Option {
+	return func(opts *clientOptions) {
+		opts.tracer = t
+	}
+}
+
+// Endpoint returns path with its identifiers replaced by {id}, as reported
+// in RequestMetrics, e.g. /pix-bb/v1/cob/{id}
+func Endpoint(path string) string {
+	return transport.Endpoint(path)
+}
+
 // WithCircuitBreaker configures the circuit breaker
 // maxFailures: number of consecutive failures before opening circuit (default: 5)
 // resetTimeout: time to wait before attempting to close circuit (default: 60s)
@@ -79,10 +455,213 @@ func WithCircuitBreaker(maxFailures int, resetTimeout time.Duration) Option {
 	}
 }
 
+// WithCircuitBreakerDisabled removes the circuit breaker layer, e.g. when a
+// service mesh already provides one; requests are never failed fast
+func WithCircuitBreakerDisabled() Option {
+	return func(opts *clientOptions) {
+		opts.circuitBreakerDisabled = true
+	}
+}
+
+// WithCircuitBreakerProbes sets how many requests the half-open circuit
+// breaker lets through at once to probe a recovering API; the others fail
+// fast until a probe closes or reopens the circuit
+// Default: 1
+func WithCircuitBreakerProbes(n int) Option {
+	return func(opts *clientOptions) {
+		opts.circuitBreakerProbes = n
+	}
+}
+
+// WithCircuitBreakerHook sets a function called whenever the circuit breaker
+// opens, closes or becomes half-open, with the reason of the change, e.g. to
+// raise an alert or flip a feature flag
+// It is called synchronously by the request that caused the change, so it
+// should not block.
+func WithCircuitBreakerHook(fn CircuitStateChangeFunc) Option {
+	return func(opts *clientOptions) {
+		opts.circuitStateChangeFunc = fn
+	}
+}
+
 // WithUserAgent sets a custom User-Agent header
-// Default: "go-bb-pix/1.0.0"
+// Default: "go-bb-pix/1.0.0". An empty value sends the Go default.
 func WithUserAgent(userAgent string) Option {
 	return func(opts *clientOptions) {
 		opts.userAgent = userAgent
 	}
 }
+
+// WithHeader adds a static header sent with every API request, e.g. a tenant
+// identifier required by a gateway; it may be given several times
+// A User-Agent set this way takes precedence over WithUserAgent.
+func WithHeader(name, value string) Option {
+	return func(opts *clientOptions) {
+		if opts.header == nil {
+			opts.header = make(http.Header)
+		}
+		opts.header.Add(name, value)
+	}
+}
+
+// WithAuditHook sets a function called after every mutating request (POST,
+// PUT, PATCH and DELETE) with the exact marshaled request body, the raw
+// response body and the decoded response, so audit systems can archive the
+// wire payloads without re-marshaling
+func WithAuditHook(fn AuditFunc) Option {
+	return func(opts *clientOptions) {
+		opts.auditFunc = fn
+	}
+}
+
+// WithHooks sets functions called around every API call of the PIX and PIX
+// Automático clients, with its method, path, status, duration and error,
+// e.g. for custom metrics, auditing or feature flags; an error returned by
+// OnRequest aborts the call
+// The path carries the resource identifiers; Endpoint replaces them.
+func WithHooks(h Hooks) Option {
+	return func(opts *clientOptions) {
+		opts.hooks = h
+	}
+}
+
+// WithRetryHook sets a function called before every retry with the attempt
+// number, the backoff, the status or error that triggered it and the
+// endpoint, e.g. to count how often BB throttles the client
+// Retries are also logged at Info level.
+func WithRetryHook(fn RetryFunc) Option {
+	return func(opts *clientOptions) {
+		opts.retryFunc = fn
+	}
+}
+
+// WithLogLevels sets the levels requests are logged at
+// Default: Info for successful requests, Warn for 4xx responses and canceled
+// requests, Error for 5xx responses and failed requests. Fields left zero
+// log at Info.
+func WithLogLevels(levels LogLevels) Option {
+	return func(opts *clientOptions) {
+		opts.logLevels = levels
+	}
+}
+
+// WithLogSampling logs only one in every n successful requests of each
+// endpoint, e.g. for high-volume polling; failed requests and error
+// responses are always logged
+// Sampled records carry n as sample_rate. Default: every request is logged.
+func WithLogSampling(n int) Option {
+	return func(opts *clientOptions) {
+		opts.logSampling = n
+	}
+}
+
+// WithSlowRequestThreshold logs a warning ("Slow HTTP request") with the
+// endpoint, status, duration and retries of every request taking longer
+// than d, retries included, to surface BB latency degradations before the
+// timeouts fire
+// Default: disabled.
+func WithSlowRequestThreshold(d time.Duration) Option {
+	return func(opts *clientOptions) {
+		opts.slowThreshold = d
+	}
+}
+
+// WithDebugBodies logs the headers and bodies of every request attempt and
+// response at debug level, up to limit bytes of each body (0 means 16 KiB),
+// to troubleshoot schema mismatches
+// The Authorization and app key headers, client_secret, tokens, CPFs, CNPJs
+// and pixCopiaECola values are masked. The logger must enable the debug
+// level; bodies are not read otherwise.
+func WithDebugBodies(limit int) Option {
+	return func(opts *clientOptions) {
+		opts.debugBodies = true
+		opts.debugBodyLimit = limit
+	}
+}
+
+// WithRedactor applies the redaction rules of r to the client logs and to
+// the messages of the errors returned by the PIX and PIX Automático clients
+func WithRedactor(r *redact.Redactor) Option {
+	return func(opts *clientOptions) {
+		opts.redactor = r
+	}
+}
+
+// WithTokenRejectedHook sets a function called for every 401 response to a
+// still valid token with the drift between the token declared expiry and the
+// rejection, e.g. to export it as a metric
+func WithTokenRejectedHook(fn TokenRejectedFunc) Option {
+	return func(opts *clientOptions) {
+		opts.tokenRejectedFunc = fn
+	}
+}
+
+// WithTokenProvider replaces the built-in OAuth2 client credentials flow with
+// p, e.g. a provider backed by Vault or a central token service
+// The rest of the transport chain is kept: the token is sent with the app
+// key, and p.Invalidate is called when the API rejects it with a 401.
+// Config.ClientID and Config.ClientSecret are not required with this option.
+func WithTokenProvider(p TokenProvider) Option {
+	return func(opts *clientOptions) {
+		opts.tokenProvider = p
+	}
+}
+
+// WithTokenStore shares the OAuth2 tokens through store, so horizontally
+// scaled workers and short-lived jobs with the same credentials reuse a
+// valid token instead of each requesting one (see NewFileTokenStore and
+// contrib/bbredis); store failures are logged and fall back to the OAuth2
+// server
+// It has no effect with WithTokenProvider.
+func WithTokenStore(store TokenStore) Option {
+	return func(opts *clientOptions) {
+		opts.tokenStore = store
+	}
+}
+
+// NewFileTokenStore creates a TokenStore that keeps each token in a file of
+// dir readable only by its owner, for jobs sharing a host
+func NewFileTokenStore(dir string) *FileTokenStore {
+	return auth.NewFileTokenStore(dir)
+}
+
+// WithPaginationStyle sets the names of the paging query parameters sent by
+// the list operations of the PIX and PIX Automático clients
+// Default: PaginationNested (paginacao.paginaAtual, paginacao.itensPorPagina)
+func WithPaginationStyle(style PaginationStyle) Option {
+	return func(opts *clientOptions) {
+		opts.paginationStyle = style
+	}
+}
+
+// WithClientCertificate presents cert in the TLS handshake with the API, as
+// required by the mutual TLS of the BB PIX API (see LoadPKCS12)
+// It requires the base transport of WithTransport or WithHTTPClient, when
+// given, to be an *http.Transport; its TLS settings are kept.
+func WithClientCertificate(cert tls.Certificate) Option {
+	return func(opts *clientOptions) {
+		opts.clientCertificate = &cert
+	}
+}
+
+// WithGetClientCertificate calls fn for the client certificate of each TLS
+// handshake, so the certificate can change while the client runs (see
+// CertificateReloader). It takes precedence over WithClientCertificate and
+// has the same transport requirement.
+func WithGetClientCertificate(fn func(*tls.CertificateRequestInfo) (*tls.Certificate, error)) Option {
+	return func(opts *clientOptions) {
+		opts.getClientCertificate = fn
+	}
+}
+
+// WithProxy sends the API and OAuth2 token requests through the HTTP(S)
+// proxy at proxyURL instead of the proxy set by the environment
+// (HTTPS_PROXY, NO_PROXY), so clients in the same process can use different
+// egress proxies. Credentials in proxyURL are sent to the proxy.
+// It requires the base transport of WithTransport or WithHTTPClient, when
+// given, to be an *http.Transport.
+func WithProxy(proxyURL *url.URL) Option {
+	return func(opts *clientOptions) {
+		opts.proxy = proxyURL
+	}
+}
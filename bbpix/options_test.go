@@ -1,11 +1,16 @@
 package bbpix
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
+	"slices"
 	"testing"
 	"time"
+
+	"github.com/pericles-luz/go-bb-pix/redact"
 )
 
 func TestWithLogger(t *testing.T) {
@@ -76,6 +81,18 @@ func TestWithCircuitBreaker(t *testing.T) {
 	}
 }
 
+func TestWithCircuitBreakerProbes(t *testing.T) {
+	opts := defaultClientOptions()
+	if opts.circuitBreakerProbes != 1 {
+		t.Errorf("default circuitBreakerProbes = %d, want 1", opts.circuitBreakerProbes)
+	}
+
+	WithCircuitBreakerProbes(3)(opts)
+	if opts.circuitBreakerProbes != 3 {
+		t.Errorf("circuitBreakerProbes = %d, want 3", opts.circuitBreakerProbes)
+	}
+}
+
 func TestWithUserAgent(t *testing.T) {
 	userAgent := "custom-agent/1.0"
 
@@ -88,6 +105,62 @@ func TestWithUserAgent(t *testing.T) {
 	}
 }
 
+func TestWithAuditHook(t *testing.T) {
+	called := false
+	opts := &clientOptions{}
+	opt := WithAuditHook(func(ctx context.Context, rec AuditRecord) {
+		called = true
+	})
+	opt(opts)
+
+	if opts.auditFunc == nil {
+		t.Fatal("auditFunc not set")
+	}
+	opts.auditFunc(context.Background(), AuditRecord{})
+	if !called {
+		t.Error("auditFunc did not call the hook")
+	}
+}
+
+func TestWithRedactor(t *testing.T) {
+	r := redact.New(redact.CPF())
+
+	opts := &clientOptions{}
+	opt := WithRedactor(r)
+	opt(opts)
+
+	if opts.redactor != r {
+		t.Error("WithRedactor did not set the redactor")
+	}
+}
+
+func TestWithPaginationStyle(t *testing.T) {
+	opts := defaultClientOptions()
+	if opts.paginationStyle != PaginationNested {
+		t.Errorf("default paginationStyle = %v, want PaginationNested", opts.paginationStyle)
+	}
+
+	WithPaginationStyle(PaginationFlat)(opts)
+
+	if opts.paginationStyle != PaginationFlat {
+		t.Errorf("paginationStyle = %v, want PaginationFlat", opts.paginationStyle)
+	}
+}
+
+func TestWithOperationTimeouts(t *testing.T) {
+	opts := defaultClientOptions()
+	if opts.operationTimeouts != (OperationTimeouts{}) {
+		t.Errorf("default operationTimeouts = %+v, want none", opts.operationTimeouts)
+	}
+
+	want := OperationTimeouts{Read: 2 * time.Second, Write: 10 * time.Second, List: time.Minute}
+	WithOperationTimeouts(want)(opts)
+
+	if opts.operationTimeouts != want {
+		t.Errorf("operationTimeouts = %+v, want %+v", opts.operationTimeouts, want)
+	}
+}
+
 func TestMultipleOptions(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	timeout := 30 * time.Second
@@ -159,3 +232,183 @@ func TestOptionsOverrideDefaults(t *testing.T) {
 		t.Errorf("maxRetries = %d, want %d (should override default)", opts.maxRetries, customRetries)
 	}
 }
+
+func TestApplyEnvOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		wantErr     bool
+		wantRetries int
+		wantBackoff time.Duration
+		wantTimeout time.Duration
+		wantCodes   []int
+	}{
+		{
+			name:        "unset keeps defaults",
+			wantRetries: 3,
+			wantBackoff: 100 * time.Millisecond,
+			wantTimeout: 30 * time.Second,
+		},
+		{
+			name:        "all set",
+			env:         map[string]string{EnvRetryCount: "0", EnvRetryDelayMS: "250", EnvTimeoutSeconds: "5", EnvRetryStatusCodes: "429, 500,503"},
+			wantRetries: 0,
+			wantBackoff: 250 * time.Millisecond,
+			wantTimeout: 5 * time.Second,
+			wantCodes:   []int{429, 500, 503},
+		},
+		{name: "invalid retry count", env: map[string]string{EnvRetryCount: "three"}, wantErr: true},
+		{name: "negative retry delay", env: map[string]string{EnvRetryDelayMS: "-1"}, wantErr: true},
+		{name: "zero timeout", env: map[string]string{EnvTimeoutSeconds: "0"}, wantErr: true},
+		{name: "invalid status code", env: map[string]string{EnvRetryStatusCodes: "500,abc"}, wantErr: true},
+		{name: "status code out of range", env: map[string]string{EnvRetryStatusCodes: "5000"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{EnvRetryCount, EnvRetryDelayMS, EnvTimeoutSeconds, EnvRetryStatusCodes} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			opts := defaultClientOptions()
+			err := applyEnvOptions(opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyEnvOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if opts.maxRetries != tt.wantRetries {
+				t.Errorf("maxRetries = %d, want %d", opts.maxRetries, tt.wantRetries)
+			}
+			if opts.initialBackoff != tt.wantBackoff {
+				t.Errorf("initialBackoff = %v, want %v", opts.initialBackoff, tt.wantBackoff)
+			}
+			if opts.timeout != tt.wantTimeout {
+				t.Errorf("timeout = %v, want %v", opts.timeout, tt.wantTimeout)
+			}
+			if !slices.Equal(opts.retryPolicy.RetryableStatusCodes, tt.wantCodes) {
+				t.Errorf("RetryableStatusCodes = %v, want %v", opts.retryPolicy.RetryableStatusCodes, tt.wantCodes)
+			}
+		})
+	}
+}
+
+func TestNew_EnvOptions(t *testing.T) {
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		ClientID:        "test-client-id",
+		ClientSecret:    "test-client-secret",
+		DeveloperAppKey: "test-app-key",
+	}
+
+	t.Setenv(EnvTimeoutSeconds, "7")
+
+	client, err := New(config)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if client.httpClient.Timeout != 7*time.Second {
+		t.Errorf("Timeout = %v, want 7s from the environment", client.httpClient.Timeout)
+	}
+
+	// Explicit options win over the environment
+	client, err = New(config, WithTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if client.httpClient.Timeout != time.Second {
+		t.Errorf("Timeout = %v, want 1s from WithTimeout", client.httpClient.Timeout)
+	}
+
+	t.Setenv(EnvTimeoutSeconds, "soon")
+	if _, err := New(config); err == nil {
+		t.Error("New() with an invalid BB_TIMEOUT_SECONDS should fail")
+	}
+}
+
+func TestWithTokenProvider(t *testing.T) {
+	provider := &staticTokenProvider{token: "token"}
+
+	opts := &clientOptions{}
+	WithTokenProvider(provider)(opts)
+
+	if opts.tokenProvider != provider {
+		t.Error("WithTokenProvider did not set the token provider")
+	}
+}
+
+func TestWithMiddleware(t *testing.T) {
+	identity := func(next http.RoundTripper) http.RoundTripper { return next }
+
+	opts := &clientOptions{}
+	WithMiddleware(identity)(opts)
+	WithMiddleware(identity, identity)(opts)
+
+	if len(opts.middlewares) != 3 {
+		t.Errorf("middlewares = %d, want 3", len(opts.middlewares))
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	proxyURL, _ := url.Parse("http://proxy.example.com:3128")
+
+	opts := &clientOptions{}
+	WithProxy(proxyURL)(opts)
+
+	if opts.proxy != proxyURL {
+		t.Error("WithProxy did not set the proxy")
+	}
+}
+
+func TestWithRateLimit(t *testing.T) {
+	opts := defaultClientOptions()
+	if opts.rateLimit != 0 {
+		t.Errorf("default rateLimit = %v, want disabled", opts.rateLimit)
+	}
+
+	WithRateLimit(20, 5)(opts)
+
+	if opts.rateLimit != 20 || opts.rateBurst != 5 {
+		t.Errorf("rate limit = %v/%d, want 20/5", opts.rateLimit, opts.rateBurst)
+	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	opts := defaultClientOptions()
+	WithRetryPolicy(RetryPolicy{RetryableStatusCodes: []int{500}, MaxBackoff: time.Second})(opts)
+
+	if len(opts.retryPolicy.RetryableStatusCodes) != 1 || opts.retryPolicy.MaxBackoff != time.Second {
+		t.Errorf("retryPolicy = %+v, want the given policy", opts.retryPolicy)
+	}
+}
+
+func TestWithRetryableStatusCodes(t *testing.T) {
+	opts := defaultClientOptions()
+	WithRetryPolicy(RetryPolicy{MaxBackoff: time.Second})(opts)
+	WithRetryableStatusCodes(500, 503)(opts)
+
+	if !slices.Equal(opts.retryPolicy.RetryableStatusCodes, []int{500, 503}) {
+		t.Errorf("RetryableStatusCodes = %v, want [500 503]", opts.retryPolicy.RetryableStatusCodes)
+	}
+	if opts.retryPolicy.MaxBackoff != time.Second {
+		t.Error("WithRetryableStatusCodes should keep the rest of the policy")
+	}
+
+	// Without codes only network errors are retried
+	WithRetryableStatusCodes()(opts)
+	if codes := opts.retryPolicy.RetryableStatusCodes; codes == nil || len(codes) != 0 {
+		t.Errorf("RetryableStatusCodes = %#v, want empty and non-nil", codes)
+	}
+}
+
+func TestIdempotentContext(t *testing.T) {
+	ctx := context.Background()
+	if IdempotentContext(ctx, "") != ctx {
+		t.Error("IdempotentContext with an empty key should return ctx")
+	}
+	if IdempotentContext(ctx, "key") == ctx {
+		t.Error("IdempotentContext should mark the context")
+	}
+}
@@ -0,0 +1,110 @@
+package bbpix
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+
+	httpclient "github.com/pericles-luz/go-bb-pix/internal/http"
+	"github.com/pericles-luz/go-bb-pix/internal/transport"
+)
+
+// customizeTransport returns a copy of base with the client certificate and
+// proxy set by opts
+func customizeTransport(base http.RoundTripper, opts *clientOptions) (http.RoundTripper, error) {
+	t, ok := base.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("client certificate and proxy options require an *http.Transport, got %T", base)
+	}
+	t = t.Clone()
+
+	if opts.proxy != nil {
+		t.Proxy = http.ProxyURL(opts.proxy)
+	}
+
+	if opts.clientCertificate != nil || opts.getClientCertificate != nil {
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		if opts.clientCertificate != nil {
+			t.TLSClientConfig.Certificates = append(t.TLSClientConfig.Certificates, *opts.clientCertificate)
+		}
+		if opts.getClientCertificate != nil {
+			t.TLSClientConfig.GetClientCertificate = opts.getClientCertificate
+		}
+	}
+
+	return t, nil
+}
+
+// needsCustomTransport reports whether opts change the base transport
+func (opts *clientOptions) needsCustomTransport() bool {
+	return opts.proxy != nil || opts.clientCertificate != nil || opts.getClientCertificate != nil
+}
+
+// staticHeader returns the headers added to every request: the User-Agent
+// and those set with WithHeader
+func (opts *clientOptions) staticHeader() (http.Header, error) {
+	header := opts.header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	if opts.userAgent != "" && header.Get("User-Agent") == "" {
+		header.Set("User-Agent", opts.userAgent)
+	}
+
+	for name, values := range header {
+		if !isToken(name) {
+			return nil, fmt.Errorf("invalid header name %q", name)
+		}
+		for _, value := range values {
+			if err := httpclient.CheckHeaderValue(name, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return header, nil
+}
+
+// metricsRecorder returns the recorder of the request metrics: the client
+// statistics and the recorder of WithMetrics
+func (opts *clientOptions) metricsRecorder(stats *transport.StatsRecorder) MetricsRecorder {
+	if opts.metrics == nil {
+		return stats
+	}
+	return multiRecorder{stats, opts.metrics}
+}
+
+// multiRecorder reports the metrics to several recorders
+type multiRecorder []MetricsRecorder
+
+// ObserveRequest implements MetricsRecorder
+func (m multiRecorder) ObserveRequest(ctx context.Context, rm RequestMetrics) {
+	for _, r := range m {
+		r.ObserveRequest(ctx, rm)
+	}
+}
+
+// ObserveCircuitState implements MetricsRecorder
+func (m multiRecorder) ObserveCircuitState(from, to CircuitState) {
+	for _, r := range m {
+		r.ObserveCircuitState(from, to)
+	}
+}
+
+// circuitStateChange returns the function told of the circuit breaker state
+// changes: the hook of WithCircuitBreakerHook and the metrics recorder
+func (opts *clientOptions) circuitStateChange(metrics MetricsRecorder) CircuitStateChangeFunc {
+	hook := opts.circuitStateChangeFunc
+	if metrics == nil {
+		return hook
+	}
+
+	return func(from, to CircuitState, reason string) {
+		metrics.ObserveCircuitState(from, to)
+		if hook != nil {
+			hook(from, to, reason)
+		}
+	}
+}
@@ -0,0 +1,529 @@
+package bbpix
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/redact"
+)
+
+func TestCustomizeTransport_Proxy(t *testing.T) {
+	proxyURL, _ := url.Parse("http://proxy.example.com:3128")
+
+	transport, err := customizeTransport(http.DefaultTransport, &clientOptions{proxy: proxyURL})
+	if err != nil {
+		t.Fatalf("customizeTransport() error = %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.bb.com.br/pix-bb/v1/cob", nil)
+	got, err := transport.(*http.Transport).Proxy(req)
+	if err != nil || got.String() != proxyURL.String() {
+		t.Errorf("Proxy() = %v, %v, want %s", got, err, proxyURL)
+	}
+
+	if _, err := customizeTransport(roundTripperFunc(http.DefaultTransport.RoundTrip), &clientOptions{proxy: proxyURL}); err == nil {
+		t.Error("expected error for a transport that is not an *http.Transport")
+	}
+}
+
+func TestNew_WithProxy(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		hosts []string
+	)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hosts = append(hosts, r.Host)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/oauth/token" {
+			w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+			return
+		}
+		w.Write([]byte(`{"txid":"abc"}`))
+	}))
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		ClientID:        "test-client-id",
+		ClientSecret:    "test-client-secret",
+		DeveloperAppKey: "test-app-key",
+		OAuthURL:        "http://oauth.bb.invalid/oauth/token",
+		APIURL:          "http://api.bb.invalid/pix-bb/v1",
+	}
+
+	client, err := New(config, WithProxy(proxyURL), WithRetry(0, time.Millisecond))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := client.PIX().GetQRCode(context.Background(), "abc"); err != nil {
+		t.Fatalf("GetQRCode() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(hosts) != 2 || hosts[0] != "oauth.bb.invalid" || hosts[1] != "api.bb.invalid" {
+		t.Errorf("proxied hosts = %v, want the OAuth and API hosts", hosts)
+	}
+}
+
+func TestNew_TokenRequestsUseBaseTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/oauth/token" {
+			w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+			return
+		}
+		w.Write([]byte(`{"txid":"abc"}`))
+	}))
+	defer server.Close()
+
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		paths = append(paths, req.URL.Path)
+		mu.Unlock()
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		ClientID:        "test-client-id",
+		ClientSecret:    "test-client-secret",
+		DeveloperAppKey: "test-app-key",
+		OAuthURL:        server.URL + "/oauth/token",
+		APIURL:          server.URL + "/pix-bb/v1",
+	}
+
+	client, err := New(config, WithTransport(base), WithRetry(0, time.Millisecond))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := client.PIX().GetQRCode(context.Background(), "abc"); err != nil {
+		t.Fatalf("GetQRCode() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(paths) != 2 || paths[0] != "/oauth/token" || paths[1] != "/pix-bb/v1/cob/abc" {
+		t.Errorf("base transport paths = %v, want the token and API requests", paths)
+	}
+}
+
+func TestNew_StaticHeaders(t *testing.T) {
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		DeveloperAppKey: "test-app-key",
+	}
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantAgent  string
+		wantTenant string
+		wantErr    bool
+	}{
+		{name: "default user agent", wantAgent: "go-bb-pix/1.0.0"},
+		{name: "custom user agent", opts: []Option{WithUserAgent("minha-app/2.0")}, wantAgent: "minha-app/2.0"},
+		{name: "empty user agent", opts: []Option{WithUserAgent("")}, wantAgent: "Go-http-client/1.1"},
+		{
+			name:       "static headers",
+			opts:       []Option{WithHeader("X-Tenant", "acme"), WithHeader("User-Agent", "gateway/1.0")},
+			wantAgent:  "gateway/1.0",
+			wantTenant: "acme",
+		},
+		{name: "invalid header name", opts: []Option{WithHeader("X Tenant", "acme")}, wantErr: true},
+		{name: "invalid header value", opts: []Option{WithHeader("X-Tenant", "acme\r\nX-Admin: 1")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got http.Header
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"txid":"abc"}`))
+			}))
+			defer server.Close()
+
+			config := config
+			config.APIURL = server.URL
+			opts := append([]Option{WithTokenProvider(&staticTokenProvider{token: "token"})}, tt.opts...)
+			client, err := New(config, opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if _, err := client.PIX().GetQRCode(context.Background(), "abc"); err != nil {
+				t.Fatalf("GetQRCode() error = %v", err)
+			}
+			if agent := got.Get("User-Agent"); agent != tt.wantAgent {
+				t.Errorf("User-Agent = %q, want %q", agent, tt.wantAgent)
+			}
+			if tenant := got.Get("X-Tenant"); tenant != tt.wantTenant {
+				t.Errorf("X-Tenant = %q, want %q", tenant, tt.wantTenant)
+			}
+		})
+	}
+}
+
+func TestNew_WithRequestID(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("X-Correlation-Id"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"txid":"abc"}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		DeveloperAppKey: "test-app-key",
+		APIURL:          server.URL,
+	}
+	client, err := New(config,
+		WithTokenProvider(&staticTokenProvider{token: "token"}),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		WithRequestID("X-Correlation-Id"),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := RequestIDContext(context.Background(), "pedido-42")
+	if _, err := client.PIX().GetQRCode(ctx, "abc"); err != nil {
+		t.Fatalf("GetQRCode() error = %v", err)
+	}
+	if _, err := client.PIX().GetQRCode(context.Background(), "abc"); err != nil {
+		t.Fatalf("GetQRCode() error = %v", err)
+	}
+
+	if len(got) != 2 || got[0] != "pedido-42" || got[1] == "" {
+		t.Errorf("X-Correlation-Id = %q, want the context ID then a generated one", got)
+	}
+	if !strings.Contains(logs.String(), "request_id=pedido-42") {
+		t.Errorf("logs = %q, want the request ID", logs.String())
+	}
+
+	if _, err := New(config, WithTokenProvider(&staticTokenProvider{token: "token"}), WithRequestID("X Id")); err == nil {
+		t.Error("New() with an invalid request ID header should fail")
+	}
+}
+
+// recordingMetrics records the metrics it receives
+type recordingMetrics struct {
+	requests []RequestMetrics
+	states   []CircuitState
+}
+
+func (r *recordingMetrics) ObserveRequest(ctx context.Context, m RequestMetrics) {
+	r.requests = append(r.requests, m)
+}
+
+func (r *recordingMetrics) ObserveCircuitState(from, to CircuitState) {
+	r.states = append(r.states, to)
+}
+
+func TestNew_WithMetrics(t *testing.T) {
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Header:     make(http.Header),
+			Body:       http.NoBody,
+			Request:    req,
+		}, nil
+	})
+
+	recorder := &recordingMetrics{}
+	var hooked []CircuitState
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		DeveloperAppKey: "test-app-key",
+	}
+	client, err := New(config,
+		WithTokenProvider(&staticTokenProvider{token: "token"}),
+		WithTransport(base),
+		WithCircuitBreaker(1, time.Minute),
+		WithCircuitBreakerHook(func(from, to CircuitState, reason string) {
+			hooked = append(hooked, to)
+		}),
+		WithMetrics(recorder),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	client.PIX().GetQRCode(context.Background(), "7978c0c97ea847e78e8849634473c1f1")
+
+	if len(recorder.requests) != 1 {
+		t.Fatalf("observed %d requests, want 1", len(recorder.requests))
+	}
+	if got := recorder.requests[0]; got.Endpoint != "/pix-bb/v1/cob/{id}" || got.StatusCode != http.StatusInternalServerError {
+		t.Errorf("request metrics = %+v, want /pix-bb/v1/cob/{id} 500", got)
+	}
+	if len(recorder.states) != 1 || recorder.states[0] != CircuitOpen {
+		t.Errorf("observed circuit states = %v, want [open]", recorder.states)
+	}
+	if len(hooked) != 1 || hooked[0] != CircuitOpen {
+		t.Errorf("hooked circuit states = %v, want [open], the hook still called", hooked)
+	}
+}
+
+// headerTracer marks the requests it traces with a header
+type headerTracer struct {
+	ended []RequestMetrics
+}
+
+func (h *headerTracer) StartRequest(req *http.Request) (*http.Request, func(RequestMetrics)) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	return req, func(m RequestMetrics) {
+		h.ended = append(h.ended, m)
+	}
+}
+
+func TestNew_WithTracer(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("Traceparent")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"txid":"abc"}`))
+	}))
+	defer server.Close()
+
+	tracer := &headerTracer{}
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		DeveloperAppKey: "test-app-key",
+		APIURL:          server.URL + "/pix-bb/v1",
+	}
+	client, err := New(config,
+		WithTokenProvider(&staticTokenProvider{token: "token"}),
+		WithTracer(tracer),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := client.PIX().GetQRCode(context.Background(), "7978c0c97ea847e78e8849634473c1f1"); err != nil {
+		t.Fatalf("GetQRCode() error = %v", err)
+	}
+
+	if traceparent == "" {
+		t.Error("Traceparent header not propagated")
+	}
+	if len(tracer.ended) != 1 || tracer.ended[0].Endpoint != "/pix-bb/v1/cob/{id}" || tracer.ended[0].StatusCode != http.StatusOK {
+		t.Errorf("ended spans = %+v, want one for /pix-bb/v1/cob/{id} 200", tracer.ended)
+	}
+}
+
+func TestNew_DebugBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"txid":"abc","pixCopiaECola":"00020101021226830014br.gov.bcb.pix"}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		DeveloperAppKey: "test-app-key",
+		APIURL:          server.URL + "/pix-bb/v1",
+	}
+	client, err := New(config,
+		WithTokenProvider(&staticTokenProvider{token: "secret-token"}),
+		WithLogger(logger),
+		WithDebugBodies(0),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	cob, err := client.PIX().GetQRCode(context.Background(), "7978c0c97ea847e78e8849634473c1f1")
+	if err != nil {
+		t.Fatalf("GetQRCode() error = %v", err)
+	}
+	if cob.QRCode != "00020101021226830014br.gov.bcb.pix" {
+		t.Errorf("QRCode = %q, want the response value", cob.QRCode)
+	}
+
+	logs := buf.String()
+	if !strings.Contains(logs, `msg="HTTP response body"`) || !strings.Contains(logs, `txid`) {
+		t.Errorf("response body not logged:\n%s", logs)
+	}
+	for _, secret := range []string{"secret-token", "test-app-key", "br.gov.bcb.pix"} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("logs contain %q:\n%s", secret, logs)
+		}
+	}
+}
+
+func TestNew_LogLevels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"type":"https://pix.bcb.gov.br/api/v2/error/CobNaoEncontrado","title":"Cobrança não encontrada","status":404}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{name: "default", want: "level=WARN"},
+		{name: "custom", opts: []Option{WithLogLevels(LogLevels{ClientError: slog.LevelError})}, want: "level=ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			config := Config{
+				Environment:     EnvironmentSandbox,
+				DeveloperAppKey: "test-app-key",
+				APIURL:          server.URL + "/pix-bb/v1",
+			}
+			opts := append([]Option{
+				WithTokenProvider(&staticTokenProvider{token: "token"}),
+				WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+				WithRetryDisabled(),
+			}, tt.opts...)
+			client, err := New(config, opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			if _, err := client.PIX().GetQRCode(context.Background(), "7978c0c97ea847e78e8849634473c1f1"); err == nil {
+				t.Fatal("GetQRCode() error = nil, want not found")
+			}
+			if !strings.Contains(buf.String(), tt.want+` msg="HTTP request completed"`) {
+				t.Errorf("logs = %s, want %s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestNew_LoggerContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"txid":"abc"}`))
+	}))
+	defer server.Close()
+
+	var clientBuf, tenantBuf bytes.Buffer
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		DeveloperAppKey: "test-app-key",
+		APIURL:          server.URL + "/pix-bb/v1",
+	}
+	client, err := New(config,
+		WithTokenProvider(&staticTokenProvider{token: "token"}),
+		WithLogger(slog.New(slog.NewTextHandler(&clientBuf, nil))),
+		WithRedactor(redact.New(redact.CPF())),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tenantLogger := slog.New(slog.NewTextHandler(&tenantBuf, nil)).With("tenant", "acme")
+	ctx := LoggerContext(context.Background(), tenantLogger)
+	ctx = LogAttrsContext(ctx, slog.String("order_id", "42"))
+	if _, err := client.PIX().GetQRCode(ctx, "12345678909"); err != nil {
+		t.Fatalf("GetQRCode() error = %v", err)
+	}
+
+	if clientBuf.Len() > 0 {
+		t.Errorf("client log = %q, want none", clientBuf.String())
+	}
+	logs := tenantBuf.String()
+	if !strings.Contains(logs, "tenant=acme") || !strings.Contains(logs, "order_id=42") {
+		t.Errorf("tenant log = %q, want the tenant and order attributes", logs)
+	}
+	if strings.Contains(logs, "12345678909") {
+		t.Errorf("tenant log = %q, want the CPF redacted", logs)
+	}
+}
+
+func TestNew_WithLogSampling(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"txid":"abc"}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		DeveloperAppKey: "test-app-key",
+		APIURL:          server.URL + "/pix-bb/v1",
+	}
+	client, err := New(config,
+		WithTokenProvider(&staticTokenProvider{token: "token"}),
+		WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+		WithLogSampling(10),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for range 20 {
+		if _, err := client.PIX().GetQRCode(context.Background(), "7978c0c97ea847e78e8849634473c1f1"); err != nil {
+			t.Fatalf("GetQRCode() error = %v", err)
+		}
+	}
+
+	if got := strings.Count(buf.String(), "HTTP request completed"); got != 2 {
+		t.Errorf("logged requests = %d, want 2:\n%s", got, buf.String())
+	}
+}
+
+func TestNew_WithSlowRequestThreshold(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(30 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"txid":"abc"}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	config := Config{
+		Environment:     EnvironmentSandbox,
+		DeveloperAppKey: "test-app-key",
+		APIURL:          server.URL + "/pix-bb/v1",
+	}
+	client, err := New(config,
+		WithTokenProvider(&staticTokenProvider{token: "token"}),
+		WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+		WithSlowRequestThreshold(10*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := client.PIX().GetQRCode(context.Background(), "7978c0c97ea847e78e8849634473c1f1"); err != nil {
+		t.Fatalf("GetQRCode() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), `level=WARN msg="Slow HTTP request"`) || !strings.Contains(buf.String(), "endpoint=/pix-bb/v1/cob/{id}") {
+		t.Errorf("logs = %s, want a slow request warning", buf.String())
+	}
+}
@@ -0,0 +1,286 @@
+package bbpix
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// yamlLine is a significant line of a YAML document
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+// parseYAML parses the subset of YAML used by configuration files: block
+// mappings, block and flow sequences of scalars, quoted and plain scalars
+// and comments
+// Scalars are returned as strings, sequences as []any, mappings as
+// map[string]any and empty values as nil. Anchors, multi-line scalars, flow
+// mappings and multiple documents are not supported.
+func parseYAML(data []byte) (map[string]any, error) {
+	var lines []yamlLine
+	for i, text := range strings.Split(string(data), "\n") {
+		text = strings.TrimRight(text, " \t\r")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || (len(lines) == 0 && trimmed == "---") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed in indentation", i+1)
+		}
+		lines = append(lines, yamlLine{num: i + 1, indent: len(text) - len(trimmed), text: trimmed})
+	}
+	if len(lines) == 0 {
+		return map[string]any{}, nil
+	}
+
+	p := &yamlParser{lines: lines}
+	if isSequenceItem(lines[0].text) {
+		return nil, fmt.Errorf("line %d: document must be a mapping", lines[0].num)
+	}
+	doc, err := p.mapping(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", lines[p.pos].num)
+	}
+	return doc, nil
+}
+
+// yamlParser walks the lines of a document
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// block parses the mapping or sequence starting at the current line
+func (p *yamlParser) block(indent int) (any, error) {
+	if isSequenceItem(p.lines[p.pos].text) {
+		return p.sequence(indent)
+	}
+	return p.mapping(indent)
+}
+
+// mapping parses the keys at indent
+func (p *yamlParser) mapping(indent int) (map[string]any, error) {
+	m := make(map[string]any)
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", line.num)
+		}
+		if isSequenceItem(line.text) {
+			return nil, fmt.Errorf("line %d: unexpected sequence item", line.num)
+		}
+
+		key, rest, ok := splitKey(line.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", line.num)
+		}
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", line.num, key)
+		}
+		p.pos++
+
+		if rest != "" && !strings.HasPrefix(rest, "#") {
+			value, err := yamlScalar(rest)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line.num, err)
+			}
+			m[key] = value
+			continue
+		}
+
+		// A nested block is indented, except for sequences, which may share
+		// the indentation of their key
+		m[key] = nil
+		if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			if next.indent > indent || (next.indent == indent && isSequenceItem(next.text)) {
+				value, err := p.block(next.indent)
+				if err != nil {
+					return nil, err
+				}
+				m[key] = value
+			}
+		}
+	}
+	return m, nil
+}
+
+// sequence parses the items at indent
+func (p *yamlParser) sequence(indent int) ([]any, error) {
+	items := []any{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent != indent || !isSequenceItem(line.text) {
+			if line.indent > indent {
+				return nil, fmt.Errorf("line %d: unexpected indentation", line.num)
+			}
+			break
+		}
+		p.pos++
+
+		rest := strings.TrimLeft(strings.TrimPrefix(line.text, "-"), " ")
+		if rest == "" || strings.HasPrefix(rest, "#") {
+			return nil, fmt.Errorf("line %d: nested sequence items are not supported", line.num)
+		}
+		if _, _, ok := splitKey(rest); ok {
+			return nil, fmt.Errorf("line %d: mappings in sequences are not supported", line.num)
+		}
+
+		value, err := yamlScalar(rest)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line.num, err)
+		}
+		items = append(items, value)
+	}
+	return items, nil
+}
+
+// isSequenceItem reports whether a line is a block sequence item
+func isSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitKey splits a "key: value" line; plain and quoted keys are supported
+func splitKey(text string) (key, rest string, ok bool) {
+	if text[0] == '"' || text[0] == '\'' {
+		quoted, after, err := cutQuoted(text)
+		if err != nil || (after != "" && after[0] != ':') {
+			return "", "", false
+		}
+		if after == "" || (len(after) > 1 && after[1] != ' ') {
+			return "", "", false
+		}
+		return quoted, strings.TrimSpace(after[1:]), true
+	}
+
+	i := strings.Index(text, ": ")
+	if i < 0 {
+		if !strings.HasSuffix(text, ":") {
+			return "", "", false
+		}
+		i = len(text) - 1
+	}
+	key = strings.TrimSpace(text[:i])
+	if key == "" || strings.ContainsAny(key, "#[]{}") {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(text[i+1:]), true
+}
+
+// yamlScalar parses an inline value: a quoted or plain scalar or a flow
+// sequence of scalars, optionally followed by a comment
+func yamlScalar(text string) (any, error) {
+	switch text[0] {
+	case '"', '\'':
+		value, rest, err := cutQuoted(text)
+		if err != nil {
+			return nil, err
+		}
+		if err := trailingComment(rest); err != nil {
+			return nil, err
+		}
+		return value, nil
+
+	case '[':
+		return flowSequence(text)
+
+	case '{':
+		return nil, errors.New("flow mappings are not supported")
+
+	case '|', '>':
+		return nil, errors.New("multi-line scalars are not supported")
+
+	case '&', '*', '!':
+		return nil, errors.New("anchors, aliases and tags are not supported")
+	}
+
+	if i := strings.Index(text, " #"); i >= 0 {
+		text = text[:i]
+	}
+	return strings.TrimSpace(text), nil
+}
+
+// flowSequence parses a flow sequence of scalars such as [a, "b"]
+func flowSequence(text string) ([]any, error) {
+	items := []any{}
+	rest := strings.TrimSpace(text[1:])
+	for {
+		if rest == "" {
+			return nil, errors.New("unterminated flow sequence")
+		}
+		if rest[0] == ']' {
+			if err := trailingComment(rest[1:]); err != nil {
+				return nil, err
+			}
+			return items, nil
+		}
+
+		var item string
+		if rest[0] == '"' || rest[0] == '\'' {
+			var err error
+			if item, rest, err = cutQuoted(rest); err != nil {
+				return nil, err
+			}
+		} else {
+			end := strings.IndexAny(rest, ",]")
+			if end < 0 {
+				return nil, errors.New("unterminated flow sequence")
+			}
+			item, rest = strings.TrimSpace(rest[:end]), rest[end:]
+			if item == "" {
+				return nil, errors.New("empty flow sequence item")
+			}
+		}
+		items = append(items, item)
+
+		rest = strings.TrimSpace(rest)
+		if strings.HasPrefix(rest, ",") {
+			rest = strings.TrimSpace(rest[1:])
+		} else if !strings.HasPrefix(rest, "]") {
+			return nil, errors.New("expected , or ] in flow sequence")
+		}
+	}
+}
+
+// cutQuoted parses the quoted scalar at the start of text and returns it
+// with the text after the closing quote
+func cutQuoted(text string) (value, rest string, err error) {
+	quote := text[0]
+	for i := 1; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case quote == '\'' && text[i] == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++
+		case text[i] == quote:
+			if quote == '\'' {
+				return strings.ReplaceAll(text[1:i], "''", "'"), text[i+1:], nil
+			}
+			value, err := strconv.Unquote(text[:i+1])
+			if err != nil {
+				return "", "", fmt.Errorf("invalid quoted string %s", text[:i+1])
+			}
+			return value, text[i+1:], nil
+		}
+	}
+	return "", "", errors.New("unterminated quoted string")
+}
+
+// trailingComment checks that only a comment follows a value
+func trailingComment(rest string) error {
+	rest = strings.TrimSpace(rest)
+	if rest != "" && !strings.HasPrefix(rest, "#") {
+		return fmt.Errorf("unexpected %q after value", rest)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package bbpix
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name:  "scalars and comments",
+			input: "---\n# comment\na: 1\nb: \"x: y\" # note\nc: 'it''s'\nd: url#frag\ne:\n",
+			want:  map[string]any{"a": "1", "b": "x: y", "c": "it's", "d": "url#frag", "e": nil},
+		},
+		{
+			name:  "nested mapping",
+			input: "retry:\n  max_retries: 3\n  backoff: 1s\nnext: x\n",
+			want:  map[string]any{"retry": map[string]any{"max_retries": "3", "backoff": "1s"}, "next": "x"},
+		},
+		{
+			name:  "sequences",
+			input: "a:\n  - x\n  - \"y\"\nb:\n- z\nc: [1, 'two', three]\nd: []\n",
+			want: map[string]any{
+				"a": []any{"x", "y"},
+				"b": []any{"z"},
+				"c": []any{"1", "two", "three"},
+				"d": []any{},
+			},
+		},
+		{
+			name:  "quoted key",
+			input: "\"odd key\": v\n",
+			want:  map[string]any{"odd key": "v"},
+		},
+		{name: "duplicate key", input: "a: 1\na: 2\n", wantErr: true},
+		{name: "bad indentation", input: "a: 1\n  b: 2\n", wantErr: true},
+		{name: "tab indentation", input: "a:\n\tb: 1\n", wantErr: true},
+		{name: "not a mapping", input: "- a\n", wantErr: true},
+		{name: "missing colon", input: "just text\n", wantErr: true},
+		{name: "unterminated quote", input: "a: \"open\n", wantErr: true},
+		{name: "flow mapping", input: "a: {b: 1}\n", wantErr: true},
+		{name: "block scalar", input: "a: |\n  text\n", wantErr: true},
+		{name: "mapping in sequence", input: "a:\n  - b: 1\n", wantErr: true},
+		{name: "unterminated flow sequence", input: "a: [1, 2\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYAML([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseYAML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseYAML() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
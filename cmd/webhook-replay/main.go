@@ -0,0 +1,96 @@
+// Command webhook-replay re-sends webhook events stored by webhook.FileStore
+// to a running receiver, for recovery after a bug in downstream handlers.
+//
+// Usage:
+//
+//	webhook-replay -store events.jsonl -url https://localhost:8443/webhook/pix \
+//	    [-since 2024-01-15T00:00:00Z] [-until 2024-01-16T00:00:00Z] [-e2eid E123...] [-dry-run]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/webhook"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "webhook-replay:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	storePath := flag.String("store", "", "path to the JSON Lines event store (required)")
+	url := flag.String("url", "", "receiver URL the events are POSTed to (required unless -dry-run)")
+	since := flag.String("since", "", "only events received at or after this RFC 3339 time")
+	until := flag.String("until", "", "only events received at or before this RFC 3339 time")
+	e2eid := flag.String("e2eid", "", "only events that notified this endToEndId")
+	timeout := flag.Duration("timeout", 30*time.Second, "timeout of each request")
+	dryRun := flag.Bool("dry-run", false, "list the matching events without sending them")
+	flag.Parse()
+
+	if *storePath == "" {
+		return errors.New("-store is required")
+	}
+	if *url == "" && !*dryRun {
+		return errors.New("-url is required")
+	}
+
+	filter := webhook.EventFilter{EndToEndID: *e2eid}
+	var err error
+	if filter.Since, err = parseTime(*since); err != nil {
+		return fmt.Errorf("invalid -since: %w", err)
+	}
+	if filter.Until, err = parseTime(*until); err != nil {
+		return fmt.Errorf("invalid -until: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	store := webhook.NewFileStore(*storePath)
+
+	if *dryRun {
+		events, err := store.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			fmt.Printf("%s %s %v\n", event.ID, event.ReceivedAt.Format(time.RFC3339), event.EndToEndIDs)
+		}
+		fmt.Printf("%d events match\n", len(events))
+		return nil
+	}
+
+	dispatcher := webhook.HTTPDispatcher(*url, &http.Client{Timeout: *timeout})
+
+	result, err := webhook.Replay(ctx, store, filter, dispatcher)
+	for _, failure := range result.Failed {
+		fmt.Fprintf(os.Stderr, "event %s failed: %v\n", failure.Event.ID, failure.Err)
+	}
+	fmt.Printf("%d events dispatched, %d failed\n", result.Dispatched, len(result.Failed))
+
+	if err != nil {
+		return err
+	}
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d events failed", len(result.Failed))
+	}
+	return nil
+}
+
+// parseTime parses an optional RFC 3339 time
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
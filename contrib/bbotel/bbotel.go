@@ -0,0 +1,131 @@
+// Package bbotel traces the requests of a bbpix.Client with OpenTelemetry
+// It is a separate module so the client itself has no dependency on the
+// OpenTelemetry libraries:
+//
+//	client, err := bbpix.New(config, bbotel.WithTracerProvider(otel.GetTracerProvider()))
+package bbotel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/pericles-luz/go-bb-pix/bbpix"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the spans
+const ScopeName = "github.com/pericles-luz/go-bb-pix/contrib/bbotel"
+
+// Option configures the tracer
+type Option func(*Tracer)
+
+// WithPropagators sets the propagators that inject the trace context in the
+// request headers
+// Default: otel.GetTextMapPropagator()
+func WithPropagators(p propagation.TextMapPropagator) Option {
+	return func(t *Tracer) {
+		t.propagators = p
+	}
+}
+
+// WithAttributes adds attributes to every span, e.g. to tell the clients of
+// several convênios apart
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(t *Tracer) {
+		t.attrs = append(t.attrs, attrs...)
+	}
+}
+
+// Tracer traces the API requests as client spans
+//
+// Each request, retries included, is a span named after its method and
+// endpoint, e.g. "GET /pix-bb/v1/cob/{id}", with the request method, URL
+// template, server address, response status code and number of retries.
+// Requests failing with an error or a 5xx status mark the span as an error.
+// The span context is propagated to BB in the request headers.
+type Tracer struct {
+	tracer      trace.Tracer
+	propagators propagation.TextMapPropagator
+	attrs       []attribute.KeyValue
+}
+
+// NewTracer creates a Tracer using the tracers of tp
+func NewTracer(tp trace.TracerProvider, opts ...Option) *Tracer {
+	t := &Tracer{
+		tracer:      tp.Tracer(ScopeName),
+		propagators: otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// WithTracerProvider returns a client option tracing the API requests with
+// the tracers of tp
+func WithTracerProvider(tp trace.TracerProvider, opts ...Option) bbpix.Option {
+	return bbpix.WithTracer(NewTracer(tp, opts...))
+}
+
+// StartRequest implements bbpix.RequestTracer
+func (t *Tracer) StartRequest(req *http.Request) (*http.Request, func(bbpix.RequestMetrics)) {
+	endpoint := bbpix.Endpoint(req.URL.Path)
+
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.URLTemplate(endpoint),
+		semconv.ServerAddress(req.URL.Hostname()),
+	}
+	if port, err := strconv.Atoi(req.URL.Port()); err == nil {
+		attrs = append(attrs, semconv.ServerPort(port))
+	}
+	attrs = append(attrs, t.attrs...)
+
+	ctx, span := t.tracer.Start(req.Context(), req.Method+" "+endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	req = req.Clone(ctx)
+	t.propagators.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	return req, func(m bbpix.RequestMetrics) {
+		defer span.End()
+
+		if m.Retries > 0 {
+			span.SetAttributes(semconv.HTTPRequestResendCount(m.Retries))
+		}
+		if m.Err != nil {
+			span.RecordError(m.Err)
+			span.SetStatus(codes.Error, m.Err.Error())
+			span.SetAttributes(semconv.ErrorTypeKey.String(errorType(m.Err)))
+			return
+		}
+
+		span.SetAttributes(semconv.HTTPResponseStatusCode(m.StatusCode))
+		if m.StatusCode >= 500 {
+			span.SetStatus(codes.Error, "")
+			span.SetAttributes(semconv.ErrorTypeKey.String(strconv.Itoa(m.StatusCode)))
+		}
+	}
+}
+
+// errorType returns the error.type of a failed request, which must have a
+// low cardinality
+func errorType(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return "_OTHER"
+	}
+}
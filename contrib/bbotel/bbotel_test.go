@@ -0,0 +1,191 @@
+package bbotel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/bbpix"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer_StartRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		metrics    bbpix.RequestMetrics
+		wantStatus codes.Code
+		wantAttrs  map[attribute.Key]attribute.Value
+	}{
+		{
+			name:       "success after retries",
+			metrics:    bbpix.RequestMetrics{StatusCode: 200, Retries: 2},
+			wantStatus: codes.Unset,
+			wantAttrs: map[attribute.Key]attribute.Value{
+				"http.response.status_code": attribute.IntValue(200),
+				"http.request.resend_count": attribute.IntValue(2),
+			},
+		},
+		{
+			name:       "client error",
+			metrics:    bbpix.RequestMetrics{StatusCode: 404},
+			wantStatus: codes.Unset,
+			wantAttrs: map[attribute.Key]attribute.Value{
+				"http.response.status_code": attribute.IntValue(404),
+			},
+		},
+		{
+			name:       "server error",
+			metrics:    bbpix.RequestMetrics{StatusCode: 503},
+			wantStatus: codes.Error,
+			wantAttrs: map[attribute.Key]attribute.Value{
+				"http.response.status_code": attribute.IntValue(503),
+				"error.type":                attribute.StringValue("503"),
+			},
+		},
+		{
+			name:       "network error",
+			metrics:    bbpix.RequestMetrics{Err: errors.New("connection reset")},
+			wantStatus: codes.Error,
+			wantAttrs: map[attribute.Key]attribute.Value{
+				"error.type": attribute.StringValue("_OTHER"),
+			},
+		},
+		{
+			name:       "deadline exceeded",
+			metrics:    bbpix.RequestMetrics{Err: context.DeadlineExceeded},
+			wantStatus: codes.Error,
+			wantAttrs: map[attribute.Key]attribute.Value{
+				"error.type": attribute.StringValue("timeout"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			tracer := NewTracer(tp,
+				WithPropagators(propagation.TraceContext{}),
+				WithAttributes(attribute.String("bbpix.convenio", "123")),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "https://api.bb.com.br:8443/pix-bb/v1/cob/7978c0c97ea847e78e8849634473c1f1", nil)
+			traced, end := tracer.StartRequest(req)
+			if traced == req {
+				t.Error("StartRequest() returned the original request")
+			}
+			if traced.Header.Get("Traceparent") == "" {
+				t.Error("Traceparent header not injected")
+			}
+			if req.Header.Get("Traceparent") != "" {
+				t.Error("original request modified")
+			}
+			if !trace.SpanContextFromContext(traced.Context()).IsValid() {
+				t.Error("span not in the request context")
+			}
+			end(tt.metrics)
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("spans = %d, want 1", len(spans))
+			}
+			span := spans[0]
+			if span.Name != "GET /pix-bb/v1/cob/{id}" {
+				t.Errorf("span name = %q, want %q", span.Name, "GET /pix-bb/v1/cob/{id}")
+			}
+			if span.SpanKind != trace.SpanKindClient {
+				t.Errorf("span kind = %v, want client", span.SpanKind)
+			}
+			if span.Status.Code != tt.wantStatus {
+				t.Errorf("span status = %v, want %v", span.Status.Code, tt.wantStatus)
+			}
+
+			want := map[attribute.Key]attribute.Value{
+				"http.request.method": attribute.StringValue("GET"),
+				"url.template":        attribute.StringValue("/pix-bb/v1/cob/{id}"),
+				"server.address":      attribute.StringValue("api.bb.com.br"),
+				"server.port":         attribute.IntValue(8443),
+				"bbpix.convenio":      attribute.StringValue("123"),
+			}
+			for k, v := range tt.wantAttrs {
+				want[k] = v
+			}
+			got := make(map[attribute.Key]attribute.Value)
+			for _, attr := range span.Attributes {
+				got[attr.Key] = attr.Value
+			}
+			for k, v := range want {
+				if got[k] != v {
+					t.Errorf("attribute %s = %v, want %v", k, got[k].Emit(), v.Emit())
+				}
+			}
+			if len(got) != len(want) {
+				t.Errorf("attributes = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestWithTracerProvider(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("Traceparent")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"txid":"abc"}`))
+	}))
+	defer server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	client, err := bbpix.New(bbpix.Config{
+		Environment:     bbpix.EnvironmentSandbox,
+		DeveloperAppKey: "test-app-key",
+		APIURL:          server.URL + "/pix-bb/v1",
+	},
+		bbpix.WithTokenProvider(staticTokenProvider{}),
+		WithTracerProvider(tp, WithPropagators(propagation.TraceContext{})),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	if _, err := client.PIX().GetQRCode(ctx, "7978c0c97ea847e78e8849634473c1f1"); err != nil {
+		t.Fatalf("GetQRCode() error = %v", err)
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("spans = %d, want 2", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "GET /pix-bb/v1/cob/{id}" {
+		t.Errorf("span name = %q", span.Name)
+	}
+	if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Error("request span is not a child of the caller span")
+	}
+	want := "00-" + span.SpanContext.TraceID().String() + "-" + span.SpanContext.SpanID().String() + "-01"
+	if traceparent != want {
+		t.Errorf("Traceparent = %q, want %q", traceparent, want)
+	}
+}
+
+// staticTokenProvider returns a fixed token
+type staticTokenProvider struct{}
+
+func (staticTokenProvider) GetToken(ctx context.Context) (*bbpix.Token, error) {
+	return &bbpix.Token{AccessToken: "token", TokenType: "Bearer", ExpiresIn: 3600, IssuedAt: time.Now()}, nil
+}
+
+func (staticTokenProvider) Invalidate() {}
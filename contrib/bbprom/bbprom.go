@@ -0,0 +1,149 @@
+// Package bbprom exports the metrics of a bbpix.Client to Prometheus
+// It is a separate module so the client itself has no dependency on the
+// Prometheus libraries:
+//
+//	collector, err := bbprom.New(prometheus.DefaultRegisterer)
+//	if err != nil {
+//		return err
+//	}
+//	client, err := bbpix.New(config, bbpix.WithMetrics(collector))
+package bbprom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/pericles-luz/go-bb-pix/bbpix"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector records the metrics of a client in Prometheus collectors
+//
+//	bbpix_requests_total{method, endpoint, code, class}
+//	bbpix_request_duration_seconds{method, endpoint}
+//	bbpix_retries_total{method, endpoint}
+//	bbpix_circuit_breaker_opens_total
+//	bbpix_circuit_breaker_state (0 closed, 1 open, 2 half-open)
+//
+// The endpoint label is the request path with its identifiers replaced by
+// {id}, so its cardinality is bounded. Failed requests without a response
+// have code "" and class "error".
+type Collector struct {
+	requests     *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	retries      *prometheus.CounterVec
+	circuitOpens prometheus.Counter
+	circuitState prometheus.Gauge
+}
+
+// Option configures a Collector
+type Option func(*options)
+
+// options holds the configurable options of a Collector
+type options struct {
+	namespace   string
+	constLabels prometheus.Labels
+	buckets     []float64
+}
+
+// WithNamespace sets the prefix of the metric names
+// Default: "bbpix"
+func WithNamespace(namespace string) Option {
+	return func(o *options) {
+		o.namespace = namespace
+	}
+}
+
+// WithConstLabels adds labels to every metric, e.g. to tell the clients of
+// several convênios apart
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(o *options) {
+		o.constLabels = labels
+	}
+}
+
+// WithBuckets sets the buckets of the request duration histogram, in
+// seconds
+// Default: prometheus.DefBuckets
+func WithBuckets(buckets []float64) Option {
+	return func(o *options) {
+		o.buckets = buckets
+	}
+}
+
+// New creates a Collector and registers its metrics on reg
+func New(reg prometheus.Registerer, opts ...Option) (*Collector, error) {
+	o := &options{namespace: "bbpix", buckets: prometheus.DefBuckets}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	c := &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   o.namespace,
+			Name:        "requests_total",
+			Help:        "API requests by endpoint and status, retries included in a single request.",
+			ConstLabels: o.constLabels,
+		}, []string{"method", "endpoint", "code", "class"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   o.namespace,
+			Name:        "request_duration_seconds",
+			Help:        "Duration of the API requests, retries included.",
+			ConstLabels: o.constLabels,
+			Buckets:     o.buckets,
+		}, []string{"method", "endpoint"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   o.namespace,
+			Name:        "retries_total",
+			Help:        "Retried attempts of the API requests.",
+			ConstLabels: o.constLabels,
+		}, []string{"method", "endpoint"}),
+		circuitOpens: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   o.namespace,
+			Name:        "circuit_breaker_opens_total",
+			Help:        "Times the circuit breaker opened.",
+			ConstLabels: o.constLabels,
+		}),
+		circuitState: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   o.namespace,
+			Name:        "circuit_breaker_state",
+			Help:        "State of the circuit breaker: 0 closed, 1 open, 2 half-open.",
+			ConstLabels: o.constLabels,
+		}),
+	}
+
+	for _, collector := range []prometheus.Collector{c.requests, c.duration, c.retries, c.circuitOpens, c.circuitState} {
+		if err := reg.Register(collector); err != nil {
+			return nil, fmt.Errorf("failed to register metrics: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// ObserveRequest implements bbpix.MetricsRecorder
+func (c *Collector) ObserveRequest(ctx context.Context, m bbpix.RequestMetrics) {
+	code, class := "", "error"
+	if m.StatusCode != 0 {
+		code = strconv.Itoa(m.StatusCode)
+		class = strconv.Itoa(m.StatusCode/100) + "xx"
+	}
+	if errors.Is(m.Err, context.Canceled) || errors.Is(m.Err, context.DeadlineExceeded) {
+		class = "canceled"
+	}
+
+	c.requests.WithLabelValues(m.Method, m.Endpoint, code, class).Inc()
+	c.duration.WithLabelValues(m.Method, m.Endpoint).Observe(m.Duration.Seconds())
+	if m.Retries > 0 {
+		c.retries.WithLabelValues(m.Method, m.Endpoint).Add(float64(m.Retries))
+	}
+}
+
+// ObserveCircuitState implements bbpix.MetricsRecorder
+func (c *Collector) ObserveCircuitState(from, to bbpix.CircuitState) {
+	c.circuitState.Set(float64(to))
+	if to == bbpix.CircuitOpen {
+		c.circuitOpens.Inc()
+	}
+}
@@ -0,0 +1,67 @@
+package bbprom
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/bbpix"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	c, err := New(reg, WithConstLabels(prometheus.Labels{"convenio": "123"}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := context.Background()
+	c.ObserveRequest(ctx, bbpix.RequestMetrics{Method: "GET", Endpoint: "/pix-bb/v1/cob/{id}", StatusCode: 200, Duration: 20 * time.Millisecond, Retries: 2})
+	c.ObserveRequest(ctx, bbpix.RequestMetrics{Method: "GET", Endpoint: "/pix-bb/v1/cob/{id}", StatusCode: 200, Duration: 30 * time.Millisecond})
+	c.ObserveRequest(ctx, bbpix.RequestMetrics{Method: "PUT", Endpoint: "/pix-bb/v1/cob/{id}", Err: errors.New("connection reset")})
+	c.ObserveRequest(ctx, bbpix.RequestMetrics{Method: "PUT", Endpoint: "/pix-bb/v1/cob/{id}", Err: context.Canceled})
+	c.ObserveCircuitState(bbpix.CircuitClosed, bbpix.CircuitOpen)
+	c.ObserveCircuitState(bbpix.CircuitOpen, bbpix.CircuitHalfOpen)
+
+	want := `
+# HELP bbpix_requests_total API requests by endpoint and status, retries included in a single request.
+# TYPE bbpix_requests_total counter
+bbpix_requests_total{class="2xx",code="200",convenio="123",endpoint="/pix-bb/v1/cob/{id}",method="GET"} 2
+bbpix_requests_total{class="canceled",code="",convenio="123",endpoint="/pix-bb/v1/cob/{id}",method="PUT"} 1
+bbpix_requests_total{class="error",code="",convenio="123",endpoint="/pix-bb/v1/cob/{id}",method="PUT"} 1
+# HELP bbpix_retries_total Retried attempts of the API requests.
+# TYPE bbpix_retries_total counter
+bbpix_retries_total{convenio="123",endpoint="/pix-bb/v1/cob/{id}",method="GET"} 2
+# HELP bbpix_circuit_breaker_opens_total Times the circuit breaker opened.
+# TYPE bbpix_circuit_breaker_opens_total counter
+bbpix_circuit_breaker_opens_total{convenio="123"} 1
+# HELP bbpix_circuit_breaker_state State of the circuit breaker: 0 closed, 1 open, 2 half-open.
+# TYPE bbpix_circuit_breaker_state gauge
+bbpix_circuit_breaker_state{convenio="123"} 2
+`
+	names := []string{"bbpix_requests_total", "bbpix_retries_total", "bbpix_circuit_breaker_opens_total", "bbpix_circuit_breaker_state"}
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), names...); err != nil {
+		t.Error(err)
+	}
+
+	if n := testutil.CollectAndCount(c.duration); n != 2 {
+		t.Errorf("duration series = %d, want 2", n)
+	}
+}
+
+func TestNew_RegisterTwice(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	if _, err := New(reg); err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := New(reg); err == nil {
+		t.Error("New() registering the same metrics twice should fail")
+	}
+	if _, err := New(reg, WithNamespace("other")); err != nil {
+		t.Errorf("New() with another namespace error = %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+// Package bbredis shares the OAuth2 tokens of bbpix clients through Redis
+// It is a separate module so the client itself has no dependency on the
+// Redis libraries:
+//
+//	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	client, err := bbpix.New(config, bbpix.WithTokenStore(bbredis.New(rdb)))
+package bbredis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/bbpix"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultPrefix is the prefix of the Redis keys of the tokens by default
+const DefaultPrefix = "bbpix:token:"
+
+// Store is a bbpix.TokenStore that keeps each token in a Redis key
+// expiring with the token
+type Store struct {
+	rdb    redis.UniversalClient
+	prefix string
+}
+
+// Option configures a Store
+type Option func(*Store)
+
+// WithPrefix sets the prefix of the Redis keys, e.g. to share a database
+// between applications
+// Default: DefaultPrefix
+func WithPrefix(prefix string) Option {
+	return func(s *Store) {
+		s.prefix = prefix
+	}
+}
+
+// New creates a new Store using rdb, which may be a single node, sentinel
+// or cluster client
+func New(rdb redis.UniversalClient, opts ...Option) *Store {
+	s := &Store{rdb: rdb, prefix: DefaultPrefix}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Get implements bbpix.TokenStore
+func (s *Store) Get(ctx context.Context, key string) (*bbpix.Token, error) {
+	data, err := s.rdb.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+
+	var token bbpix.Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+	return &token, nil
+}
+
+// Put implements bbpix.TokenStore
+// Tokens already expired are not stored.
+func (s *Store) Put(ctx context.Context, key string, token *bbpix.Token) error {
+	ttl := time.Until(token.ExpiresAt())
+	if ttl <= 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to encode token: %w", err)
+	}
+
+	if err := s.rdb.Set(ctx, s.prefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to put token: %w", err)
+	}
+	return nil
+}
+
+// Delete implements bbpix.TokenStore
+func (s *Store) Delete(ctx context.Context, key string) error {
+	if err := s.rdb.Del(ctx, s.prefix+key).Err(); err != nil {
+		return fmt.Errorf("failed to delete token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package bbredis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/pericles-luz/go-bb-pix/bbpix"
+	"github.com/redis/go-redis/v9"
+)
+
+// Store must satisfy the interface expected by bbpix.WithTokenStore
+var _ bbpix.TokenStore = (*Store)(nil)
+
+func TestStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	store := New(rdb, WithPrefix("test:"))
+	ctx := context.Background()
+
+	token, err := store.Get(ctx, "key")
+	if err != nil || token != nil {
+		t.Fatalf("Get() on empty store = %v, %v, want nil, nil", token, err)
+	}
+
+	want := &bbpix.Token{
+		AccessToken: "access-token",
+		TokenType:   "Bearer",
+		ExpiresIn:   600,
+		IssuedAt:    time.Now().Add(-time.Minute).Round(0),
+	}
+	if err := store.Put(ctx, "key", want); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	if ttl := mr.TTL("test:key"); ttl <= 8*time.Minute || ttl > 9*time.Minute {
+		t.Errorf("TTL = %v, want the 9 minutes left of the token", ttl)
+	}
+
+	got, err := store.Get(ctx, "key")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.AccessToken != want.AccessToken || got.ExpiresIn != want.ExpiresIn || !got.IssuedAt.Equal(want.IssuedAt) {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+
+	if err := store.Delete(ctx, "key"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if mr.Exists("test:key") {
+		t.Error("token kept after Delete()")
+	}
+}
+
+func TestStore_ExpiredToken(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	store := New(rdb)
+	expired := &bbpix.Token{AccessToken: "old", ExpiresIn: 60, IssuedAt: time.Now().Add(-time.Hour)}
+	if err := store.Put(context.Background(), "key", expired); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if mr.Exists(DefaultPrefix + "key") {
+		t.Error("expired token stored")
+	}
+}
+
+func TestStore_Unavailable(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	defer rdb.Close()
+	mr.Close()
+
+	if _, err := New(rdb).Get(context.Background(), "key"); err == nil {
+		t.Error("Get() error = nil, want the connection error")
+	}
+}
@@ -22,6 +22,16 @@ type APIError struct {
 	StatusCode int
 	Message    string
 	Details    []ErrorDetail
+
+	// Type is the problem type URI of RFC 7807 responses, for example
+	// https://api.bb.com.br/api/v2/error/RegraDeNegocio
+	Type string
+}
+
+// TypeName returns the last segment of the problem type URI
+// (for example "RegraDeNegocio"), or an empty string when Type is not set
+func (e *APIError) TypeName() string {
+	return e.Type[strings.LastIndex(e.Type, "/")+1:]
 }
 
 // Error implements the error interface
@@ -70,3 +80,27 @@ func As(err error) (*APIError, error) {
 
 	return nil, errors.New("not an API error")
 }
+
+// ScopeError is returned when the API rejects a request with 403 because
+// the access token lacks a scope, usually one not requested or not
+// registered for the application
+type ScopeError struct {
+	*APIError
+
+	// Scopes are the scopes required by the request, as reported by the
+	// API; empty when the response does not name them
+	Scopes []string
+}
+
+// Error implements the error interface
+func (e *ScopeError) Error() string {
+	if len(e.Scopes) == 0 {
+		return fmt.Sprintf("insufficient scope: %s", e.APIError.Error())
+	}
+	return fmt.Sprintf("insufficient scope (missing %s): %s", strings.Join(e.Scopes, " "), e.APIError.Error())
+}
+
+// Unwrap returns the APIError of the response
+func (e *ScopeError) Unwrap() error {
+	return e.APIError
+}
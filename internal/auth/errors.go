@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// OAuth2 error codes of the token endpoint (RFC 6749, section 5.2)
+const (
+	ErrorInvalidRequest       = "invalid_request"
+	ErrorInvalidClient        = "invalid_client"
+	ErrorInvalidGrant         = "invalid_grant"
+	ErrorUnauthorizedClient   = "unauthorized_client"
+	ErrorUnsupportedGrantType = "unsupported_grant_type"
+	ErrorInvalidScope         = "invalid_scope"
+)
+
+// AuthError is returned when the token endpoint rejects a token request
+type AuthError struct {
+	StatusCode int
+
+	// Code is the OAuth2 error, e.g. ErrorInvalidClient; empty when the
+	// response is not an OAuth2 error
+	Code string
+
+	// Description is the error_description of the response, or its body
+	// when it is not an OAuth2 error
+	Description string
+}
+
+// Error implements the error interface
+func (e *AuthError) Error() string {
+	switch {
+	case e.Code != "" && e.Description != "":
+		return fmt.Sprintf("token request failed with status %d: %s: %s", e.StatusCode, e.Code, e.Description)
+	case e.Code != "":
+		return fmt.Sprintf("token request failed with status %d: %s", e.StatusCode, e.Code)
+	default:
+		return fmt.Sprintf("token request failed with status %d: %s", e.StatusCode, e.Description)
+	}
+}
+
+// Temporary reports whether the request may succeed if retried later, i.e.
+// the token endpoint failed with 5xx or was rate limited, as opposed to
+// rejecting the credentials or the request
+func (e *AuthError) Temporary() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
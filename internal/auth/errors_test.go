@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseAuthError(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		body          string
+		want          AuthError
+		wantMessage   string
+		wantTemporary bool
+	}{
+		{
+			name:        "invalid client",
+			statusCode:  http.StatusUnauthorized,
+			body:        `{"error":"invalid_client","error_description":"Client authentication failed"}`,
+			want:        AuthError{StatusCode: http.StatusUnauthorized, Code: ErrorInvalidClient, Description: "Client authentication failed"},
+			wantMessage: "token request failed with status 401: invalid_client: Client authentication failed",
+		},
+		{
+			name:        "invalid scope without description",
+			statusCode:  http.StatusBadRequest,
+			body:        `{"error":"invalid_scope"}`,
+			want:        AuthError{StatusCode: http.StatusBadRequest, Code: ErrorInvalidScope},
+			wantMessage: "token request failed with status 400: invalid_scope",
+		},
+		{
+			name:          "gateway failure",
+			statusCode:    http.StatusBadGateway,
+			body:          "<html>Bad Gateway</html>\n",
+			want:          AuthError{StatusCode: http.StatusBadGateway, Description: "<html>Bad Gateway</html>"},
+			wantMessage:   "token request failed with status 502: <html>Bad Gateway</html>",
+			wantTemporary: true,
+		},
+		{
+			name:          "rate limited",
+			statusCode:    http.StatusTooManyRequests,
+			body:          `{"message":"Too many requests"}`,
+			want:          AuthError{StatusCode: http.StatusTooManyRequests, Description: `{"message":"Too many requests"}`},
+			wantMessage:   `token request failed with status 429: {"message":"Too many requests"}`,
+			wantTemporary: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parseAuthError(tt.statusCode, []byte(tt.body))
+
+			if *err != tt.want {
+				t.Errorf("parseAuthError() = %+v, want %+v", *err, tt.want)
+			}
+			if err.Error() != tt.wantMessage {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.wantMessage)
+			}
+			if err.Temporary() != tt.wantTemporary {
+				t.Errorf("Temporary() = %v, want %v", err.Temporary(), tt.wantTemporary)
+			}
+		})
+	}
+}
+
+func TestOAuth2Provider_GetToken_AuthError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"invalid_client","error_description":"Client authentication failed"}`))
+	}))
+	defer server.Close()
+
+	provider := NewOAuth2Provider(server.URL, "wrong-id", "wrong-secret")
+
+	_, err := provider.GetToken(context.Background())
+
+	var authErr *AuthError
+	if !errors.As(err, &authErr) {
+		t.Fatalf("GetToken() error = %v, want an AuthError", err)
+	}
+	if authErr.Code != ErrorInvalidClient || authErr.Temporary() {
+		t.Errorf("AuthError = %+v, want a permanent invalid_client", authErr)
+	}
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testJWT returns an unsigned JWT with the given payload
+func testJWT(payload string) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`)) + "." + enc.EncodeToString([]byte(payload)) + ".c2lnbmF0dXJl"
+}
+
+func TestJWTExpiry(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		want   time.Time
+		wantOK bool
+	}{
+		{name: "exp claim", token: testJWT(`{"sub":"app","exp":1736938800}`), want: time.Unix(1736938800, 0), wantOK: true},
+		{name: "padded payload", token: "eyJhbGciOiJSUzI1NiJ9.eyJleHAiOiAxNzM2OTM4ODAwfQ==.c2ln", want: time.Unix(1736938800, 0), wantOK: true},
+		{name: "fractional exp", token: testJWT(`{"exp":1736938800.5}`), want: time.Unix(1736938800, 0), wantOK: true},
+		{name: "no exp claim", token: testJWT(`{"sub":"app"}`)},
+		{name: "invalid payload", token: "header.%%%.signature"},
+		{name: "opaque token", token: "2YotnFZFEjr1zCsicMWpAA"},
+		{name: "empty", token: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := jwtExpiry(tt.token)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("jwtExpiry() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestOAuth2Provider_GetToken_ExpiryFromJWT(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	accessToken := testJWT(fmt.Sprintf(`{"exp":%d}`, exp))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":%q,"token_type":"Bearer"}`, accessToken)
+	}))
+	defer server.Close()
+
+	provider := NewOAuth2Provider(server.URL, "client-id", "client-secret")
+	token, err := provider.GetToken(context.Background())
+	if err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+
+	if token.ExpiresIn < 3590 || token.ExpiresIn > 3600 {
+		t.Errorf("ExpiresIn = %d, want about 3600 from the exp claim", token.ExpiresIn)
+	}
+	if token.IsExpired() {
+		t.Error("token from the JWT considered expired")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
@@ -12,15 +13,96 @@ import (
 	"time"
 )
 
+// MaxRefreshDuration bounds a shared token refresh, store access included,
+// even when the token requests have no timeout, so a hung request cannot
+// hold back every GetToken call
+const MaxRefreshDuration = 2 * time.Minute
+
 // OAuth2Provider implements TokenProvider using OAuth2 Client Credentials flow
 type OAuth2Provider struct {
 	tokenURL     string
 	clientID     string
 	clientSecret string
 
-	mu           sync.RWMutex
-	cachedToken  *Token
-	httpClient   *http.Client
+	scopes []string
+
+	mu          sync.RWMutex
+	cachedToken *Token
+	inflight    *tokenCall
+	stats       FetchStats
+	httpClient  *http.Client
+
+	// refreshTimeout bounds each shared refresh; MaxRefreshDuration except
+	// in tests
+	refreshTimeout time.Duration
+
+	// store shares the tokens with other processes under storeKey
+	store    TokenStore
+	storeKey string
+	logger   *slog.Logger
+}
+
+// tokenCall is a token refresh shared by the concurrent GetToken calls
+type tokenCall struct {
+	done  chan struct{}
+	token *Token
+	err   error
+}
+
+// FetchStats are the counters of the token requests sent to the OAuth2
+// server; tokens found in the cache or the token store are not counted
+type FetchStats struct {
+	Fetches  uint64
+	Failures uint64
+
+	// Duration is the total time spent on the token requests, failed ones
+	// included, and LastDuration the time of the last one
+	Duration     time.Duration
+	LastDuration time.Duration
+}
+
+// OAuth2Option is a functional option for configuring the OAuth2Provider
+type OAuth2Option func(*OAuth2Provider)
+
+// WithScopes sets the scopes requested with each token
+// If no scopes are set, the server grants the scopes registered for the client
+func WithScopes(scopes ...string) OAuth2Option {
+	return func(p *OAuth2Provider) {
+		p.scopes = append([]string(nil), scopes...)
+	}
+}
+
+// WithTransport sets the transport used to request tokens
+// Default: http.DefaultTransport
+func WithTransport(rt http.RoundTripper) OAuth2Option {
+	return func(p *OAuth2Provider) {
+		p.httpClient.Transport = rt
+	}
+}
+
+// WithTimeout sets the time limit of each token request, zero meaning no
+// limit other than MaxRefreshDuration
+// Default: 30 seconds
+func WithTimeout(timeout time.Duration) OAuth2Option {
+	return func(p *OAuth2Provider) {
+		p.httpClient.Timeout = timeout
+	}
+}
+
+// WithTokenStore shares the tokens through store: a valid stored token is
+// used before requesting a new one, and new tokens are stored
+// Store failures are logged and fall back to the OAuth2 server.
+func WithTokenStore(store TokenStore) OAuth2Option {
+	return func(p *OAuth2Provider) {
+		p.store = store
+	}
+}
+
+// WithLogger sets the logger of the token store failures
+func WithLogger(logger *slog.Logger) OAuth2Option {
+	return func(p *OAuth2Provider) {
+		p.logger = logger
+	}
 }
 
 // tokenResponse represents the OAuth2 token response
@@ -30,20 +112,36 @@ type tokenResponse struct {
 	ExpiresIn   int    `json:"expires_in"`
 }
 
+// errorResponse represents the OAuth2 error response
+type errorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
 // NewOAuth2Provider creates a new OAuth2Provider
-func NewOAuth2Provider(tokenURL, clientID, clientSecret string) *OAuth2Provider {
-	return &OAuth2Provider{
+func NewOAuth2Provider(tokenURL, clientID, clientSecret string, opts ...OAuth2Option) *OAuth2Provider {
+	p := &OAuth2Provider{
 		tokenURL:     tokenURL,
 		clientID:     clientID,
 		clientSecret: clientSecret,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		refreshTimeout: MaxRefreshDuration,
 	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.storeKey = StoreKey(tokenURL, clientID, p.scopes)
+
+	return p
 }
 
 // GetToken returns a valid access token
-// If the current token is expired or doesn't exist, it will fetch a new one
+// If the current token is expired or doesn't exist, it will fetch a new one;
+// concurrent calls share a single refresh, which is not interrupted when the
+// context of a caller is done but is bounded by MaxRefreshDuration.
 func (p *OAuth2Provider) GetToken(ctx context.Context) (*Token, error) {
 	// Check if we have a valid cached token (read lock)
 	p.mu.RLock()
@@ -56,29 +154,157 @@ func (p *OAuth2Provider) GetToken(ctx context.Context) (*Token, error) {
 
 	// Need to fetch new token (write lock)
 	p.mu.Lock()
-	defer p.mu.Unlock()
 
 	// Double-check after acquiring write lock (another goroutine might have fetched it)
 	if p.cachedToken != nil && !p.cachedToken.IsExpired() {
-		return p.cachedToken, nil
+		token := p.cachedToken
+		p.mu.Unlock()
+		return token, nil
+	}
+
+	// Join the refresh in flight, or start one without holding the lock
+	call := p.inflight
+	if call == nil {
+		call = &tokenCall{done: make(chan struct{})}
+		p.inflight = call
+		go p.refresh(context.WithoutCancel(ctx), call)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("failed to fetch token: %w", ctx.Err())
+	}
+}
+
+// refresh obtains a token from the token store or the OAuth2 server and
+// completes call with it
+func (p *OAuth2Provider) refresh(ctx context.Context, call *tokenCall) {
+	ctx, cancel := context.WithTimeout(ctx, p.refreshTimeout)
+	defer cancel()
+
+	// Use the token stored by another process, if still valid
+	token := p.loadToken(ctx)
+	if token == nil || token.IsExpired() {
+		start := time.Now()
+		token, call.err = p.fetchToken(ctx)
+		p.recordFetch(time.Since(start), call.err)
+
+		if call.err == nil {
+			p.saveToken(ctx, token)
+		}
+	}
+
+	p.mu.Lock()
+	if call.err == nil {
+		// Cache the token
+		p.cachedToken = token
+		call.token = token
 	}
+	p.inflight = nil
+	p.mu.Unlock()
+
+	close(call.done)
+}
+
+// recordFetch counts a token request that took d
+func (p *OAuth2Provider) recordFetch(d time.Duration, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	// Fetch new token
-	token, err := p.fetchToken(ctx)
+	p.stats.Fetches++
 	if err != nil {
-		return nil, err
+		p.stats.Failures++
 	}
+	p.stats.Duration += d
+	p.stats.LastDuration = d
+}
 
-	// Cache the token
-	p.cachedToken = token
-	return token, nil
+// Stats returns the counters of the token requests sent so far
+func (p *OAuth2Provider) Stats() FetchStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.stats
 }
 
-// Invalidate marks the current token as invalid
+// Invalidate marks the current token as invalid, removing it from the
+// token store unless it was already replaced there
 func (p *OAuth2Provider) Invalidate() {
 	p.mu.Lock()
-	defer p.mu.Unlock()
+	invalid := p.cachedToken
 	p.cachedToken = nil
+	p.mu.Unlock()
+
+	p.deleteStored(context.Background(), invalid)
+}
+
+// InvalidateToken implements TokenInvalidator: token is removed from the
+// cache and the token store only where it was not replaced yet, so a 401
+// received for an old token keeps the fresh one
+func (p *OAuth2Provider) InvalidateToken(ctx context.Context, token *Token) {
+	if token == nil {
+		return
+	}
+
+	p.mu.Lock()
+	if p.cachedToken != nil && p.cachedToken.AccessToken == token.AccessToken {
+		p.cachedToken = nil
+	}
+	p.mu.Unlock()
+
+	p.deleteStored(ctx, token)
+}
+
+// deleteStored removes invalid from the token store if it is the stored
+// token
+func (p *OAuth2Provider) deleteStored(ctx context.Context, invalid *Token) {
+	if p.store == nil || invalid == nil {
+		return
+	}
+	if stored := p.loadToken(ctx); stored != nil && stored.AccessToken == invalid.AccessToken {
+		if err := p.store.Delete(ctx, p.storeKey); err != nil {
+			p.logStoreError(ctx, "delete", err)
+		}
+	}
+}
+
+// loadToken returns the token of the store, or nil when there is none or
+// the store failed
+func (p *OAuth2Provider) loadToken(ctx context.Context) *Token {
+	if p.store == nil {
+		return nil
+	}
+
+	token, err := p.store.Get(ctx, p.storeKey)
+	if err != nil {
+		p.logStoreError(ctx, "get", err)
+		return nil
+	}
+	return token
+}
+
+// saveToken puts token in the store
+func (p *OAuth2Provider) saveToken(ctx context.Context, token *Token) {
+	if p.store == nil {
+		return
+	}
+
+	if err := p.store.Put(ctx, p.storeKey, token); err != nil {
+		p.logStoreError(ctx, "put", err)
+	}
+}
+
+// logStoreError logs a failed token store operation
+func (p *OAuth2Provider) logStoreError(ctx context.Context, op string, err error) {
+	if p.logger == nil {
+		return
+	}
+	p.logger.WarnContext(ctx, "Token store failed",
+		slog.String("operation", op),
+		slog.String("error", err.Error()),
+	)
 }
 
 // fetchToken fetches a new token from the OAuth2 server
@@ -86,6 +312,9 @@ func (p *OAuth2Provider) fetchToken(ctx context.Context) (*Token, error) {
 	// Prepare request body
 	data := url.Values{}
 	data.Set("grant_type", "client_credentials")
+	if len(p.scopes) > 0 {
+		data.Set("scope", strings.Join(p.scopes, " "))
+	}
 
 	// Create request
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.tokenURL, strings.NewReader(data.Encode()))
@@ -112,7 +341,7 @@ func (p *OAuth2Provider) fetchToken(ctx context.Context) (*Token, error) {
 
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("token request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, parseAuthError(resp.StatusCode, body)
 	}
 
 	// Parse response
@@ -129,5 +358,27 @@ func (p *OAuth2Provider) fetchToken(ctx context.Context) (*Token, error) {
 		IssuedAt:    time.Now(),
 	}
 
+	// Some gateways omit expires_in; take the expiry from the JWT instead
+	if token.ExpiresIn <= 0 {
+		if exp, ok := jwtExpiry(token.AccessToken); ok {
+			token.ExpiresIn = int(exp.Sub(token.IssuedAt).Seconds())
+		}
+	}
+
 	return token, nil
 }
+
+// parseAuthError parses an error response of the token endpoint into an
+// AuthError
+func parseAuthError(statusCode int, body []byte) *AuthError {
+	var errResp errorResponse
+	if err := json.Unmarshal(body, &errResp); err != nil || errResp.Error == "" {
+		return &AuthError{StatusCode: statusCode, Description: strings.TrimSpace(string(body))}
+	}
+
+	return &AuthError{
+		StatusCode:  statusCode,
+		Code:        errResp.Error,
+		Description: errResp.ErrorDescription,
+	}
+}
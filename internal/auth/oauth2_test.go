@@ -3,11 +3,13 @@ package auth
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -88,6 +90,43 @@ func TestOAuth2Provider_GetToken_Success(t *testing.T) {
 	}
 }
 
+func TestOAuth2Provider_GetToken_SendsScopes(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []OAuth2Option
+		wantScope string
+	}{
+		{name: "no scopes", opts: nil, wantScope: ""},
+		{name: "with scopes", opts: []OAuth2Option{WithScopes("cob.read", "pix.read")}, wantScope: "cob.read pix.read"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					t.Fatalf("Failed to parse form: %v", err)
+				}
+				if got := r.FormValue("scope"); got != tt.wantScope {
+					t.Errorf("scope = %q, want %q", got, tt.wantScope)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"access_token": "test-access-token",
+					"token_type":   "Bearer",
+					"expires_in":   3600,
+				})
+			}))
+			defer server.Close()
+
+			provider := NewOAuth2Provider(server.URL, "client-id", "client-secret", tt.opts...)
+			if _, err := provider.GetToken(context.Background()); err != nil {
+				t.Fatalf("GetToken() error = %v", err)
+			}
+		})
+	}
+}
+
 func TestOAuth2Provider_GetToken_CachedToken(t *testing.T) {
 	callCount := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -178,6 +217,31 @@ func TestOAuth2Provider_GetToken_NetworkError(t *testing.T) {
 	}
 }
 
+func TestOAuth2Provider_WithTransportAndTimeout(t *testing.T) {
+	var used bool
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		used = true
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+
+	provider := NewOAuth2Provider("https://oauth.example.com/token", "client-id", "client-secret",
+		WithTransport(rt),
+		WithTimeout(20*time.Millisecond),
+	)
+
+	start := time.Now()
+	if _, err := provider.GetToken(context.Background()); err == nil {
+		t.Fatal("expected timeout error")
+	}
+	if !used {
+		t.Error("token request did not use the transport")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GetToken() took %v, want the 20ms timeout", elapsed)
+	}
+}
+
 func TestOAuth2Provider_GetToken_401Error(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnauthorized)
@@ -296,6 +360,108 @@ func TestOAuth2Provider_Concurrency(t *testing.T) {
 	}
 }
 
+func TestOAuth2Provider_SharedRefresh(t *testing.T) {
+	release := make(chan struct{})
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"test-token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	provider := NewOAuth2Provider(server.URL+"/token", "client-id", "client-secret")
+
+	// A caller giving up does not cancel the refresh of the others
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error)
+	go func() {
+		_, err := provider.GetToken(ctx)
+		canceled <- err
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if token, err := provider.GetToken(context.Background()); err != nil || token.AccessToken != "test-token" {
+				t.Errorf("GetToken() = %v, %v, want test-token", token, err)
+			}
+		}()
+	}
+
+	for fetches.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-canceled; !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled GetToken() error = %v, want context.Canceled", err)
+	}
+
+	close(release)
+	wg.Wait()
+
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("token requests = %d, want 1", n)
+	}
+	stats := provider.Stats()
+	if stats.Fetches != 1 || stats.Failures != 0 || stats.Duration <= 0 || stats.LastDuration != stats.Duration {
+		t.Errorf("Stats() = %+v, want a single successful fetch", stats)
+	}
+}
+
+func TestOAuth2Provider_SharedRefreshTimeout(t *testing.T) {
+	release := make(chan struct{})
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first request hangs until the end of the test
+		if fetches.Add(1) == 1 {
+			<-release
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"test-token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	provider := NewOAuth2Provider(server.URL+"/token", "client-id", "client-secret", WithTimeout(0))
+	provider.refreshTimeout = 50 * time.Millisecond
+
+	if _, err := provider.GetToken(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetToken() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	// The hung refresh no longer blocks a new one
+	token, err := provider.GetToken(context.Background())
+	if err != nil || token.AccessToken != "test-token" {
+		t.Fatalf("GetToken() = %v, %v, want test-token", token, err)
+	}
+	if n := fetches.Load(); n != 2 {
+		t.Errorf("token requests = %d, want 2", n)
+	}
+}
+
+func TestOAuth2Provider_Stats_Failures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	provider := NewOAuth2Provider(server.URL+"/token", "client-id", "client-secret")
+	for i := 0; i < 2; i++ {
+		if _, err := provider.GetToken(context.Background()); err == nil {
+			t.Fatal("expected error for 503 response")
+		}
+	}
+
+	if stats := provider.Stats(); stats.Fetches != 2 || stats.Failures != 2 {
+		t.Errorf("Stats() = %+v, want 2 failed fetches", stats)
+	}
+}
+
 func TestOAuth2Provider_ContextCancellation(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Simulate slow response
@@ -324,3 +490,10 @@ func TestOAuth2Provider_ContextCancellation(t *testing.T) {
 		t.Errorf("Error should mention context: %v", err)
 	}
 }
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -5,12 +5,16 @@ import (
 	"time"
 )
 
+// ExpiryMargin is how long before its expiry a token is considered expired,
+// as a buffer for clock skew and request time
+const ExpiryMargin = 5 * time.Minute
+
 // Token represents an OAuth2 access token
 type Token struct {
-	AccessToken string
-	TokenType   string
-	ExpiresIn   int       // seconds
-	IssuedAt    time.Time
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	ExpiresIn   int       `json:"expires_in"` // seconds
+	IssuedAt    time.Time `json:"issued_at"`
 }
 
 // IsExpired checks if the token is expired or about to expire
@@ -20,13 +24,7 @@ func (t *Token) IsExpired() bool {
 		return true
 	}
 
-	// Consider token expired if it expires in less than 5 minutes
-	// This gives us a buffer for clock skew and request time
-	expirationThreshold := 5 * time.Minute
-	expiresAt := t.IssuedAt.Add(time.Duration(t.ExpiresIn) * time.Second)
-	timeUntilExpiration := time.Until(expiresAt)
-
-	return timeUntilExpiration < expirationThreshold
+	return time.Until(t.ExpiresAt()) < ExpiryMargin
 }
 
 // ExpiresAt returns the time when the token expires
@@ -44,3 +42,10 @@ type TokenProvider interface {
 	// to be fetched on the next GetToken call
 	Invalidate()
 }
+
+// TokenInvalidator is implemented by the TokenProviders that can invalidate
+// a given token, keeping a newer one obtained meanwhile by another request
+type TokenInvalidator interface {
+	// InvalidateToken marks token as invalid if it is still the current one
+	InvalidateToken(ctx context.Context, token *Token)
+}
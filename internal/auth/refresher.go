@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	// minRefreshInterval bounds how often a Refresher asks for a token, for
+	// tokens that live less than ExpiryMargin
+	minRefreshInterval = 30 * time.Second
+
+	// maxRefreshBackoff bounds the wait between failed refreshes
+	maxRefreshBackoff = time.Minute
+)
+
+// Refresher renews the token of a TokenProvider in the background as soon
+// as it is considered expired, so requests after an idle period do not wait
+// for a token fetch
+type Refresher struct {
+	provider    TokenProvider
+	logger      *slog.Logger
+	minInterval time.Duration // minimum wait between refreshes
+	backoff     time.Duration // wait after the first failed refresh
+}
+
+// NewRefresher creates a new Refresher of provider
+func NewRefresher(provider TokenProvider) *Refresher {
+	return &Refresher{
+		provider:    provider,
+		minInterval: minRefreshInterval,
+		backoff:     time.Second,
+	}
+}
+
+// SetLogger sets the logger of the failed refreshes; nil disables logging
+func (r *Refresher) SetLogger(logger *slog.Logger) {
+	r.logger = logger
+}
+
+// Run fetches a token and renews it ExpiryMargin before it expires, until
+// ctx is done; failed refreshes are retried with exponential backoff
+// It returns ctx.Err(). Run must not be called concurrently.
+func (r *Refresher) Run(ctx context.Context) error {
+	backoff := r.backoff
+	for {
+		var wait time.Duration
+		token, err := r.provider.GetToken(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil:
+			if r.logger != nil {
+				r.logger.WarnContext(ctx, "Token refresh failed",
+					slog.String("error", err.Error()),
+					slog.Duration("retry_in", backoff),
+				)
+			}
+			wait = backoff
+			backoff = min(backoff*2, maxRefreshBackoff)
+		default:
+			wait = max(time.Until(token.ExpiresAt().Add(-ExpiryMargin)), r.minInterval)
+			backoff = r.backoff
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sequenceProvider is a TokenProvider that returns the results of next
+type sequenceProvider struct {
+	mu    sync.Mutex
+	calls int
+	next  func(call int) (*Token, error)
+}
+
+func (p *sequenceProvider) GetToken(ctx context.Context) (*Token, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	return p.next(p.calls)
+}
+
+func (p *sequenceProvider) Invalidate() {}
+
+func (p *sequenceProvider) Calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+// expiringToken returns a token considered expired after d
+func expiringToken(d time.Duration) *Token {
+	return &Token{
+		AccessToken: "token",
+		ExpiresIn:   600,
+		IssuedAt:    time.Now().Add(d + ExpiryMargin - 600*time.Second),
+	}
+}
+
+func TestRefresher_Run(t *testing.T) {
+	provider := &sequenceProvider{next: func(int) (*Token, error) {
+		return expiringToken(20 * time.Millisecond), nil
+	}}
+	refresher := NewRefresher(provider)
+	refresher.minInterval = time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if err := refresher.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run() error = %v, want context.DeadlineExceeded", err)
+	}
+	if calls := provider.Calls(); calls < 3 || calls > 6 {
+		t.Errorf("GetToken() calls = %d, want about one every 20ms", calls)
+	}
+}
+
+func TestRefresher_Run_WaitsForExpiry(t *testing.T) {
+	provider := &sequenceProvider{next: func(int) (*Token, error) {
+		return expiringToken(time.Hour), nil
+	}}
+	refresher := NewRefresher(provider)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	refresher.Run(ctx)
+	if calls := provider.Calls(); calls != 1 {
+		t.Errorf("GetToken() calls = %d, want 1", calls)
+	}
+}
+
+func TestRefresher_Run_RetriesFailures(t *testing.T) {
+	provider := &sequenceProvider{next: func(call int) (*Token, error) {
+		if call < 3 {
+			return nil, errors.New("token server unavailable")
+		}
+		return expiringToken(time.Hour), nil
+	}}
+
+	var buf bytes.Buffer
+	refresher := NewRefresher(provider)
+	refresher.backoff = time.Millisecond
+	refresher.SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	refresher.Run(ctx)
+	if calls := provider.Calls(); calls != 3 {
+		t.Errorf("GetToken() calls = %d, want 3", calls)
+	}
+	if got := strings.Count(buf.String(), `level=WARN msg="Token refresh failed"`); got != 2 {
+		t.Errorf("warnings = %d, want 2:\n%s", got, buf.String())
+	}
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TokenStore persists access tokens outside the process, so workers and
+// short-lived jobs using the same credentials share them instead of each
+// requesting its own
+// Keys identify the credentials and scopes of a token; they are hex strings
+// safe to use as file names and carry no secret.
+type TokenStore interface {
+	// Get returns the token stored under key, or nil when there is none
+	Get(ctx context.Context, key string) (*Token, error)
+
+	// Put stores token under key, replacing the previous one
+	Put(ctx context.Context, key string, token *Token) error
+
+	// Delete removes the token stored under key, if any
+	Delete(ctx context.Context, key string) error
+}
+
+// StoreKey returns the TokenStore key of the tokens of clientID for scopes
+// issued by tokenURL
+func StoreKey(tokenURL, clientID string, scopes []string) string {
+	sum := sha256.Sum256([]byte(tokenURL + "\n" + clientID + "\n" + strings.Join(scopes, " ")))
+	return hex.EncodeToString(sum[:])
+}
+
+// FileTokenStore is a TokenStore that keeps each token in a JSON file of a
+// directory, readable only by its owner
+type FileTokenStore struct {
+	dir string
+}
+
+// NewFileTokenStore creates a new FileTokenStore in dir, which is created
+// on the first Put if needed
+func NewFileTokenStore(dir string) *FileTokenStore {
+	return &FileTokenStore{dir: dir}
+}
+
+// path returns the file of the token stored under key
+func (s *FileTokenStore) path(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
+
+// Get implements TokenStore
+func (s *FileTokenStore) Get(ctx context.Context, key string) (*Token, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	var token Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("failed to parse token file: %w", err)
+	}
+	return &token, nil
+}
+
+// Put implements TokenStore
+// The file is replaced atomically, so concurrent readers never see a
+// partial token.
+func (s *FileTokenStore) Put(ctx context.Context, key string, token *Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to encode token: %w", err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create token directory: %w", err)
+	}
+
+	f, err := os.CreateTemp(s.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create token file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	if err := os.Rename(f.Name(), s.path(key)); err != nil {
+		return fmt.Errorf("failed to replace token file: %w", err)
+	}
+	return nil
+}
+
+// Delete implements TokenStore
+func (s *FileTokenStore) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove token file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFileTokenStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tokens")
+	store := NewFileTokenStore(dir)
+	ctx := context.Background()
+	key := StoreKey("https://oauth.example.com/token", "client-id", nil)
+
+	token, err := store.Get(ctx, key)
+	if err != nil || token != nil {
+		t.Fatalf("Get() on empty store = %v, %v, want nil, nil", token, err)
+	}
+
+	want := &Token{
+		AccessToken: "access-token",
+		TokenType:   "Bearer",
+		ExpiresIn:   600,
+		IssuedAt:    time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	if err := store.Put(ctx, key, want); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, key+".json"))
+	if err != nil {
+		t.Fatalf("token file not written: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("token file mode = %v, want 0600", perm)
+	}
+
+	got, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if *got != *want {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("Delete() of a missing token error = %v", err)
+	}
+	if token, _ := store.Get(ctx, key); token != nil {
+		t.Errorf("Get() after Delete() = %+v, want nil", token)
+	}
+}
+
+func TestStoreKey(t *testing.T) {
+	key := StoreKey("https://oauth.example.com/token", "client-id", []string{"cob.read"})
+
+	if key == StoreKey("https://oauth.example.com/token", "other-client", []string{"cob.read"}) {
+		t.Error("StoreKey() is the same for different clients")
+	}
+	if key == StoreKey("https://oauth.example.com/token", "client-id", []string{"cob.write"}) {
+		t.Error("StoreKey() is the same for different scopes")
+	}
+	if len(key) != 64 {
+		t.Errorf("StoreKey() = %q, want a sha256 hex digest", key)
+	}
+}
+
+// memoryStore is an in-memory TokenStore
+type memoryStore struct {
+	mu     sync.Mutex
+	tokens map[string]*Token
+	err    error
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens[key], s.err
+}
+
+func (s *memoryStore) Put(ctx context.Context, key string, token *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.tokens[key] = token
+	return nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, key)
+	return s.err
+}
+
+func TestOAuth2Provider_WithTokenStore(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := fetches.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": fmt.Sprintf("fetched-token-%d", n),
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	defer server.Close()
+
+	store := &memoryStore{tokens: make(map[string]*Token)}
+	ctx := context.Background()
+
+	// A process fetches a token and stores it
+	first := NewOAuth2Provider(server.URL, "client-id", "client-secret", WithTokenStore(store))
+	token, err := first.GetToken(ctx)
+	if err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+	if token.AccessToken != "fetched-token-1" {
+		t.Fatalf("AccessToken = %q, want fetched-token-1", token.AccessToken)
+	}
+
+	// Another process reuses it
+	second := NewOAuth2Provider(server.URL, "client-id", "client-secret", WithTokenStore(store))
+	token, err = second.GetToken(ctx)
+	if err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+	if token.AccessToken != "fetched-token-1" || fetches.Load() != 1 {
+		t.Errorf("AccessToken = %q after %d fetches, want the stored token", token.AccessToken, fetches.Load())
+	}
+
+	// Invalidating the token removes it from the store
+	second.Invalidate()
+	if stored, _ := store.Get(ctx, second.storeKey); stored != nil {
+		t.Errorf("stored token = %+v after Invalidate(), want nil", stored)
+	}
+
+	token, err = second.GetToken(ctx)
+	if err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+	if token.AccessToken != "fetched-token-2" {
+		t.Errorf("AccessToken = %q, want fetched-token-2", token.AccessToken)
+	}
+
+	// The first process, told its old token is invalid, keeps the new one
+	first.Invalidate()
+	if stored, _ := store.Get(ctx, first.storeKey); stored == nil || stored.AccessToken != "fetched-token-2" {
+		t.Errorf("stored token = %+v, want fetched-token-2 kept", stored)
+	}
+	token, err = first.GetToken(ctx)
+	if err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+	if token.AccessToken != "fetched-token-2" || fetches.Load() != 2 {
+		t.Errorf("AccessToken = %q after %d fetches, want the stored token", token.AccessToken, fetches.Load())
+	}
+}
+
+func TestOAuth2Provider_InvalidateToken(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := fetches.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"fetched-token-%d","token_type":"Bearer","expires_in":3600}`, n)
+	}))
+	defer server.Close()
+
+	store := &memoryStore{tokens: make(map[string]*Token)}
+	provider := NewOAuth2Provider(server.URL, "client-id", "client-secret", WithTokenStore(store))
+	ctx := context.Background()
+
+	old, err := provider.GetToken(ctx)
+	if err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+	provider.InvalidateToken(ctx, old)
+	fresh, err := provider.GetToken(ctx)
+	if err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+
+	// A late rejection of the old token keeps the fresh one
+	provider.InvalidateToken(ctx, old)
+	if token, _ := provider.GetToken(ctx); token.AccessToken != fresh.AccessToken || fetches.Load() != 2 {
+		t.Errorf("AccessToken = %q after %d fetches, want %s kept", token.AccessToken, fetches.Load(), fresh.AccessToken)
+	}
+	if stored, _ := store.Get(ctx, provider.storeKey); stored == nil || stored.AccessToken != fresh.AccessToken {
+		t.Errorf("stored token = %+v, want %s kept", stored, fresh.AccessToken)
+	}
+
+	provider.InvalidateToken(ctx, fresh)
+	if stored, _ := store.Get(ctx, provider.storeKey); stored != nil {
+		t.Errorf("stored token = %+v after InvalidateToken(), want nil", stored)
+	}
+	if token, _ := provider.GetToken(ctx); token.AccessToken != "fetched-token-3" {
+		t.Errorf("AccessToken = %q, want fetched-token-3", token.AccessToken)
+	}
+}
+
+func TestOAuth2Provider_WithTokenStore_StoreFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"fetched-token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	store := &memoryStore{tokens: make(map[string]*Token), err: errors.New("connection refused")}
+	provider := NewOAuth2Provider(server.URL, "client-id", "client-secret", WithTokenStore(store))
+
+	token, err := provider.GetToken(context.Background())
+	if err != nil {
+		t.Fatalf("GetToken() error = %v, want the store failure ignored", err)
+	}
+	if token.AccessToken != "fetched-token" {
+		t.Errorf("AccessToken = %q, want fetched-token", token.AccessToken)
+	}
+}
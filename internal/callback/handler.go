@@ -0,0 +1,98 @@
+// Package callback implements the HTTP handling shared by the webhook
+// handlers of the pix and pixauto packages
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// DefaultMaxBodySize is the largest callback body accepted by default
+const DefaultMaxBodySize = 1 << 20
+
+// Options configures a callback handler
+type Options struct {
+	MaxBodySize int64
+	Logger      *slog.Logger
+}
+
+// DefaultOptions returns the default handler options
+func DefaultOptions() Options {
+	return Options{MaxBodySize: DefaultMaxBodySize}
+}
+
+// handler is the http.Handler returned by NewHandler
+type handler[P, T any] struct {
+	opts  Options
+	items func(P) ([]T, error)
+	fn    func(context.Context, []T) error
+}
+
+// NewHandler returns an http.Handler that decodes POSTed payloads of type P,
+// extracts and validates their items with items and passes them to fn.
+//
+// Responses:
+//   - 200 when fn succeeds (or the payload has no items)
+//   - 400 for malformed payloads or when items returns an error
+//   - 405 for methods other than POST
+//   - 413 when the body exceeds the maximum size
+//   - 500 when fn returns an error
+func NewHandler[P, T any](opts Options, items func(P) ([]T, error), fn func(context.Context, []T) error) http.Handler {
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = DefaultMaxBodySize
+	}
+
+	return &handler[P, T]{opts: opts, items: items, fn: fn}
+}
+
+// ServeHTTP implements http.Handler
+func (h *handler[P, T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.opts.MaxBodySize))
+
+	var payload P
+	if err := dec.Decode(&payload); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			h.log(r.Context(), "webhook payload too large", err)
+			http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		h.log(r.Context(), "invalid webhook payload", err)
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	items, err := h.items(payload)
+	if err != nil {
+		h.log(r.Context(), "invalid webhook payload", err)
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if len(items) > 0 && h.fn != nil {
+		if err := h.fn(r.Context(), items); err != nil {
+			h.log(r.Context(), "failed to process webhook", err)
+			http.Error(w, "failed to process notification", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// log reports a rejected or failed callback when a logger is configured
+func (h *handler[P, T]) log(ctx context.Context, msg string, err error) {
+	if h.opts.Logger == nil {
+		return
+	}
+	h.opts.Logger.ErrorContext(ctx, msg, slog.String("error", err.Error()))
+}
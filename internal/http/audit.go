@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// AuditRecord describes a mutating request and its outcome exactly as they
+// went over the wire
+type AuditRecord struct {
+	Method string
+	URL    string
+
+	// RequestBody is the marshaled request body that was sent
+	RequestBody []byte
+
+	// StatusCode is zero when no response was received
+	StatusCode int
+
+	// ResponseBody is the raw response body, including error responses
+	ResponseBody []byte
+
+	// Response is the decoded response target, nil when the call failed or
+	// no target was given
+	Response interface{}
+
+	Duration time.Duration
+	Err      error
+}
+
+// AuditFunc receives an AuditRecord after every mutating request completes.
+// It runs synchronously on the calling goroutine; the byte slices are owned
+// by the function and may be retained.
+type AuditFunc func(ctx context.Context, record AuditRecord)
+
+// Option is a functional option for configuring the Client
+type Option func(*Client)
+
+// WithAuditFunc sets a function called with the wire payloads of every
+// mutating request (POST, PUT, PATCH and DELETE)
+func WithAuditFunc(fn AuditFunc) Option {
+	return func(c *Client) {
+		c.auditFunc = fn
+	}
+}
+
+// isMutating reports whether method changes state on the server
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// requestBody returns a copy of the request body without consuming it
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil
+	}
+
+	return data
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pericles-luz/go-bb-pix/internal/apierror"
+)
+
+func TestClient_Do_AuditFunc(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fail":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"invalid"}`))
+		default:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"result":"success"}`))
+		}
+	}))
+	defer server.Close()
+
+	var records []AuditRecord
+	client := NewClient(&http.Client{}, server.URL, WithAuditFunc(func(ctx context.Context, rec AuditRecord) {
+		records = append(records, rec)
+	}))
+
+	body := map[string]string{"valor": "10.00"}
+
+	// Successful mutating request
+	req, _ := client.NewRequest(context.Background(), http.MethodPut, "/cob/txid", body)
+	var result map[string]string
+	if err := client.Do(req, &result); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	// Reads are not audited
+	req, _ = client.NewRequest(context.Background(), http.MethodGet, "/cob/txid", nil)
+	if err := client.Do(req, &result); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	// Failed mutating request
+	req, _ = client.NewRequest(context.Background(), http.MethodPost, "/fail", body)
+	if err := client.Do(req, &result); !apierror.Is(err) {
+		t.Fatalf("Do() error = %v, want APIError", err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("len(records) = %d, want 2", len(records))
+	}
+
+	ok := records[0]
+	if ok.Method != http.MethodPut || ok.URL != server.URL+"/cob/txid" {
+		t.Errorf("record = %s %s, want PUT %s/cob/txid", ok.Method, ok.URL, server.URL)
+	}
+	if string(ok.RequestBody) != `{"valor":"10.00"}` {
+		t.Errorf("RequestBody = %s", ok.RequestBody)
+	}
+	if ok.StatusCode != http.StatusOK || string(ok.ResponseBody) != `{"result":"success"}` {
+		t.Errorf("response = %d %s", ok.StatusCode, ok.ResponseBody)
+	}
+	if ok.Response != &result || ok.Err != nil {
+		t.Errorf("Response = %v, Err = %v; want decoded target and nil error", ok.Response, ok.Err)
+	}
+
+	failed := records[1]
+	if failed.StatusCode != http.StatusBadRequest || string(failed.ResponseBody) != `{"message":"invalid"}` {
+		t.Errorf("failed response = %d %s", failed.StatusCode, failed.ResponseBody)
+	}
+	if failed.Err == nil || failed.Response != nil {
+		t.Errorf("failed record Err = %v, Response = %v; want error and nil response", failed.Err, failed.Response)
+	}
+}
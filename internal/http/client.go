@@ -2,29 +2,63 @@ package http
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/pericles-luz/go-bb-pix/internal/apierror"
+	"github.com/pericles-luz/go-bb-pix/redact"
 )
 
+// Resetter is implemented by response types that can be reused across calls
+// Do calls Reset before decoding into such a target, so callers can keep a
+// pooled response value and decode every response into it
+type Resetter interface {
+	Reset()
+}
+
+// maxPooledBufferSize is the largest read buffer returned to the pool;
+// buffers that grew past it (very large pages) are left to the GC
+const maxPooledBufferSize = 1 << 20
+
+// bufferPool holds the buffers used to read response bodies
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
 // Client is an HTTP client for making API requests
 type Client struct {
 	httpClient *http.Client
 	baseURL    string
+	auditFunc  AuditFunc
+	hooks      *Hooks
+	listQuery  url.Values
+	redact     *redact.Redactor
+	pagination PaginationStyle
 }
 
 // NewClient creates a new HTTP client
-func NewClient(httpClient *http.Client, baseURL string) *Client {
-	return &Client{
+func NewClient(httpClient *http.Client, baseURL string, opts ...Option) *Client {
+	c := &Client{
 		httpClient: httpClient,
 		baseURL:    strings.TrimSuffix(baseURL, "/"),
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 // NewRequest creates a new HTTP request
@@ -62,7 +96,40 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, body inter
 
 // Do executes the HTTP request and decodes the response into target
 // If target is nil, the response body is discarded
-func (c *Client) Do(req *http.Request, target interface{}) error {
+func (c *Client) Do(req *http.Request, target interface{}) (err error) {
+	// Registered first so the hooks see the redacted error
+	call, start, err := c.startCall(req)
+	if err != nil {
+		return err
+	}
+	if call != nil {
+		defer func() {
+			c.hooks.endCall(req.Context(), call, start, err)
+		}()
+	}
+
+	if c.redact != nil {
+		defer func() {
+			err = c.redactError(err)
+		}()
+	}
+
+	// Audit mutating requests with the exact wire payloads
+	var rec *AuditRecord
+	if c.auditFunc != nil && isMutating(req.Method) {
+		rec = &AuditRecord{
+			Method:      req.Method,
+			URL:         req.URL.String(),
+			RequestBody: requestBody(req),
+		}
+		start := time.Now()
+		defer func() {
+			rec.Duration = time.Since(start)
+			rec.Err = err
+			c.auditFunc(req.Context(), *rec)
+		}()
+	}
+
 	// Execute request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -70,26 +137,113 @@ func (c *Client) Do(req *http.Request, target interface{}) error {
 	}
 	defer resp.Body.Close()
 
+	if rec != nil {
+		rec.StatusCode = resp.StatusCode
+	}
+	if call != nil {
+		call.StatusCode = resp.StatusCode
+	}
+
 	// Check for error status codes
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return parseErrorResponse(resp.StatusCode, resp.Body)
+		if rec == nil {
+			return parseErrorResponse(resp.StatusCode, resp.Header, resp.Body)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		rec.ResponseBody = body
+		return parseErrorResponse(resp.StatusCode, resp.Header, bytes.NewReader(body))
 	}
 
 	// If target is nil, just discard the body
 	if target == nil {
-		io.Copy(io.Discard, resp.Body)
+		if rec == nil {
+			io.Copy(io.Discard, resp.Body)
+			return nil
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		rec.ResponseBody = body
 		return nil
 	}
 
+	// Read the body into a pooled buffer to avoid a per-call allocation
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBufferSize {
+			bufferPool.Put(buf)
+		}
+	}()
+
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// The pooled buffer is reused, so the audit record needs its own copy
+	if rec != nil {
+		rec.ResponseBody = bytes.Clone(buf.Bytes())
+	}
+
+	// Clear reusable targets so no data from a previous call survives
+	if r, ok := target.(Resetter); ok {
+		r.Reset()
+	}
+
 	// Decode response
-	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+	if err := json.Unmarshal(buf.Bytes(), target); err != nil {
 		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	if rec != nil {
+		rec.Response = target
+	}
+
 	return nil
 }
 
+// DoStream executes the HTTP request and passes the body of a successful
+// response to fn, which can decode it as it arrives instead of reading it
+// whole. Error responses are returned as by Do. Requests sent with DoStream
+// are not audited.
+func (c *Client) DoStream(req *http.Request, fn func(body io.Reader) error) (err error) {
+	call, start, err := c.startCall(req)
+	if err != nil {
+		return err
+	}
+	if call != nil {
+		defer func() {
+			c.hooks.endCall(req.Context(), call, start, err)
+		}()
+	}
+
+	if c.redact != nil {
+		defer func() {
+			err = c.redactError(err)
+		}()
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if call != nil {
+		call.StatusCode = resp.StatusCode
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return parseErrorResponse(resp.StatusCode, resp.Header, resp.Body)
+	}
+
+	return fn(resp.Body)
+}
+
 // buildURL builds the full URL from base URL and path
+// The path is appended to the path of the base URL, so a base URL such as
+// https://gateway.example.com/pix-bb/v1 keeps its prefix.
 func (c *Client) buildURL(path string) (string, error) {
 	// Ensure path starts with /
 	if !strings.HasPrefix(path, "/") {
@@ -108,33 +262,95 @@ func (c *Client) buildURL(path string) (string, error) {
 		return "", err
 	}
 
-	// Resolve reference
-	u := base.ResolveReference(ref)
+	// Append the path to the base path
+	u := *base
+	u.Path = strings.TrimSuffix(base.Path, "/") + ref.Path
+	u.RawPath = ""
+	if ref.RawPath != "" || base.RawPath != "" {
+		u.RawPath = strings.TrimSuffix(base.EscapedPath(), "/") + ref.EscapedPath()
+	}
+	u.RawQuery = ref.RawQuery
+	u.Fragment = ""
+
 	return u.String(), nil
 }
 
 // errorResponse represents an error response from the API
+// Both the gateway format (message/errors) and the RFC 7807 problem format
+// used by the PIX API (type/title/detail/violacoes) are supported
 type errorResponse struct {
 	Message string `json:"message"`
 	Errors  []struct {
 		Field   string `json:"field"`
 		Message string `json:"message"`
 	} `json:"errors"`
+
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	Detail     string `json:"detail"`
+	Violations []struct {
+		Reason   string `json:"razao"`
+		Property string `json:"propriedade"`
+	} `json:"violacoes"`
+
+	// OAuth2 errors of the gateway (RFC 6750)
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+	Scope            string `json:"scope"`
+}
+
+// parseErrorResponse parses an error response into an APIError, or a
+// ScopeError for a 403 caused by a missing scope
+func parseErrorResponse(statusCode int, header http.Header, body io.Reader) error {
+	apiErr, errResp := newAPIError(statusCode, body)
+
+	if statusCode == http.StatusForbidden {
+		challenge := bearerChallenge(header)
+		if errResp.Error == "insufficient_scope" || challenge["error"] == "insufficient_scope" {
+			return &apierror.ScopeError{
+				APIError: apiErr,
+				Scopes:   strings.Fields(cmp.Or(errResp.Scope, challenge["scope"])),
+			}
+		}
+	}
+
+	return apiErr
 }
 
-// parseErrorResponse parses an error response into an APIError
-func parseErrorResponse(statusCode int, body io.Reader) error {
+// bearerChallengeParam matches the auth-params of a WWW-Authenticate header
+var bearerChallengeParam = regexp.MustCompile(`([a-zA-Z_]+)\s*=\s*"([^"]*)"`)
+
+// bearerChallenge returns the parameters of the Bearer challenge of header,
+// e.g. error and scope
+func bearerChallenge(header http.Header) map[string]string {
+	params := make(map[string]string)
+	for _, value := range header.Values("WWW-Authenticate") {
+		scheme, rest, _ := strings.Cut(strings.TrimSpace(value), " ")
+		if !strings.EqualFold(scheme, "Bearer") {
+			continue
+		}
+		for _, m := range bearerChallengeParam.FindAllStringSubmatch(rest, -1) {
+			params[strings.ToLower(m[1])] = m[2]
+		}
+	}
+	return params
+}
+
+// newAPIError builds the APIError of an error response, returning the
+// decoded body as well
+func newAPIError(statusCode int, body io.Reader) (*apierror.APIError, errorResponse) {
+	var errResp errorResponse
+
 	// Read body
 	bodyBytes, err := io.ReadAll(body)
 	if err != nil {
-		return apierror.New(statusCode, fmt.Sprintf("HTTP %d", statusCode))
+		return apierror.New(statusCode, fmt.Sprintf("HTTP %d", statusCode)), errResp
 	}
 
 	// Try to parse as JSON
-	var errResp errorResponse
 	if err := json.Unmarshal(bodyBytes, &errResp); err != nil {
 		// Not JSON, use status code
-		return apierror.New(statusCode, fmt.Sprintf("HTTP %d", statusCode))
+		return apierror.New(statusCode, fmt.Sprintf("HTTP %d", statusCode)), errorResponse{}
 	}
 
 	// Build error details
@@ -145,12 +361,33 @@ func parseErrorResponse(statusCode int, body io.Reader) error {
 			Message: e.Message,
 		})
 	}
+	for _, v := range errResp.Violations {
+		details = append(details, apierror.ErrorDetail{
+			Field:   v.Property,
+			Message: v.Reason,
+		})
+	}
 
 	// Use message from response or default to status code
 	message := errResp.Message
+	if message == "" {
+		message = errResp.Detail
+	}
+	if message == "" {
+		message = errResp.Title
+	}
+	if message == "" {
+		message = errResp.ErrorDescription
+	}
+	if message == "" {
+		message = errResp.Error
+	}
 	if message == "" {
 		message = fmt.Sprintf("HTTP %d", statusCode)
 	}
 
-	return apierror.New(statusCode, message, details...)
+	apiErr := apierror.New(statusCode, message, details...)
+	apiErr.Type = errResp.Type
+
+	return apiErr, errResp
 }
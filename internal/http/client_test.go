@@ -3,9 +3,11 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
 
@@ -213,6 +215,18 @@ func TestParseErrorResponse(t *testing.T) {
 			wantMessage:    "HTTP 500",
 			wantDetailsLen: 0,
 		},
+		{
+			name:       "problem details with violations",
+			statusCode: 422,
+			body: `{
+				"type": "https://api.bb.com.br/api/v2/error/RegraDeNegocio",
+				"title": "Entidade não processável",
+				"detail": "Violação de regra de negócio",
+				"violacoes": [{"razao": "O txid já existe para este CPF/CNPJ", "propriedade": "txid"}]
+			}`,
+			wantMessage:    "Violação de regra de negócio",
+			wantDetailsLen: 1,
+		},
 		{
 			name:           "invalid JSON",
 			statusCode:     500,
@@ -226,7 +240,7 @@ func TestParseErrorResponse(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			body := io.NopCloser(strings.NewReader(tt.body))
 
-			err := parseErrorResponse(tt.statusCode, body)
+			err := parseErrorResponse(tt.statusCode, nil, body)
 
 			apiErr, getErr := apierror.As(err)
 			if getErr != nil {
@@ -246,6 +260,76 @@ func TestParseErrorResponse(t *testing.T) {
 	}
 }
 
+func TestParseErrorResponse_InsufficientScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		header     http.Header
+		body       string
+		wantScope  bool
+		wantScopes []string
+	}{
+		{
+			name:       "scope in the body",
+			statusCode: http.StatusForbidden,
+			body:       `{"error":"insufficient_scope","error_description":"Token sem o escopo necessário","scope":"cob.write"}`,
+			wantScope:  true,
+			wantScopes: []string{"cob.write"},
+		},
+		{
+			name:       "scope in the challenge",
+			statusCode: http.StatusForbidden,
+			header: http.Header{"Www-Authenticate": []string{
+				`Bearer realm="api.bb.com.br", error="insufficient_scope", scope="pix.read pix.write"`,
+			}},
+			body:       `{"message":"Forbidden"}`,
+			wantScope:  true,
+			wantScopes: []string{"pix.read", "pix.write"},
+		},
+		{
+			name:       "scope not named",
+			statusCode: http.StatusForbidden,
+			body:       `{"error":"insufficient_scope"}`,
+			wantScope:  true,
+		},
+		{
+			name:       "other forbidden",
+			statusCode: http.StatusForbidden,
+			body:       `{"message":"Acesso negado"}`,
+		},
+		{
+			name:       "not forbidden",
+			statusCode: http.StatusUnauthorized,
+			header:     http.Header{"Www-Authenticate": []string{`Bearer error="insufficient_scope"`}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parseErrorResponse(tt.statusCode, tt.header, strings.NewReader(tt.body))
+
+			var scopeErr *apierror.ScopeError
+			if got := errors.As(err, &scopeErr); got != tt.wantScope {
+				t.Fatalf("ScopeError = %v, want %v (err = %v)", got, tt.wantScope, err)
+			}
+			if apiErr, asErr := apierror.As(err); asErr != nil || apiErr.StatusCode != tt.statusCode {
+				t.Errorf("APIError = %v, %v, want status %d", apiErr, asErr, tt.statusCode)
+			}
+			if !tt.wantScope {
+				return
+			}
+			if !slices.Equal(scopeErr.Scopes, tt.wantScopes) {
+				t.Errorf("Scopes = %v, want %v", scopeErr.Scopes, tt.wantScopes)
+			}
+			for _, scope := range tt.wantScopes {
+				if !strings.Contains(err.Error(), scope) {
+					t.Errorf("Error() = %q, want it to name %s", err.Error(), scope)
+				}
+			}
+		})
+	}
+}
+
 func TestClient_BuildURL(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -279,7 +363,21 @@ func TestClient_BuildURL(t *testing.T) {
 			name:    "base URL with path",
 			baseURL: "https://api.example.com/api/v1",
 			path:    "/resource",
-			wantURL: "https://api.example.com/resource",
+			wantURL: "https://api.example.com/api/v1/resource",
+			wantErr: false,
+		},
+		{
+			name:    "base URL with path and trailing slash",
+			baseURL: "https://gateway.example.com/pix-bb/v1/",
+			path:    "/cob/abc?status=ATIVA",
+			wantURL: "https://gateway.example.com/pix-bb/v1/cob/abc?status=ATIVA",
+			wantErr: false,
+		},
+		{
+			name:    "escaped path",
+			baseURL: "https://api.example.com/api/v1",
+			path:    "/webhook/chave%2Fcom%2Fbarra",
+			wantURL: "https://api.example.com/api/v1/webhook/chave%2Fcom%2Fbarra",
 			wantErr: false,
 		},
 	}
@@ -300,3 +398,110 @@ func TestClient_BuildURL(t *testing.T) {
 		})
 	}
 }
+
+// resettableTarget records Reset calls made by Do
+type resettableTarget struct {
+	Name       string `json:"name"`
+	Extra      string `json:"extra"`
+	resetCount int
+}
+
+func (r *resettableTarget) Reset() {
+	count := r.resetCount
+	*r = resettableTarget{resetCount: count + 1}
+}
+
+func TestClient_Do_ResetsReusableTarget(t *testing.T) {
+	responses := []string{
+		`{"name":"first","extra":"only-in-first"}`,
+		`{"name":"second"}`,
+	}
+	call := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(responses[call]))
+		call++
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	var target resettableTarget
+	for range responses {
+		req, _ := client.NewRequest(context.Background(), http.MethodGet, "/test", nil)
+		if err := client.Do(req, &target); err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+	}
+
+	if target.resetCount != 2 {
+		t.Errorf("resetCount = %d, want 2", target.resetCount)
+	}
+	if target.Name != "second" {
+		t.Errorf("Name = %s, want second", target.Name)
+	}
+	if target.Extra != "" {
+		t.Errorf("Extra = %q, want empty (stale data from previous call)", target.Extra)
+	}
+}
+
+func BenchmarkClient_Do(b *testing.B) {
+	body := []byte(`{"name":"` + strings.Repeat("x", 4096) + `"}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL)
+
+	var target resettableTarget
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req, _ := client.NewRequest(context.Background(), http.MethodGet, "/test", nil)
+		if err := client.Do(req, &target); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestClient_DoStream(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantBody   string
+		wantAPIErr bool
+	}{
+		{"success", http.StatusOK, `{"pix":[]}`, `{"pix":[]}`, false},
+		{"api error", http.StatusNotFound, `{"title":"Not found","detail":"no such payment"}`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(&http.Client{}, server.URL)
+			req, _ := client.NewRequest(context.Background(), http.MethodGet, "/pix", nil)
+
+			var got string
+			err := client.DoStream(req, func(body io.Reader) error {
+				b, err := io.ReadAll(body)
+				got = string(b)
+				return err
+			})
+
+			if _, asErr := apierror.As(err); (asErr == nil) != tt.wantAPIErr {
+				t.Errorf("DoStream() error = %v, want API error %v", err, tt.wantAPIErr)
+			}
+			if got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
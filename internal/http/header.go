@@ -0,0 +1,15 @@
+package http
+
+import "fmt"
+
+// CheckHeaderValue returns an error when value cannot be safely sent as the
+// value of header name. Control characters such as CR and LF are rejected so
+// that user-controlled input can never inject additional headers.
+func CheckHeaderValue(name, value string) error {
+	for i := 0; i < len(value); i++ {
+		if b := value[i]; (b < 0x20 && b != '\t') || b == 0x7f {
+			return fmt.Errorf("invalid value for header %s: control character at position %d", name, i)
+		}
+	}
+	return nil
+}
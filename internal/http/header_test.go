@@ -0,0 +1,46 @@
+package http
+
+import "testing"
+
+func TestCheckHeaderValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"plain", "abc123", false},
+		{"empty", "", false},
+		{"tab", "a\tb", false},
+		{"utf-8", "João 💸", false},
+		{"CRLF injection", "key\r\nX-Evil: 1", true},
+		{"LF", "key\n", true},
+		{"NUL", "key\x00", true},
+		{"DEL", "key\x7f", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckHeaderValue("X-Test", tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckHeaderValue(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func FuzzCheckHeaderValue(f *testing.F) {
+	for _, seed := range []string{"abc", "a\r\nb", "João", "\x00"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, value string) {
+		if CheckHeaderValue("X-Test", value) != nil {
+			return
+		}
+		for i := 0; i < len(value); i++ {
+			if value[i] == '\r' || value[i] == '\n' {
+				t.Fatalf("CheckHeaderValue(%q) accepted a line break", value)
+			}
+		}
+	})
+}
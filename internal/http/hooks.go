@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Call describes an API call passed to the client hooks
+type Call struct {
+	Method string
+	Path   string // URL path as sent, e.g. /pix-bb/v1/cob/abc
+
+	// StatusCode, Duration and Err describe the outcome of the call; they
+	// are unset in OnRequest. StatusCode is zero when no response was
+	// received.
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+// Hooks are functions called around every API call, retries included in a
+// single call
+// They run synchronously on the calling goroutine; nil hooks are skipped.
+type Hooks struct {
+	// OnRequest is called before the call is sent; an error aborts the
+	// call and is returned by it
+	OnRequest func(ctx context.Context, call Call) error
+
+	// OnResponse is called after a call that succeeded
+	OnResponse func(ctx context.Context, call Call)
+
+	// OnError is called after a call that failed, with an error response
+	// or without a response
+	OnError func(ctx context.Context, call Call)
+}
+
+// WithHooks sets functions called around every request sent with Do or
+// DoStream
+func WithHooks(h Hooks) Option {
+	return func(c *Client) {
+		if h.OnRequest != nil || h.OnResponse != nil || h.OnError != nil {
+			c.hooks = &h
+		}
+	}
+}
+
+// startCall runs the OnRequest hook and returns the call to complete once
+// the request is done, or nil when the client has no hooks
+func (c *Client) startCall(req *http.Request) (*Call, time.Time, error) {
+	if c.hooks == nil {
+		return nil, time.Time{}, nil
+	}
+
+	call := &Call{
+		Method: req.Method,
+		Path:   req.URL.Path,
+	}
+	if c.hooks.OnRequest != nil {
+		if err := c.hooks.OnRequest(req.Context(), *call); err != nil {
+			return nil, time.Time{}, err
+		}
+	}
+	return call, time.Now(), nil
+}
+
+// endCall runs the OnResponse or OnError hook with the outcome of call
+func (h *Hooks) endCall(ctx context.Context, call *Call, start time.Time, err error) {
+	call.Duration = time.Since(start)
+	call.Err = err
+	switch {
+	case err == nil && h.OnResponse != nil:
+		h.OnResponse(ctx, *call)
+	case err != nil && h.OnError != nil:
+		h.OnError(ctx, *call)
+	}
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/pericles-luz/go-bb-pix/internal/apierror"
+	"github.com/pericles-luz/go-bb-pix/redact"
+)
+
+func TestClient_Do_Hooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fail":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"not found: 123.456.789-09"}`))
+		default:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"result":"success"}`))
+		}
+	}))
+	defer server.Close()
+
+	var started, succeeded, failed []Call
+	blocked := errors.New("blocked by feature flag")
+	client := NewClient(&http.Client{}, server.URL,
+		WithRedactor(redact.New(redact.Pattern(regexp.MustCompile(`123\.456\.789-09`), func(string) string { return "[CPF]" }))),
+		WithHooks(Hooks{
+			OnRequest: func(ctx context.Context, call Call) error {
+				started = append(started, call)
+				if call.Path == "/blocked" {
+					return blocked
+				}
+				return nil
+			},
+			OnResponse: func(ctx context.Context, call Call) {
+				succeeded = append(succeeded, call)
+			},
+			OnError: func(ctx context.Context, call Call) {
+				failed = append(failed, call)
+			},
+		}),
+	)
+
+	req, _ := client.NewRequest(context.Background(), http.MethodGet, "/cob/txid", nil)
+	var result map[string]string
+	if err := client.Do(req, &result); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	req, _ = client.NewRequest(context.Background(), http.MethodPut, "/fail", map[string]string{"valor": "10.00"})
+	if err := client.Do(req, &result); !apierror.Is(err) {
+		t.Fatalf("Do() error = %v, want APIError", err)
+	}
+
+	req, _ = client.NewRequest(context.Background(), http.MethodGet, "/stream", nil)
+	if err := client.DoStream(req, func(body io.Reader) error { return nil }); err != nil {
+		t.Fatalf("DoStream() error = %v", err)
+	}
+
+	req, _ = client.NewRequest(context.Background(), http.MethodGet, "/blocked", nil)
+	if err := client.Do(req, &result); !errors.Is(err, blocked) {
+		t.Fatalf("Do() error = %v, want the OnRequest error", err)
+	}
+
+	if len(started) != 4 {
+		t.Errorf("OnRequest calls = %d, want 4", len(started))
+	}
+	if started[0].Method != http.MethodGet || started[0].Path != "/cob/txid" || started[0].StatusCode != 0 {
+		t.Errorf("OnRequest call = %+v", started[0])
+	}
+
+	if len(succeeded) != 2 {
+		t.Fatalf("OnResponse calls = %d, want 2", len(succeeded))
+	}
+	if succeeded[0].StatusCode != http.StatusOK || succeeded[0].Duration <= 0 || succeeded[0].Err != nil {
+		t.Errorf("OnResponse call = %+v", succeeded[0])
+	}
+	if succeeded[1].Path != "/stream" {
+		t.Errorf("OnResponse path = %s, want /stream", succeeded[1].Path)
+	}
+
+	if len(failed) != 1 {
+		t.Fatalf("OnError calls = %d, want 1", len(failed))
+	}
+	if failed[0].Method != http.MethodPut || failed[0].StatusCode != http.StatusNotFound || !apierror.Is(failed[0].Err) {
+		t.Errorf("OnError call = %+v", failed[0])
+	}
+	if strings.Contains(failed[0].Err.Error(), "123.456.789-09") {
+		t.Errorf("OnError error not redacted: %v", failed[0].Err)
+	}
+}
+
+func TestClient_Do_HooksNetworkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	var failed []Call
+	client := NewClient(&http.Client{}, server.URL, WithHooks(Hooks{
+		OnError: func(ctx context.Context, call Call) {
+			failed = append(failed, call)
+		},
+	}))
+
+	req, _ := client.NewRequest(context.Background(), http.MethodGet, "/cob/txid", nil)
+	if err := client.Do(req, nil); err == nil {
+		t.Fatal("Do() error = nil, want a network error")
+	}
+
+	if len(failed) != 1 || failed[0].StatusCode != 0 || failed[0].Err == nil {
+		t.Errorf("OnError calls = %+v, want one without status", failed)
+	}
+}
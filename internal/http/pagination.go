@@ -0,0 +1,72 @@
+package http
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// DefaultPageSize is the itensPorPagina sent when a listing does not set one
+const DefaultPageSize = 100
+
+// PaginationStyle selects the names of the paging query parameters
+type PaginationStyle int
+
+const (
+	// PaginationNested sends paginacao.paginaAtual and
+	// paginacao.itensPorPagina, as documented by the API specification
+	PaginationNested PaginationStyle = iota
+	// PaginationFlat sends paginaAtual and itensPorPagina
+	PaginationFlat
+)
+
+// names returns the page and page size query parameter names
+func (s PaginationStyle) names() (page, pageSize string) {
+	if s == PaginationFlat {
+		return "paginaAtual", "itensPorPagina"
+	}
+	return "paginacao.paginaAtual", "paginacao.itensPorPagina"
+}
+
+// WithPaginationStyle sets the names of the paging query parameters
+// Default: PaginationNested
+func WithPaginationStyle(style PaginationStyle) Option {
+	return func(c *Client) {
+		c.pagination = style
+	}
+}
+
+// WithListParam adds a query parameter to every listing, through
+// SetPagination, unless the listing already sets it
+// Requests on a single resource do not receive it.
+func WithListParam(name, value string) Option {
+	return func(c *Client) {
+		if c.listQuery == nil {
+			c.listQuery = url.Values{}
+		}
+		c.listQuery.Set(name, value)
+	}
+}
+
+// SetPagination sets the paging query parameters of a listing, along with
+// the parameters set with WithListParam
+// Both paging parameters are always sent, so page 0 can be requested
+// explicitly: a negative page is sent as 0 and a non-positive pageSize as
+// DefaultPageSize.
+func (c *Client) SetPagination(q url.Values, page, pageSize int) {
+	if page < 0 {
+		page = 0
+	}
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	pageName, pageSizeName := c.pagination.names()
+	q.Set(pageName, strconv.Itoa(page))
+	q.Set(pageSizeName, strconv.Itoa(pageSize))
+
+	for name, values := range c.listQuery {
+		if !q.Has(name) {
+			q[name] = values
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestClient_SetPagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []Option
+		page, size   int
+		wantParams   [2]string
+		wantPage     string
+		wantPageSize string
+	}{
+		{"explicit page zero", nil, 0, 50, [2]string{"paginacao.paginaAtual", "paginacao.itensPorPagina"}, "0", "50"},
+		{"defaults", nil, 0, 0, [2]string{"paginacao.paginaAtual", "paginacao.itensPorPagina"}, "0", "100"},
+		{"later page", nil, 3, 10, [2]string{"paginacao.paginaAtual", "paginacao.itensPorPagina"}, "3", "10"},
+		{"negative values", nil, -1, -5, [2]string{"paginacao.paginaAtual", "paginacao.itensPorPagina"}, "0", "100"},
+		{"flat style", []Option{WithPaginationStyle(PaginationFlat)}, 2, 0, [2]string{"paginaAtual", "itensPorPagina"}, "2", "100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(&http.Client{}, "https://api.example.com", tt.opts...)
+
+			q := url.Values{}
+			c.SetPagination(q, tt.page, tt.size)
+
+			if len(q) != 2 {
+				t.Errorf("query = %v, want exactly the two paging parameters", q)
+			}
+			if got := q.Get(tt.wantParams[0]); got != tt.wantPage {
+				t.Errorf("%s = %q, want %q", tt.wantParams[0], got, tt.wantPage)
+			}
+			if got := q.Get(tt.wantParams[1]); got != tt.wantPageSize {
+				t.Errorf("%s = %q, want %q", tt.wantParams[1], got, tt.wantPageSize)
+			}
+		})
+	}
+}
+
+func TestClient_WithListParam(t *testing.T) {
+	client := NewClient(&http.Client{}, "https://api.example.com", WithListParam("numeroConvenio", "123456"))
+
+	// Requests on a single resource do not receive it
+	req, err := client.NewRequest(context.Background(), http.MethodPut, "/cob/abc", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	if req.URL.Query().Has("numeroConvenio") {
+		t.Errorf("URL = %s, want no numeroConvenio", req.URL)
+	}
+
+	q := url.Values{"status": {"ATIVA"}}
+	client.SetPagination(q, 0, 0)
+	if got := q.Get("numeroConvenio"); got != "123456" {
+		t.Errorf("numeroConvenio = %q, want 123456", got)
+	}
+	if got := q.Get("status"); got != "ATIVA" {
+		t.Errorf("status = %q, want ATIVA", got)
+	}
+
+	// Parameters set by the listing take precedence
+	q = url.Values{"numeroConvenio": {"999"}}
+	client.SetPagination(q, 0, 0)
+	if got := q.Get("numeroConvenio"); got != "999" {
+		t.Errorf("numeroConvenio = %q, want 999", got)
+	}
+}
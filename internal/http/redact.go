@@ -0,0 +1,38 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/pericles-luz/go-bb-pix/internal/apierror"
+	"github.com/pericles-luz/go-bb-pix/redact"
+)
+
+// WithRedactor applies the pattern rules of r to the messages of the errors
+// returned by Do, including API error messages and details and transport
+// errors, which carry the request URL
+func WithRedactor(r *redact.Redactor) Option {
+	return func(c *Client) {
+		c.redact = r
+	}
+}
+
+// redactError applies the client redaction to err
+// API errors are redacted in place so callers can still inspect them with
+// apierror.As; other errors are wrapped by redact.Redactor.Error, keeping
+// the original in the chain.
+func (c *Client) redactError(err error) error {
+	if c.redact == nil || err == nil {
+		return err
+	}
+
+	var apiErr *apierror.APIError
+	if errors.As(err, &apiErr) {
+		apiErr.Message = c.redact.String(apiErr.Message)
+		for i := range apiErr.Details {
+			apiErr.Details[i].Message = c.redact.String(apiErr.Details[i].Message)
+		}
+		return err
+	}
+
+	return c.redact.Error(err)
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/pericles-luz/go-bb-pix/internal/apierror"
+	"github.com/pericles-luz/go-bb-pix/redact"
+)
+
+func TestClient_Do_Redactor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"detail":"CPF 12345678909 bloqueado","violacoes":[{"razao":"CPF 12345678909 inválido","propriedade":"devedor.cpf"}]}`))
+	}))
+	defer server.Close()
+
+	redactor := redact.New(redact.Pattern(regexp.MustCompile(`12345678909`), func(string) string { return "***" }))
+
+	client := NewClient(&http.Client{}, server.URL, WithRedactor(redactor))
+
+	req, _ := client.NewRequest(context.Background(), http.MethodGet, "/cob", nil)
+	err := client.Do(req, nil)
+
+	apiErr, asErr := apierror.As(err)
+	if asErr != nil {
+		t.Fatalf("Do() error = %v, want APIError", err)
+	}
+	if apiErr.Message != "CPF *** bloqueado" {
+		t.Errorf("Message = %q, want %q", apiErr.Message, "CPF *** bloqueado")
+	}
+	if apiErr.Details[0].Message != "CPF *** inválido" {
+		t.Errorf("Details[0].Message = %q, want %q", apiErr.Details[0].Message, "CPF *** inválido")
+	}
+
+	// Transport errors carry the URL, including query parameters
+	unreachable := NewClient(&http.Client{}, "http://invalid.localhost:99999", WithRedactor(redactor))
+	req, _ = unreachable.NewRequest(context.Background(), http.MethodGet, "/cob?cpf=12345678909", nil)
+	err = unreachable.Do(req, nil)
+	if err == nil {
+		t.Fatal("Do() error = nil, want transport error")
+	}
+	if strings.Contains(err.Error(), "12345678909") {
+		t.Errorf("error leaks the CPF: %v", err)
+	}
+	var urlErr interface{ Timeout() bool }
+	if !errors.As(err, &urlErr) {
+		t.Error("redacted error should keep the transport error in the chain")
+	}
+}
@@ -0,0 +1,293 @@
+package pkcs12
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+var (
+	oidPBEWithSHAAnd3KeyTripleDESCBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 3}
+	oidPBEWithSHAAnd40BitRC2CBC      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 6}
+	oidPBES2                         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2                        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+
+	oidSHA1   = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA384 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+
+	oidAES128CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+// Purposes of the PKCS#12 key derivation (RFC 7292, appendix B.3)
+const (
+	kdfKey byte = 1
+	kdfIV  byte = 2
+	kdfMAC byte = 3
+)
+
+// password holds the encodings of the bundle password: the PKCS#12 schemes
+// use a NUL-terminated BMPString, PBES2 uses the UTF-8 text
+type password struct {
+	bmp  []byte
+	utf8 string
+}
+
+// newPassword encodes s for the PKCS#12 schemes
+func newPassword(s string) (password, error) {
+	bmp := make([]byte, 0, 2*len(s)+2)
+	for _, r := range s {
+		if r > 0xFFFF {
+			return password{}, errors.New("pkcs12: password must only contain characters of the Basic Multilingual Plane")
+		}
+		bmp = append(bmp, byte(r>>8), byte(r))
+	}
+	return password{bmp: append(bmp, 0, 0), utf8: s}, nil
+}
+
+// pbeParams are the parameters of the PKCS#12 PBE schemes
+type pbeParams struct {
+	Salt       []byte
+	Iterations int
+}
+
+// pbes2Params are the parameters of PBES2 (RFC 8018, appendix A.4)
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params are the parameters of PBKDF2 (RFC 8018, appendix A.2)
+type pbkdf2Params struct {
+	Salt       []byte
+	Iterations int
+	KeyLength  int                      `asn1:"optional"`
+	PRF        pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// hashFor returns the hash of a digest algorithm
+func hashFor(oid asn1.ObjectIdentifier) (func() hash.Hash, error) {
+	switch {
+	case oid.Equal(oidSHA1):
+		return sha1.New, nil
+	case oid.Equal(oidSHA256):
+		return sha256.New, nil
+	case oid.Equal(oidSHA384):
+		return sha512.New384, nil
+	case oid.Equal(oidSHA512):
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("pkcs12: unsupported digest algorithm %s", oid)
+	}
+}
+
+// verifyMAC checks the integrity MAC of the authenticated safe
+func verifyMAC(md *macData, content []byte, pw password) error {
+	h, err := hashFor(md.Mac.Algorithm.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	key := pkcs12KDF(h, pw.bmp, md.MacSalt, md.Iterations, kdfMAC, h().Size())
+	mac := hmac.New(h, key)
+	mac.Write(content)
+
+	if !hmac.Equal(mac.Sum(nil), md.Mac.Digest) {
+		return ErrIncorrectPassword
+	}
+	return nil
+}
+
+// decrypt decrypts data encrypted with a password-based scheme
+func decrypt(alg pkix.AlgorithmIdentifier, data []byte, pw password) ([]byte, error) {
+	block, iv, err := pbeCipher(alg, pw)
+	if err != nil {
+		return nil, err
+	}
+
+	size := block.BlockSize()
+	if len(data) == 0 || len(data)%size != 0 {
+		return nil, errors.New("pkcs12: encrypted data is not a multiple of the block size")
+	}
+
+	out := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, data)
+
+	// A wrong password leaves garbage behind, usually a malformed padding
+	padding := int(out[len(out)-1])
+	if padding == 0 || padding > size || !bytes.Equal(out[len(out)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, ErrIncorrectPassword
+	}
+	return out[:len(out)-padding], nil
+}
+
+// pbeCipher derives the block cipher and IV of an encryption algorithm
+func pbeCipher(alg pkix.AlgorithmIdentifier, pw password) (cipher.Block, []byte, error) {
+	switch {
+	case alg.Algorithm.Equal(oidPBEWithSHAAnd3KeyTripleDESCBC):
+		var params pbeParams
+		if err := unmarshal(alg.Parameters.FullBytes, &params); err != nil {
+			return nil, nil, fmt.Errorf("pkcs12: invalid PBE parameters: %w", err)
+		}
+		key := pkcs12KDF(sha1.New, pw.bmp, params.Salt, params.Iterations, kdfKey, 24)
+		iv := pkcs12KDF(sha1.New, pw.bmp, params.Salt, params.Iterations, kdfIV, 8)
+
+		block, err := des.NewTripleDESCipher(key)
+		return block, iv, err
+
+	case alg.Algorithm.Equal(oidPBEWithSHAAnd40BitRC2CBC):
+		var params pbeParams
+		if err := unmarshal(alg.Parameters.FullBytes, &params); err != nil {
+			return nil, nil, fmt.Errorf("pkcs12: invalid PBE parameters: %w", err)
+		}
+		key := pkcs12KDF(sha1.New, pw.bmp, params.Salt, params.Iterations, kdfKey, 5)
+		iv := pkcs12KDF(sha1.New, pw.bmp, params.Salt, params.Iterations, kdfIV, 8)
+
+		return newRC2Cipher(key, 40), iv, nil
+
+	case alg.Algorithm.Equal(oidPBES2):
+		return pbes2Cipher(alg, pw)
+
+	default:
+		return nil, nil, fmt.Errorf("pkcs12: unsupported encryption algorithm %s", alg.Algorithm)
+	}
+}
+
+// pbes2Cipher derives the block cipher and IV of a PBES2 algorithm
+func pbes2Cipher(alg pkix.AlgorithmIdentifier, pw password) (cipher.Block, []byte, error) {
+	var params pbes2Params
+	if err := unmarshal(alg.Parameters.FullBytes, &params); err != nil {
+		return nil, nil, fmt.Errorf("pkcs12: invalid PBES2 parameters: %w", err)
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, nil, fmt.Errorf("pkcs12: unsupported key derivation function %s", params.KeyDerivationFunc.Algorithm)
+	}
+
+	var kdf pbkdf2Params
+	if err := unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		return nil, nil, fmt.Errorf("pkcs12: invalid PBKDF2 parameters: %w", err)
+	}
+
+	prf := sha1.New
+	switch {
+	case len(kdf.PRF.Algorithm) == 0, kdf.PRF.Algorithm.Equal(oidHMACWithSHA1):
+	case kdf.PRF.Algorithm.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	case kdf.PRF.Algorithm.Equal(oidHMACWithSHA384):
+		prf = sha512.New384
+	case kdf.PRF.Algorithm.Equal(oidHMACWithSHA512):
+		prf = sha512.New
+	default:
+		return nil, nil, fmt.Errorf("pkcs12: unsupported PBKDF2 PRF %s", kdf.PRF.Algorithm)
+	}
+
+	var (
+		keyLen   int
+		newBlock func(key []byte) (cipher.Block, error)
+	)
+	scheme := params.EncryptionScheme.Algorithm
+	switch {
+	case scheme.Equal(oidAES128CBC):
+		keyLen, newBlock = 16, aes.NewCipher
+	case scheme.Equal(oidAES192CBC):
+		keyLen, newBlock = 24, aes.NewCipher
+	case scheme.Equal(oidAES256CBC):
+		keyLen, newBlock = 32, aes.NewCipher
+	case scheme.Equal(oidDESEDE3CBC):
+		keyLen, newBlock = 24, des.NewTripleDESCipher
+	default:
+		return nil, nil, fmt.Errorf("pkcs12: unsupported PBES2 encryption scheme %s", scheme)
+	}
+
+	var iv []byte
+	if err := unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, nil, fmt.Errorf("pkcs12: invalid PBES2 IV: %w", err)
+	}
+
+	key, err := pbkdf2.Key(prf, pw.utf8, kdf.Salt, kdf.Iterations, keyLen)
+	if err != nil {
+		return nil, nil, fmt.Errorf("pkcs12: failed to derive key: %w", err)
+	}
+
+	block, err := newBlock(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(iv) != block.BlockSize() {
+		return nil, nil, errors.New("pkcs12: invalid PBES2 IV length")
+	}
+	return block, iv, nil
+}
+
+// pkcs12KDF derives size bytes for purpose id from the password and salt
+// (RFC 7292, appendix B.2)
+func pkcs12KDF(h func() hash.Hash, pw, salt []byte, iterations int, id byte, size int) []byte {
+	digest := h()
+	v := digest.BlockSize()
+
+	d := bytes.Repeat([]byte{id}, v)
+	i := append(fillBlocks(salt, v), fillBlocks(pw, v)...)
+
+	out := make([]byte, 0, size)
+	for {
+		digest.Reset()
+		digest.Write(d)
+		digest.Write(i)
+		a := digest.Sum(nil)
+		for range iterations - 1 {
+			digest.Reset()
+			digest.Write(a)
+			a = digest.Sum(a[:0])
+		}
+
+		out = append(out, a...)
+		if len(out) >= size {
+			return out[:size]
+		}
+
+		// I_j = (I_j + B + 1) mod 2^(8v) for each v-byte block of I
+		b := fillBlocks(a, v)
+		for j := 0; j < len(i); j += v {
+			carry := 1
+			for k := v - 1; k >= 0; k-- {
+				sum := int(i[j+k]) + int(b[k]) + carry
+				i[j+k] = byte(sum)
+				carry = sum >> 8
+			}
+		}
+	}
+}
+
+// fillBlocks repeats data to fill the smallest multiple of v bytes that
+// holds it
+func fillBlocks(data []byte, v int) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+
+	out := make([]byte, v*((len(data)+v-1)/v))
+	for k := range out {
+		out[k] = data[k%len(data)]
+	}
+	return out
+}
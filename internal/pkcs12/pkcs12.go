@@ -0,0 +1,254 @@
+// Package pkcs12 decodes the PKCS#12 (.pfx/.p12) bundles BB distributes
+// client certificates in
+// It supports the password-based encryption used by OpenSSL, Windows and
+// Java: PBES2 (PBKDF2 with AES or 3DES), pbeWithSHAAnd3-KeyTripleDES-CBC
+// and pbeWithSHAAnd40BitRC2-CBC, with SHA-1 or SHA-2 integrity MACs.
+package pkcs12
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+)
+
+// ErrIncorrectPassword is returned when the password does not open the
+// bundle
+var ErrIncorrectPassword = errors.New("pkcs12: incorrect password")
+
+var (
+	oidDataContentType          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidEncryptedDataContentType = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 6}
+
+	oidKeyBag              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 1}
+	oidPKCS8ShroudedKeyBag = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidX509CertificateBag  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+)
+
+// pfxPdu is the outer PFX structure (RFC 7292, section 4)
+type pfxPdu struct {
+	Version  int
+	AuthSafe contentInfo
+	MacData  macData `asn1:"optional"`
+}
+
+// contentInfo is a PKCS#7 ContentInfo
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"tag:0,explicit,optional"`
+}
+
+// encryptedData is a PKCS#7 EncryptedData
+type encryptedData struct {
+	Version              int
+	EncryptedContentInfo encryptedContentInfo
+}
+
+// encryptedContentInfo is a PKCS#7 EncryptedContentInfo
+type encryptedContentInfo struct {
+	ContentType                asn1.ObjectIdentifier
+	ContentEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedContent           []byte `asn1:"tag:0,optional"`
+}
+
+// macData holds the integrity MAC of the bundle
+type macData struct {
+	Mac        digestInfo
+	MacSalt    []byte
+	Iterations int `asn1:"optional,default:1"`
+}
+
+// digestInfo is a PKCS#1 DigestInfo
+type digestInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Digest    []byte
+}
+
+// safeBag is an element of a SafeContents
+type safeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue     `asn1:"tag:0,explicit"`
+	Attributes []pkcs12Attribute `asn1:"set,optional"`
+}
+
+// pkcs12Attribute is a bag attribute such as friendlyName or localKeyId
+type pkcs12Attribute struct {
+	ID    asn1.ObjectIdentifier
+	Value asn1.RawValue `asn1:"set"`
+}
+
+// certBag holds a DER certificate
+type certBag struct {
+	ID   asn1.ObjectIdentifier
+	Data []byte `asn1:"tag:0,explicit"`
+}
+
+// encryptedPrivateKeyInfo is a PKCS#8 EncryptedPrivateKeyInfo
+type encryptedPrivateKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Data      []byte
+}
+
+// Decode extracts the private key and the certificates of a PKCS#12
+// bundle. Certificates are returned in the order they are stored.
+func Decode(data []byte, password string) (crypto.PrivateKey, []*x509.Certificate, error) {
+	var pfx pfxPdu
+	if err := unmarshal(data, &pfx); err != nil {
+		return nil, nil, fmt.Errorf("pkcs12: invalid bundle: %w", err)
+	}
+	if pfx.Version != 3 {
+		return nil, nil, fmt.Errorf("pkcs12: unsupported version %d", pfx.Version)
+	}
+	if !pfx.AuthSafe.ContentType.Equal(oidDataContentType) {
+		return nil, nil, errors.New("pkcs12: only password-protected bundles are supported")
+	}
+
+	var authSafe []byte
+	if err := unmarshal(pfx.AuthSafe.Content.Bytes, &authSafe); err != nil {
+		return nil, nil, fmt.Errorf("pkcs12: invalid authenticated safe: %w", err)
+	}
+
+	pw, err := newPassword(password)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(pfx.MacData.Mac.Algorithm.Algorithm) > 0 {
+		err := verifyMAC(&pfx.MacData, authSafe, pw)
+		if errors.Is(err, ErrIncorrectPassword) && password == "" {
+			// An empty password is encoded either as an empty string or as
+			// a lone terminator, depending on the tool that built the bundle
+			pw.bmp = nil
+			err = verifyMAC(&pfx.MacData, authSafe, pw)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var contents []contentInfo
+	if err := unmarshal(authSafe, &contents); err != nil {
+		return nil, nil, fmt.Errorf("pkcs12: invalid authenticated safe: %w", err)
+	}
+
+	var (
+		key   crypto.PrivateKey
+		certs []*x509.Certificate
+	)
+	for _, ci := range contents {
+		bags, err := safeBags(ci, pw)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, bag := range bags {
+			switch {
+			case bag.ID.Equal(oidCertBag):
+				cert, err := decodeCertBag(bag.Value.Bytes)
+				if err != nil {
+					return nil, nil, err
+				}
+				if cert != nil {
+					certs = append(certs, cert)
+				}
+
+			case bag.ID.Equal(oidPKCS8ShroudedKeyBag), bag.ID.Equal(oidKeyBag):
+				if key != nil {
+					return nil, nil, errors.New("pkcs12: more than one private key found")
+				}
+				if key, err = decodeKeyBag(bag, pw); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+	}
+
+	return key, certs, nil
+}
+
+// safeBags returns the bags of a SafeContents, decrypting it if needed
+func safeBags(ci contentInfo, pw password) ([]safeBag, error) {
+	var data []byte
+
+	switch {
+	case ci.ContentType.Equal(oidDataContentType):
+		if err := unmarshal(ci.Content.Bytes, &data); err != nil {
+			return nil, fmt.Errorf("pkcs12: invalid safe contents: %w", err)
+		}
+
+	case ci.ContentType.Equal(oidEncryptedDataContentType):
+		var ed encryptedData
+		if err := unmarshal(ci.Content.Bytes, &ed); err != nil {
+			return nil, fmt.Errorf("pkcs12: invalid encrypted data: %w", err)
+		}
+
+		var err error
+		data, err = decrypt(ed.EncryptedContentInfo.ContentEncryptionAlgorithm, ed.EncryptedContentInfo.EncryptedContent, pw)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("pkcs12: unsupported content type %s", ci.ContentType)
+	}
+
+	var bags []safeBag
+	if err := unmarshal(data, &bags); err != nil {
+		return nil, fmt.Errorf("pkcs12: invalid safe contents: %w", err)
+	}
+	return bags, nil
+}
+
+// decodeCertBag parses an X.509 certificate bag; other certificate types
+// are skipped
+func decodeCertBag(data []byte) (*x509.Certificate, error) {
+	var bag certBag
+	if err := unmarshal(data, &bag); err != nil {
+		return nil, fmt.Errorf("pkcs12: invalid certificate bag: %w", err)
+	}
+	if !bag.ID.Equal(oidX509CertificateBag) {
+		return nil, nil
+	}
+
+	cert, err := x509.ParseCertificate(bag.Data)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs12: invalid certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// decodeKeyBag parses a plain or shrouded PKCS#8 key bag
+func decodeKeyBag(bag safeBag, pw password) (crypto.PrivateKey, error) {
+	der := bag.Value.Bytes
+	if bag.ID.Equal(oidPKCS8ShroudedKeyBag) {
+		var info encryptedPrivateKeyInfo
+		if err := unmarshal(der, &info); err != nil {
+			return nil, fmt.Errorf("pkcs12: invalid key bag: %w", err)
+		}
+
+		var err error
+		if der, err = decrypt(info.Algorithm, info.Data, pw); err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs12: invalid private key: %w", err)
+	}
+	return key, nil
+}
+
+// unmarshal decodes DER data into out, rejecting trailing bytes
+func unmarshal(data []byte, out any) error {
+	rest, err := asn1.Unmarshal(data, out)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return errors.New("trailing data")
+	}
+	return nil
+}
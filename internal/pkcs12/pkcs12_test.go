@@ -0,0 +1,122 @@
+package pkcs12
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		file      string
+		password  string
+		wantCerts []string
+		wantRSA   bool
+	}{
+		// OpenSSL 3 defaults: PBES2 with AES-256-CBC, SHA-256 MAC
+		{"modern.p12", "senha123", []string{"go-bb-pix test client", "Test CA"}, true},
+		// pbeWithSHAAnd3-KeyTripleDES-CBC, SHA-1 MAC
+		{"3des.p12", "senha123", []string{"go-bb-pix test client", "Test CA"}, true},
+		// OpenSSL 1.x defaults: certificates under 40-bit RC2
+		{"legacy.p12", "senha123", []string{"go-bb-pix test client"}, true},
+		// ECDSA key with an empty password
+		{"ec-nopass.p12", "", []string{"ec client"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatalf("failed to read fixture: %v", err)
+			}
+
+			key, certs, err := Decode(data, tt.password)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			var names []string
+			for _, cert := range certs {
+				names = append(names, cert.Subject.CommonName)
+			}
+			if len(names) != len(tt.wantCerts) {
+				t.Fatalf("certificates = %v, want %v", names, tt.wantCerts)
+			}
+			for i := range names {
+				if names[i] != tt.wantCerts[i] {
+					t.Errorf("certificates = %v, want %v", names, tt.wantCerts)
+				}
+			}
+
+			switch k := key.(type) {
+			case *rsa.PrivateKey:
+				if !tt.wantRSA || !k.PublicKey.Equal(certs[0].PublicKey) {
+					t.Error("RSA key does not match the leaf certificate")
+				}
+			case *ecdsa.PrivateKey:
+				if tt.wantRSA || !k.PublicKey.Equal(certs[0].PublicKey) {
+					t.Error("ECDSA key does not match the leaf certificate")
+				}
+			default:
+				t.Errorf("key type = %T", key)
+			}
+		})
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "modern.p12"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	if _, _, err := Decode(data, "wrong"); !errors.Is(err, ErrIncorrectPassword) {
+		t.Errorf("Decode(wrong password) error = %v, want ErrIncorrectPassword", err)
+	}
+	if _, _, err := Decode([]byte("not a bundle"), "senha123"); err == nil {
+		t.Error("Decode(garbage) expected error")
+	}
+	if _, _, err := Decode(data, "senha\U0001F600"); err == nil {
+		t.Error("Decode(non-BMP password) expected error")
+	}
+}
+
+func TestRC2(t *testing.T) {
+	// Test vectors from RFC 2268, section 5
+	tests := []struct {
+		key, plain, cipher string
+		bits               int
+	}{
+		{"0000000000000000", "0000000000000000", "ebb773f993278eff", 63},
+		{"ffffffffffffffff", "ffffffffffffffff", "278b27e42e2f0d49", 64},
+		{"3000000000000000", "1000000000000001", "30649edf9be7d2c2", 64},
+		{"88", "0000000000000000", "61a8a244adacccf0", 64},
+		{"88bca90e90875a", "0000000000000000", "6ccf4308974c267f", 64},
+		{"88bca90e90875a7f0f79c384627bafb2", "0000000000000000", "1a807d272bbe5db1", 64},
+		{"88bca90e90875a7f0f79c384627bafb2", "0000000000000000", "2269552ab0f85ca6", 128},
+	}
+
+	for _, tt := range tests {
+		key, _ := hex.DecodeString(tt.key)
+		plain, _ := hex.DecodeString(tt.plain)
+		want, _ := hex.DecodeString(tt.cipher)
+
+		c := newRC2Cipher(key, tt.bits)
+
+		got := make([]byte, 8)
+		c.Encrypt(got, plain)
+		if !bytes.Equal(got, want) {
+			t.Errorf("Encrypt(key %s, %d bits) = %x, want %s", tt.key, tt.bits, got, tt.cipher)
+		}
+
+		c.Decrypt(got, want)
+		if !bytes.Equal(got, plain) {
+			t.Errorf("Decrypt(key %s, %d bits) = %x, want %s", tt.key, tt.bits, got, tt.plain)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package transport
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"sync"
 	"time"
@@ -10,90 +12,221 @@ import (
 // ErrCircuitOpen is returned when the circuit breaker is open
 var ErrCircuitOpen = errors.New("circuit breaker is open")
 
-// circuitState represents the state of the circuit breaker
-type circuitState int
+// CircuitState is the state of a circuit breaker
+type CircuitState int
 
 const (
-	stateClosed circuitState = iota
-	stateOpen
-	stateHalfOpen
+	// CircuitClosed lets requests through, counting failures
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects requests with ErrCircuitOpen
+	CircuitOpen
+	// CircuitHalfOpen lets probe requests through to test the backend
+	CircuitHalfOpen
 )
 
+// String returns the name of the state
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitStateChangeFunc is called when a circuit breaker changes state,
+// with the reason of the change
+type CircuitStateChangeFunc func(from, to CircuitState, reason string)
+
+// CircuitBreakerStats describes the current state of a circuit breaker
+type CircuitBreakerStats struct {
+	// State is the state of the breaker; an open breaker whose reset timeout
+	// elapsed stays open until the next request probes the backend
+	State CircuitState
+	// Failures is the number of consecutive failures
+	Failures int
+	// UntilHalfOpen is how long an open breaker keeps rejecting requests
+	// before letting a probe through, zero when it is not open
+	UntilHalfOpen time.Duration
+}
+
+// stateChange is a state transition waiting to be reported to fn
+type stateChange struct {
+	from, to CircuitState
+	reason   string
+	fn       CircuitStateChangeFunc
+}
+
 // circuitBreaker implements the circuit breaker pattern
 type circuitBreaker struct {
 	mu            sync.RWMutex
-	state         circuitState
+	state         CircuitState
 	failureCount  int
 	maxFailures   int
 	resetTimeout  time.Duration
 	lastFailTime  time.Time
+	onStateChange CircuitStateChangeFunc
+
+	// maxProbes bounds the requests let through at once while half-open;
+	// probes counts those in flight for the current half-open period, which
+	// halfOpens numbers so late probes of a previous one are not counted
+	maxProbes int
+	probes    int
+	halfOpens uint64
 }
 
 // newCircuitBreaker creates a new circuit breaker
 func newCircuitBreaker(maxFailures int, resetTimeout time.Duration) *circuitBreaker {
 	return &circuitBreaker{
-		state:        stateClosed,
+		state:        CircuitClosed,
 		maxFailures:  maxFailures,
 		resetTimeout: resetTimeout,
+		maxProbes:    1,
 	}
 }
 
 // canExecute checks if a request can be executed
-func (cb *circuitBreaker) canExecute() error {
+// It returns the half-open period the request probes, zero when it is not a
+// probe, to pass to recordSuccess or recordFailure.
+func (cb *circuitBreaker) canExecute() (uint64, error) {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
+	var (
+		change *stateChange
+		probe  uint64
+		err    error
+	)
 
 	switch cb.state {
-	case stateClosed:
-		return nil
+	case CircuitClosed:
 
-	case stateOpen:
+	case CircuitOpen:
 		// Check if it's time to transition to half-open
 		if time.Since(cb.lastFailTime) > cb.resetTimeout {
-			cb.state = stateHalfOpen
-			return nil
+			change = cb.setState(CircuitHalfOpen, "reset timeout elapsed")
+			probe = cb.startProbe()
+		} else {
+			err = ErrCircuitOpen
 		}
-		return ErrCircuitOpen
 
-	case stateHalfOpen:
-		// Allow one request in half-open state
-		return nil
+	case CircuitHalfOpen:
+		// Allow a limited number of probes in half-open state
+		if cb.probes < cb.maxProbes {
+			probe = cb.startProbe()
+		} else {
+			err = ErrCircuitOpen
+		}
 
 	default:
-		return ErrCircuitOpen
+		err = ErrCircuitOpen
 	}
+
+	cb.mu.Unlock()
+	cb.notify(change)
+	return probe, err
 }
 
-// recordSuccess records a successful request
-func (cb *circuitBreaker) recordSuccess() {
+// startProbe counts a probe request of the current half-open period;
+// cb.mu must be held
+func (cb *circuitBreaker) startProbe() uint64 {
+	cb.probes++
+	return cb.halfOpens
+}
+
+// endProbe releases the slot of a finished probe request; cb.mu must be
+// held
+func (cb *circuitBreaker) endProbe(probe uint64) {
+	if probe != 0 && probe == cb.halfOpens && cb.state == CircuitHalfOpen {
+		cb.probes--
+	}
+}
+
+// recordAbandoned records a request the caller cancelled, which says
+// nothing about the health of the backend
+func (cb *circuitBreaker) recordAbandoned(probe uint64) {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
+	cb.endProbe(probe)
+}
+
+// recordSuccess records a successful request
+func (cb *circuitBreaker) recordSuccess(probe uint64) {
+	cb.mu.Lock()
+	var change *stateChange
+
+	cb.endProbe(probe)
+
 	cb.failureCount = 0
 
 	// If we were half-open and succeeded, close the circuit
-	if cb.state == stateHalfOpen {
-		cb.state = stateClosed
+	if cb.state == CircuitHalfOpen {
+		change = cb.setState(CircuitClosed, "probe request succeeded")
 	}
+
+	cb.mu.Unlock()
+	cb.notify(change)
 }
 
 // recordFailure records a failed request
-func (cb *circuitBreaker) recordFailure() {
+func (cb *circuitBreaker) recordFailure(probe uint64) {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
+	var change *stateChange
+
+	cb.endProbe(probe)
 
 	cb.failureCount++
 	cb.lastFailTime = time.Now()
 
-	// If we're half-open and failed, reopen the circuit
-	if cb.state == stateHalfOpen {
-		cb.state = stateOpen
-		return
+	switch {
+	case cb.state == CircuitHalfOpen:
+		// If we're half-open and failed, reopen the circuit
+		change = cb.setState(CircuitOpen, "probe request failed")
+
+	case cb.state == CircuitClosed && cb.failureCount >= cb.maxFailures:
+		// Open circuit if we've hit max failures
+		change = cb.setState(CircuitOpen, fmt.Sprintf("%d consecutive failures", cb.failureCount))
 	}
 
-	// Open circuit if we've hit max failures
-	if cb.failureCount >= cb.maxFailures {
-		cb.state = stateOpen
+	cb.mu.Unlock()
+	cb.notify(change)
+}
+
+// stats returns the current state of the breaker
+func (cb *circuitBreaker) stats() CircuitBreakerStats {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+
+	stats := CircuitBreakerStats{State: cb.state, Failures: cb.failureCount}
+	if cb.state == CircuitOpen {
+		stats.UntilHalfOpen = max(0, cb.resetTimeout-time.Since(cb.lastFailTime))
+	}
+	return stats
+}
+
+// setState moves the breaker to state and returns the change to report
+// once cb.mu is released, so the callback may use the breaker; cb.mu must
+// be held
+func (cb *circuitBreaker) setState(state CircuitState, reason string) *stateChange {
+	if cb.state == state {
+		return nil
+	}
+	change := &stateChange{from: cb.state, to: state, reason: reason, fn: cb.onStateChange}
+	cb.state = state
+	if state == CircuitHalfOpen {
+		cb.halfOpens++
+		cb.probes = 0
+	}
+	return change
+}
+
+// notify reports a state change to its callback
+func (cb *circuitBreaker) notify(change *stateChange) {
+	if change != nil && change.fn != nil {
+		change.fn(change.from, change.to, change.reason)
 	}
 }
 
@@ -115,28 +248,89 @@ func NewCircuitBreakerTransport(base http.RoundTripper, maxFailures int, resetTi
 	}
 }
 
+// OnStateChange sets a function called whenever the breaker opens, closes
+// or becomes half-open, e.g. to raise an alert
+// It is called synchronously by the request that caused the change, so it
+// should not block.
+func (t *CircuitBreakerTransport) OnStateChange(fn CircuitStateChangeFunc) {
+	t.breaker.mu.Lock()
+	defer t.breaker.mu.Unlock()
+	t.breaker.onStateChange = fn
+}
+
+// SetMaxHalfOpenProbes sets how many requests the half-open breaker lets
+// through at once to probe the backend; the others fail with ErrCircuitOpen
+// until a probe closes or reopens the circuit
+// Default: 1. Values below 1 are treated as 1.
+func (t *CircuitBreakerTransport) SetMaxHalfOpenProbes(n int) {
+	t.breaker.mu.Lock()
+	defer t.breaker.mu.Unlock()
+	t.breaker.maxProbes = max(1, n)
+}
+
+// Stats returns the current state of the breaker, e.g. for a health
+// endpoint
+func (t *CircuitBreakerTransport) Stats() CircuitBreakerStats {
+	return t.breaker.stats()
+}
+
+// State returns the current state of the breaker
+func (t *CircuitBreakerTransport) State() CircuitState {
+	return t.breaker.stats().State
+}
+
+// FailureCount returns the number of consecutive failures
+func (t *CircuitBreakerTransport) FailureCount() int {
+	return t.breaker.stats().Failures
+}
+
+// TimeUntilHalfOpen returns how long the open breaker keeps rejecting
+// requests, zero when it is not open
+func (t *CircuitBreakerTransport) TimeUntilHalfOpen() time.Duration {
+	return t.breaker.stats().UntilHalfOpen
+}
+
 // RoundTrip implements http.RoundTripper with circuit breaker logic
 func (t *CircuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	// Check if we can execute the request
-	if err := t.breaker.canExecute(); err != nil {
+	probe, err := t.breaker.canExecute()
+	if err != nil {
 		return nil, err
 	}
 
 	// Execute request
 	resp, err := t.base.RoundTrip(req)
 
+	// A request cancelled or timed out by its caller is neither a failure
+	// nor a success
+	if isCanceled(req, err) {
+		t.breaker.recordAbandoned(probe)
+		return resp, err
+	}
+
 	// Check if request failed
 	if isCircuitBreakerFailure(resp, err) {
-		t.breaker.recordFailure()
+		t.breaker.recordFailure(probe)
 		return resp, err
 	}
 
 	// Request succeeded
-	t.breaker.recordSuccess()
+	t.breaker.recordSuccess(probe)
 	return resp, err
 }
 
+// isCanceled reports whether a request failed because its context was
+// cancelled or its deadline exceeded
+func isCanceled(req *http.Request, err error) bool {
+	if err == nil {
+		return false
+	}
+	return req.Context().Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
 // isCircuitBreakerFailure determines if a response/error should be counted as a failure
+// Client errors, e.g. a 403 for a scope missing from the token, are not:
+// they point to the request or the configuration, not to an unavailable API.
 func isCircuitBreakerFailure(resp *http.Response, err error) bool {
 	// Network errors are failures
 	if err != nil {
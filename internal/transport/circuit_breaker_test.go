@@ -1,9 +1,13 @@
 package transport
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -324,6 +328,7 @@ func TestCircuitBreaker_5xxErrors(t *testing.T) {
 		{"504 Gateway Timeout", http.StatusGatewayTimeout, true},
 		{"200 OK", http.StatusOK, false},
 		{"400 Bad Request", http.StatusBadRequest, false},
+		{"403 Forbidden", http.StatusForbidden, false},
 		{"404 Not Found", http.StatusNotFound, false},
 	}
 
@@ -363,3 +368,273 @@ func TestCircuitBreaker_5xxErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestCircuitBreaker_OnStateChange(t *testing.T) {
+	fail := true
+	base := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			if fail {
+				return nil, errors.New("service unavailable")
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: make(http.Header)}, nil
+		},
+	}
+
+	resetTimeout := 50 * time.Millisecond
+	transport := NewCircuitBreakerTransport(base, 2, resetTimeout)
+
+	var changes []string
+	transport.OnStateChange(func(from, to CircuitState, reason string) {
+		changes = append(changes, fmt.Sprintf("%s->%s: %s", from, to, reason))
+	})
+
+	roundTrip := func() {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+		transport.RoundTrip(req)
+	}
+
+	// Open, probe and fail, probe and succeed
+	roundTrip()
+	roundTrip()
+	roundTrip()
+	time.Sleep(resetTimeout + 20*time.Millisecond)
+	roundTrip()
+	time.Sleep(resetTimeout + 20*time.Millisecond)
+	fail = false
+	roundTrip()
+	roundTrip()
+
+	want := []string{
+		"closed->open: 2 consecutive failures",
+		"open->half-open: reset timeout elapsed",
+		"half-open->open: probe request failed",
+		"open->half-open: reset timeout elapsed",
+		"half-open->closed: probe request succeeded",
+	}
+	if strings.Join(changes, "\n") != strings.Join(want, "\n") {
+		t.Errorf("state changes = %q, want %q", changes, want)
+	}
+}
+
+func TestCircuitState_String(t *testing.T) {
+	tests := map[CircuitState]string{
+		CircuitClosed:   "closed",
+		CircuitOpen:     "open",
+		CircuitHalfOpen: "half-open",
+		CircuitState(9): "unknown",
+	}
+	for state, want := range tests {
+		if got := state.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestCircuitBreakerTransport_Stats(t *testing.T) {
+	base := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("service unavailable")
+		},
+	}
+
+	transport := NewCircuitBreakerTransport(base, 2, time.Minute)
+
+	// The hook may inspect the breaker
+	var statsInHook CircuitBreakerStats
+	transport.OnStateChange(func(from, to CircuitState, reason string) {
+		statsInHook = transport.Stats()
+	})
+
+	if stats := transport.Stats(); stats != (CircuitBreakerStats{State: CircuitClosed}) {
+		t.Errorf("initial Stats() = %+v, want closed without failures", stats)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+	transport.RoundTrip(req)
+	if transport.State() != CircuitClosed || transport.FailureCount() != 1 {
+		t.Errorf("after 1 failure: State() = %v, FailureCount() = %d, want closed, 1", transport.State(), transport.FailureCount())
+	}
+	if transport.TimeUntilHalfOpen() != 0 {
+		t.Errorf("TimeUntilHalfOpen() = %v, want 0 while closed", transport.TimeUntilHalfOpen())
+	}
+
+	transport.RoundTrip(req)
+	if transport.State() != CircuitOpen || transport.FailureCount() != 2 {
+		t.Errorf("after 2 failures: State() = %v, FailureCount() = %d, want open, 2", transport.State(), transport.FailureCount())
+	}
+	if d := transport.TimeUntilHalfOpen(); d <= 59*time.Second || d > time.Minute {
+		t.Errorf("TimeUntilHalfOpen() = %v, want about 1m", d)
+	}
+	if statsInHook.State != CircuitOpen {
+		t.Errorf("Stats() in hook = %+v, want the new state", statsInHook)
+	}
+}
+
+func TestCircuitBreaker_MaxHalfOpenProbes(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxProbes int
+		want      int
+	}{
+		{"default", 0, 1},
+		{"several", 3, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu      sync.Mutex
+				calls   int
+				fail    = true
+				release = make(chan struct{})
+			)
+			base := &mockRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					mu.Lock()
+					calls++
+					failing := fail
+					mu.Unlock()
+					if failing {
+						return nil, errors.New("service unavailable")
+					}
+					<-release
+					return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: make(http.Header)}, nil
+				},
+			}
+
+			resetTimeout := 20 * time.Millisecond
+			transport := NewCircuitBreakerTransport(base, 1, resetTimeout)
+			if tt.maxProbes > 0 {
+				transport.SetMaxHalfOpenProbes(tt.maxProbes)
+			}
+
+			// Open the circuit, then let it become half-open
+			transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://example.com", nil))
+			time.Sleep(resetTimeout + 10*time.Millisecond)
+			mu.Lock()
+			fail, calls = false, 0
+			mu.Unlock()
+
+			const concurrent = 6
+			var wg sync.WaitGroup
+			errs := make(chan error, concurrent)
+			for range concurrent {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://example.com", nil))
+					errs <- err
+				}()
+			}
+
+			// The rejected requests return at once, the probes wait
+			for range concurrent - tt.want {
+				if err := <-errs; !errors.Is(err, ErrCircuitOpen) {
+					t.Errorf("RoundTrip() error = %v, want ErrCircuitOpen", err)
+				}
+			}
+			close(release)
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				if err != nil {
+					t.Errorf("probe RoundTrip() error = %v", err)
+				}
+			}
+
+			if calls != tt.want {
+				t.Errorf("probes sent = %d, want %d", calls, tt.want)
+			}
+			if transport.State() != CircuitClosed {
+				t.Errorf("State() = %v, want closed after the probes succeeded", transport.State())
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_IgnoresCanceledRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		newCtx func() (context.Context, context.CancelFunc)
+		err    error
+	}{
+		{
+			name:   "cancelled context",
+			newCtx: func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			err:    context.Canceled,
+		},
+		{
+			name: "deadline exceeded",
+			newCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Nanosecond)
+			},
+			err: context.DeadlineExceeded,
+		},
+		{
+			name:   "wrapped context error",
+			newCtx: func() (context.Context, context.CancelFunc) { return context.Background(), func() {} },
+			err:    fmt.Errorf("read tcp: %w", context.Canceled),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := &mockRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					return nil, tt.err
+				},
+			}
+			transport := NewCircuitBreakerTransport(base, 2, time.Minute)
+
+			for range 3 {
+				ctx, cancel := tt.newCtx()
+				cancel()
+				req := httptest.NewRequest(http.MethodGet, "http://example.com", nil).WithContext(ctx)
+				if _, err := transport.RoundTrip(req); !errors.Is(err, tt.err) {
+					t.Errorf("RoundTrip() error = %v, want %v", err, tt.err)
+				}
+			}
+
+			if stats := transport.Stats(); stats.State != CircuitClosed || stats.Failures != 0 {
+				t.Errorf("Stats() = %+v, want closed without failures", stats)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_CanceledProbeReleasesSlot(t *testing.T) {
+	var fail, cancelled bool
+	base := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			switch {
+			case fail:
+				return nil, errors.New("service unavailable")
+			case cancelled:
+				return nil, context.Canceled
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: make(http.Header)}, nil
+		},
+	}
+
+	resetTimeout := 20 * time.Millisecond
+	transport := NewCircuitBreakerTransport(base, 1, resetTimeout)
+
+	fail = true
+	transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://example.com", nil))
+	time.Sleep(resetTimeout + 10*time.Millisecond)
+
+	// The cancelled probe leaves the circuit half-open for the next one
+	fail, cancelled = false, true
+	transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://example.com", nil))
+	if transport.State() != CircuitHalfOpen {
+		t.Fatalf("State() = %v, want half-open after a cancelled probe", transport.State())
+	}
+
+	cancelled = false
+	if _, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://example.com", nil)); err != nil {
+		t.Fatalf("RoundTrip() error = %v, want the next probe let through", err)
+	}
+	if transport.State() != CircuitClosed {
+		t.Errorf("State() = %v, want closed", transport.State())
+	}
+}
@@ -0,0 +1,286 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pericles-luz/go-bb-pix/redact"
+)
+
+// DefaultDebugBodyLimit is the number of bytes of each body logged by a
+// DebugTransport by default
+const DefaultDebugBodyLimit = 16 << 10
+
+// DebugTransport is an http.RoundTripper that logs the headers and bodies
+// of every attempt at debug level, to troubleshoot schema mismatches
+// Secrets and personal data are masked: the Authorization, cookie and app
+// key headers, client_secret and tokens, CPFs and CNPJs and the
+// pixCopiaECola payloads.
+type DebugTransport struct {
+	base      http.RoundTripper
+	logger    *slog.Logger
+	limit     int
+	sensitive map[string]bool
+}
+
+// NewDebugTransport creates a new DebugTransport logging up to limit bytes
+// of each body; limit <= 0 means DefaultDebugBodyLimit
+func NewDebugTransport(base http.RoundTripper, logger *slog.Logger, limit int) *DebugTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if limit <= 0 {
+		limit = DefaultDebugBodyLimit
+	}
+
+	t := &DebugTransport{
+		base:      base,
+		logger:    logger,
+		limit:     limit,
+		sensitive: make(map[string]bool),
+	}
+	t.RedactHeaders("Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", DefaultAppKeyHeader)
+	return t
+}
+
+// RedactHeaders masks the values of the named headers in the logs, in
+// addition to the default ones
+func (t *DebugTransport) RedactHeaders(names ...string) {
+	for _, name := range names {
+		t.sensitive[http.CanonicalHeaderKey(name)] = true
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *DebugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if !t.logger.Enabled(ctx, slog.LevelDebug) {
+		return t.base.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		// Only the body is replaced, the headers are left shared
+		r := new(http.Request)
+		*r = *req
+		req = r
+		body, req.Body = t.peek(req.Body)
+	}
+	t.log(ctx, "HTTP request body",
+		slog.String("method", req.Method),
+		slog.String("url", t.redactURL(req.URL)),
+		t.headerAttr(req.Header),
+		slog.String("body", t.redactBody(req.Header, body)),
+	)
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, resp.Body = t.peek(resp.Body)
+	t.log(ctx, "HTTP response body",
+		slog.String("method", req.Method),
+		slog.String("url", t.redactURL(req.URL)),
+		slog.Int("status", resp.StatusCode),
+		t.headerAttr(resp.Header),
+		slog.String("body", t.redactBody(resp.Header, body)),
+	)
+	return resp, nil
+}
+
+// log logs a debug record with the request ID of ctx
+func (t *DebugTransport) log(ctx context.Context, msg string, attrs ...slog.Attr) {
+	if id := RequestID(ctx); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	t.logger.LogAttrs(ctx, slog.LevelDebug, msg, attrs...)
+}
+
+// peek reads up to limit+1 bytes of body and returns them with a body that
+// still yields the whole content
+func (t *DebugTransport) peek(body io.ReadCloser) ([]byte, io.ReadCloser) {
+	if body == nil || body == http.NoBody {
+		return nil, body
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, int64(t.limit)+1))
+	rest := io.MultiReader(bytes.NewReader(data), body)
+	if err != nil {
+		// Let the reader of the body see the error
+		rest = io.MultiReader(bytes.NewReader(data), errReader{err})
+	}
+	return data, struct {
+		io.Reader
+		io.Closer
+	}{rest, body}
+}
+
+// errReader is a reader that always fails with err
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+// headerAttr returns the headers as a log group, with sensitive values
+// masked
+func (t *DebugTransport) headerAttr(header http.Header) slog.Attr {
+	attrs := make([]any, 0, len(header))
+	for name, values := range header {
+		value := strings.Join(values, ", ")
+		if t.sensitive[name] {
+			value = redact.Mask
+		}
+		attrs = append(attrs, slog.String(name, value))
+	}
+	return slog.Group("headers", attrs...)
+}
+
+// redactURL returns u with the values of sensitive query parameters masked
+func (t *DebugTransport) redactURL(u *url.URL) string {
+	query := u.Query()
+	if len(query) == 0 {
+		return u.String()
+	}
+
+	redacted := false
+	for key := range query {
+		if t.sensitive[http.CanonicalHeaderKey(key)] || sensitiveFields[strings.ToLower(key)] != nil {
+			query[key] = []string{redact.Mask}
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u.String()
+	}
+
+	masked := *u
+	masked.RawQuery = query.Encode()
+	return masked.String()
+}
+
+// sensitiveFields maps the lower-case names of the body fields masked in the
+// debug logs to their replacement
+var sensitiveFields = map[string]func(string) string{
+	"client_secret": maskAll,
+	"access_token":  maskAll,
+	"refresh_token": maskAll,
+	"id_token":      maskAll,
+	"password":      maskAll,
+	"pixcopiaecola": maskAll,
+	"cpf":           redact.MaskDigits(2),
+	"cnpj":          redact.MaskDigits(2),
+}
+
+// maskAll replaces a value with redact.Mask
+func maskAll(string) string {
+	return redact.Mask
+}
+
+// documentRedactor masks the CPFs and CNPJs found in free text
+var documentRedactor = redact.New(
+	redact.CPF(),
+	redact.Pattern(regexp.MustCompile(`\b\d{2}\.?\d{3}\.?\d{3}/?\d{4}-?\d{2}\b`), redact.MaskDigits(2)),
+)
+
+// sensitiveJSONField matches the sensitive fields of JSON documents that
+// could not be parsed, e.g. truncated ones
+var sensitiveJSONField = regexp.MustCompile(`(?i)"(client_secret|access_token|refresh_token|id_token|password|pixCopiaECola|cpf|cnpj)"\s*:\s*"(?:[^"\\]|\\.)*"?`)
+
+// redactBody returns the body to log, with its sensitive fields masked
+func (t *DebugTransport) redactBody(header http.Header, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	truncated := len(body) > t.limit
+	if truncated {
+		body = body[:t.limit]
+	}
+
+	var s string
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		s = redactForm(body)
+	case !truncated && json.Valid(body):
+		s = redactJSON(body)
+	default:
+		s = sensitiveJSONField.ReplaceAllStringFunc(string(body), func(match string) string {
+			key, _, _ := strings.Cut(match, ":")
+			return key + `:"` + redact.Mask + `"`
+		})
+	}
+
+	s = documentRedactor.String(s)
+	if truncated {
+		s += "...(truncated)"
+	}
+	return s
+}
+
+// redactForm masks the sensitive fields of a form-encoded body
+func redactForm(body []byte) string {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return redact.Mask
+	}
+
+	for key, values := range form {
+		if replace := sensitiveFields[strings.ToLower(key)]; replace != nil {
+			for i, v := range values {
+				values[i] = replace(v)
+			}
+		}
+	}
+	return form.Encode()
+}
+
+// redactJSON masks the sensitive fields of a valid JSON body
+func redactJSON(body []byte) string {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return redact.Mask
+	}
+
+	data, err := json.Marshal(redactValue(doc))
+	if err != nil {
+		return redact.Mask
+	}
+	return string(data)
+}
+
+// redactValue masks the sensitive fields of a decoded JSON value
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if replace := sensitiveFields[strings.ToLower(key)]; replace != nil {
+				if s, ok := item.(string); ok {
+					v[key] = replace(s)
+					continue
+				}
+				if n, ok := item.(json.Number); ok {
+					v[key] = replace(n.String())
+					continue
+				}
+			}
+			v[key] = redactValue(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return v
+}
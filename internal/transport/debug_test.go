@@ -0,0 +1,160 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugTransport_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	respBody := `{"txid":"abc","pixCopiaECola":"00020101021226...6304ABCD","devedor":{"cpf":"12345678909","nome":"Fulano"}}`
+	var sent string
+	base := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			data, _ := io.ReadAll(req.Body)
+			sent = string(data)
+			header := make(http.Header)
+			header.Set("Content-Type", "application/json")
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(respBody)),
+				Header:     header,
+			}, nil
+		},
+	}
+	transport := NewDebugTransport(base, logger, 0)
+
+	reqBody := `{"devedor":{"cnpj":"12.345.678/0001-95"},"solicitacaoPagador":"CPF 123.456.789-09"}`
+	req := httptest.NewRequest(http.MethodPut, "http://example.com/pix-bb/v1/cob/abc?gw-dev-app-key=secret-key", strings.NewReader(reqBody))
+	req.Header.Set("Authorization", "Bearer secret-token")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	got, _ := io.ReadAll(resp.Body)
+	if string(got) != respBody {
+		t.Errorf("response body = %s, want %s", got, respBody)
+	}
+	if sent != reqBody {
+		t.Errorf("sent body = %s, want %s", sent, reqBody)
+	}
+
+	logs := buf.String()
+	for _, secret := range []string{"secret-token", "secret-key", "12345678909", "678/0001", "123.456.789", "6304ABCD"} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("logs contain %q:\n%s", secret, logs)
+		}
+	}
+
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logs), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("failed to parse log line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("log entries = %d, want 2", len(entries))
+	}
+
+	reqEntry, respEntry := entries[0], entries[1]
+	if reqEntry["level"] != "DEBUG" || reqEntry["msg"] != "HTTP request body" {
+		t.Errorf("request entry = %v", reqEntry)
+	}
+	if want := `{"devedor":{"cnpj":"**.***.***/****-95"},"solicitacaoPagador":"CPF ***.***.***-09"}`; reqEntry["body"] != want {
+		t.Errorf("request body = %v, want %s", reqEntry["body"], want)
+	}
+	if headers, _ := reqEntry["headers"].(map[string]any); headers["Authorization"] != "[REDACTED]" || headers["Content-Type"] != "application/json" {
+		t.Errorf("request headers = %v", reqEntry["headers"])
+	}
+	if want := `{"devedor":{"cpf":"*********09","nome":"Fulano"},"pixCopiaECola":"[REDACTED]","txid":"abc"}`; respEntry["body"] != want {
+		t.Errorf("response body = %v, want %s", respEntry["body"], want)
+	}
+	if respEntry["status"] != float64(http.StatusOK) {
+		t.Errorf("response status = %v, want 200", respEntry["status"])
+	}
+}
+
+func TestDebugTransport_RedactBody(t *testing.T) {
+	transport := NewDebugTransport(&mockRoundTripper{}, slog.Default(), 60)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{
+			name:        "form",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "grant_type=client_credentials&client_secret=s3cr3t",
+			want:        "client_secret=%5BREDACTED%5D&grant_type=client_credentials",
+		},
+		{
+			name:        "token response",
+			contentType: "application/json",
+			body:        `{"access_token":"eyJhbGciOi","expires_in":600}`,
+			want:        `{"access_token":"[REDACTED]","expires_in":600}`,
+		},
+		{
+			name:        "truncated json",
+			contentType: "application/json",
+			body:        `{"cpf":"12345678909","pixCopiaECola":"00020101021226830014br.gov.bcb.pix"}`,
+			want:        `{"cpf":"[REDACTED]","pixCopiaECola":"[REDACTED]"...(truncated)`,
+		},
+		{
+			name:        "text",
+			contentType: "text/plain",
+			body:        "CPF inválido: 12345678909",
+			want:        "CPF inválido: *********09",
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := make(http.Header)
+			header.Set("Content-Type", tt.contentType)
+			if got := transport.redactBody(header, []byte(tt.body)); got != tt.want {
+				t.Errorf("redactBody() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDebugTransport_DebugDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	body := io.NopCloser(strings.NewReader(`{"txid":"abc"}`))
+	base := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: body, Header: make(http.Header)}, nil
+		},
+	}
+	transport := NewDebugTransport(base, logger, 0)
+
+	resp, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://example.com", nil))
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if resp.Body != body {
+		t.Error("response body wrapped with the debug level disabled")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("logs = %s, want none", buf.String())
+	}
+}
@@ -0,0 +1,107 @@
+package transport
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/internal/auth"
+)
+
+// driftSmoothing is the weight of the latest sample in the exponentially
+// weighted average drift
+const driftSmoothing = 0.2
+
+// TokenRejection describes a 401 response received for a token the client
+// still considered valid
+type TokenRejection struct {
+	IssuedAt   time.Time
+	ExpiresAt  time.Time
+	RejectedAt time.Time
+
+	// Drift is how long before its declared expiry (expires_in) the token
+	// was rejected. Large values point to clock drift or to the gateway
+	// revoking tokens early.
+	Drift time.Duration
+}
+
+// TokenDriftStats summarizes the token rejections observed by a transport
+type TokenDriftStats struct {
+	// Rejections is the number of 401 responses received with a token
+	Rejections int64
+	// LastDrift is the drift of the latest rejection
+	LastDrift time.Duration
+	// AverageDrift is the exponentially weighted average drift, favoring
+	// recent rejections
+	AverageDrift time.Duration
+	// LastRejectedAt is when the latest rejection happened
+	LastRejectedAt time.Time
+}
+
+// TokenRejectedFunc is called for every rejection of a token the client
+// still considered valid
+type TokenRejectedFunc func(ctx context.Context, rejection TokenRejection)
+
+// driftTracker records token rejections and warns about them with
+// exponentially spaced logs, so a 401 storm produces a handful of warnings
+// instead of one per request
+type driftTracker struct {
+	mu     sync.Mutex
+	stats  TokenDriftStats
+	logger *slog.Logger
+	hook   TokenRejectedFunc
+	now    func() time.Time
+}
+
+// record registers the rejection of token. Tokens without an expiry or
+// already expired by the client's own rule (auth.Token.IsExpired) are
+// ignored, since their rejection says nothing about drift.
+func (d *driftTracker) record(ctx context.Context, token *auth.Token) {
+	now := d.now()
+	if token == nil || token.ExpiresIn <= 0 || token.ExpiresAt().Sub(now) < auth.ExpiryMargin {
+		return
+	}
+
+	rejection := TokenRejection{
+		IssuedAt:   token.IssuedAt,
+		ExpiresAt:  token.ExpiresAt(),
+		RejectedAt: now,
+		Drift:      token.ExpiresAt().Sub(now),
+	}
+
+	d.mu.Lock()
+	d.stats.Rejections++
+	d.stats.LastDrift = rejection.Drift
+	d.stats.LastRejectedAt = now
+	if d.stats.Rejections == 1 {
+		d.stats.AverageDrift = rejection.Drift
+	} else {
+		d.stats.AverageDrift += time.Duration(driftSmoothing * float64(rejection.Drift-d.stats.AverageDrift))
+	}
+	stats := d.stats
+	logger, hook := d.logger, d.hook
+	d.mu.Unlock()
+
+	// Log the 1st, 2nd, 4th, 8th... rejection
+	if logger != nil && stats.Rejections&(stats.Rejections-1) == 0 {
+		logger.WarnContext(ctx, "OAuth2 token rejected by the API",
+			slog.Duration("drift", rejection.Drift),
+			slog.Duration("average_drift", stats.AverageDrift),
+			slog.Int64("rejections", stats.Rejections),
+			slog.Time("issued_at", rejection.IssuedAt),
+			slog.Time("expires_at", rejection.ExpiresAt),
+		)
+	}
+
+	if hook != nil {
+		hook(ctx, rejection)
+	}
+}
+
+// snapshot returns the current statistics
+func (d *driftTracker) snapshot() TokenDriftStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stats
+}
@@ -0,0 +1,144 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/internal/auth"
+)
+
+func TestAuthTransport_TracksTokenDrift(t *testing.T) {
+	issuedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	provider := &mockTokenProvider{
+		token: &auth.Token{AccessToken: "token", TokenType: "Bearer", ExpiresIn: 600, IssuedAt: issuedAt},
+	}
+	base := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusUnauthorized, Body: http.NoBody}, nil
+		},
+	}
+
+	var logs bytes.Buffer
+	var rejections []TokenRejection
+
+	transport := NewAuthTransport(base, provider, "app-key")
+	transport.SetLogger(slog.New(slog.NewTextHandler(&logs, nil)))
+	transport.OnTokenRejected(func(ctx context.Context, r TokenRejection) {
+		rejections = append(rejections, r)
+	})
+
+	// Rejected 2 minutes and then 4 minutes after issue of a 10-minute token
+	now := issuedAt.Add(2 * time.Minute)
+	transport.drift.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		if i == 1 {
+			now = issuedAt.Add(4 * time.Minute)
+		}
+		req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+		if _, err := transport.RoundTrip(req); err != nil {
+			t.Fatalf("RoundTrip() error = %v", err)
+		}
+	}
+
+	if len(rejections) != 5 {
+		t.Fatalf("hook calls = %d, want 5", len(rejections))
+	}
+	if rejections[0].Drift != 8*time.Minute {
+		t.Errorf("first drift = %v, want 8m", rejections[0].Drift)
+	}
+
+	stats := transport.TokenDrift()
+	if stats.Rejections != 5 {
+		t.Errorf("Rejections = %d, want 5", stats.Rejections)
+	}
+	if stats.LastDrift != 6*time.Minute {
+		t.Errorf("LastDrift = %v, want 6m", stats.LastDrift)
+	}
+	if stats.AverageDrift <= 6*time.Minute || stats.AverageDrift >= 8*time.Minute {
+		t.Errorf("AverageDrift = %v, want between 6m and 8m", stats.AverageDrift)
+	}
+	if !stats.LastRejectedAt.Equal(now) {
+		t.Errorf("LastRejectedAt = %v, want %v", stats.LastRejectedAt, now)
+	}
+
+	// Warnings are logged on the 1st, 2nd and 4th rejection only
+	if got := strings.Count(logs.String(), "OAuth2 token rejected"); got != 3 {
+		t.Errorf("warnings = %d, want 3\n%s", got, logs.String())
+	}
+	if provider.invalidateCount != 5 {
+		t.Errorf("invalidateCount = %d, want 5", provider.invalidateCount)
+	}
+}
+
+func TestAuthTransport_TokenDrift_NoRejections(t *testing.T) {
+	provider := &mockTokenProvider{token: &auth.Token{AccessToken: "token", ExpiresIn: 600, IssuedAt: time.Now()}}
+	transport := NewAuthTransport(&mockRoundTripper{}, provider, "app-key")
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+
+	if stats := transport.TokenDrift(); stats != (TokenDriftStats{}) {
+		t.Errorf("TokenDrift() = %+v, want zero", stats)
+	}
+}
+
+func TestAuthTransport_TokenDrift_IgnoresExpiredTokens(t *testing.T) {
+	issuedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		token *auth.Token
+	}{
+		{
+			name:  "expired",
+			token: &auth.Token{AccessToken: "token", ExpiresIn: 600, IssuedAt: issuedAt.Add(-time.Hour)},
+		},
+		{
+			name:  "within expiry margin",
+			token: &auth.Token{AccessToken: "token", ExpiresIn: 600, IssuedAt: issuedAt.Add(-8 * time.Minute)},
+		},
+		{
+			name:  "no expiry",
+			token: &auth.Token{AccessToken: "token", IssuedAt: issuedAt},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &mockTokenProvider{token: tt.token}
+			base := &mockRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					return &http.Response{StatusCode: http.StatusUnauthorized, Body: http.NoBody}, nil
+				},
+			}
+
+			hookCalls := 0
+			transport := NewAuthTransport(base, provider, "app-key")
+			transport.OnTokenRejected(func(ctx context.Context, r TokenRejection) { hookCalls++ })
+			transport.drift.now = func() time.Time { return issuedAt }
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+			if _, err := transport.RoundTrip(req); err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+
+			if stats := transport.TokenDrift(); stats != (TokenDriftStats{}) {
+				t.Errorf("TokenDrift() = %+v, want zero", stats)
+			}
+			if hookCalls != 0 {
+				t.Errorf("hook calls = %d, want 0", hookCalls)
+			}
+			if provider.invalidateCount != 1 {
+				t.Errorf("invalidateCount = %d, want 1", provider.invalidateCount)
+			}
+		})
+	}
+}
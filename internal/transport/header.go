@@ -0,0 +1,45 @@
+package transport
+
+import (
+	"net/http"
+	"slices"
+)
+
+// HeaderTransport is an http.RoundTripper that adds static headers, such as
+// User-Agent, to every request
+type HeaderTransport struct {
+	base   http.RoundTripper
+	header http.Header
+}
+
+// NewHeaderTransport creates a new HeaderTransport adding header to the
+// requests; a header already set on a request is kept
+func NewHeaderTransport(base http.RoundTripper, header http.Header) *HeaderTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &HeaderTransport{
+		base:   base,
+		header: header.Clone(),
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *HeaderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var owned bool
+	for name, values := range t.header {
+		if _, ok := req.Header[name]; ok {
+			continue
+		}
+
+		// Avoid modifying the original request
+		if !owned {
+			req = ownHeader(req)
+			owned = true
+		}
+		req.Header[name] = slices.Clip(values)
+	}
+
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,59 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHeaderTransport(t *testing.T) {
+	header := http.Header{}
+	header.Set("User-Agent", "go-bb-pix/1.0.0")
+	header.Set("X-Tenant", "acme")
+
+	tests := []struct {
+		name       string
+		reqHeader  http.Header
+		wantAgent  string
+		wantTenant string
+	}{
+		{name: "adds headers", wantAgent: "go-bb-pix/1.0.0", wantTenant: "acme"},
+		{
+			name:       "keeps request headers",
+			reqHeader:  http.Header{"X-Tenant": []string{"other"}},
+			wantAgent:  "go-bb-pix/1.0.0",
+			wantTenant: "other",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got http.Header
+			base := &mockRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					got = req.Header
+					return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: make(http.Header)}, nil
+				},
+			}
+			transport := NewHeaderTransport(base, header)
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+			for name, values := range tt.reqHeader {
+				req.Header[name] = values
+			}
+			if _, err := transport.RoundTrip(req); err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+
+			if agent := got.Get("User-Agent"); agent != tt.wantAgent {
+				t.Errorf("User-Agent = %q, want %q", agent, tt.wantAgent)
+			}
+			if tenant := got.Get("X-Tenant"); tenant != tt.wantTenant {
+				t.Errorf("X-Tenant = %q, want %q", tenant, tt.wantTenant)
+			}
+			if req.Header.Get("User-Agent") != "" {
+				t.Error("original request was modified")
+			}
+		})
+	}
+}
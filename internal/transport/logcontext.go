@@ -0,0 +1,101 @@
+package transport
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// loggerContextKey is the context key of the request logger
+type loggerContextKey struct{}
+
+// logAttrsContextKey is the context key of the request log attributes
+type logAttrsContextKey struct{}
+
+// WithLogger returns a context whose requests are logged with logger
+// instead of the client logger, e.g. a logger tagged with the tenant being
+// served; it takes effect through ContextHandler
+// A nil logger leaves the context unchanged.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	if logger == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// WithLogAttrs returns a context whose request logs carry attrs, besides
+// those already attached to ctx; it takes effect through ContextHandler
+func WithLogAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	prev, _ := ctx.Value(logAttrsContextKey{}).([]slog.Attr)
+	return context.WithValue(ctx, logAttrsContextKey{}, append(slices.Clip(prev), attrs...))
+}
+
+// contextHandler is a slog.Handler that sends records to the logger of
+// their context
+type contextHandler struct {
+	next slog.Handler
+
+	// ops are the WithAttrs and WithGroup calls, replayed on the handler
+	// of a context logger
+	ops []func(slog.Handler) slog.Handler
+}
+
+// ContextHandler returns a slog.Handler that sends each record to the
+// logger attached to its context with WithLogger, or to next otherwise, and
+// adds the attributes attached with WithLogAttrs
+func ContextHandler(next slog.Handler) slog.Handler {
+	return &contextHandler{next: next}
+}
+
+// handler returns the handler of the records logged with ctx
+func (h *contextHandler) handler(ctx context.Context) slog.Handler {
+	logger, ok := ctx.Value(loggerContextKey{}).(*slog.Logger)
+	if !ok {
+		return h.next
+	}
+
+	next := logger.Handler()
+	for _, op := range h.ops {
+		next = op(next)
+	}
+	return next
+}
+
+// Enabled implements slog.Handler
+func (h *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler(ctx).Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs, ok := ctx.Value(logAttrsContextKey{}).([]slog.Attr); ok {
+		record = record.Clone()
+		record.AddAttrs(attrs...)
+	}
+	return h.handler(ctx).Handle(ctx, record)
+}
+
+// WithAttrs implements slog.Handler
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler {
+		return next.WithAttrs(attrs)
+	})
+}
+
+// WithGroup implements slog.Handler
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler {
+		return next.WithGroup(name)
+	})
+}
+
+// with returns a copy of h with op applied to its handlers
+func (h *contextHandler) with(op func(slog.Handler) slog.Handler) slog.Handler {
+	return &contextHandler{
+		next: op(h.next),
+		ops:  append(slices.Clip(h.ops), op),
+	}
+}
@@ -0,0 +1,90 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContextHandler(t *testing.T) {
+	var clientBuf, requestBuf bytes.Buffer
+	clientLogger := slog.New(slog.NewTextHandler(&clientBuf, nil))
+	requestLogger := slog.New(slog.NewTextHandler(&requestBuf, nil)).With("tenant", "acme")
+	logger := slog.New(ContextHandler(clientLogger.Handler())).With("component", "bbpix")
+
+	base := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: make(http.Header)}, nil
+		},
+	}
+	transport := NewLoggingTransport(base, logger)
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantClient string
+		wantReq    string
+	}{
+		{
+			name:       "client logger",
+			ctx:        context.Background(),
+			wantClient: "component=bbpix method=GET",
+		},
+		{
+			name:       "attributes",
+			ctx:        WithLogAttrs(WithLogAttrs(context.Background(), slog.String("order_id", "42")), slog.String("tenant", "beta")),
+			wantClient: "order_id=42 tenant=beta",
+		},
+		{
+			name:    "request logger",
+			ctx:     WithLogger(context.Background(), requestLogger),
+			wantReq: "tenant=acme component=bbpix method=GET",
+		},
+		{
+			name:    "request logger and attributes",
+			ctx:     WithLogAttrs(WithLogger(context.Background(), requestLogger), slog.String("order_id", "42")),
+			wantReq: "order_id=42",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientBuf.Reset()
+			requestBuf.Reset()
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com", nil).WithContext(tt.ctx)
+			if _, err := transport.RoundTrip(req); err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+
+			if tt.wantClient != "" && !strings.Contains(clientBuf.String(), tt.wantClient) {
+				t.Errorf("client log = %q, want it to contain %q", clientBuf.String(), tt.wantClient)
+			}
+			if tt.wantClient == "" && clientBuf.Len() > 0 {
+				t.Errorf("client log = %q, want none", clientBuf.String())
+			}
+			if tt.wantReq != "" && !strings.Contains(requestBuf.String(), tt.wantReq) {
+				t.Errorf("request log = %q, want it to contain %q", requestBuf.String(), tt.wantReq)
+			}
+			if tt.wantReq == "" && requestBuf.Len() > 0 {
+				t.Errorf("request log = %q, want none", requestBuf.String())
+			}
+		})
+	}
+}
+
+func TestContextHandler_Enabled(t *testing.T) {
+	logger := slog.New(ContextHandler(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+	debugLogger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	if logger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Enabled(debug) = true with the client logger at info")
+	}
+	if !logger.Enabled(WithLogger(context.Background(), debugLogger), slog.LevelDebug) {
+		t.Error("Enabled(debug) = false with a request logger at debug")
+	}
+}
@@ -3,13 +3,58 @@ package transport
 import (
 	"log/slog"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// LogLevels are the levels requests are logged at, by outcome
+type LogLevels struct {
+	Success     slog.Level // 1xx, 2xx and 3xx responses
+	ClientError slog.Level // 4xx responses
+	ServerError slog.Level // 5xx responses
+	Failure     slog.Level // requests failed without a response
+	Canceled    slog.Level // requests whose context was canceled or expired
+}
+
+// DefaultLogLevels logs 4xx responses at Warn and 5xx responses and failed
+// requests at Error, so log-based alerting needs no custom processing
+var DefaultLogLevels = LogLevels{
+	Success:     slog.LevelInfo,
+	ClientError: slog.LevelWarn,
+	ServerError: slog.LevelError,
+	Failure:     slog.LevelError,
+	Canceled:    slog.LevelWarn,
+}
+
+// level returns the level of a request outcome
+func (l LogLevels) level(req *http.Request, resp *http.Response, err error) slog.Level {
+	switch {
+	case isCanceled(req, err):
+		return l.Canceled
+	case err != nil:
+		return l.Failure
+	case resp.StatusCode >= 500:
+		return l.ServerError
+	case resp.StatusCode >= 400:
+		return l.ClientError
+	default:
+		return l.Success
+	}
+}
+
 // LoggingTransport is an http.RoundTripper that logs requests and responses
 type LoggingTransport struct {
 	base   http.RoundTripper
 	logger *slog.Logger
+	levels LogLevels
+
+	// sampleRate logs one in sampleRate successful requests per endpoint
+	sampleRate uint64
+	samples    sync.Map // method and endpoint -> *atomic.Uint64
+
+	// slow is the duration past which a request is reported as slow
+	slow time.Duration
 }
 
 // NewLoggingTransport creates a new LoggingTransport
@@ -21,11 +66,52 @@ func NewLoggingTransport(base http.RoundTripper, logger *slog.Logger) *LoggingTr
 	return &LoggingTransport{
 		base:   base,
 		logger: logger,
+		levels: DefaultLogLevels,
+	}
+}
+
+// SetLevels sets the levels requests are logged at
+func (t *LoggingTransport) SetLevels(levels LogLevels) {
+	t.levels = levels
+}
+
+// SetSampling logs only one in every n successful requests of each
+// endpoint, starting with the first one, to keep the volume of polling logs
+// manageable; failed requests and error responses are always logged
+// Sampled records carry n as sample_rate. n <= 1 logs every request.
+func (t *LoggingTransport) SetSampling(n int) {
+	t.sampleRate = uint64(max(n, 1))
+}
+
+// sampled reports whether a successful request should be logged
+func (t *LoggingTransport) sampled(req *http.Request) bool {
+	if t.sampleRate <= 1 {
+		return true
+	}
+
+	key := req.Method + " " + Endpoint(req.URL.Path)
+	counter, ok := t.samples.Load(key)
+	if !ok {
+		counter, _ = t.samples.LoadOrStore(key, new(atomic.Uint64))
 	}
+	return (counter.(*atomic.Uint64).Add(1)-1)%t.sampleRate == 0
+}
+
+// SetSlowThreshold logs a warning for every request that takes longer than
+// d, retries included, to surface latency degradations before timeouts
+// fire; zero disables the warning
+// The warning is logged regardless of the sampling.
+func (t *LoggingTransport) SetSlowThreshold(d time.Duration) {
+	t.slow = d
 }
 
 // RoundTrip implements http.RoundTripper with logging
 func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var retries *atomic.Int32
+	if t.slow > 0 {
+		req, retries = withRetryCounter(req)
+	}
+
 	start := time.Now()
 
 	// Execute request
@@ -34,22 +120,64 @@ func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 	// Calculate duration
 	duration := time.Since(start)
 
+	if t.slow > 0 && duration > t.slow {
+		t.logSlow(req, resp, err, duration, retries)
+	}
+
+	success := err == nil && resp.StatusCode < 400
+	if success && !t.sampled(req) {
+		return resp, err
+	}
+
 	// Log the request/response
+	level := t.levels.level(req, resp, err)
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+	}
+	if id := RequestID(req.Context()); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+
 	if err != nil {
-		t.logger.InfoContext(req.Context(), "HTTP request failed",
-			slog.String("method", req.Method),
-			slog.String("url", req.URL.String()),
+		attrs = append(attrs,
 			slog.Float64("duration_ms", float64(duration.Milliseconds())),
 			slog.String("error", err.Error()),
 		)
+		t.logger.LogAttrs(req.Context(), level, "HTTP request failed", attrs...)
 	} else {
-		t.logger.InfoContext(req.Context(), "HTTP request completed",
-			slog.String("method", req.Method),
-			slog.String("url", req.URL.String()),
+		attrs = append(attrs,
 			slog.Int("status", resp.StatusCode),
 			slog.Float64("duration_ms", float64(duration.Milliseconds())),
 		)
+		if success && t.sampleRate > 1 {
+			attrs = append(attrs, slog.Uint64("sample_rate", t.sampleRate))
+		}
+		t.logger.LogAttrs(req.Context(), level, "HTTP request completed", attrs...)
 	}
 
 	return resp, err
 }
+
+// logSlow warns about a request slower than the threshold
+func (t *LoggingTransport) logSlow(req *http.Request, resp *http.Response, err error, duration time.Duration, retries *atomic.Int32) {
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.String("endpoint", Endpoint(req.URL.Path)),
+	}
+	if id := RequestID(req.Context()); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	} else {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	attrs = append(attrs,
+		slog.Float64("duration_ms", float64(duration.Milliseconds())),
+		slog.Float64("threshold_ms", float64(t.slow.Milliseconds())),
+		slog.Int("retries", int(retries.Load())),
+	)
+	t.logger.LogAttrs(req.Context(), slog.LevelWarn, "Slow HTTP request", attrs...)
+}
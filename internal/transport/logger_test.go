@@ -300,3 +300,168 @@ func TestLoggingTransport_PropagatesResponse(t *testing.T) {
 		t.Error("Response was not propagated correctly")
 	}
 }
+
+func TestLoggingTransport_Levels(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		err    error
+		levels *LogLevels
+		want   string
+	}{
+		{name: "success", status: http.StatusOK, want: "INFO"},
+		{name: "redirect", status: http.StatusNotModified, want: "INFO"},
+		{name: "client error", status: http.StatusNotFound, want: "WARN"},
+		{name: "server error", status: http.StatusBadGateway, want: "ERROR"},
+		{name: "network error", err: errors.New("connection reset"), want: "ERROR"},
+		{name: "canceled", err: context.Canceled, want: "WARN"},
+		{
+			name:   "custom levels",
+			status: http.StatusNotFound,
+			levels: &LogLevels{Success: slog.LevelDebug, ClientError: slog.LevelInfo, ServerError: slog.LevelWarn},
+			want:   "INFO",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+			base := &mockRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &http.Response{StatusCode: tt.status, Body: http.NoBody, Header: make(http.Header)}, nil
+				},
+			}
+			transport := NewLoggingTransport(base, logger)
+			if tt.levels != nil {
+				transport.SetLevels(*tt.levels)
+			}
+
+			transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://example.com", nil))
+
+			var logEntry map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &logEntry); err != nil {
+				t.Fatalf("Failed to parse log output: %v", err)
+			}
+			if logEntry["level"] != tt.want {
+				t.Errorf("level = %v, want %s", logEntry["level"], tt.want)
+			}
+		})
+	}
+}
+
+func TestLoggingTransport_Sampling(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	base := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			status := http.StatusOK
+			if strings.HasSuffix(req.URL.Path, "/fail") {
+				status = http.StatusServiceUnavailable
+			}
+			return &http.Response{StatusCode: status, Body: http.NoBody, Header: make(http.Header)}, nil
+		},
+	}
+	transport := NewLoggingTransport(base, logger)
+	transport.SetSampling(3)
+
+	paths := []string{
+		"/cob/a1b2c3d4e5f6a7b8c9d0e1f2a3", "/cob/b1b2c3d4e5f6a7b8c9d0e1f2a3", "/cob/c1b2c3d4e5f6a7b8c9d0e1f2a3",
+		"/cob/d1b2c3d4e5f6a7b8c9d0e1f2a3", "/cob/e1b2c3d4e5f6a7b8c9d0e1f2a3", "/cob/f1b2c3d4e5f6a7b8c9d0e1f2a3",
+		"/cob/fail", "/cob/fail",
+		"/webhook",
+	}
+	for _, path := range paths {
+		transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://example.com"+path, nil))
+	}
+
+	var logged []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to parse log output: %v", err)
+		}
+		if entry["status"] == float64(http.StatusOK) && entry["sample_rate"] != float64(3) {
+			t.Errorf("sample_rate = %v, want 3", entry["sample_rate"])
+		}
+		if entry["status"] != float64(http.StatusOK) && entry["sample_rate"] != nil {
+			t.Errorf("sample_rate = %v on an error response", entry["sample_rate"])
+		}
+		logged = append(logged, strings.TrimPrefix(entry["url"].(string), "http://example.com"))
+	}
+
+	// Successes are sampled per endpoint; errors are always logged
+	want := []string{
+		"/cob/a1b2c3d4e5f6a7b8c9d0e1f2a3", "/cob/d1b2c3d4e5f6a7b8c9d0e1f2a3",
+		"/cob/fail", "/cob/fail",
+		"/webhook",
+	}
+	if strings.Join(logged, " ") != strings.Join(want, " ") {
+		t.Errorf("logged = %v, want %v", logged, want)
+	}
+}
+
+func TestLoggingTransport_SlowThreshold(t *testing.T) {
+	tests := []struct {
+		name     string
+		delay    time.Duration
+		wantSlow bool
+	}{
+		{name: "fast", delay: 0, wantSlow: false},
+		{name: "slow", delay: 30 * time.Millisecond, wantSlow: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+			base := &mockRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					countRetry(req.Context())
+					time.Sleep(tt.delay)
+					return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: make(http.Header)}, nil
+				},
+			}
+			transport := NewLoggingTransport(base, logger)
+			transport.SetSlowThreshold(20 * time.Millisecond)
+			transport.SetSampling(100)
+
+			// The first request of the endpoint is sampled in, skip it
+			for range 2 {
+				transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://example.com/cob/7978c0c97ea847e78e8849634473c1f1", nil))
+			}
+
+			var slow []map[string]interface{}
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				var entry map[string]interface{}
+				if err := json.Unmarshal([]byte(line), &entry); err != nil {
+					t.Fatalf("Failed to parse log output: %v", err)
+				}
+				if entry["msg"] == "Slow HTTP request" {
+					slow = append(slow, entry)
+				}
+			}
+
+			if !tt.wantSlow {
+				if len(slow) != 0 {
+					t.Errorf("slow warnings = %v, want none", slow)
+				}
+				return
+			}
+			if len(slow) != 2 {
+				t.Fatalf("slow warnings = %d, want 2 regardless of the sampling", len(slow))
+			}
+			entry := slow[1]
+			if entry["level"] != "WARN" || entry["endpoint"] != "/cob/{id}" || entry["status"] != float64(200) ||
+				entry["threshold_ms"] != float64(20) || entry["retries"] != float64(1) || entry["duration_ms"].(float64) < 20 {
+				t.Errorf("slow warning = %v", entry)
+			}
+		})
+	}
+}
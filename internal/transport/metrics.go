@@ -0,0 +1,145 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// RequestMetrics describes a finished API request, retries included
+type RequestMetrics struct {
+	// Method is the HTTP method
+	Method string
+	// Endpoint is the request path with its identifiers replaced by {id},
+	// e.g. /pix-bb/v1/cob/{id}, so it can be used as a metric label
+	Endpoint string
+	// StatusCode is the status of the last response, zero when the request
+	// failed without one
+	StatusCode int
+	// Err is the error of the request, if any
+	Err error
+	// Duration is the time spent on the request
+	Duration time.Duration
+	// Retries is the number of attempts after the first one
+	Retries int
+}
+
+// MetricsRecorder receives the metrics of the requests and of the circuit
+// breaker, e.g. to export them to Prometheus
+// Its methods are called synchronously by the requests, so they should not
+// block.
+type MetricsRecorder interface {
+	// ObserveRequest is called once for every finished request
+	ObserveRequest(ctx context.Context, m RequestMetrics)
+	// ObserveCircuitState is called whenever the circuit breaker changes
+	// state
+	ObserveCircuitState(from, to CircuitState)
+}
+
+// retryCounterContextKey is the context key of the retry counter of a
+// request
+type retryCounterContextKey struct{}
+
+// countRetry counts a retry for the request of ctx, when it is measured
+func countRetry(ctx context.Context) {
+	if n, ok := ctx.Value(retryCounterContextKey{}).(*atomic.Int32); ok {
+		n.Add(1)
+	}
+}
+
+// withRetryCounter returns req with a context counting its retries, reusing
+// the counter of an outer transport if any
+func withRetryCounter(req *http.Request) (*http.Request, *atomic.Int32) {
+	if n, ok := req.Context().Value(retryCounterContextKey{}).(*atomic.Int32); ok {
+		return req, n
+	}
+	n := new(atomic.Int32)
+	return req.WithContext(context.WithValue(req.Context(), retryCounterContextKey{}, n)), n
+}
+
+// requestMetrics returns the metrics of a finished request
+func requestMetrics(req *http.Request, resp *http.Response, err error, start time.Time, retries *atomic.Int32) RequestMetrics {
+	m := RequestMetrics{
+		Method:   req.Method,
+		Endpoint: Endpoint(req.URL.Path),
+		Err:      err,
+		Duration: time.Since(start),
+		Retries:  int(retries.Load()),
+	}
+	if resp != nil {
+		m.StatusCode = resp.StatusCode
+	}
+	return m
+}
+
+// MetricsTransport is an http.RoundTripper that reports the metrics of each
+// request to a MetricsRecorder
+type MetricsTransport struct {
+	base     http.RoundTripper
+	recorder MetricsRecorder
+}
+
+// NewMetricsTransport creates a new MetricsTransport
+func NewMetricsTransport(base http.RoundTripper, recorder MetricsRecorder) *MetricsTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &MetricsTransport{
+		base:     base,
+		recorder: recorder,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *MetricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, retries := withRetryCounter(req)
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	t.recorder.ObserveRequest(req.Context(), requestMetrics(req, resp, err, start, retries))
+	return resp, err
+}
+
+// Endpoint returns path with its identifiers replaced by {id}, keeping the
+// resource names (letters and hyphens, up to 20 characters) and API
+// versions such as v1
+func Endpoint(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if s != "" && !isResourceName(s) && !isVersion(s) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// isResourceName reports whether a path segment looks like a resource name
+// such as cob or idRec rather than an identifier
+func isResourceName(s string) bool {
+	if len(s) > 20 || s[0] == '-' {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// isVersion reports whether a path segment is an API version such as v1
+func isVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	for _, r := range s[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
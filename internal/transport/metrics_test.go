@@ -0,0 +1,92 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// recordingMetrics records the metrics it receives
+type recordingMetrics struct {
+	requests []RequestMetrics
+	states   []CircuitState
+}
+
+func (r *recordingMetrics) ObserveRequest(ctx context.Context, m RequestMetrics) {
+	r.requests = append(r.requests, m)
+}
+
+func (r *recordingMetrics) ObserveCircuitState(from, to CircuitState) {
+	r.states = append(r.states, to)
+}
+
+func TestEndpoint(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/pix-bb/v1/cob", "/pix-bb/v1/cob"},
+		{"/pix-bb/v1/cob/7978c0c97ea847e78e8849634473c1f1", "/pix-bb/v1/cob/{id}"},
+		{"/pix-bb/v1/cobv/abcdefghijklmnopqrstuvwxyz", "/pix-bb/v1/cobv/{id}"},
+		{"/pix-bb/v1/pix/E00038166201907261559y6j6mt9l0pi/devolucao/D123", "/pix-bb/v1/pix/{id}/devolucao/{id}"},
+		{"/pix-bb/v1/loc/123/txid", "/pix-bb/v1/loc/{id}/txid"},
+		{"/pix-bb/v1/locrec/42/idRec", "/pix-bb/v1/locrec/{id}/idRec"},
+		{"/pix-bb/v1/cobr/abc123/retentativa/2024-01-01", "/pix-bb/v1/cobr/{id}/retentativa/{id}"},
+		{"/pix-bb/v1/webhook/fulano@example.com", "/pix-bb/v1/webhook/{id}"},
+		{"/pix/v2/rec/RN123456", "/pix/v2/rec/{id}"},
+	}
+
+	for _, tt := range tests {
+		if got := Endpoint(tt.path); got != tt.want {
+			t.Errorf("Endpoint(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestMetricsTransport(t *testing.T) {
+	calls := 0
+	base := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			calls++
+			switch calls {
+			case 1:
+				return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody, Header: make(http.Header)}, nil
+			case 2:
+				return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: make(http.Header)}, nil
+			}
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	recorder := &recordingMetrics{}
+	transport := NewMetricsTransport(NewRetryTransport(base, 1, time.Millisecond), recorder)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/pix-bb/v1/cob/abc123", nil)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	req = httptest.NewRequest(http.MethodPost, "http://example.com/pix-bb/v1/cob", nil)
+	if _, err := transport.RoundTrip(req); err == nil {
+		t.Fatal("RoundTrip() error = nil, want the network error")
+	}
+
+	if len(recorder.requests) != 2 {
+		t.Fatalf("observed %d requests, want 2", len(recorder.requests))
+	}
+
+	got := recorder.requests[0]
+	if got.Method != http.MethodGet || got.Endpoint != "/pix-bb/v1/cob/{id}" || got.StatusCode != http.StatusOK || got.Retries != 1 || got.Err != nil {
+		t.Errorf("first request = %+v, want GET /pix-bb/v1/cob/{id} 200 after 1 retry", got)
+	}
+	if got.Duration <= 0 {
+		t.Errorf("Duration = %v, want positive", got.Duration)
+	}
+
+	got = recorder.requests[1]
+	if got.Method != http.MethodPost || got.StatusCode != 0 || got.Err == nil || got.Retries != 0 {
+		t.Errorf("second request = %+v, want POST failed without retries", got)
+	}
+}
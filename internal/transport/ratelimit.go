@@ -0,0 +1,102 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimitTransport is an http.RoundTripper that limits the rate of
+// requests with a token bucket, waiting for a token before each request
+type RateLimitTransport struct {
+	base  http.RoundTripper
+	rate  float64 // tokens per second
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewRateLimitTransport creates a new RateLimitTransport allowing rps
+// requests per second on average and bursts of up to burst requests
+// burst is at least 1.
+func NewRateLimitTransport(base http.RoundTripper, rps float64, burst int) *RateLimitTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimitTransport{
+		base:   base,
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}
+}
+
+// RoundTrip implements http.RoundTripper, waiting for a token first
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// wait takes a token, sleeping until one is available
+// It fails at once when the wait would outlast the context deadline.
+func (t *RateLimitTransport) wait(ctx context.Context) error {
+	delay := t.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		t.cancel()
+		return fmt.Errorf("rate limit wait of %v exceeds the context deadline: %w", delay, context.DeadlineExceeded)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		t.cancel()
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token, possibly ahead of time, and returns how long to
+// wait until it is available
+func (t *RateLimitTransport) reserve() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if !t.last.IsZero() {
+		t.tokens = min(t.burst, t.tokens+now.Sub(t.last).Seconds()*t.rate)
+	}
+	t.last = now
+
+	t.tokens--
+	if t.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-t.tokens / t.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token that was not used
+func (t *RateLimitTransport) cancel() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.tokens = min(t.burst, t.tokens+1)
+}
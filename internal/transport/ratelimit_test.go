@@ -0,0 +1,107 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimitTransport_Reserve(t *testing.T) {
+	transport := NewRateLimitTransport(&mockRoundTripper{}, 10, 2)
+	now := time.Now()
+	transport.now = func() time.Time { return now }
+
+	// The burst is available at once, then tokens come every 100ms
+	tests := []struct {
+		advance time.Duration
+		want    time.Duration
+	}{
+		{0, 0},
+		{0, 0},
+		{0, 100 * time.Millisecond},
+		{0, 200 * time.Millisecond},
+		{300 * time.Millisecond, 0},
+		{time.Second, 0},
+		{0, 0},
+		{0, 100 * time.Millisecond},
+	}
+
+	for i, tt := range tests {
+		now = now.Add(tt.advance)
+		if got := transport.reserve(); got != tt.want {
+			t.Errorf("reserve() #%d = %v, want %v", i, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimitTransport_RoundTrip(t *testing.T) {
+	calls := 0
+	base := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			calls++
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: make(http.Header)}, nil
+		},
+	}
+	transport := NewRateLimitTransport(base, 50, 1)
+
+	start := time.Now()
+	for range 3 {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+		if _, err := transport.RoundTrip(req); err != nil {
+			t.Fatalf("RoundTrip() error = %v", err)
+		}
+	}
+
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("3 requests at 50 rps took %v, want at least 35ms", elapsed)
+	}
+}
+
+func TestRateLimitTransport_Context(t *testing.T) {
+	base := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			t.Error("request sent without a token")
+			return nil, errors.New("unexpected request")
+		},
+	}
+
+	t.Run("deadline shorter than the wait", func(t *testing.T) {
+		transport := NewRateLimitTransport(base, 1, 1)
+		transport.tokens = 0
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		req := httptest.NewRequest(http.MethodGet, "http://example.com", nil).WithContext(ctx)
+
+		start := time.Now()
+		_, err := transport.RoundTrip(req)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("RoundTrip() error = %v, want context.DeadlineExceeded", err)
+		}
+		if time.Since(start) > 50*time.Millisecond {
+			t.Error("RoundTrip() waited instead of failing at once")
+		}
+		if transport.tokens < -0.01 {
+			t.Errorf("tokens = %v, want the reservation returned", transport.tokens)
+		}
+	})
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		transport := NewRateLimitTransport(base, 1, 1)
+		transport.tokens = 0
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		req := httptest.NewRequest(http.MethodGet, "http://example.com", nil).WithContext(ctx)
+
+		if _, err := transport.RoundTrip(req); !errors.Is(err, context.Canceled) {
+			t.Errorf("RoundTrip() error = %v, want context.Canceled", err)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package transport
+
+import (
+	"context"
+	"crypto/rand"
+	"net/http"
+
+	httpclient "github.com/pericles-luz/go-bb-pix/internal/http"
+)
+
+// DefaultRequestIDHeader is the header that carries the request ID
+const DefaultRequestIDHeader = "X-Request-Id"
+
+// requestIDContextKey is the context key of the request ID
+type requestIDContextKey struct{}
+
+// WithRequestID returns a context whose requests are sent with id as their
+// request ID, e.g. the correlation ID of the incoming request being served
+// An empty id leaves the context unchanged.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDContextKey{}, id)
+}
+
+// RequestID returns the request ID of a context, if any
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
+
+// RequestIDTransport is an http.RoundTripper that sends a request ID with
+// every request and stores it in the request context, so the transports
+// below, such as LoggingTransport, can report it
+// The ID comes from the context (see WithRequestID), then from the request
+// header, and is generated otherwise. Retries of a request share its ID.
+type RequestIDTransport struct {
+	base   http.RoundTripper
+	header string
+}
+
+// NewRequestIDTransport creates a new RequestIDTransport sending the ID in
+// header; an empty header means DefaultRequestIDHeader
+func NewRequestIDTransport(base http.RoundTripper, header string) *RequestIDTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+
+	return &RequestIDTransport{
+		base:   base,
+		header: http.CanonicalHeaderKey(header),
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RequestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := RequestID(req.Context())
+	if id == "" {
+		id = req.Header.Get(t.header)
+	}
+	if id == "" {
+		id = rand.Text()
+	}
+	if err := httpclient.CheckHeaderValue(t.header, id); err != nil {
+		return nil, err
+	}
+
+	// Avoid modifying the original request
+	req = ownHeaderContext(req, WithRequestID(req.Context(), id))
+	req.Header.Set(t.header, id)
+
+	return t.base.RoundTrip(req)
+}
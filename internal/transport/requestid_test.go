@@ -0,0 +1,97 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestIDTransport(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		ctxID     string
+		reqHeader string
+		wantID    string
+		wantErr   bool
+	}{
+		{name: "from context", ctxID: "ctx-id", reqHeader: "header-id", wantID: "ctx-id"},
+		{name: "from request header", reqHeader: "header-id", wantID: "header-id"},
+		{name: "generated"},
+		{name: "custom header", header: "x-correlation-id", ctxID: "ctx-id", wantID: "ctx-id"},
+		{name: "invalid id", ctxID: "id\r\nX-Admin: 1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := tt.header
+			if header == "" {
+				header = DefaultRequestIDHeader
+			}
+
+			var sentHeader, ctxID string
+			base := &mockRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					sentHeader = req.Header.Get(header)
+					ctxID = RequestID(req.Context())
+					return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: make(http.Header)}, nil
+				},
+			}
+			transport := NewRequestIDTransport(base, tt.header)
+
+			ctx := WithRequestID(context.Background(), tt.ctxID)
+			req := httptest.NewRequest(http.MethodGet, "http://example.com", nil).WithContext(ctx)
+			if tt.reqHeader != "" {
+				req.Header.Set(header, tt.reqHeader)
+			}
+
+			_, err := transport.RoundTrip(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RoundTrip() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if sentHeader == "" || sentHeader != ctxID {
+				t.Errorf("sent ID = %q, context ID = %q, want the same non-empty ID", sentHeader, ctxID)
+			}
+			if tt.wantID != "" && sentHeader != tt.wantID {
+				t.Errorf("sent ID = %q, want %q", sentHeader, tt.wantID)
+			}
+			if req.Header.Get(header) != tt.reqHeader {
+				t.Error("original request was modified")
+			}
+		})
+	}
+}
+
+func TestRequestIDTransport_Logged(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	base := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: make(http.Header)}, nil
+		},
+	}
+	transport := NewRequestIDTransport(NewLoggingTransport(base, logger), "")
+
+	ctx := WithRequestID(context.Background(), "abc-123")
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil).WithContext(ctx)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse log output: %v", err)
+	}
+	if entry["request_id"] != "abc-123" {
+		t.Errorf("request_id = %v, want abc-123", entry["request_id"])
+	}
+}
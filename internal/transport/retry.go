@@ -1,18 +1,131 @@
 package transport
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"math"
 	"math/rand"
 	"net/http"
+	"slices"
 	"time"
 )
 
+// IdempotencyKeyHeader is the header that carries the idempotency key of a
+// request marked with WithIdempotencyKey
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyKeyContextKey is the context key of the idempotency key
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a context whose requests carry key in the
+// Idempotency-Key header and are retried even when their method is not
+// idempotent (POST, PATCH), since the server can use the key to apply them
+// once
+// An empty key leaves the context unchanged.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	if key == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// idempotencyKey returns the idempotency key of a context, if any
+func idempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
+// Jitter selects how the wait between attempts is randomized
+type Jitter int
+
+const (
+	// JitterProportional waits the exponential backoff ±25%
+	JitterProportional Jitter = iota
+	// JitterNone waits exactly the exponential backoff
+	JitterNone
+	// JitterFull waits a random time between zero and the exponential
+	// backoff, spreading retries the most
+	JitterFull
+	// JitterEqual waits half the exponential backoff plus a random time up
+	// to the other half
+	JitterEqual
+)
+
+// RetryPolicy customizes which failed attempts RetryTransport retries and
+// how long it waits between them
+// The zero value keeps the defaults.
+type RetryPolicy struct {
+	// ShouldRetry decides whether a failed attempt is retried, replacing
+	// RetryableStatusCodes; resp is nil when err is set. Requests neither
+	// idempotent nor marked with WithIdempotencyKey are never retried.
+	ShouldRetry func(resp *http.Response, err error) bool
+
+	// RetryableStatusCodes are the response statuses retried, besides
+	// network errors
+	// Default: 429, 502, 503 and 504
+	RetryableStatusCodes []int
+
+	// MaxBackoff caps the wait between attempts
+	// Default: no cap
+	MaxBackoff time.Duration
+
+	// Jitter randomizes the wait between attempts
+	// Default: JitterProportional
+	Jitter Jitter
+
+	// MaxElapsedTime bounds the time spent on a request, attempts and waits
+	// included: no retry is started that would wait past it
+	// Default: no limit
+	MaxElapsedTime time.Duration
+
+	// Budget limits the retries of the client as a whole
+	// Default: no limit
+	Budget RetryBudget
+}
+
+// retryable reports whether a failed attempt should be retried
+func (p *RetryPolicy) retryable(resp *http.Response, err error) bool {
+	if p.ShouldRetry != nil {
+		return p.ShouldRetry(resp, err)
+	}
+	if err != nil || resp == nil || p.RetryableStatusCodes == nil {
+		return shouldRetry(resp, err)
+	}
+	return slices.Contains(p.RetryableStatusCodes, resp.StatusCode)
+}
+
+// RetryAttempt describes a retry about to be attempted
+type RetryAttempt struct {
+	Method   string
+	Endpoint string // request path with its identifiers replaced by {id}
+
+	// Attempt is the number of the retry, starting at 1
+	Attempt int
+
+	// Backoff is the wait before the retry
+	Backoff time.Duration
+
+	// StatusCode is the status of the failed attempt, zero when it failed
+	// with Err
+	StatusCode int
+	Err        error
+}
+
+// RetryFunc is called before every retry
+type RetryFunc func(ctx context.Context, attempt RetryAttempt)
+
 // RetryTransport is an http.RoundTripper that implements retry logic with exponential backoff
 type RetryTransport struct {
 	base           http.RoundTripper
 	maxRetries     int
 	initialBackoff time.Duration
+	policy         RetryPolicy
+	budget         *retryBudget
+	logger         *slog.Logger
+	hook           RetryFunc
 }
 
 // NewRetryTransport creates a new RetryTransport
@@ -28,69 +141,215 @@ func NewRetryTransport(base http.RoundTripper, maxRetries int, initialBackoff ti
 	}
 }
 
+// SetPolicy sets the retry policy
+func (t *RetryTransport) SetPolicy(p RetryPolicy) {
+	t.policy = p
+	t.budget = nil
+	if p.Budget.enabled() {
+		t.budget = newRetryBudget(p.Budget)
+	}
+}
+
+// SetLogger sets the logger every retry is logged with, at Info level
+func (t *RetryTransport) SetLogger(logger *slog.Logger) {
+	t.logger = logger
+}
+
+// OnRetry sets a function called before every retry, e.g. to count the
+// throttled requests
+func (t *RetryTransport) OnRetry(fn RetryFunc) {
+	t.hook = fn
+}
+
 // RoundTrip implements http.RoundTripper with retry logic
+// When the retries are exhausted, the last response is returned as is, or
+// the last error wrapped. The request body is rewound before each retry.
+// A retry whose backoff outlasts the context deadline is not attempted.
 func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	var lastErr error
-	var resp *http.Response
+	start := time.Now()
 
-	for attempt := 0; attempt <= t.maxRetries; attempt++ {
+	// Requests marked with an idempotency key are safe to retry
+	retryable := isIdempotent(req.Method)
+	if key := idempotencyKey(req.Context()); key != "" {
+		req = ownHeader(req)
+		req.Header.Set(IdempotencyKeyHeader, key)
+		retryable = true
+	}
+
+	if retryable {
+		var err error
+		if req, err = rewindableBody(req); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.budget != nil {
+		t.budget.request()
+	}
+
+	for attempt := 0; ; attempt++ {
 		// Check if context is cancelled
-		select {
-		case <-req.Context().Done():
-			return nil, req.Context().Err()
-		default:
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+
+		if attempt > 0 {
+			countRetry(req.Context())
+		}
+
+		// The previous attempt consumed the body, send a fresh copy
+		attemptReq := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+			attemptReq = new(http.Request)
+			*attemptReq = *req
+			attemptReq.Body = body
 		}
 
 		// Execute request
-		resp, lastErr = t.base.RoundTrip(req)
+		resp, err := t.base.RoundTrip(attemptReq)
+
+		// Not retryable or not idempotent, return immediately
+		if !retryable || !t.policy.retryable(resp, err) {
+			return resp, err
+		}
 
-		// If successful or should not retry, return
-		if lastErr == nil && !shouldRetry(resp, nil) {
-			return resp, nil
+		backoff := t.calculateBackoff(attempt)
+		limit := t.policy.MaxElapsedTime
+		if attempt >= t.maxRetries || (limit > 0 && time.Since(start)+backoff > limit) {
+			return giveUp(resp, err, "max retries exceeded")
 		}
 
-		// If error occurred or retryable status code
-		if shouldRetry(resp, lastErr) && isIdempotent(req.Method) {
-			// Close response body if we got one (to avoid leaks)
+		// Waiting past the deadline would only end in a timeout
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < backoff {
 			if resp != nil && resp.Body != nil {
 				resp.Body.Close()
 			}
+			return nil, fmt.Errorf("retry backoff of %v exceeds the context deadline: %w", backoff, context.DeadlineExceeded)
+		}
+		if t.budget != nil && !t.budget.withdraw() {
+			return giveUp(resp, err, "retry budget exhausted")
+		}
 
-			// If this is not the last attempt, sleep before retry
-			if attempt < t.maxRetries {
-				backoff := t.calculateBackoff(attempt)
-
-				// Check context before sleeping
-				select {
-				case <-req.Context().Done():
-					return nil, req.Context().Err()
-				case <-time.After(backoff):
-					// Continue to next attempt
-				}
-			}
-		} else {
-			// Not retryable or not idempotent, return immediately
-			return resp, lastErr
+		t.notify(req, attempt+1, backoff, resp, err)
+
+		// Close response body if we got one (to avoid leaks)
+		if resp != nil && resp.Body != nil {
+			resp.Body.Close()
 		}
+
+		// Check context before sleeping
+		timer := time.NewTimer(backoff)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+			// Continue to next attempt
+		}
+	}
+}
+
+// notify logs a retry and calls the retry hook
+func (t *RetryTransport) notify(req *http.Request, attempt int, backoff time.Duration, resp *http.Response, err error) {
+	if t.logger == nil && t.hook == nil {
+		return
+	}
+
+	a := RetryAttempt{
+		Method:   req.Method,
+		Endpoint: Endpoint(req.URL.Path),
+		Attempt:  attempt,
+		Backoff:  backoff,
+		Err:      err,
+	}
+	if resp != nil {
+		a.StatusCode = resp.StatusCode
 	}
 
-	// All retries exhausted
-	if lastErr != nil {
-		return nil, fmt.Errorf("max retries exceeded: %w", lastErr)
+	ctx := req.Context()
+	if t.logger != nil {
+		attrs := []slog.Attr{
+			slog.String("method", a.Method),
+			slog.String("endpoint", a.Endpoint),
+			slog.Int("attempt", a.Attempt),
+			slog.Float64("backoff_ms", float64(a.Backoff.Milliseconds())),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		} else {
+			attrs = append(attrs, slog.Int("status", a.StatusCode))
+		}
+		if id := RequestID(ctx); id != "" {
+			attrs = append(attrs, slog.String("request_id", id))
+		}
+		t.logger.LogAttrs(ctx, slog.LevelInfo, "Retrying HTTP request", attrs...)
+	}
+	if t.hook != nil {
+		t.hook(ctx, a)
 	}
+}
 
+// giveUp returns the outcome of the last attempt once no retry is left:
+// the response as is, or the error wrapped with reason
+func giveUp(resp *http.Response, err error, reason string) (*http.Response, error) {
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", reason, err)
+	}
 	return resp, nil
 }
 
+// rewindableBody makes the body of req readable again for each attempt,
+// buffering it in memory unless req.GetBody already provides a fresh copy
+func rewindableBody(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return req, nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	r := new(http.Request)
+	*r = *req
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	return r, nil
+}
+
 // calculateBackoff calculates exponential backoff with jitter
 func (t *RetryTransport) calculateBackoff(attempt int) time.Duration {
 	// Exponential backoff: initialBackoff * 2^attempt
 	backoff := float64(t.initialBackoff) * math.Pow(2, float64(attempt))
 
-	// Add jitter (random ±25%)
-	jitter := 0.75 + (rand.Float64() * 0.5) // 0.75 to 1.25
-	backoff *= jitter
+	maxBackoff := float64(t.policy.MaxBackoff)
+	if maxBackoff > 0 && backoff > maxBackoff {
+		backoff = maxBackoff
+	}
 
+	switch t.policy.Jitter {
+	case JitterNone:
+	case JitterFull:
+		backoff *= rand.Float64()
+	case JitterEqual:
+		backoff = backoff/2 + rand.Float64()*backoff/2
+	default:
+		backoff *= 0.75 + (rand.Float64() * 0.5) // 0.75 to 1.25
+	}
+
+	if maxBackoff > 0 && backoff > maxBackoff {
+		return t.policy.MaxBackoff
+	}
+	if backoff > math.MaxInt64 {
+		return math.MaxInt64
+	}
 	return time.Duration(backoff)
 }
 
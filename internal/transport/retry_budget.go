@@ -0,0 +1,106 @@
+package transport
+
+import (
+	"sync"
+	"time"
+)
+
+// retryBudgetBuckets is the number of buckets a RetryBudget window is
+// counted in
+const retryBudgetBuckets = 10
+
+// RetryBudget limits the retries of a client to a share of its requests, so
+// a storm of retryable failures cannot multiply the load on the API
+// The zero value disables the budget.
+type RetryBudget struct {
+	// Ratio is the number of retries allowed per request, e.g. 0.1 allows
+	// one retry for every ten requests in the window
+	Ratio float64
+
+	// MinRetries are allowed in each window regardless of Ratio, so a
+	// client with little traffic can still retry
+	MinRetries int
+
+	// Window is the period over which requests and retries are counted
+	// Default: 10 seconds
+	Window time.Duration
+}
+
+// enabled reports whether the budget limits retries
+func (b RetryBudget) enabled() bool {
+	return b.Ratio > 0 || b.MinRetries > 0
+}
+
+// retryBudget counts the requests and retries of the current window
+type retryBudget struct {
+	RetryBudget
+	width time.Duration
+	now   func() time.Time
+
+	mu      sync.Mutex
+	buckets [retryBudgetBuckets]budgetBucket
+}
+
+// budgetBucket holds the counts of a slice of the window
+type budgetBucket struct {
+	slot     int64
+	requests int
+	retries  int
+}
+
+// newRetryBudget creates the counters of a budget
+func newRetryBudget(b RetryBudget) *retryBudget {
+	if b.Window <= 0 {
+		b.Window = 10 * time.Second
+	}
+
+	return &retryBudget{
+		RetryBudget: b,
+		width:       max(b.Window/retryBudgetBuckets, 1),
+		now:         time.Now,
+	}
+}
+
+// request records a request
+func (b *retryBudget) request() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bucket(b.slot()).requests++
+}
+
+// withdraw records a retry if the budget allows it
+func (b *retryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	slot := b.slot()
+	var requests, retries int
+	for _, bucket := range b.buckets {
+		if slot-bucket.slot < retryBudgetBuckets {
+			requests += bucket.requests
+			retries += bucket.retries
+		}
+	}
+
+	if retries >= b.MinRetries && float64(retries+1) > b.Ratio*float64(requests) {
+		return false
+	}
+	b.bucket(slot).retries++
+	return true
+}
+
+// slot returns the current slice of the window
+func (b *retryBudget) slot() int64 {
+	return b.now().UnixNano() / int64(b.width)
+}
+
+// bucket returns the bucket of slot, resetting it when it held an older
+// slice; b.mu must be held
+func (b *retryBudget) bucket(slot int64) *budgetBucket {
+	bucket := &b.buckets[slot%retryBudgetBuckets]
+	if bucket.slot != slot {
+		*bucket = budgetBucket{slot: slot}
+	}
+	return bucket
+}
@@ -0,0 +1,81 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryBudget_Withdraw(t *testing.T) {
+	budget := newRetryBudget(RetryBudget{Ratio: 0.2, MinRetries: 1, Window: 10 * time.Second})
+	now := time.Unix(1_700_000_000, 0)
+	budget.now = func() time.Time { return now }
+
+	// MinRetries is available without requests
+	if !budget.withdraw() {
+		t.Fatal("first retry should use MinRetries")
+	}
+	if budget.withdraw() {
+		t.Fatal("second retry should exceed the budget")
+	}
+
+	// Ten requests allow two retries in total
+	for range 10 {
+		budget.request()
+	}
+	if !budget.withdraw() {
+		t.Error("retry within the ratio should be allowed")
+	}
+	if budget.withdraw() {
+		t.Error("retry above the ratio should be denied")
+	}
+
+	// Counts expire with the window
+	now = now.Add(11 * time.Second)
+	if !budget.withdraw() {
+		t.Error("retry should be allowed once the window moved on")
+	}
+}
+
+func TestRetryBudget_Enabled(t *testing.T) {
+	if (RetryBudget{}).enabled() {
+		t.Error("zero budget should be disabled")
+	}
+	if !(RetryBudget{MinRetries: 1}).enabled() || !(RetryBudget{Ratio: 0.1}).enabled() {
+		t.Error("budget with a ratio or minimum should be enabled")
+	}
+}
+
+func TestRetryTransport_Budget(t *testing.T) {
+	callCount := 0
+	networkErr := errors.New("network error")
+	base := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			callCount++
+			return nil, networkErr
+		},
+	}
+
+	transport := NewRetryTransport(base, 5, time.Millisecond)
+	transport.SetPolicy(RetryPolicy{Budget: RetryBudget{MinRetries: 2}})
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil).WithContext(context.Background())
+	_, err := transport.RoundTrip(req)
+	if !errors.Is(err, networkErr) || !strings.Contains(err.Error(), "retry budget exhausted") {
+		t.Errorf("RoundTrip() error = %v, want the network error wrapped by the budget", err)
+	}
+	if callCount != 3 {
+		t.Errorf("callCount = %d, want 3 (initial + 2 budgeted retries)", callCount)
+	}
+
+	// The budget is shared by the following requests
+	callCount = 0
+	transport.RoundTrip(req)
+	if callCount != 1 {
+		t.Errorf("callCount = %d, want 1 once the budget is spent", callCount)
+	}
+}
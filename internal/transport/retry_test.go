@@ -1,9 +1,12 @@
 package transport
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -502,3 +505,346 @@ func (r *trackingReader) Close() error {
 	}
 	return nil
 }
+
+func TestRetryPolicy_Retryable(t *testing.T) {
+	networkErr := errors.New("network error")
+	status := func(code int) *http.Response { return &http.Response{StatusCode: code, Body: http.NoBody} }
+
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		resp   *http.Response
+		err    error
+		want   bool
+	}{
+		{"default 503", RetryPolicy{}, status(503), nil, true},
+		{"default 500", RetryPolicy{}, status(500), nil, false},
+		{"custom codes include 500", RetryPolicy{RetryableStatusCodes: []int{500, 503}}, status(500), nil, true},
+		{"custom codes exclude 429", RetryPolicy{RetryableStatusCodes: []int{500, 503}}, status(429), nil, false},
+		{"custom codes keep network errors", RetryPolicy{RetryableStatusCodes: []int{}}, nil, networkErr, true},
+		{"empty codes retry nothing", RetryPolicy{RetryableStatusCodes: []int{}}, status(503), nil, false},
+		{
+			name:   "predicate",
+			policy: RetryPolicy{ShouldRetry: func(resp *http.Response, err error) bool { return err == nil && resp.StatusCode == 409 }},
+			resp:   status(409),
+			want:   true,
+		},
+		{
+			name:   "predicate overrides network errors",
+			policy: RetryPolicy{ShouldRetry: func(resp *http.Response, err error) bool { return false }},
+			err:    networkErr,
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.retryable(tt.resp, tt.err); got != tt.want {
+				t.Errorf("retryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_PolicyMaxBackoff(t *testing.T) {
+	transport := NewRetryTransport(&mockRoundTripper{}, 10, time.Second)
+	transport.SetPolicy(RetryPolicy{MaxBackoff: 2 * time.Second})
+
+	for attempt := range 10 {
+		if backoff := transport.calculateBackoff(attempt); backoff > 2*time.Second {
+			t.Errorf("calculateBackoff(%d) = %v, want at most 2s", attempt, backoff)
+		}
+	}
+}
+
+func TestRetryTransport_PolicyMaxElapsedTime(t *testing.T) {
+	callCount := 0
+	base := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			callCount++
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       io.NopCloser(strings.NewReader("unavailable")),
+				Header:     make(http.Header),
+			}, nil
+		},
+	}
+
+	transport := NewRetryTransport(base, 10, 20*time.Millisecond)
+	transport.SetPolicy(RetryPolicy{MaxElapsedTime: 50 * time.Millisecond})
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+	start := time.Now()
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond+20*time.Millisecond {
+		t.Errorf("RoundTrip() took %v, want about 50ms", elapsed)
+	}
+	if callCount < 2 || callCount > 3 {
+		t.Errorf("callCount = %d, want 2 or 3 within 50ms", callCount)
+	}
+
+	// The last response is returned readable
+	if body, _ := io.ReadAll(resp.Body); string(body) != "unavailable" {
+		t.Errorf("body = %q, want the last response body", body)
+	}
+}
+
+func TestRetryTransport_PolicyRetryable500(t *testing.T) {
+	callCount := 0
+	base := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			callCount++
+			code := http.StatusInternalServerError
+			if callCount == 2 {
+				code = http.StatusOK
+			}
+			return &http.Response{StatusCode: code, Body: http.NoBody, Header: make(http.Header)}, nil
+		},
+	}
+
+	transport := NewRetryTransport(base, 3, time.Millisecond)
+	transport.SetPolicy(RetryPolicy{RetryableStatusCodes: []int{500}})
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("RoundTrip() = %v, %v, want 200 after retrying the 500", resp, err)
+	}
+	if callCount != 2 {
+		t.Errorf("callCount = %d, want 2", callCount)
+	}
+}
+
+func TestRetryTransport_IdempotencyKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		key        string
+		wantCalls  int
+		wantHeader string
+	}{
+		{name: "POST with key", method: http.MethodPost, key: "key-123", wantCalls: 2, wantHeader: "key-123"},
+		{name: "PATCH with key", method: http.MethodPatch, key: "key-456", wantCalls: 2, wantHeader: "key-456"},
+		{name: "POST without key", method: http.MethodPost, wantCalls: 1},
+		{name: "PUT with key", method: http.MethodPut, key: "key-789", wantCalls: 2, wantHeader: "key-789"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			var headers []string
+			base := &mockRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					calls++
+					headers = append(headers, req.Header.Get(IdempotencyKeyHeader))
+					code := http.StatusServiceUnavailable
+					if calls > 1 {
+						code = http.StatusCreated
+					}
+					return &http.Response{StatusCode: code, Body: http.NoBody, Header: make(http.Header)}, nil
+				},
+			}
+			transport := NewRetryTransport(base, 3, time.Millisecond)
+
+			ctx := WithIdempotencyKey(context.Background(), tt.key)
+			req := httptest.NewRequest(tt.method, "http://example.com", nil).WithContext(ctx)
+			if _, err := transport.RoundTrip(req); err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			for i, h := range headers {
+				if h != tt.wantHeader {
+					t.Errorf("attempt %d %s = %q, want %q", i, IdempotencyKeyHeader, h, tt.wantHeader)
+				}
+			}
+			if req.Header.Get(IdempotencyKeyHeader) != "" {
+				t.Error("original request was modified")
+			}
+		})
+	}
+}
+
+func TestRetryTransport_RewindsBody(t *testing.T) {
+	const payload = `{"valor":{"original":"10.00"}}`
+
+	tests := []struct {
+		name   string
+		newReq func() *http.Request
+	}{
+		{
+			name: "with GetBody",
+			newReq: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodPut, "http://example.com/cob/txid", strings.NewReader(payload))
+				return req
+			},
+		},
+		{
+			name: "without GetBody",
+			newReq: func() *http.Request {
+				return httptest.NewRequest(http.MethodPut, "http://example.com/cob/txid", strings.NewReader(payload))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bodies []string
+			base := &mockRoundTripper{
+				roundTripFunc: func(req *http.Request) (*http.Response, error) {
+					data, err := io.ReadAll(req.Body)
+					if err != nil {
+						t.Fatalf("failed to read body: %v", err)
+					}
+					req.Body.Close()
+					bodies = append(bodies, string(data))
+
+					code := http.StatusServiceUnavailable
+					if len(bodies) == 3 {
+						code = http.StatusOK
+					}
+					return &http.Response{StatusCode: code, Body: http.NoBody, Header: make(http.Header)}, nil
+				},
+			}
+			transport := NewRetryTransport(base, 3, time.Millisecond)
+
+			if _, err := transport.RoundTrip(tt.newReq()); err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+
+			if len(bodies) != 3 {
+				t.Fatalf("attempts = %d, want 3", len(bodies))
+			}
+			for i, body := range bodies {
+				if body != payload {
+					t.Errorf("attempt %d body = %q, want %q", i, body, payload)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransport_PolicyJitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		attempt  int
+		min, max time.Duration
+	}{
+		{"proportional", RetryPolicy{}, 2, 300 * time.Millisecond, 500 * time.Millisecond},
+		{"proportional capped", RetryPolicy{MaxBackoff: 350 * time.Millisecond}, 2, 262500 * time.Microsecond, 350 * time.Millisecond},
+		{"none", RetryPolicy{Jitter: JitterNone}, 2, 400 * time.Millisecond, 400 * time.Millisecond},
+		{"none capped", RetryPolicy{Jitter: JitterNone, MaxBackoff: time.Second}, 20, time.Second, time.Second},
+		{"full", RetryPolicy{Jitter: JitterFull}, 2, 0, 400 * time.Millisecond},
+		{"full capped", RetryPolicy{Jitter: JitterFull, MaxBackoff: time.Second}, 20, 0, time.Second},
+		{"equal", RetryPolicy{Jitter: JitterEqual}, 2, 200 * time.Millisecond, 400 * time.Millisecond},
+		{"equal capped", RetryPolicy{Jitter: JitterEqual, MaxBackoff: time.Second}, 20, 500 * time.Millisecond, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := NewRetryTransport(&mockRoundTripper{}, 30, 100*time.Millisecond)
+			transport.SetPolicy(tt.policy)
+
+			for range 100 {
+				if got := transport.calculateBackoff(tt.attempt); got < tt.min || got > tt.max {
+					t.Fatalf("calculateBackoff(%d) = %v, want between %v and %v", tt.attempt, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransport_BackoffExceedsDeadline(t *testing.T) {
+	callCount := 0
+	base := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			callCount++
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody, Header: make(http.Header)}, nil
+		},
+	}
+
+	transport := NewRetryTransport(base, 3, time.Second)
+	transport.SetPolicy(RetryPolicy{Jitter: JitterNone})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil).WithContext(ctx)
+
+	start := time.Now()
+	_, err := transport.RoundTrip(req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RoundTrip() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("RoundTrip() took %v, want to fail without sleeping", elapsed)
+	}
+	if callCount != 1 {
+		t.Errorf("callCount = %d, want 1", callCount)
+	}
+}
+
+func TestRetryTransport_OnRetry(t *testing.T) {
+	callCount := 0
+	netErr := errors.New("connection reset")
+	base := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			callCount++
+			switch callCount {
+			case 1:
+				return &http.Response{StatusCode: http.StatusTooManyRequests, Body: http.NoBody, Header: make(http.Header)}, nil
+			case 2:
+				return nil, netErr
+			default:
+				return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: make(http.Header)}, nil
+			}
+		},
+	}
+
+	var buf bytes.Buffer
+	var attempts []RetryAttempt
+	transport := NewRetryTransport(base, 3, time.Millisecond)
+	transport.SetLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+	transport.OnRetry(func(ctx context.Context, a RetryAttempt) {
+		attempts = append(attempts, a)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/pix-bb/v1/cob/7978c0c97ea847e78e8849634473c1f1", nil)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+
+	if len(attempts) != 2 {
+		t.Fatalf("retries notified = %d, want 2", len(attempts))
+	}
+	for i, a := range attempts {
+		if a.Attempt != i+1 || a.Method != http.MethodGet || a.Endpoint != "/pix-bb/v1/cob/{id}" || a.Backoff <= 0 {
+			t.Errorf("attempt %d = %+v", i, a)
+		}
+	}
+	if attempts[0].StatusCode != http.StatusTooManyRequests || attempts[0].Err != nil {
+		t.Errorf("first retry = %+v, want status 429", attempts[0])
+	}
+	if attempts[1].StatusCode != 0 || attempts[1].Err != netErr {
+		t.Errorf("second retry = %+v, want the network error", attempts[1])
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("log lines = %d, want 2", len(lines))
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("failed to parse log line: %v", err)
+	}
+	if entry["msg"] != "Retrying HTTP request" || entry["attempt"] != float64(1) || entry["status"] != float64(429) || entry["endpoint"] != "/pix-bb/v1/cob/{id}" {
+		t.Errorf("log entry = %v", entry)
+	}
+}
@@ -1,17 +1,38 @@
 package transport
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"reflect"
+	"sync"
+	"time"
 
 	"github.com/pericles-luz/go-bb-pix/internal/auth"
+	httpclient "github.com/pericles-luz/go-bb-pix/internal/http"
 )
 
+// DefaultAppKeyHeader is the header used to send the developer application key
+const DefaultAppKeyHeader = "gw-dev-app-key"
+
+// maxDrainBytes is how much of a rejected response body is read before it
+// is closed, so its connection can be reused
+const maxDrainBytes = 64 << 10
+
 // AuthTransport is an http.RoundTripper that injects OAuth2 authentication
 type AuthTransport struct {
 	base            http.RoundTripper
 	tokenProvider   auth.TokenProvider
 	developerAppKey string
+	appKeyHeader    string
+	drift           *driftTracker
+
+	// tokens counts the distinct access tokens used
+	mu        sync.Mutex
+	lastToken string
+	tokens    uint64
 }
 
 // NewAuthTransport creates a new AuthTransport
@@ -24,25 +45,136 @@ func NewAuthTransport(base http.RoundTripper, provider auth.TokenProvider, devel
 		base:            base,
 		tokenProvider:   provider,
 		developerAppKey: developerAppKey,
+		appKeyHeader:    DefaultAppKeyHeader,
+		drift:           &driftTracker{now: time.Now},
+	}
+}
+
+// SetAppKeyHeader sets the header used to send the developer application key
+// An empty name restores DefaultAppKeyHeader
+func (t *AuthTransport) SetAppKeyHeader(name string) {
+	if name == "" {
+		name = DefaultAppKeyHeader
+	}
+	t.appKeyHeader = name
+}
+
+// SetLogger sets the logger used to warn about tokens rejected before their
+// declared expiry
+func (t *AuthTransport) SetLogger(logger *slog.Logger) {
+	t.drift.mu.Lock()
+	defer t.drift.mu.Unlock()
+	t.drift.logger = logger
+}
+
+// OnTokenRejected sets a function called for every 401 response, e.g. to
+// export the token drift as a metric
+func (t *AuthTransport) OnTokenRejected(fn TokenRejectedFunc) {
+	t.drift.mu.Lock()
+	defer t.drift.mu.Unlock()
+	t.drift.hook = fn
+}
+
+// TokenDrift returns the statistics of the tokens rejected so far
+func (t *AuthTransport) TokenDrift() TokenDriftStats {
+	return t.drift.snapshot()
+}
+
+// TokenRefreshes returns the number of access tokens obtained from the
+// provider so far, the first one included
+func (t *AuthTransport) TokenRefreshes() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tokens
+}
+
+// countToken counts token if it differs from the last one used
+func (t *AuthTransport) countToken(token *auth.Token) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if token.AccessToken != t.lastToken {
+		t.lastToken = token.AccessToken
+		t.tokens++
 	}
 }
 
 // RoundTrip implements http.RoundTripper
+// A request rejected with 401 is sent once more when the provider has a
+// fresh token, e.g. after the token expired early, and its body can be
+// rewound.
 func (t *AuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	// Get token
+	token, err := t.token(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.send(req, token)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	// The token was invalidated, retry with a fresh one; the 401 is
+	// returned when there is none
+	fresh, err := t.tokenProvider.GetToken(req.Context())
+	if err != nil || fresh.AccessToken == token.AccessToken {
+		return resp, nil
+	}
+	if err := checkAuthHeaders(fresh, t.appKeyHeader, t.developerAppKey); err != nil {
+		return resp, nil
+	}
+	t.countToken(fresh)
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+	resp.Body.Close()
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+		r := new(http.Request)
+		*r = *req
+		r.Body = body
+		req = r
+	}
+	return t.send(req, fresh)
+}
+
+// token returns the token of the provider for req
+func (t *AuthTransport) token(req *http.Request) (*auth.Token, error) {
 	token, err := t.tokenProvider.GetToken(req.Context())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get auth token: %w", err)
 	}
+	if err := checkAuthHeaders(token, t.appKeyHeader, t.developerAppKey); err != nil {
+		return nil, err
+	}
+	t.countToken(token)
+	return token, nil
+}
+
+// checkAuthHeaders checks that the token and app key are valid header
+// values
+func checkAuthHeaders(token *auth.Token, appKeyHeader, appKey string) error {
+	if err := httpclient.CheckHeaderValue("Authorization", token.TokenType+" "+token.AccessToken); err != nil {
+		return err
+	}
+	return httpclient.CheckHeaderValue(appKeyHeader, appKey)
+}
 
-	// Clone request to avoid modifying the original
-	req = cloneRequest(req)
+// send sends req with token, invalidating it on 401
+func (t *AuthTransport) send(req *http.Request, token *auth.Token) (*http.Response, error) {
+	// Avoid modifying the original request
+	req = ownHeader(req)
 
 	// Add Authorization header
-	req.Header.Set("Authorization", fmt.Sprintf("%s %s", token.TokenType, token.AccessToken))
+	req.Header.Set("Authorization", token.TokenType+" "+token.AccessToken)
 
 	// Add Developer Application Key header
-	req.Header.Set("gw-dev-app-key", t.developerAppKey)
+	req.Header.Set(t.appKeyHeader, t.developerAppKey)
 
 	// Execute request
 	resp, err := t.base.RoundTrip(req)
@@ -50,24 +182,72 @@ func (t *AuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		return nil, err
 	}
 
-	// If we get 401, invalidate the token for next request
+	// If we get 401, record the drift and invalidate the token
 	if resp.StatusCode == http.StatusUnauthorized {
-		t.tokenProvider.Invalidate()
+		t.drift.record(req.Context(), token)
+		t.invalidate(req.Context(), token)
 	}
 
 	return resp, nil
 }
 
-// cloneRequest creates a shallow copy of the request with a cloned header
+// invalidate invalidates the rejected token, leaving a newer one in place
+// when the provider supports it
+func (t *AuthTransport) invalidate(ctx context.Context, token *auth.Token) {
+	if p, ok := t.tokenProvider.(auth.TokenInvalidator); ok {
+		p.InvalidateToken(ctx, token)
+		return
+	}
+	t.tokenProvider.Invalidate()
+}
+
+// cloneRequest creates a shallow copy of the request with a deep copy of
+// its headers
 func cloneRequest(req *http.Request) *http.Request {
 	r := new(http.Request)
 	*r = *req
 
-	// Deep copy headers
-	r.Header = make(http.Header, len(req.Header))
-	for k, v := range req.Header {
-		r.Header[k] = append([]string(nil), v...)
+	r.Header = req.Header.Clone()
+	if r.Header == nil {
+		r.Header = make(http.Header)
+	}
+
+	return r
+}
+
+// ownedHeaderKey is the context key of the header map copied for a request
+// by the transport chain
+type ownedHeaderKey struct{}
+
+// ownHeader returns req with a header map the transport may modify
+// The first transport of the chain to modify the headers of a request
+// copies them; the next transports and the retries of the request reuse
+// that copy, so the headers are copied once per request and the caller's
+// request is left untouched.
+func ownHeader(req *http.Request) *http.Request {
+	return ownHeaderContext(req, req.Context())
+}
+
+// ownHeaderContext is ownHeader for a request whose context is replaced by
+// ctx, which must derive from the context of req
+func ownHeaderContext(req *http.Request, ctx context.Context) *http.Request {
+	if owned, ok := ctx.Value(ownedHeaderKey{}).(http.Header); ok && sameHeader(owned, req.Header) {
+		if ctx == req.Context() {
+			return req
+		}
+		return req.WithContext(ctx)
 	}
 
+	header := req.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	r := req.WithContext(context.WithValue(ctx, ownedHeaderKey{}, header))
+	r.Header = header
 	return r
 }
+
+// sameHeader reports whether a and b are the same map
+func sameHeader(a, b http.Header) bool {
+	return a != nil && b != nil && reflect.ValueOf(a).UnsafePointer() == reflect.ValueOf(b).UnsafePointer()
+}
@@ -0,0 +1,117 @@
+package transport
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// EndpointStats are the counters of the requests to an endpoint
+type EndpointStats struct {
+	Method   string
+	Endpoint string // request path with its identifiers replaced by {id}
+
+	// Requests counts the finished requests, retries included in a single
+	// request
+	Requests uint64
+	// StatusCodes counts the requests by the status of their last response
+	StatusCodes map[int]uint64
+	// Errors counts the requests that failed without a response
+	Errors uint64
+	// Retries counts the attempts after the first one
+	Retries uint64
+	// Duration is the total time spent on the requests
+	Duration time.Duration
+}
+
+// Stats is a snapshot of the counters of a client
+type Stats struct {
+	Requests            uint64
+	Errors              uint64
+	Retries             uint64
+	CircuitBreakerOpens uint64
+
+	// Endpoints are the counters of each endpoint, sorted by endpoint and
+	// method
+	Endpoints []EndpointStats
+}
+
+// endpointKey identifies the counters of an endpoint
+type endpointKey struct {
+	method   string
+	endpoint string
+}
+
+// StatsRecorder is a MetricsRecorder that keeps counters in memory, for a
+// snapshot of the client activity without a metrics system
+type StatsRecorder struct {
+	mu        sync.Mutex
+	endpoints map[endpointKey]*EndpointStats
+	opens     uint64
+}
+
+// NewStatsRecorder creates a new StatsRecorder
+func NewStatsRecorder() *StatsRecorder {
+	return &StatsRecorder{endpoints: make(map[endpointKey]*EndpointStats)}
+}
+
+// ObserveRequest implements MetricsRecorder
+func (r *StatsRecorder) ObserveRequest(ctx context.Context, m RequestMetrics) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := endpointKey{method: m.Method, endpoint: m.Endpoint}
+	s, ok := r.endpoints[key]
+	if !ok {
+		s = &EndpointStats{Method: m.Method, Endpoint: m.Endpoint, StatusCodes: make(map[int]uint64)}
+		r.endpoints[key] = s
+	}
+
+	s.Requests++
+	if m.StatusCode != 0 {
+		s.StatusCodes[m.StatusCode]++
+	} else {
+		s.Errors++
+	}
+	s.Retries += uint64(m.Retries)
+	s.Duration += m.Duration
+}
+
+// ObserveCircuitState implements MetricsRecorder
+func (r *StatsRecorder) ObserveCircuitState(from, to CircuitState) {
+	if to != CircuitOpen {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.opens++
+}
+
+// Snapshot returns a copy of the counters
+func (r *StatsRecorder) Snapshot() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := Stats{
+		CircuitBreakerOpens: r.opens,
+		Endpoints:           make([]EndpointStats, 0, len(r.endpoints)),
+	}
+	for _, s := range r.endpoints {
+		e := *s
+		e.StatusCodes = maps.Clone(s.StatusCodes)
+		stats.Endpoints = append(stats.Endpoints, e)
+
+		stats.Requests += e.Requests
+		stats.Errors += e.Errors
+		stats.Retries += e.Retries
+	}
+
+	slices.SortFunc(stats.Endpoints, func(a, b EndpointStats) int {
+		return cmp.Or(cmp.Compare(a.Endpoint, b.Endpoint), cmp.Compare(a.Method, b.Method))
+	})
+	return stats
+}
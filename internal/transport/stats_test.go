@@ -0,0 +1,51 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStatsRecorder(t *testing.T) {
+	r := NewStatsRecorder()
+	ctx := context.Background()
+
+	r.ObserveRequest(ctx, RequestMetrics{Method: "GET", Endpoint: "/cob/{id}", StatusCode: 200, Duration: 10 * time.Millisecond, Retries: 2})
+	r.ObserveRequest(ctx, RequestMetrics{Method: "GET", Endpoint: "/cob/{id}", StatusCode: 200, Duration: 20 * time.Millisecond})
+	r.ObserveRequest(ctx, RequestMetrics{Method: "GET", Endpoint: "/cob/{id}", StatusCode: 404, Duration: 5 * time.Millisecond})
+	r.ObserveRequest(ctx, RequestMetrics{Method: "PUT", Endpoint: "/cob/{id}", Err: errors.New("connection reset"), Retries: 3})
+	r.ObserveRequest(ctx, RequestMetrics{Method: "GET", Endpoint: "/cob", StatusCode: 200})
+	r.ObserveCircuitState(CircuitClosed, CircuitOpen)
+	r.ObserveCircuitState(CircuitOpen, CircuitHalfOpen)
+	r.ObserveCircuitState(CircuitHalfOpen, CircuitOpen)
+
+	stats := r.Snapshot()
+	if stats.Requests != 5 || stats.Errors != 1 || stats.Retries != 5 || stats.CircuitBreakerOpens != 2 {
+		t.Errorf("totals = %+v, want 5 requests, 1 error, 5 retries and 2 opens", stats)
+	}
+
+	if len(stats.Endpoints) != 3 {
+		t.Fatalf("endpoints = %d, want 3", len(stats.Endpoints))
+	}
+	order := []string{"GET /cob", "GET /cob/{id}", "PUT /cob/{id}"}
+	for i, e := range stats.Endpoints {
+		if got := e.Method + " " + e.Endpoint; got != order[i] {
+			t.Errorf("endpoint %d = %s, want %s", i, got, order[i])
+		}
+	}
+
+	get := stats.Endpoints[1]
+	if get.Requests != 3 || get.StatusCodes[200] != 2 || get.StatusCodes[404] != 1 || get.Retries != 2 || get.Duration != 35*time.Millisecond {
+		t.Errorf("GET /cob/{id} = %+v", get)
+	}
+	if put := stats.Endpoints[2]; put.Requests != 1 || put.Errors != 1 || len(put.StatusCodes) != 0 {
+		t.Errorf("PUT /cob/{id} = %+v", put)
+	}
+
+	// The snapshot is a copy
+	get.StatusCodes[200] = 100
+	if r.Snapshot().Endpoints[1].StatusCodes[200] != 2 {
+		t.Error("snapshot shares its status counters with the recorder")
+	}
+}
@@ -0,0 +1,47 @@
+package transport
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestTracer traces the API requests, e.g. with OpenTelemetry
+type RequestTracer interface {
+	// StartRequest is called before a request is sent, retries included,
+	// and returns the request to send, e.g. with a span in its context and
+	// the trace propagation headers, and a function called with the
+	// metrics of the request once it finishes
+	// The returned request must be a copy when it differs from req.
+	StartRequest(req *http.Request) (*http.Request, func(RequestMetrics))
+}
+
+// TracingTransport is an http.RoundTripper that traces each request with a
+// RequestTracer
+type TracingTransport struct {
+	base   http.RoundTripper
+	tracer RequestTracer
+}
+
+// NewTracingTransport creates a new TracingTransport
+func NewTracingTransport(base http.RoundTripper, tracer RequestTracer) *TracingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &TracingTransport{
+		base:   base,
+		tracer: tracer,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *TracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, end := t.tracer.StartRequest(req)
+	req, retries := withRetryCounter(req)
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	end(requestMetrics(req, resp, err, start, retries))
+	return resp, err
+}
@@ -0,0 +1,67 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// spanContextKey is the context key of the fake span
+type spanContextKey struct{}
+
+// fakeTracer records the requests it traces
+type fakeTracer struct {
+	ended []RequestMetrics
+}
+
+func (f *fakeTracer) StartRequest(req *http.Request) (*http.Request, func(RequestMetrics)) {
+	req = cloneRequest(req).WithContext(context.WithValue(req.Context(), spanContextKey{}, "span-1"))
+	req.Header.Set("Traceparent", "00-trace-span-01")
+	return req, func(m RequestMetrics) {
+		f.ended = append(f.ended, m)
+	}
+}
+
+func TestTracingTransport(t *testing.T) {
+	var calls int
+	base := &mockRoundTripper{
+		roundTripFunc: func(req *http.Request) (*http.Response, error) {
+			calls++
+			if req.Header.Get("Traceparent") == "" || req.Context().Value(spanContextKey{}) != "span-1" {
+				t.Errorf("attempt %d sent without the span", calls)
+			}
+			code := http.StatusServiceUnavailable
+			if calls == 3 {
+				code = http.StatusOK
+			}
+			return &http.Response{StatusCode: code, Body: http.NoBody, Header: make(http.Header)}, nil
+		},
+	}
+
+	tracer := &fakeTracer{}
+	recorder := &recordingMetrics{}
+	retry := NewRetryTransport(base, 3, time.Millisecond)
+	transport := NewMetricsTransport(NewTracingTransport(retry, tracer), recorder)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/pix-bb/v1/cob/abc123", nil)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+
+	if len(tracer.ended) != 1 {
+		t.Fatalf("ended %d spans, want 1", len(tracer.ended))
+	}
+	if got := tracer.ended[0]; got.Endpoint != "/pix-bb/v1/cob/{id}" || got.StatusCode != http.StatusOK || got.Retries != 2 {
+		t.Errorf("span metrics = %+v, want /pix-bb/v1/cob/{id} 200 after 2 retries", got)
+	}
+
+	// The metrics share the retry count of the span
+	if len(recorder.requests) != 1 || recorder.requests[0].Retries != 2 {
+		t.Errorf("metrics = %+v, want 2 retries", recorder.requests)
+	}
+	if req.Header.Get("Traceparent") != "" {
+		t.Error("original request was modified")
+	}
+}
@@ -0,0 +1,73 @@
+package pix
+
+import "time"
+
+// MaxPaymentListWindow is the widest inicio/fim interval requested per
+// /pix listing call when splitting larger periods
+const MaxPaymentListWindow = 5 * 24 * time.Hour
+
+// PaymentWindow is a time interval used to list received payments
+type PaymentWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Params returns ListPaymentsParams covering the window
+func (w PaymentWindow) Params() ListPaymentsParams {
+	return ListPaymentsParams{
+		StartDate: w.Start,
+		EndDate:   w.End,
+	}
+}
+
+// SplitPaymentWindow splits [start, end] into consecutive windows no wider
+// than size. A non-positive size uses MaxPaymentListWindow.
+func SplitPaymentWindow(start, end time.Time, size time.Duration) []PaymentWindow {
+	if !end.After(start) {
+		return nil
+	}
+	if size <= 0 {
+		size = MaxPaymentListWindow
+	}
+
+	var windows []PaymentWindow
+	for cur := start; cur.Before(end); cur = cur.Add(size) {
+		next := cur.Add(size)
+		if next.After(end) {
+			next = end
+		}
+		windows = append(windows, PaymentWindow{Start: cur, End: next})
+	}
+
+	return windows
+}
+
+// BackfillWindows computes the list windows needed to recover payments whose
+// webhook notifications were missed. lastProcessed maps each PIX key to the
+// horario of the last payment processed for it; keys with a zero time are
+// ignored. The gaps from every key up to until are merged and split into
+// windows no wider than maxWindow, ordered by start time.
+//
+// Windows start at the last processed horario (truncated to the second,
+// which is the precision of the inicio parameter), so the last processed
+// payment is listed again; callers should dedupe by EndToEndID.
+func BackfillWindows(lastProcessed map[string]time.Time, until time.Time, maxWindow time.Duration) []PaymentWindow {
+	var oldest time.Time
+	for _, last := range lastProcessed {
+		if last.IsZero() {
+			continue
+		}
+		last = last.Truncate(time.Second)
+		if oldest.IsZero() || last.Before(oldest) {
+			oldest = last
+		}
+	}
+
+	// Every gap ends at until, so the union of all gaps is a single
+	// interval starting at the oldest checkpoint
+	if oldest.IsZero() || !oldest.Before(until) {
+		return nil
+	}
+
+	return SplitPaymentWindow(oldest, until, maxWindow)
+}
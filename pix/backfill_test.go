@@ -0,0 +1,118 @@
+package pix
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSplitPaymentWindow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		end       time.Time
+		size      time.Duration
+		wantCount int
+		wantLast  time.Time
+	}{
+		{
+			name:      "single window",
+			end:       start.Add(24 * time.Hour),
+			size:      48 * time.Hour,
+			wantCount: 1,
+			wantLast:  start.Add(24 * time.Hour),
+		},
+		{
+			name:      "exact multiple",
+			end:       start.Add(10 * 24 * time.Hour),
+			size:      0,
+			wantCount: 2,
+			wantLast:  start.Add(10 * 24 * time.Hour),
+		},
+		{
+			name:      "partial last window",
+			end:       start.Add(25 * time.Hour),
+			size:      12 * time.Hour,
+			wantCount: 3,
+			wantLast:  start.Add(25 * time.Hour),
+		},
+		{
+			name:      "empty range",
+			end:       start,
+			size:      time.Hour,
+			wantCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			windows := SplitPaymentWindow(start, tt.end, tt.size)
+			if len(windows) != tt.wantCount {
+				t.Fatalf("len(windows) = %d, want %d", len(windows), tt.wantCount)
+			}
+			if tt.wantCount == 0 {
+				return
+			}
+			if !windows[0].Start.Equal(start) {
+				t.Errorf("first Start = %v, want %v", windows[0].Start, start)
+			}
+			if !windows[len(windows)-1].End.Equal(tt.wantLast) {
+				t.Errorf("last End = %v, want %v", windows[len(windows)-1].End, tt.wantLast)
+			}
+			for i := 1; i < len(windows); i++ {
+				if !windows[i].Start.Equal(windows[i-1].End) {
+					t.Errorf("window %d does not start where window %d ends", i, i-1)
+				}
+			}
+		})
+	}
+}
+
+func TestBackfillWindows(t *testing.T) {
+	until := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	lastProcessed := map[string]time.Time{
+		"chave-a": time.Date(2024, 1, 9, 8, 30, 15, 500, time.UTC),
+		"chave-b": time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC),
+		"chave-c": {},
+	}
+
+	windows := BackfillWindows(lastProcessed, until, 0)
+	if len(windows) != 2 {
+		t.Fatalf("len(windows) = %d, want 2", len(windows))
+	}
+
+	wantStart := time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC)
+	if !windows[0].Start.Equal(wantStart) {
+		t.Errorf("Start = %v, want %v", windows[0].Start, wantStart)
+	}
+	if !windows[1].End.Equal(until) {
+		t.Errorf("End = %v, want %v", windows[1].End, until)
+	}
+
+	params := windows[0].Params()
+	if !params.StartDate.Equal(windows[0].Start) || !params.EndDate.Equal(windows[0].End) {
+		t.Error("Params() should cover the window")
+	}
+}
+
+func TestBackfillWindows_NoGap(t *testing.T) {
+	until := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		lastProcessed map[string]time.Time
+	}{
+		{name: "nil map", lastProcessed: nil},
+		{name: "only zero times", lastProcessed: map[string]time.Time{"chave": {}}},
+		{name: "checkpoint at until", lastProcessed: map[string]time.Time{"chave": until}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if windows := BackfillWindows(tt.lastProcessed, until, time.Hour); windows != nil {
+				t.Errorf("BackfillWindows() = %v, want nil", windows)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package pix
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ChargeKind identifies the API a charge belongs to
+type ChargeKind string
+
+// Charge kinds
+const (
+	// ChargeKindCob is an immediate charge (cob)
+	ChargeKindCob ChargeKind = "cob"
+
+	// ChargeKindCobV is a charge with due date (cobv)
+	ChargeKindCobV ChargeKind = "cobv"
+)
+
+// Charge is the common view of an immediate charge or a charge with due
+// date returned by ListCharges
+type Charge struct {
+	Kind   ChargeKind
+	TxID   string
+	Status string
+	Amount string
+	// DueDate is the due date (YYYY-MM-DD) of cobv charges; empty for cob
+	DueDate string
+
+	// Cob holds the original charge when Kind is ChargeKindCob
+	Cob *QRCodeResponse
+	// CobV holds the original charge when Kind is ChargeKindCobV
+	CobV *CobVResponse
+}
+
+// ListChargesParams represents parameters for listing cob and cobv charges
+type ListChargesParams struct {
+	StartDate time.Time
+	EndDate   time.Time
+	CPF       string
+	CNPJ      string
+	Status    string
+	// PageSize is the itensPorPagina used for every page; zero uses
+	// DefaultPageSize (100)
+	PageSize int
+	// Prefetch is how many pages are fetched concurrently ahead of the one
+	// being processed; zero fetches one page at a time
+	Prefetch int
+}
+
+// ListCharges lists immediate charges (cob) and charges with due date
+// (cobv) created within the window, following every page of both APIs.
+// Immediate charges come first, each group in the order returned by the API.
+func (c *Client) ListCharges(ctx context.Context, params ListChargesParams) ([]Charge, error) {
+	var charges []Charge
+
+	cobParams := ListQRCodesParams{
+		StartDate: params.StartDate,
+		EndDate:   params.EndDate,
+		CPF:       params.CPF,
+		CNPJ:      params.CNPJ,
+		Status:    params.Status,
+		PageSize:  params.PageSize,
+	}
+	cobPages := prefetchPages(ctx, params.Prefetch, func(ctx context.Context, page int) (*QRCodeListResponse, int, error) {
+		pageParams := cobParams
+		pageParams.Page = page
+
+		resp, err := c.ListQRCodes(ctx, pageParams)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp, resp.Parameters.Pagination.TotalPages, nil
+	})
+	for resp, err := range cobPages {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list charges: %w", err)
+		}
+		for i := range resp.QRCodes {
+			charges = append(charges, chargeFromCob(&resp.QRCodes[i]))
+		}
+	}
+
+	cobvParams := ListCobVParams{
+		StartDate: params.StartDate,
+		EndDate:   params.EndDate,
+		CPF:       params.CPF,
+		CNPJ:      params.CNPJ,
+		Status:    params.Status,
+		PageSize:  params.PageSize,
+	}
+	cobvPages := prefetchPages(ctx, params.Prefetch, func(ctx context.Context, page int) (*CobVListResponse, int, error) {
+		pageParams := cobvParams
+		pageParams.Page = page
+
+		resp, err := c.ListCobV(ctx, pageParams)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp, resp.Parameters.Pagination.TotalPages, nil
+	})
+	for resp, err := range cobvPages {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list charges: %w", err)
+		}
+		for i := range resp.Charges {
+			charges = append(charges, chargeFromCobV(&resp.Charges[i]))
+		}
+	}
+
+	return charges, nil
+}
+
+// chargeFromCob converts an immediate charge to a Charge
+func chargeFromCob(cob *QRCodeResponse) Charge {
+	return Charge{
+		Kind:   ChargeKindCob,
+		TxID:   cob.TxID,
+		Status: cob.Status,
+		Amount: cob.Value.Original,
+		Cob:    cob,
+	}
+}
+
+// chargeFromCobV converts a charge with due date to a Charge
+func chargeFromCobV(cobv *CobVResponse) Charge {
+	return Charge{
+		Kind:    ChargeKindCobV,
+		TxID:    cobv.TxID,
+		Status:  cobv.Status,
+		Amount:  cobv.Value.Original,
+		DueDate: cobv.Calendar.DueDate,
+		CobV:    cobv,
+	}
+}
@@ -0,0 +1,128 @@
+package pix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_ListCobV_QueryParams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Method = %s, want GET", r.Method)
+		}
+		if r.URL.Path != "/cobv" {
+			t.Errorf("Path = %s, want /cobv", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("inicio") != "2024-01-01T00:00:00Z" {
+			t.Errorf("inicio = %s, want 2024-01-01T00:00:00Z", query.Get("inicio"))
+		}
+		if query.Get("cpf") != "12345678909" {
+			t.Errorf("cpf = %s, want 12345678909", query.Get("cpf"))
+		}
+		if query.Get("paginacao.paginaAtual") != "2" {
+			t.Errorf("paginaAtual = %s, want 2", query.Get("paginacao.paginaAtual"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"parametros":{"inicio":"2024-01-01T00:00:00Z","fim":"2024-01-31T23:59:59Z","paginacao":{"paginaAtual":2,"itensPorPagina":100,"quantidadeDePaginas":3,"quantidadeTotalDeItens":201}},"cobs":[{"calendario":{"dataDeVencimento":"2024-02-10"},"txid":"cobv1","status":"ATIVA","valor":{"original":"50.00"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	resp, err := client.ListCobV(context.Background(), ListCobVParams{
+		StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC),
+		CPF:       "12345678909",
+		Page:      2,
+	})
+	if err != nil {
+		t.Fatalf("ListCobV() error = %v", err)
+	}
+	if len(resp.Charges) != 1 || resp.Charges[0].Calendar.DueDate != "2024-02-10" {
+		t.Errorf("Charges = %+v, want one charge due 2024-02-10", resp.Charges)
+	}
+	if resp.Parameters.Pagination.TotalPages != 3 {
+		t.Errorf("TotalPages = %d, want 3", resp.Parameters.Pagination.TotalPages)
+	}
+}
+
+func TestClient_ListCharges_MergesCobAndCobV(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("paginacao.paginaAtual")
+		if page == "" {
+			page = "0"
+		}
+		requests = append(requests, r.URL.Path+"?"+page)
+
+		if r.URL.Query().Get("status") != ChargeStatusActive {
+			t.Errorf("status = %s, want %s", r.URL.Query().Get("status"), ChargeStatusActive)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/cob":
+			fmt.Fprintf(w, `{"parametros":{"paginacao":{"paginaAtual":%s,"quantidadeDePaginas":2}},"cobs":[{"txid":"cob%s","status":"ATIVA","valor":{"original":"10.00"}}]}`, page, page)
+		case "/cobv":
+			w.Write([]byte(`{"parametros":{"paginacao":{"paginaAtual":0,"quantidadeDePaginas":1}},"cobs":[{"calendario":{"dataDeVencimento":"2024-02-10"},"txid":"cobv0","status":"ATIVA","valor":{"original":"50.00"}}]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	charges, err := client.ListCharges(context.Background(), ListChargesParams{
+		StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC),
+		Status:    ChargeStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("ListCharges() error = %v", err)
+	}
+
+	wantRequests := []string{"/cob?0", "/cob?1", "/cobv?0"}
+	if fmt.Sprint(requests) != fmt.Sprint(wantRequests) {
+		t.Errorf("requests = %v, want %v", requests, wantRequests)
+	}
+
+	if len(charges) != 3 {
+		t.Fatalf("len(charges) = %d, want 3", len(charges))
+	}
+	if charges[0].Kind != ChargeKindCob || charges[0].TxID != "cob0" || charges[0].Cob == nil {
+		t.Errorf("charges[0] = %+v, want cob0", charges[0])
+	}
+	if charges[1].TxID != "cob1" || charges[1].Amount != "10.00" {
+		t.Errorf("charges[1] = %+v, want cob1 of 10.00", charges[1])
+	}
+	last := charges[2]
+	if last.Kind != ChargeKindCobV || last.DueDate != "2024-02-10" || last.Amount != "50.00" || last.CobV == nil {
+		t.Errorf("charges[2] = %+v, want cobv0 due 2024-02-10", last)
+	}
+}
+
+func TestClient_ListCharges_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/cobv" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"detail":"erro interno"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"cobs":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	if _, err := client.ListCharges(context.Background(), ListChargesParams{}); err == nil {
+		t.Error("ListCharges() error = nil, want error")
+	}
+}
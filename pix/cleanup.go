@@ -0,0 +1,128 @@
+package pix
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CleanupParams selects the test data removed by Cleanup
+// Only charges whose txid starts with TxIDPrefix and webhooks whose URL
+// starts with WebhookURLPrefix are touched; an empty prefix skips that
+// kind of data.
+type CleanupParams struct {
+	TxIDPrefix       string
+	WebhookURLPrefix string
+
+	// StartDate and EndDate bound the window searched for charges
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+// CleanupFailure records an item Cleanup could not remove
+type CleanupFailure struct {
+	// ID is the txid of a charge or the PIX key of a webhook
+	ID  string
+	Err error
+}
+
+// CleanupResult summarizes a Cleanup run
+type CleanupResult struct {
+	// RemovedCharges holds the txids of the removed charges
+	RemovedCharges []string
+	// RemovedWebhooks holds the PIX keys whose webhook was deleted
+	RemovedWebhooks []string
+	Failed          []CleanupFailure
+}
+
+// Cleanup removes the active charges (cob and cobv) and the webhooks created
+// by a test run, identified by the prefixes in params. Individual removal
+// failures are reported in the result; an error is returned only when the
+// data cannot be listed. It is meant for sandbox and homologation
+// environments that otherwise accumulate stale charges across CI runs.
+func (c *Client) Cleanup(ctx context.Context, params CleanupParams) (*CleanupResult, error) {
+	if params.TxIDPrefix == "" && params.WebhookURLPrefix == "" {
+		return nil, fmt.Errorf("a txid or webhook url prefix is required")
+	}
+
+	result := &CleanupResult{}
+
+	if params.TxIDPrefix != "" {
+		if err := c.cleanupCharges(ctx, params, result); err != nil {
+			return result, err
+		}
+	}
+
+	if params.WebhookURLPrefix != "" {
+		if err := c.cleanupWebhooks(ctx, params.WebhookURLPrefix, result); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// cleanupCharges removes the active charges whose txid has the prefix
+func (c *Client) cleanupCharges(ctx context.Context, params CleanupParams, result *CleanupResult) error {
+	charges, err := c.ListCharges(ctx, ListChargesParams{
+		StartDate: params.StartDate,
+		EndDate:   params.EndDate,
+		Status:    ChargeStatusActive,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cleanup charges: %w", err)
+	}
+
+	for _, charge := range charges {
+		if !strings.HasPrefix(charge.TxID, params.TxIDPrefix) {
+			continue
+		}
+
+		switch charge.Kind {
+		case ChargeKindCobV:
+			_, err = c.RemoveCobV(ctx, charge.TxID)
+		default:
+			_, err = c.RemoveQRCode(ctx, charge.TxID)
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, CleanupFailure{ID: charge.TxID, Err: err})
+			continue
+		}
+		result.RemovedCharges = append(result.RemovedCharges, charge.TxID)
+	}
+
+	return nil
+}
+
+// cleanupWebhooks deletes the webhooks whose URL has the prefix
+func (c *Client) cleanupWebhooks(ctx context.Context, prefix string, result *CleanupResult) error {
+	var webhooks []WebhookConfig
+
+	params := ListWebhooksParams{}
+	for {
+		resp, err := c.ListWebhooks(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to cleanup webhooks: %w", err)
+		}
+		webhooks = append(webhooks, resp.Webhooks...)
+		if params.Page+1 >= resp.Parameters.Pagination.TotalPages {
+			break
+		}
+		params.Page++
+	}
+
+	for _, webhook := range webhooks {
+		if webhook.Key == "" || !strings.HasPrefix(webhook.WebhookURL, prefix) {
+			continue
+		}
+
+		if err := c.DeleteWebhook(ctx, webhook.Key); err != nil {
+			result.Failed = append(result.Failed, CleanupFailure{ID: webhook.Key, Err: err})
+			continue
+		}
+		result.RemovedWebhooks = append(result.RemovedWebhooks, webhook.Key)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package pix
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_Cleanup_RemovesPrefixedData(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/cob":
+			w.Write([]byte(`{"cobs":[{"txid":"ci42abc","status":"ATIVA","valor":{"original":"1.00"}},{"txid":"prod1","status":"ATIVA","valor":{"original":"1.00"}}]}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/cobv":
+			w.Write([]byte(`{"cobs":[{"calendario":{"dataDeVencimento":"2024-02-10"},"txid":"ci42def","status":"ATIVA","valor":{"original":"1.00"}}]}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/webhook":
+			w.Write([]byte(`{"webhooks":[{"chave":"key-ci","webhookUrl":"https://ci.example.com/run42/pix"},{"chave":"key-prod","webhookUrl":"https://app.example.com/pix"}]}`))
+		case r.Method == http.MethodPatch:
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["status"] != ChargeStatusRemovedByReceiver {
+				t.Errorf("status = %s, want %s", body["status"], ChargeStatusRemovedByReceiver)
+			}
+			if r.URL.Path == "/cobv/ci42def" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"detail":"falha"}`))
+				return
+			}
+			w.Write([]byte(`{"status":"REMOVIDA_PELO_USUARIO_RECEBEDOR"}`))
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	result, err := client.Cleanup(context.Background(), CleanupParams{
+		TxIDPrefix:       "ci42",
+		WebhookURLPrefix: "https://ci.example.com/run42",
+		StartDate:        time.Now().Add(-time.Hour),
+		EndDate:          time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Cleanup() error = %v", err)
+	}
+
+	if len(result.RemovedCharges) != 1 || result.RemovedCharges[0] != "ci42abc" {
+		t.Errorf("RemovedCharges = %v, want [ci42abc]", result.RemovedCharges)
+	}
+	if len(result.RemovedWebhooks) != 1 || result.RemovedWebhooks[0] != "key-ci" {
+		t.Errorf("RemovedWebhooks = %v, want [key-ci]", result.RemovedWebhooks)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].ID != "ci42def" {
+		t.Errorf("Failed = %+v, want ci42def", result.Failed)
+	}
+
+	sort.Strings(requests)
+	for _, untouched := range []string{"PATCH /cob/prod1", "DELETE /webhook/key-prod"} {
+		if i := sort.SearchStrings(requests, untouched); i < len(requests) && requests[i] == untouched {
+			t.Errorf("%s should not be requested", untouched)
+		}
+	}
+}
+
+func TestClient_Cleanup_RequiresPrefix(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Server should not be called without a prefix")
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	if _, err := client.Cleanup(context.Background(), CleanupParams{}); err == nil {
+		t.Error("Cleanup() error = nil, want error")
+	}
+}
@@ -1,19 +1,166 @@
 package pix
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	httpclient "github.com/pericles-luz/go-bb-pix/internal/http"
+	"github.com/pericles-luz/go-bb-pix/redact"
 )
 
+// Re-export audit types from internal/http for public API
+type (
+	// AuditRecord describes a mutating request and its outcome as sent and
+	// received on the wire
+	AuditRecord = httpclient.AuditRecord
+
+	// AuditFunc receives an AuditRecord after every mutating request
+	AuditFunc = httpclient.AuditFunc
+
+	// Hooks are functions called around every API call
+	Hooks = httpclient.Hooks
+
+	// Call describes an API call passed to the Hooks
+	Call = httpclient.Call
+
+	// PaginationStyle selects the names of the paging query parameters
+	PaginationStyle = httpclient.PaginationStyle
+)
+
+// Pagination styles
+const (
+	// PaginationNested sends paginacao.paginaAtual and
+	// paginacao.itensPorPagina, as documented by the API specification
+	PaginationNested = httpclient.PaginationNested
+	// PaginationFlat sends paginaAtual and itensPorPagina, for gateways
+	// that do not accept the documented names
+	PaginationFlat = httpclient.PaginationFlat
+)
+
+// DefaultPageSize is the itensPorPagina sent when a listing does not set one
+const DefaultPageSize = httpclient.DefaultPageSize
+
+// ConvenioParam is the query parameter of the listings that carries the BB
+// agreement (convênio) number
+const ConvenioParam = "numeroConvenio"
+
+// Timeouts are the default durations of the API calls by operation class,
+// applied as context deadlines; a zero duration sets no deadline
+// A deadline already set on the context of a call is kept when earlier.
+type Timeouts struct {
+	// Read applies to the calls that fetch a single resource
+	Read time.Duration
+	// Write applies to the calls that create, update or remove a resource
+	Write time.Duration
+	// List applies to each page of the list calls
+	List time.Duration
+}
+
 // Client is the PIX API client
 type Client struct {
-	http *httpclient.Client
+	http       *httpclient.Client
+	normalizer *Normalizer
+	timeouts   Timeouts
+}
+
+// ClientOption is a functional option for configuring the PIX client
+type ClientOption func(*clientOptions)
+
+// clientOptions holds the options used to build the HTTP client
+type clientOptions struct {
+	http       []httpclient.Option
+	normalizer *Normalizer
+	timeouts   Timeouts
+}
+
+// WithAuditHook sets a function that receives the exact marshaled request
+// body and the response of every mutating operation
+func WithAuditHook(fn AuditFunc) ClientOption {
+	return func(opts *clientOptions) {
+		if fn != nil {
+			opts.http = append(opts.http, httpclient.WithAuditFunc(fn))
+		}
+	}
+}
+
+// WithHooks sets functions called before and after every API call, with
+// its method, path, status, duration and error
+func WithHooks(h Hooks) ClientOption {
+	return func(opts *clientOptions) {
+		opts.http = append(opts.http, httpclient.WithHooks(h))
+	}
+}
+
+// WithConvenio sends the BB agreement (convênio) number as the
+// numeroConvenio query parameter of the listings, the endpoints that take it
+func WithConvenio(numero string) ClientOption {
+	return func(opts *clientOptions) {
+		if numero != "" {
+			opts.http = append(opts.http, httpclient.WithListParam(ConvenioParam, numero))
+		}
+	}
+}
+
+// WithPaginationStyle sets the names of the paging query parameters sent by
+// the list operations
+// Default: PaginationNested
+func WithPaginationStyle(style PaginationStyle) ClientOption {
+	return func(opts *clientOptions) {
+		opts.http = append(opts.http, httpclient.WithPaginationStyle(style))
+	}
+}
+
+// WithNormalization normalizes debtor documents and names and truncates
+// texts with DefaultNormalizer before charges are created
+func WithNormalization() ClientOption {
+	return WithNormalizer(DefaultNormalizer())
+}
+
+// WithNormalizer normalizes charge creation requests with n
+func WithNormalizer(n Normalizer) ClientOption {
+	return func(opts *clientOptions) {
+		opts.normalizer = &n
+	}
+}
+
+// WithTimeouts sets the default durations of the read, write and list calls,
+// so quick lookups can fail fast while heavy listings get more time
+func WithTimeouts(t Timeouts) ClientOption {
+	return func(opts *clientOptions) {
+		opts.timeouts = t
+	}
+}
+
+// WithRedactor applies the pattern rules of r to the messages of the
+// errors returned by the client, including API error details
+func WithRedactor(r *redact.Redactor) ClientOption {
+	return func(opts *clientOptions) {
+		if r != nil {
+			opts.http = append(opts.http, httpclient.WithRedactor(r))
+		}
+	}
 }
 
 // NewClient creates a new PIX client
-func NewClient(httpClient *http.Client, apiURL string) *Client {
+func NewClient(httpClient *http.Client, apiURL string, opts ...ClientOption) *Client {
+	var options clientOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	return &Client{
-		http: httpclient.NewClient(httpClient, apiURL),
+		http:       httpclient.NewClient(httpClient, apiURL, options.http...),
+		normalizer: options.normalizer,
+		timeouts:   options.timeouts,
+	}
+}
+
+// withTimeout returns ctx with a deadline d from now, or ctx itself when d
+// is not positive
+func (c *Client) withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return ctx, func() {}
 	}
+	return context.WithTimeout(ctx, d)
 }
@@ -0,0 +1,101 @@
+package pix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// CreateCobV creates a charge with due date (cobv)
+func (c *Client) CreateCobV(ctx context.Context, txID string, req CobVRequest) (*CobVResponse, error) {
+	if txID == "" {
+		return nil, fmt.Errorf("txid is required")
+	}
+
+	if c.normalizer != nil {
+		req = c.normalizer.CobVRequest(req)
+	}
+
+	path := fmt.Sprintf("/cobv/%s", url.PathEscape(txID))
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.Write)
+	defer cancel()
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp CobVResponse
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to create cobv: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// ListCobV lists charges with due date (cobv) with optional filters
+func (c *Client) ListCobV(ctx context.Context, params ListCobVParams) (*CobVListResponse, error) {
+	path := "/cobv"
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.List)
+	defer cancel()
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Add query parameters
+	q := httpReq.URL.Query()
+	q.Set("inicio", params.StartDate.Format("2006-01-02T15:04:05Z07:00"))
+	q.Set("fim", params.EndDate.Format("2006-01-02T15:04:05Z07:00"))
+
+	if params.CPF != "" {
+		q.Set("cpf", params.CPF)
+	}
+	if params.CNPJ != "" {
+		q.Set("cnpj", params.CNPJ)
+	}
+	if params.Status != "" {
+		q.Set("status", params.Status)
+	}
+	c.http.SetPagination(q, params.Page, params.PageSize)
+
+	httpReq.URL.RawQuery = q.Encode()
+
+	var resp CobVListResponse
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list cobv: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// RemoveCobV removes a charge with due date by setting its status to
+// REMOVIDA_PELO_USUARIO_RECEBEDOR
+func (c *Client) RemoveCobV(ctx context.Context, txID string) (*CobVResponse, error) {
+	if txID == "" {
+		return nil, fmt.Errorf("txid is required")
+	}
+
+	path := fmt.Sprintf("/cobv/%s", url.PathEscape(txID))
+
+	body := removeChargeRequest{Status: ChargeStatusRemovedByReceiver}
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.Write)
+	defer cancel()
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodPatch, path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp CobVResponse
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to remove cobv: %w", err)
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,99 @@
+package pix
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CobVRequest represents a charge with due date (cobrança com vencimento)
+type CobVRequest struct {
+	Calendar          CobVCalendar `json:"calendario"`
+	Debtor            *Debtor      `json:"devedor,omitempty"`
+	Value             CobVValue    `json:"valor"`
+	Key               string       `json:"chave"`
+	PayerSolicitation string       `json:"solicitacaoPagador,omitempty"`
+
+	// LocationID references an existing location (loc.id)
+	// When zero, the bank creates a new location.
+	LocationID int `json:"-"`
+}
+
+// MarshalJSON implements custom JSON marshaling for CobVRequest
+func (r CobVRequest) MarshalJSON() ([]byte, error) {
+	type alias CobVRequest
+	return json.Marshal(struct {
+		alias
+		Loc *locationRef `json:"loc,omitempty"`
+	}{
+		alias: alias(r),
+		Loc:   newLocationRef(r.LocationID),
+	})
+}
+
+// CobVCalendar represents calendar for charges with due date
+type CobVCalendar struct {
+	DueDate       string `json:"dataDeVencimento"` // YYYY-MM-DD
+	ValidAfterDue int    `json:"validadeAposVencimento,omitempty"`
+}
+
+// CobVValue represents value with fines and interest
+type CobVValue struct {
+	Original string        `json:"original"`
+	Fine     *CobVModality `json:"multa,omitempty"`
+	Interest *CobVModality `json:"juros,omitempty"`
+	Discount *CobVDiscount `json:"desconto,omitempty"`
+}
+
+// CobVModality represents fine or interest modality
+type CobVModality struct {
+	Modality  string `json:"modalidade"` // "1" = fixed value, "2" = percentage
+	ValuePerc string `json:"valorPerc,omitempty"`
+}
+
+// CobVDiscount represents discount information
+type CobVDiscount struct {
+	Modality          string              `json:"modalidade"` // "1" = fixed date
+	FixedDateDiscount []FixedDateDiscount `json:"descontoDataFixa,omitempty"`
+}
+
+// FixedDateDiscount represents a discount for a specific date
+type FixedDateDiscount struct {
+	Date      string `json:"data"` // YYYY-MM-DD
+	ValuePerc string `json:"valorPerc"`
+}
+
+// CobVResponse represents a charge with due date response
+type CobVResponse struct {
+	Calendar          CobVCalendar `json:"calendario"`
+	TxID              string       `json:"txid"`
+	Revision          int          `json:"revisao"`
+	Loc               *Location    `json:"loc,omitempty"`
+	Location          string       `json:"location,omitempty"`
+	Status            string       `json:"status"`
+	Debtor            *Debtor      `json:"devedor,omitempty"`
+	Value             CobVValue    `json:"valor"`
+	Key               string       `json:"chave"`
+	PayerSolicitation string       `json:"solicitacaoPagador,omitempty"`
+	QRCode            string       `json:"pixCopiaECola,omitempty"`
+}
+
+// ListCobVParams represents parameters for listing charges with due date
+type ListCobVParams struct {
+	StartDate time.Time `json:"inicio"`
+	EndDate   time.Time `json:"fim"`
+	CPF       string    `json:"cpf,omitempty"`
+	CNPJ      string    `json:"cnpj,omitempty"`
+	Status    string    `json:"status,omitempty"`
+	Page      int       `json:"paginaAtual,omitempty"`
+	PageSize  int       `json:"itensPorPagina,omitempty"`
+}
+
+// CobVListResponse represents a list of charges with due date
+type CobVListResponse struct {
+	Parameters struct {
+		Start      time.Time  `json:"inicio"`
+		End        time.Time  `json:"fim"`
+		Pagination Pagination `json:"paginacao"`
+	} `json:"parametros"`
+	Charges []CobVResponse `json:"cobs"`
+}
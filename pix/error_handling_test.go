@@ -3,6 +3,8 @@ package pix
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -85,6 +87,9 @@ func TestAPIErrorResponses(t *testing.T) {
 			if apiErr.StatusCode != tt.wantStatusCode {
 				t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, tt.wantStatusCode)
 			}
+			if apiErr.TypeName() != tt.wantErrType {
+				t.Errorf("TypeName() = %q, want %q", apiErr.TypeName(), tt.wantErrType)
+			}
 		})
 	}
 }
@@ -377,6 +382,85 @@ func TestDuplicateTxID(t *testing.T) {
 	if apiErr.StatusCode != 422 {
 		t.Errorf("StatusCode = %d, want 422", apiErr.StatusCode)
 	}
+
+	if !IsDuplicateTxIDError(err) {
+		t.Error("IsDuplicateTxIDError() = false, want true")
+	}
+}
+
+// TestIsDuplicateTxIDError tests which API errors are treated as a reused txid
+func TestIsDuplicateTxIDError(t *testing.T) {
+	const businessRule = "https://api.bb.com.br/api/v2/error/RegraDeNegocio"
+	duplicate := apierror.ErrorDetail{Field: "txid", Message: "O txid já existe para este CPF/CNPJ"}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "conflict",
+			err:  &apierror.APIError{StatusCode: http.StatusConflict},
+			want: true,
+		},
+		{
+			name: "business rule with duplicate txid reason",
+			err:  &apierror.APIError{StatusCode: http.StatusUnprocessableEntity, Type: businessRule, Details: []apierror.ErrorDetail{duplicate}},
+			want: true,
+		},
+		{
+			name: "untyped 422 with duplicate txid reason",
+			err:  &apierror.APIError{StatusCode: http.StatusUnprocessableEntity, Details: []apierror.ErrorDetail{duplicate}},
+			want: true,
+		},
+		{
+			name: "wrapped conflict",
+			err:  fmt.Errorf("failed to create charge: %w", &apierror.APIError{StatusCode: http.StatusConflict}),
+			want: true,
+		},
+		{
+			name: "business rule about txid format",
+			err: &apierror.APIError{StatusCode: http.StatusUnprocessableEntity, Type: businessRule, Details: []apierror.ErrorDetail{
+				{Field: "txid", Message: "O campo txid não respeita o schema"},
+			}},
+			want: false,
+		},
+		{
+			name: "duplicate reason on another field",
+			err: &apierror.APIError{StatusCode: http.StatusUnprocessableEntity, Type: businessRule, Details: []apierror.ErrorDetail{
+				{Field: "chave", Message: "A chave já existe"},
+			}},
+			want: false,
+		},
+		{
+			name: "other problem type with duplicate txid reason",
+			err:  &apierror.APIError{StatusCode: http.StatusUnprocessableEntity, Type: "https://api.bb.com.br/api/v2/error/ValorInvalido", Details: []apierror.ErrorDetail{duplicate}},
+			want: false,
+		},
+		{
+			name: "bad request with duplicate txid reason",
+			err:  &apierror.APIError{StatusCode: http.StatusBadRequest, Type: businessRule, Details: []apierror.ErrorDetail{duplicate}},
+			want: false,
+		},
+		{
+			name: "business rule without details",
+			err:  &apierror.APIError{StatusCode: http.StatusUnprocessableEntity, Type: businessRule},
+			want: false,
+		},
+		{
+			name: "not an API error",
+			err:  errors.New("txid já existe"),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsDuplicateTxIDError(tt.err); got != tt.want {
+				t.Errorf("IsDuplicateTxIDError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
 }
 
 // TestNetworkErrors tests handling of network-level errors
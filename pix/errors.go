@@ -0,0 +1,71 @@
+package pix
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/pericles-luz/go-bb-pix/internal/apierror"
+)
+
+// ErrTxIDConflict is returned by CreateOrGetQRCode when the txid is already
+// used by a charge that does not match the request
+var ErrTxIDConflict = errors.New("txid already used by a different charge")
+
+// ErrChargeAlreadyConcluded is returned when removing a charge that was
+// paid in the meantime
+var ErrChargeAlreadyConcluded = errors.New("charge already concluded")
+
+// ErrChargeAlreadyRemoved is returned when removing a charge that was
+// already removed
+var ErrChargeAlreadyRemoved = errors.New("charge already removed")
+
+// businessRuleType is the problem type name of the business rule
+// violations, under which BB reports a reused txid with a 422
+const businessRuleType = "RegraDeNegocio"
+
+// duplicateTxIDReasons are fragments of the violation reasons BB returns
+// when a txid is reused; see IsDuplicateTxIDError
+var duplicateTxIDReasons = []string{"já existe", "ja existe", "já utilizado", "ja utilizado", "duplicad", "already exists"}
+
+// IsDuplicateTxIDError reports whether err is the error returned when
+// creating a charge with a txid that already exists
+// A 409 Conflict is a duplicate by itself. A 422 carries no code for it, so
+// as a fallback it is a duplicate only when it is a business rule violation
+// (or has no problem type) whose txid violation reads like "txid já
+// existe"; a change in that wording makes it no longer recognized.
+func IsDuplicateTxIDError(err error) bool {
+	apiErr, asErr := apierror.As(err)
+	if asErr != nil {
+		return false
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusConflict:
+		return true
+	case http.StatusUnprocessableEntity:
+		if apiErr.Type != "" && apiErr.TypeName() != businessRuleType {
+			return false
+		}
+		return hasDuplicateTxIDReason(apiErr.Details)
+	default:
+		return false
+	}
+}
+
+// hasDuplicateTxIDReason reports whether a txid violation says the txid is
+// already used
+func hasDuplicateTxIDReason(details []apierror.ErrorDetail) bool {
+	for _, detail := range details {
+		if detail.Field != "txid" {
+			continue
+		}
+		reason := strings.ToLower(detail.Message)
+		for _, fragment := range duplicateTxIDReasons {
+			if strings.Contains(reason, fragment) {
+				return true
+			}
+		}
+	}
+	return false
+}
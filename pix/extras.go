@@ -0,0 +1,62 @@
+package pix
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// knownFieldsCache maps a struct type to the set of its JSON field names
+var knownFieldsCache sync.Map
+
+// knownFields returns the JSON names of the fields of struct type t
+func knownFields(t reflect.Type) map[string]bool {
+	if cached, ok := knownFieldsCache.Load(t); ok {
+		return cached.(map[string]bool)
+	}
+
+	fields := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		fields[name] = true
+	}
+
+	knownFieldsCache.Store(t, fields)
+	return fields
+}
+
+// unknownFields returns the members of the JSON object data that do not
+// map to a field of v's struct type, or nil when there are none
+func unknownFields(data []byte, v interface{}) (map[string]json.RawMessage, error) {
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	known := knownFields(reflect.TypeOf(v))
+
+	var extras map[string]json.RawMessage
+	for name, raw := range all {
+		if known[name] {
+			continue
+		}
+		if extras == nil {
+			extras = make(map[string]json.RawMessage)
+		}
+		extras[name] = raw
+	}
+
+	return extras, nil
+}
@@ -0,0 +1,126 @@
+package pix
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"unicode/utf8"
+)
+
+// wireSeeds are customer-provided strings that used to be risky on the wire
+var wireSeeds = []string{
+	"",
+	"João da Silva",
+	`Maria "Mary" O'Brien`,
+	"linha 1\nlinha 2\r\n",
+	"tab\there",
+	`back\slash`,
+	"pagamento 💸🇧🇷",
+	"</script><script>alert(1)</script>",
+	"\x00\x1f\x7f",
+	"\xff\xfe invalid utf-8",
+	`{"valor":{"original":"0.01"}}`,
+}
+
+func FuzzCreateQRCodeRequest_MarshalJSON(f *testing.F) {
+	for _, seed := range wireSeeds {
+		f.Add(seed, seed)
+	}
+
+	f.Fuzz(func(t *testing.T, name, text string) {
+		req := CreateQRCodeRequest{
+			Value:                 10.5,
+			Expiration:            3600,
+			PayerSolicitation:     text,
+			AdditionalInformation: text,
+			Debtor:                &Debtor{CPF: "12345678909", Name: name},
+		}
+
+		data, err := json.Marshal(req)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		if !json.Valid(data) {
+			t.Fatalf("Marshal() produced invalid JSON: %s", data)
+		}
+
+		var decoded struct {
+			Debtor            Debtor           `json:"devedor"`
+			Value             Value            `json:"valor"`
+			PayerSolicitation string           `json:"solicitacaoPagador"`
+			Info              []AdditionalInfo `json:"infoAdicionais"`
+		}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if decoded.Value.Original != "10.50" {
+			t.Errorf("valor.original = %q, want 10.50", decoded.Value.Original)
+		}
+		if !utf8.ValidString(name) || !utf8.ValidString(text) {
+			return
+		}
+		if decoded.Debtor.Name != name || decoded.PayerSolicitation != text || decoded.Info[0].Value != text {
+			t.Errorf("strings did not round-trip: %+v", decoded)
+		}
+	})
+}
+
+func FuzzCobVRequest_MarshalJSON(f *testing.F) {
+	for _, seed := range wireSeeds {
+		f.Add(seed, seed)
+	}
+
+	f.Fuzz(func(t *testing.T, name, text string) {
+		req := CobVRequest{
+			Calendar:          CobVCalendar{DueDate: "2024-12-31"},
+			Debtor:            &Debtor{CNPJ: "12345678000195", Name: name},
+			Value:             CobVValue{Original: "10.00"},
+			Key:               text,
+			PayerSolicitation: text,
+		}
+
+		data, err := json.Marshal(req)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		if !json.Valid(data) {
+			t.Fatalf("Marshal() produced invalid JSON: %s", data)
+		}
+
+		var decoded CobVRequest
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if !utf8.ValidString(name) || !utf8.ValidString(text) {
+			return
+		}
+		if decoded.Debtor.Name != name || decoded.Key != text || decoded.PayerSolicitation != text {
+			t.Errorf("strings did not round-trip: %+v", decoded)
+		}
+	})
+}
+
+func TestClient_PathParamsAreEscaped(t *testing.T) {
+	var gotPath, gotRawQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotRawQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL)
+
+	if _, err := client.GetQRCode(context.Background(), "../webhook?x=1#frag"); err != nil {
+		t.Fatalf("GetQRCode() error = %v", err)
+	}
+	if gotPath != "/cob/..%2Fwebhook%3Fx=1%23frag" {
+		t.Errorf("path = %s, want the txid escaped as a single segment", gotPath)
+	}
+	if gotRawQuery != "" {
+		t.Errorf("query = %s, want none", gotRawQuery)
+	}
+}
@@ -0,0 +1,63 @@
+package pix
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CanonicalJSON returns the canonical JSON encoding of a request body.
+// The value is marshaled using its own MarshalJSON (so it matches what is
+// sent on the wire) and then normalized: object keys are sorted and the
+// whitespace between tokens is removed. String and number values are kept
+// as they are, so payloads that differ in any value get different keys.
+func CanonicalJSON(v interface{}) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(string(raw)))
+	dec.UseNumber()
+
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("failed to decode request: %w", err)
+	}
+
+	// encoding/json sorts map keys, which gives us a stable field order, and
+	// writes json.Number values with their original text
+	return json.Marshal(generic)
+}
+
+// IdempotencyKey returns a deterministic key for a mutating request.
+// The scope identifies the operation and its path parameters (for example
+// "cob:txid123"), and body is the request payload. Requests that only differ
+// in field order or formatting produce the same key, so the result can be
+// used as an Idempotency-Key header or as a dedupe key.
+func IdempotencyKey(scope string, body interface{}) (string, error) {
+	canonical, err := CanonicalJSON(body)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	h.Write([]byte(strings.TrimSpace(scope)))
+	h.Write([]byte{0})
+	h.Write(canonical)
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// QRCodeIdempotencyKey returns the idempotency key for a charge creation
+func QRCodeIdempotencyKey(req CreateQRCodeRequest) (string, error) {
+	return IdempotencyKey("cob:"+req.TxID, req)
+}
+
+// RefundIdempotencyKey returns the idempotency key for a refund of the
+// payment identified by e2eid
+func RefundIdempotencyKey(e2eid string, req CreateRefundRequest) (string, error) {
+	return IdempotencyKey("devolucao:"+e2eid, req)
+}
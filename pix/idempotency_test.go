@@ -0,0 +1,155 @@
+package pix
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCanonicalJSON_SortsKeysAndNormalizes(t *testing.T) {
+	a := json.RawMessage(`{"valor":{"original":"10.00"},"calendario":{"expiracao":3600},"chave":"abc"}`)
+	b := json.RawMessage(`{
+		"chave": "abc",
+		"calendario": {"expiracao": 3600},
+		"valor": {"original": "10.00"}
+	}`)
+
+	ca, err := CanonicalJSON(a)
+	if err != nil {
+		t.Fatalf("CanonicalJSON() error = %v", err)
+	}
+	cb, err := CanonicalJSON(b)
+	if err != nil {
+		t.Fatalf("CanonicalJSON() error = %v", err)
+	}
+
+	if string(ca) != string(cb) {
+		t.Errorf("canonical forms differ:\n%s\n%s", ca, cb)
+	}
+
+	want := `{"calendario":{"expiracao":3600},"chave":"abc","valor":{"original":"10.00"}}`
+	if string(ca) != want {
+		t.Errorf("CanonicalJSON() = %s, want %s", ca, want)
+	}
+}
+
+func TestCanonicalJSON_KeepsValues(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{"large integers", `{"id":9007199254740993}`, `{"id":9007199254740992}`},
+		{"precise decimals", `{"valor":0.1000000000000000001}`, `{"valor":0.1}`},
+		{"surrounding spaces", `{"chave":" abc "}`, `{"chave":"abc"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ca, err := CanonicalJSON(json.RawMessage(tt.a))
+			if err != nil {
+				t.Fatalf("CanonicalJSON() error = %v", err)
+			}
+			cb, err := CanonicalJSON(json.RawMessage(tt.b))
+			if err != nil {
+				t.Fatalf("CanonicalJSON() error = %v", err)
+			}
+
+			if string(ca) != tt.a {
+				t.Errorf("CanonicalJSON() = %s, want %s", ca, tt.a)
+			}
+			if string(ca) == string(cb) {
+				t.Errorf("CanonicalJSON() = %s for both %s and %s", ca, tt.a, tt.b)
+			}
+		})
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	base := CreateQRCodeRequest{
+		TxID:              "txid123",
+		Value:             100.5,
+		Expiration:        3600,
+		PayerSolicitation: "Pedido 42",
+	}
+
+	tests := []struct {
+		name     string
+		scope    string
+		req      CreateQRCodeRequest
+		wantSame bool
+	}{
+		{
+			name:     "identical request",
+			scope:    "cob:txid123",
+			req:      base,
+			wantSame: true,
+		},
+		{
+			name:  "whitespace in a value",
+			scope: "cob:txid123",
+			req: CreateQRCodeRequest{
+				TxID:              "txid123",
+				Value:             100.5,
+				Expiration:        3600,
+				PayerSolicitation: "  Pedido 42 ",
+			},
+			wantSame: false,
+		},
+		{
+			name:  "different value",
+			scope: "cob:txid123",
+			req: CreateQRCodeRequest{
+				TxID:              "txid123",
+				Value:             100.51,
+				Expiration:        3600,
+				PayerSolicitation: "Pedido 42",
+			},
+			wantSame: false,
+		},
+		{
+			name:     "different scope",
+			scope:    "cob:txid456",
+			req:      base,
+			wantSame: false,
+		},
+	}
+
+	baseKey, err := IdempotencyKey("cob:txid123", base)
+	if err != nil {
+		t.Fatalf("IdempotencyKey() error = %v", err)
+	}
+	if len(baseKey) != 64 {
+		t.Errorf("len(key) = %d, want 64", len(baseKey))
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := IdempotencyKey(tt.scope, tt.req)
+			if err != nil {
+				t.Fatalf("IdempotencyKey() error = %v", err)
+			}
+			if (key == baseKey) != tt.wantSame {
+				t.Errorf("key equality = %v, want %v", key == baseKey, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestQRCodeAndRefundIdempotencyKeys(t *testing.T) {
+	qrKey, err := QRCodeIdempotencyKey(CreateQRCodeRequest{TxID: "txid123", Value: 10})
+	if err != nil {
+		t.Fatalf("QRCodeIdempotencyKey() error = %v", err)
+	}
+
+	refundKey, err := RefundIdempotencyKey("E12345678202401151000000000001", CreateRefundRequest{Value: 10})
+	if err != nil {
+		t.Fatalf("RefundIdempotencyKey() error = %v", err)
+	}
+
+	again, _ := RefundIdempotencyKey("E12345678202401151000000000001", CreateRefundRequest{Value: 10})
+	if refundKey != again {
+		t.Error("RefundIdempotencyKey() should be deterministic")
+	}
+	if qrKey == refundKey {
+		t.Error("keys for different operations should differ")
+	}
+}
@@ -270,7 +270,7 @@ func TestRefundCreation(t *testing.T) {
 		e2eID       string
 		refundID    string
 		request     CreateRefundRequest
-		wantStatus  string
+		wantStatus  RefundStatus
 		wantValue   string
 	}{
 		{
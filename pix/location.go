@@ -0,0 +1,116 @@
+package pix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// CreateLocation creates a payload location that can later be attached to a charge
+func (c *Client) CreateLocation(ctx context.Context, req CreateLocationRequest) (*Location, error) {
+	if req.Type == "" {
+		return nil, fmt.Errorf("tipoCob is required")
+	}
+
+	path := "/loc"
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.Write)
+	defer cancel()
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodPost, path, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp Location
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to create location: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// GetLocation retrieves a payload location by ID
+func (c *Client) GetLocation(ctx context.Context, id int) (*Location, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("location id is required")
+	}
+
+	path := fmt.Sprintf("/loc/%d", id)
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.Read)
+	defer cancel()
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp Location
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get location: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// ListLocations lists payload locations with optional filters
+func (c *Client) ListLocations(ctx context.Context, params ListLocationsParams) (*LocationListResponse, error) {
+	path := "/loc"
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.List)
+	defer cancel()
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Add query parameters
+	q := httpReq.URL.Query()
+	q.Set("inicio", params.StartDate.Format("2006-01-02T15:04:05Z07:00"))
+	q.Set("fim", params.EndDate.Format("2006-01-02T15:04:05Z07:00"))
+
+	if params.TxIDPresent != nil {
+		q.Set("txIdPresente", strconv.FormatBool(*params.TxIDPresent))
+	}
+	if params.Type != "" {
+		q.Set("tipoCob", params.Type)
+	}
+	c.http.SetPagination(q, params.Page, params.PageSize)
+
+	httpReq.URL.RawQuery = q.Encode()
+
+	var resp LocationListResponse
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list locations: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// UnlinkLocationTxID detaches the charge (txid) from a payload location so
+// the location can be attached to a new charge
+func (c *Client) UnlinkLocationTxID(ctx context.Context, id int) (*Location, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("location id is required")
+	}
+
+	path := fmt.Sprintf("/loc/%d/txid", id)
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.Write)
+	defer cancel()
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp Location
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unlink location txid: %w", err)
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,226 @@
+package pix
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_CreateLocation_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Method = %s, want POST", r.Method)
+		}
+		if r.URL.Path != "/loc" {
+			t.Errorf("Path = %s, want /loc", r.URL.Path)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if body["tipoCob"] != "cob" {
+			t.Errorf("tipoCob = %v, want cob", body["tipoCob"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":7,"location":"pix.example.com/qr/v2/loc7","tipoCob":"cob","criacao":"2024-01-15T10:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	loc, err := client.CreateLocation(context.Background(), CreateLocationRequest{Type: LocationTypeCob})
+	if err != nil {
+		t.Fatalf("CreateLocation() error = %v", err)
+	}
+	if loc.ID != 7 {
+		t.Errorf("ID = %d, want 7", loc.ID)
+	}
+	if loc.Creation.IsZero() {
+		t.Error("Creation should not be zero")
+	}
+	if loc.TxID != "" {
+		t.Errorf("TxID = %s, want empty", loc.TxID)
+	}
+}
+
+func TestClient_GetLocation_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Method = %s, want GET", r.Method)
+		}
+		if r.URL.Path != "/loc/7" {
+			t.Errorf("Path = %s, want /loc/7", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":7,"location":"pix.example.com/qr/v2/loc7","tipoCob":"cob","criacao":"2024-01-15T10:00:00Z","txid":"txid123"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	loc, err := client.GetLocation(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetLocation() error = %v", err)
+	}
+	if loc.TxID != "txid123" {
+		t.Errorf("TxID = %s, want txid123", loc.TxID)
+	}
+}
+
+func TestClient_ListLocations_QueryParams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("inicio") != "2024-01-01T00:00:00Z" {
+			t.Errorf("inicio = %s, want 2024-01-01T00:00:00Z", query.Get("inicio"))
+		}
+		if query.Get("txIdPresente") != "false" {
+			t.Errorf("txIdPresente = %s, want false", query.Get("txIdPresente"))
+		}
+		if query.Get("tipoCob") != "cobv" {
+			t.Errorf("tipoCob = %s, want cobv", query.Get("tipoCob"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"parametros": {"inicio": "2024-01-01T00:00:00Z", "fim": "2024-01-31T23:59:59Z", "paginacao": {"paginaAtual": 0, "itensPorPagina": 100, "quantidadeDePaginas": 1, "quantidadeTotalDeItens": 1}},
+			"loc": [{"id": 8, "location": "pix.example.com/qr/v2/loc8", "tipoCob": "cobv", "criacao": "2024-01-15T10:00:00Z"}]
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	txIDPresent := false
+	resp, err := client.ListLocations(context.Background(), ListLocationsParams{
+		StartDate:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC),
+		TxIDPresent: &txIDPresent,
+		Type:        LocationTypeCobV,
+	})
+	if err != nil {
+		t.Fatalf("ListLocations() error = %v", err)
+	}
+	if len(resp.Locations) != 1 || resp.Locations[0].ID != 8 {
+		t.Errorf("Locations = %+v, want one location with ID 8", resp.Locations)
+	}
+}
+
+func TestClient_UnlinkLocationTxID_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("Method = %s, want DELETE", r.Method)
+		}
+		if r.URL.Path != "/loc/7/txid" {
+			t.Errorf("Path = %s, want /loc/7/txid", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":7,"location":"pix.example.com/qr/v2/loc7","tipoCob":"cob","criacao":"2024-01-15T10:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	loc, err := client.UnlinkLocationTxID(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("UnlinkLocationTxID() error = %v", err)
+	}
+	if loc.TxID != "" {
+		t.Errorf("TxID = %s, want empty after unlink", loc.TxID)
+	}
+}
+
+func TestClient_Location_Validation(t *testing.T) {
+	client := NewClient(&http.Client{}, "http://localhost")
+
+	if _, err := client.CreateLocation(context.Background(), CreateLocationRequest{}); err == nil {
+		t.Error("CreateLocation() expected error for empty tipoCob")
+	}
+	if _, err := client.GetLocation(context.Background(), 0); err == nil {
+		t.Error("GetLocation() expected error for zero id")
+	}
+	if _, err := client.UnlinkLocationTxID(context.Background(), -1); err == nil {
+		t.Error("UnlinkLocationTxID() expected error for invalid id")
+	}
+}
+
+// TestPreCreatedLocationFlow covers the pre-printed QR Code flow: a location
+// is created once, attached to a charge, released and attached again
+func TestPreCreatedLocationFlow(t *testing.T) {
+	var attachedTxID string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/loc":
+			w.Write([]byte(`{"id":55,"location":"pix.example.com/qr/v2/loc55","tipoCob":"cob","criacao":"2024-01-15T10:00:00Z"}`))
+
+		case r.Method == http.MethodPut && len(r.URL.Path) > len("/cob/"):
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode request: %v", err)
+			}
+			loc, ok := body["loc"].(map[string]interface{})
+			if !ok || loc["id"] != float64(55) {
+				t.Errorf("loc = %v, want {id: 55}", body["loc"])
+			}
+			if attachedTxID != "" {
+				t.Errorf("location still attached to %s", attachedTxID)
+			}
+			attachedTxID = r.URL.Path[len("/cob/"):]
+
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"calendario": map[string]interface{}{"criacao": "2024-01-15T10:00:00Z", "expiracao": 3600},
+				"txid":       attachedTxID,
+				"revisao":    0,
+				"status":     "ATIVA",
+				"valor":      map[string]interface{}{"original": "10.00"},
+				"loc":        map[string]interface{}{"id": 55, "location": "pix.example.com/qr/v2/loc55", "tipoCob": "cob"},
+			})
+
+		case r.Method == http.MethodDelete && r.URL.Path == "/loc/55/txid":
+			attachedTxID = ""
+			w.Write([]byte(`{"id":55,"location":"pix.example.com/qr/v2/loc55","tipoCob":"cob","criacao":"2024-01-15T10:00:00Z"}`))
+
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+	ctx := context.Background()
+
+	loc, err := client.CreateLocation(ctx, CreateLocationRequest{Type: LocationTypeCob})
+	if err != nil {
+		t.Fatalf("CreateLocation() error = %v", err)
+	}
+
+	for _, txID := range []string{"txidfirstcharge0000000000001", "txidsecondcharge000000000002"} {
+		charge, err := client.CreateQRCode(ctx, CreateQRCodeRequest{
+			TxID:       txID,
+			Value:      10,
+			Expiration: 3600,
+			LocationID: loc.ID,
+		})
+		if err != nil {
+			t.Fatalf("CreateQRCode(%s) error = %v", txID, err)
+		}
+		if charge.Loc == nil || charge.Loc.ID != loc.ID {
+			t.Errorf("charge %s not attached to location %d", txID, loc.ID)
+		}
+
+		if _, err := client.UnlinkLocationTxID(ctx, loc.ID); err != nil {
+			t.Fatalf("UnlinkLocationTxID() error = %v", err)
+		}
+	}
+}
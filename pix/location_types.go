@@ -0,0 +1,39 @@
+package pix
+
+import "time"
+
+// Location types (tipoCob)
+const (
+	// LocationTypeCob is a location for immediate charges (cob)
+	LocationTypeCob = "cob"
+
+	// LocationTypeCobV is a location for charges with due date (cobv)
+	LocationTypeCobV = "cobv"
+)
+
+// CreateLocationRequest represents a request to create a payload location
+type CreateLocationRequest struct {
+	Type string `json:"tipoCob"`
+}
+
+// ListLocationsParams represents parameters for listing payload locations
+type ListLocationsParams struct {
+	StartDate time.Time `json:"inicio"`
+	EndDate   time.Time `json:"fim"`
+	// TxIDPresent filters locations with (true) or without (false) an
+	// attached txid; nil lists both
+	TxIDPresent *bool  `json:"txIdPresente,omitempty"`
+	Type        string `json:"tipoCob,omitempty"`
+	Page        int    `json:"paginaAtual,omitempty"`
+	PageSize    int    `json:"itensPorPagina,omitempty"`
+}
+
+// LocationListResponse represents a list of payload locations
+type LocationListResponse struct {
+	Parameters struct {
+		Start      time.Time  `json:"inicio"`
+		End        time.Time  `json:"fim"`
+		Pagination Pagination `json:"paginacao"`
+	} `json:"parametros"`
+	Locations []Location `json:"loc"`
+}
@@ -0,0 +1,115 @@
+package pix
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Field length limits of the charge schema
+const (
+	MaxDebtorNameLength        = 200
+	MaxPayerSolicitationLength = 140
+)
+
+// Normalizer cleans up user-entered charge data before it is sent, avoiding
+// 400 responses caused by UI-formatted documents or oversized texts
+type Normalizer struct {
+	// UppercaseNames converts debtor names to upper case
+	UppercaseNames bool
+
+	// MaxNameLength truncates debtor names; zero uses MaxDebtorNameLength
+	MaxNameLength int
+
+	// MaxPayerSolicitationLength truncates solicitacaoPagador; zero uses
+	// MaxPayerSolicitationLength
+	MaxPayerSolicitationLength int
+}
+
+// DefaultNormalizer returns the normalizer used by WithNormalization
+func DefaultNormalizer() Normalizer {
+	return Normalizer{UppercaseNames: true}
+}
+
+// NormalizeDocument strips everything but digits from a CPF or CNPJ, so
+// "123.456.789-09" becomes "12345678909"
+func NormalizeDocument(doc string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, doc)
+}
+
+// Name trims and collapses whitespace in name, optionally uppercases it and
+// truncates it to the maximum length
+func (n Normalizer) Name(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if n.UppercaseNames {
+		name = strings.ToUpper(name)
+	}
+	return truncateRunes(name, limitOrDefault(n.MaxNameLength, MaxDebtorNameLength))
+}
+
+// Debtor returns a normalized copy of d. A 14-digit document given as CPF
+// is moved to CNPJ and an 11-digit document given as CNPJ is moved to CPF.
+func (n Normalizer) Debtor(d *Debtor) *Debtor {
+	if d == nil {
+		return nil
+	}
+
+	out := Debtor{
+		CPF:  NormalizeDocument(d.CPF),
+		CNPJ: NormalizeDocument(d.CNPJ),
+		Name: n.Name(d.Name),
+	}
+
+	switch {
+	case out.CNPJ == "" && len(out.CPF) == 14:
+		out.CNPJ, out.CPF = out.CPF, ""
+	case out.CPF == "" && len(out.CNPJ) == 11:
+		out.CPF, out.CNPJ = out.CNPJ, ""
+	}
+
+	return &out
+}
+
+// PayerSolicitation trims s and truncates it to the maximum length
+func (n Normalizer) PayerSolicitation(s string) string {
+	return truncateRunes(strings.TrimSpace(s), limitOrDefault(n.MaxPayerSolicitationLength, MaxPayerSolicitationLength))
+}
+
+// QRCodeRequest returns a normalized copy of req
+func (n Normalizer) QRCodeRequest(req CreateQRCodeRequest) CreateQRCodeRequest {
+	req.Debtor = n.Debtor(req.Debtor)
+	req.PayerSolicitation = n.PayerSolicitation(req.PayerSolicitation)
+	return req
+}
+
+// CobVRequest returns a normalized copy of req
+func (n Normalizer) CobVRequest(req CobVRequest) CobVRequest {
+	req.Debtor = n.Debtor(req.Debtor)
+	req.PayerSolicitation = n.PayerSolicitation(req.PayerSolicitation)
+	return req
+}
+
+// limitOrDefault returns limit, or def when limit is not positive
+func limitOrDefault(limit, def int) int {
+	if limit <= 0 {
+		return def
+	}
+	return limit
+}
+
+// truncateRunes truncates s to at most n characters, without splitting a
+// multi-byte character, and trims trailing spaces left by the cut
+func truncateRunes(s string, n int) string {
+	count := 0
+	for i := range s {
+		if count == n {
+			return strings.TrimRightFunc(s[:i], unicode.IsSpace)
+		}
+		count++
+	}
+	return s
+}
@@ -0,0 +1,115 @@
+package pix
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeDocument(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"123.456.789-09", "12345678909"},
+		{"12.345.678/0001-95", "12345678000195"},
+		{" 12345678909 ", "12345678909"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeDocument(tt.in); got != tt.want {
+			t.Errorf("NormalizeDocument(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizer_Debtor(t *testing.T) {
+	n := DefaultNormalizer()
+
+	tests := []struct {
+		name string
+		in   *Debtor
+		want *Debtor
+	}{
+		{
+			name: "formatted CPF",
+			in:   &Debtor{CPF: "123.456.789-09", Name: "  Francisco   da Silva "},
+			want: &Debtor{CPF: "12345678909", Name: "FRANCISCO DA SILVA"},
+		},
+		{
+			name: "CNPJ typed in CPF field",
+			in:   &Debtor{CPF: "12.345.678/0001-95", Name: "Empresa SA"},
+			want: &Debtor{CNPJ: "12345678000195", Name: "EMPRESA SA"},
+		},
+		{
+			name: "nil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := n.Debtor(tt.in)
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("Debtor() = %v, want %v", got, tt.want)
+			}
+			if got != nil && *got != *tt.want {
+				t.Errorf("Debtor() = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+
+	long := strings.Repeat("á", MaxDebtorNameLength+10)
+	if got := []rune(n.Name(long)); len(got) != MaxDebtorNameLength {
+		t.Errorf("len(Name()) = %d, want %d", len(got), MaxDebtorNameLength)
+	}
+
+	keepCase := Normalizer{MaxNameLength: 5}
+	if got := keepCase.Name("Maria Silva"); got != "Maria" {
+		t.Errorf("Name() = %q, want %q", got, "Maria")
+	}
+}
+
+func TestClient_CreateQRCode_WithNormalization(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Debtor            Debtor `json:"devedor"`
+			PayerSolicitation string `json:"solicitacaoPagador"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode body: %v", err)
+		}
+
+		if body.Debtor.CPF != "12345678909" || body.Debtor.Name != "JOSÉ DA SILVA" {
+			t.Errorf("devedor = %+v", body.Debtor)
+		}
+		if len([]rune(body.PayerSolicitation)) != MaxPayerSolicitationLength {
+			t.Errorf("len(solicitacaoPagador) = %d, want %d", len([]rune(body.PayerSolicitation)), MaxPayerSolicitationLength)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"txid":"txid123","status":"ATIVA","valor":{"original":"10.00"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL, WithNormalization())
+
+	req := CreateQRCodeRequest{
+		TxID:              "txid123",
+		Value:             10,
+		PayerSolicitation: strings.Repeat("x", 200),
+		Debtor:            &Debtor{CPF: "123.456.789-09", Name: "José da Silva"},
+	}
+
+	if _, err := client.CreateQRCode(context.Background(), req); err != nil {
+		t.Fatalf("CreateQRCode() error = %v", err)
+	}
+
+	// The caller's request is not modified
+	if req.Debtor.CPF != "123.456.789-09" {
+		t.Errorf("caller debtor modified: %+v", req.Debtor)
+	}
+}
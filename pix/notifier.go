@@ -0,0 +1,309 @@
+package pix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// NotificationSource identifies how a payment was discovered
+type NotificationSource string
+
+// Notification sources
+const (
+	SourceWebhook NotificationSource = "webhook"
+	SourcePolling NotificationSource = "polling"
+)
+
+// PaymentReceived is emitted by a Notifier once per received payment
+type PaymentReceived struct {
+	Payment PaymentResponse
+	Source  NotificationSource
+}
+
+// NotifierOption is a functional option for NewNotifier
+type NotifierOption func(*notifierOptions)
+
+// notifierOptions holds the configurable options of a Notifier
+type notifierOptions struct {
+	pollInterval   time.Duration
+	lookback       time.Duration
+	webhookTimeout time.Duration
+	dedupeTTL      time.Duration
+	bufferSize     int
+	logger         *slog.Logger
+}
+
+// WithPollInterval sets how often /pix is polled when the webhook is not
+// delivering notifications
+// Default: 1 minute
+func WithPollInterval(d time.Duration) NotifierOption {
+	return func(opts *notifierOptions) {
+		if d > 0 {
+			opts.pollInterval = d
+		}
+	}
+}
+
+// WithPollLookback sets how far back the first poll looks for payments when
+// no webhook handler is wired in
+// Default: 1 hour
+func WithPollLookback(d time.Duration) NotifierOption {
+	return func(opts *notifierOptions) {
+		if d > 0 {
+			opts.lookback = d
+		}
+	}
+}
+
+// WithWebhookTimeout sets how long the webhook may stay silent before the
+// notifier falls back to polling. Polling resumes from the last polled
+// instant, so payments missed by the webhook are recovered.
+// Default: 10 minutes
+func WithWebhookTimeout(d time.Duration) NotifierOption {
+	return func(opts *notifierOptions) {
+		if d > 0 {
+			opts.webhookTimeout = d
+		}
+	}
+}
+
+// WithDedupeTTL sets how long an endToEndId is remembered to drop duplicate
+// notifications
+// Default: 24 hours
+func WithDedupeTTL(d time.Duration) NotifierOption {
+	return func(opts *notifierOptions) {
+		if d > 0 {
+			opts.dedupeTTL = d
+		}
+	}
+}
+
+// WithNotifierBuffer sets the capacity of the events channel
+// Default: 100
+func WithNotifierBuffer(n int) NotifierOption {
+	return func(opts *notifierOptions) {
+		if n >= 0 {
+			opts.bufferSize = n
+		}
+	}
+}
+
+// WithNotifierLogger sets the logger used to report failed polls
+func WithNotifierLogger(logger *slog.Logger) NotifierOption {
+	return func(opts *notifierOptions) {
+		opts.logger = logger
+	}
+}
+
+// Notifier emits a deduplicated PaymentReceived event for every received
+// payment. It prefers webhook callbacks when Handler is wired in and falls
+// back to polling /pix when the webhook is not delivering.
+type Notifier struct {
+	client *Client
+	opts   notifierOptions
+	events chan PaymentReceived
+	now    func() time.Time
+
+	mu          sync.Mutex
+	seen        map[string]time.Time
+	webhookOn   bool
+	lastWebhook time.Time
+
+	// sendMu guards events against being closed while a send is pending
+	sendMu sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+// ErrNotifierClosed is returned by the Notifier handler after Run returned
+var ErrNotifierClosed = errors.New("notifier closed")
+
+// NewNotifier creates a Notifier that polls with client
+func NewNotifier(client *Client, opts ...NotifierOption) *Notifier {
+	options := notifierOptions{
+		pollInterval:   time.Minute,
+		lookback:       time.Hour,
+		webhookTimeout: 10 * time.Minute,
+		dedupeTTL:      24 * time.Hour,
+		bufferSize:     100,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &Notifier{
+		client: client,
+		opts:   options,
+		events: make(chan PaymentReceived, options.bufferSize),
+		now:    time.Now,
+		seen:   make(map[string]time.Time),
+		done:   make(chan struct{}),
+	}
+}
+
+// Events returns the channel of received payments
+// It is closed when Run returns.
+func (n *Notifier) Events() <-chan PaymentReceived {
+	return n.events
+}
+
+// Handler returns a webhook handler that feeds the notifier. While it keeps
+// receiving callbacks, polling is suspended.
+// When the events channel is full until the callback is cancelled, the
+// handler answers 500 so BB retries the notification.
+func (n *Notifier) Handler(opts ...WebhookHandlerOption) http.Handler {
+	n.mu.Lock()
+	n.webhookOn = true
+	n.lastWebhook = n.now()
+	n.mu.Unlock()
+
+	return NewWebhookHandler(func(ctx context.Context, payments []PaymentResponse) error {
+		n.mu.Lock()
+		n.lastWebhook = n.now()
+		n.mu.Unlock()
+
+		return n.emit(ctx, payments, SourceWebhook)
+	}, opts...)
+}
+
+// Run polls /pix while the webhook is silent until ctx is done, then closes
+// the events channel. Run must be called at most once.
+func (n *Notifier) Run(ctx context.Context) error {
+	defer n.close()
+
+	ticker := time.NewTicker(n.opts.pollInterval)
+	defer ticker.Stop()
+
+	cursor := n.now().Add(-n.opts.lookback)
+	failed := false
+	for {
+		if n.shouldPoll() {
+			end := n.now()
+			if err := n.poll(ctx, cursor, end); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if n.opts.logger != nil {
+					n.opts.logger.WarnContext(ctx, "payment polling failed", slog.Any("error", err))
+				}
+				failed = true
+			} else {
+				// The next window overlaps the previous by one second, the
+				// precision of inicio; duplicates are dropped by emit
+				cursor = end.Add(-time.Second)
+				failed = false
+			}
+		} else if !failed {
+			// The webhook delivered within the timeout, so a later fallback
+			// only needs to cover the time since then
+			cursor = n.now().Add(-n.opts.webhookTimeout)
+		}
+		n.prune()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// shouldPoll reports whether the webhook is absent or has been silent for
+// longer than the webhook timeout
+func (n *Notifier) shouldPoll() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return !n.webhookOn || n.now().Sub(n.lastWebhook) >= n.opts.webhookTimeout
+}
+
+// poll lists every payment in [start, end] and emits the unseen ones
+func (n *Notifier) poll(ctx context.Context, start, end time.Time) error {
+	for _, window := range SplitPaymentWindow(start, end, MaxPaymentListWindow) {
+		params := window.Params()
+		for {
+			resp, err := n.client.ListPayments(ctx, params)
+			if err != nil {
+				return fmt.Errorf("failed to poll payments: %w", err)
+			}
+			if err := n.emit(ctx, resp.Payments, SourcePolling); err != nil {
+				return err
+			}
+
+			if params.Page+1 >= resp.Parameters.Pagination.TotalPages {
+				break
+			}
+			params.Page++
+		}
+	}
+
+	return nil
+}
+
+// close stops the pending sends and closes the events channel
+func (n *Notifier) close() {
+	close(n.done)
+
+	n.sendMu.Lock()
+	defer n.sendMu.Unlock()
+	n.closed = true
+	close(n.events)
+}
+
+// emit sends the payments not seen before. Each endToEndId is claimed
+// before sending, so a payment notified by the webhook and the polling at
+// the same time is emitted once, and released if the send fails.
+func (n *Notifier) emit(ctx context.Context, payments []PaymentResponse, source NotificationSource) error {
+	n.sendMu.RLock()
+	defer n.sendMu.RUnlock()
+	if n.closed {
+		return ErrNotifierClosed
+	}
+
+	for _, payment := range payments {
+		n.mu.Lock()
+		_, dup := n.seen[payment.EndToEndID]
+		if !dup {
+			n.seen[payment.EndToEndID] = n.now()
+		}
+		n.mu.Unlock()
+		if dup {
+			continue
+		}
+
+		var err error
+		select {
+		case n.events <- PaymentReceived{Payment: payment, Source: source}:
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-n.done:
+			err = ErrNotifierClosed
+		}
+
+		if err != nil {
+			n.mu.Lock()
+			delete(n.seen, payment.EndToEndID)
+			n.mu.Unlock()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// prune forgets the endToEndIds older than the dedupe TTL
+func (n *Notifier) prune() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	cutoff := n.now().Add(-n.opts.dedupeTTL)
+	for id, at := range n.seen {
+		if at.Before(cutoff) {
+			delete(n.seen, id)
+		}
+	}
+}
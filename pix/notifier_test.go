@@ -0,0 +1,157 @@
+package pix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// collectEvents reads n events or fails after a timeout
+func collectEvents(t *testing.T, events <-chan PaymentReceived, n int) []PaymentReceived {
+	t.Helper()
+
+	var got []PaymentReceived
+	for len(got) < n {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatalf("events closed after %d events, want %d", len(got), n)
+			}
+			got = append(got, event)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out after %d events, want %d", len(got), n)
+		}
+	}
+	return got
+}
+
+func TestNotifier_Polling(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("paginacao.paginaAtual") == "1" {
+			w.Write([]byte(`{"parametros":{"paginacao":{"paginaAtual":1,"quantidadeDePaginas":2}},"pix":[{"endToEndId":"E2"},{"endToEndId":"E3"}]}`))
+			return
+		}
+		w.Write([]byte(`{"parametros":{"paginacao":{"paginaAtual":0,"quantidadeDePaginas":2}},"pix":[{"endToEndId":"E1"},{"endToEndId":"E2"}]}`))
+	}))
+	defer server.Close()
+
+	notifier := NewNotifier(NewClient(&http.Client{}, server.URL), WithPollInterval(time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- notifier.Run(ctx) }()
+
+	got := collectEvents(t, notifier.Events(), 3)
+	for i, want := range []string{"E1", "E2", "E3"} {
+		if got[i].Payment.EndToEndID != want || got[i].Source != SourcePolling {
+			t.Errorf("event %d = %s/%s, want %s/polling", i, got[i].Payment.EndToEndID, got[i].Source, want)
+		}
+	}
+
+	// Later polls list the same payments, which are dropped
+	select {
+	case event := <-notifier.Events():
+		t.Errorf("unexpected duplicate event %s", event.Payment.EndToEndID)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run() error = %v, want context.Canceled", err)
+	}
+	if _, ok := <-notifier.Events(); ok {
+		t.Error("events should be closed after Run returns")
+	}
+}
+
+func TestNotifier_WebhookPreferred(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"pix":[{"endToEndId":"E1"},{"endToEndId":"E2"}]}`))
+	}))
+	defer server.Close()
+
+	notifier := NewNotifier(NewClient(&http.Client{}, server.URL),
+		WithPollInterval(time.Millisecond),
+		WithWebhookTimeout(time.Hour),
+	)
+	handler := notifier.Handler()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go notifier.Run(ctx)
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"pix":[{"endToEndId":"E1","valor":"1.00"}]}`)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", rec.Code)
+		}
+	}
+
+	got := collectEvents(t, notifier.Events(), 1)
+	if got[0].Payment.EndToEndID != "E1" || got[0].Source != SourceWebhook {
+		t.Errorf("event = %s/%s, want E1/webhook", got[0].Payment.EndToEndID, got[0].Source)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if n := polls.Load(); n != 0 {
+		t.Errorf("polls = %d, want 0 while the webhook is active", n)
+	}
+	select {
+	case event := <-notifier.Events():
+		t.Errorf("unexpected event %s", event.Payment.EndToEndID)
+	default:
+	}
+}
+
+func TestNotifier_FallsBackToPolling(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"pix":[{"endToEndId":"E1"},{"endToEndId":"E2"}]}`))
+	}))
+	defer server.Close()
+
+	notifier := NewNotifier(NewClient(&http.Client{}, server.URL),
+		WithPollInterval(time.Millisecond),
+		WithWebhookTimeout(10*time.Millisecond),
+	)
+	handler := notifier.Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"pix":[{"endToEndId":"E1"}]}`)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go notifier.Run(ctx)
+
+	got := collectEvents(t, notifier.Events(), 2)
+	if got[0].Payment.EndToEndID != "E1" || got[0].Source != SourceWebhook {
+		t.Errorf("event 0 = %s/%s, want E1/webhook", got[0].Payment.EndToEndID, got[0].Source)
+	}
+	if got[1].Payment.EndToEndID != "E2" || got[1].Source != SourcePolling {
+		t.Errorf("event 1 = %s/%s, want E2/polling", got[1].Payment.EndToEndID, got[1].Source)
+	}
+}
+
+func TestNotifier_HandlerAfterRun(t *testing.T) {
+	notifier := NewNotifier(NewClient(&http.Client{}, "http://example.com"), WithWebhookTimeout(time.Hour))
+	handler := notifier.Handler()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	notifier.Run(ctx)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"pix":[{"endToEndId":"E1"}]}`)))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500 after the notifier closed", rec.Code)
+	}
+}
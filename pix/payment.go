@@ -2,8 +2,12 @@ package pix
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 )
 
 // GetPayment retrieves a payment by EndToEndID
@@ -12,7 +16,10 @@ func (c *Client) GetPayment(ctx context.Context, e2eid string) (*PaymentResponse
 		return nil, fmt.Errorf("e2eid is required")
 	}
 
-	path := fmt.Sprintf("/pix/%s", e2eid)
+	path := fmt.Sprintf("/pix/%s", url.PathEscape(e2eid))
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.Read)
+	defer cancel()
 
 	httpReq, err := c.http.NewRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
@@ -29,6 +36,65 @@ func (c *Client) GetPayment(ctx context.Context, e2eid string) (*PaymentResponse
 
 // ListPayments lists payments with optional filters
 func (c *Client) ListPayments(ctx context.Context, params ListPaymentsParams) (*PaymentListResponse, error) {
+	var resp PaymentListResponse
+	if err := c.ListPaymentsInto(ctx, params, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ListPaymentsInto lists payments with optional filters, decoding the page into dst
+// dst is reset before decoding and keeps its slice capacity, so a single
+// value can be reused across pages and polling iterations
+func (c *Client) ListPaymentsInto(ctx context.Context, params ListPaymentsParams, dst *PaymentListResponse) error {
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.List)
+	defer cancel()
+
+	httpReq, err := c.listPaymentsRequest(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	if err := c.http.Do(httpReq, dst); err != nil {
+		return fmt.Errorf("failed to list payments: %w", err)
+	}
+
+	return nil
+}
+
+// ListPaymentsFunc lists payments with optional filters, calling fn with
+// each payment as it is decoded from the wire instead of holding the whole
+// page in memory
+// The returned response carries the parametros of the page, including the
+// pagination, and no payments. Listing stops at the first error returned by
+// fn, which is returned wrapped.
+func (c *Client) ListPaymentsFunc(ctx context.Context, params ListPaymentsParams, fn func(PaymentResponse) error) (*PaymentListResponse, error) {
+	if fn == nil {
+		return nil, fmt.Errorf("fn is required")
+	}
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.List)
+	defer cancel()
+
+	httpReq, err := c.listPaymentsRequest(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp PaymentListResponse
+	err = c.http.DoStream(httpReq, func(body io.Reader) error {
+		return decodePaymentStream(body, &resp, fn)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payments: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// listPaymentsRequest builds the GET /pix request for params
+func (c *Client) listPaymentsRequest(ctx context.Context, params ListPaymentsParams) (*http.Request, error) {
 	path := "/pix"
 
 	httpReq, err := c.http.NewRequest(ctx, http.MethodGet, path, nil)
@@ -50,19 +116,91 @@ func (c *Client) ListPayments(ctx context.Context, params ListPaymentsParams) (*
 	if params.CNPJ != "" {
 		q.Set("cnpj", params.CNPJ)
 	}
-	if params.Page > 0 {
-		q.Set("paginaAtual", fmt.Sprintf("%d", params.Page))
+	if params.TxIDPresent != nil {
+		q.Set("txIdPresente", strconv.FormatBool(*params.TxIDPresent))
 	}
-	if params.PageSize > 0 {
-		q.Set("itensPorPagina", fmt.Sprintf("%d", params.PageSize))
+	if params.RefundPresent != nil {
+		q.Set("devolucaoPresente", strconv.FormatBool(*params.RefundPresent))
 	}
+	if params.BatchID > 0 {
+		q.Set("loteCobVId", strconv.Itoa(params.BatchID))
+	}
+	c.http.SetPagination(q, params.Page, params.PageSize)
 
 	httpReq.URL.RawQuery = q.Encode()
 
-	var resp PaymentListResponse
-	if err := c.http.Do(httpReq, &resp); err != nil {
-		return nil, fmt.Errorf("failed to list payments: %w", err)
+	return httpReq, nil
+}
+
+// decodePaymentStream decodes a payment list from r into dst, passing each
+// element of pix to fn instead of storing it
+func decodePaymentStream(r io.Reader, dst *PaymentListResponse, fn func(PaymentResponse) error) error {
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
 	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
 
-	return &resp, nil
+		switch tok {
+		case "parametros":
+			if err := dec.Decode(&dst.Parameters); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+		case "pix":
+			if err := decodePaymentArray(dec, fn); err != nil {
+				return err
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+		}
+	}
+
+	return expectDelim(dec, '}')
+}
+
+// decodePaymentArray passes each element of a JSON array of payments to fn
+// A null array has no elements.
+func decodePaymentArray(dec *json.Decoder, fn func(PaymentResponse) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("failed to decode response: pix is not an array")
+	}
+
+	for dec.More() {
+		var payment PaymentResponse
+		if err := dec.Decode(&payment); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		if err := fn(payment); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, ']')
+}
+
+// expectDelim consumes the next token of dec, which must be delim
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if tok != delim {
+		return fmt.Errorf("failed to decode response: expected %s, got %v", delim, tok)
+	}
+	return nil
 }
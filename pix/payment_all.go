@@ -0,0 +1,208 @@
+package pix
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+)
+
+// ListAllOption is a functional option for ListAllPayments
+type ListAllOption func(*listAllOptions)
+
+// listAllOptions holds the options of ListAllPayments
+type listAllOptions struct {
+	windowSize  time.Duration
+	concurrency int
+	prefetch    int
+}
+
+// WithWindowSize sets the widest inicio/fim interval of each request
+// Default: MaxPaymentListWindow
+func WithWindowSize(d time.Duration) ListAllOption {
+	return func(opts *listAllOptions) {
+		if d > 0 {
+			opts.windowSize = d
+		}
+	}
+}
+
+// WithWindowConcurrency sets how many windows are fetched at the same time
+// Payments are still yielded in window order. With more than one window in
+// flight, each window is fully fetched before being yielded.
+// Default: 1 (sequential, streaming page by page)
+func WithWindowConcurrency(n int) ListAllOption {
+	return func(opts *listAllOptions) {
+		if n > 0 {
+			opts.concurrency = n
+		}
+	}
+}
+
+// WithPagePrefetch fetches up to n pages of a window ahead of the consumer,
+// after its first page tells how many pages there are. Pages are still
+// yielded in order.
+// Default: 0 (one page at a time)
+func WithPagePrefetch(n int) ListAllOption {
+	return func(opts *listAllOptions) {
+		if n >= 0 {
+			opts.prefetch = n
+		}
+	}
+}
+
+// ListAllPayments lists every payment between params.StartDate and
+// params.EndDate, splitting the period into windows accepted by the API and
+// following every page. The other filters of params apply to every window;
+// params.Page is ignored.
+//
+// Payments are yielded in window order. A payment listed by two adjacent
+// windows, at their shared boundary, is yielded once. Iteration stops after
+// the first error, which is yielded with a zero PaymentResponse.
+func (c *Client) ListAllPayments(ctx context.Context, params ListPaymentsParams, opts ...ListAllOption) iter.Seq2[PaymentResponse, error] {
+	options := listAllOptions{windowSize: MaxPaymentListWindow, concurrency: 1}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	windows := SplitPaymentWindow(params.StartDate, params.EndDate, options.windowSize)
+
+	return func(yield func(PaymentResponse, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		pages := c.sequentialWindows(ctx, params, windows, options.prefetch)
+		if options.concurrency > 1 {
+			pages = c.concurrentWindows(ctx, params, windows, options.concurrency, options.prefetch)
+		}
+
+		// Payments listed in the last second of a window, which the next
+		// window lists again
+		var previous, current map[string]bool
+		for window, batch := range pages {
+			if batch.err != nil {
+				yield(PaymentResponse{}, batch.err)
+				return
+			}
+
+			if batch.first {
+				previous, current = current, make(map[string]bool)
+			}
+			for _, payment := range batch.payments {
+				if previous[payment.EndToEndID] {
+					continue
+				}
+				if !payment.Time.Before(window.End.Add(-time.Second)) {
+					current[payment.EndToEndID] = true
+				}
+				if !yield(payment, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// windowBatch is a set of payments of a window
+// first is true for the first batch of each window.
+type windowBatch struct {
+	payments []PaymentResponse
+	first    bool
+	err      error
+}
+
+// sequentialWindows fetches the windows one after the other, delivering
+// each page as soon as it is consumed
+func (c *Client) sequentialWindows(ctx context.Context, params ListPaymentsParams, windows []PaymentWindow, prefetch int) iter.Seq2[PaymentWindow, windowBatch] {
+	return func(yield func(PaymentWindow, windowBatch) bool) {
+		for _, window := range windows {
+			first := true
+			for resp, err := range c.windowPages(ctx, params, window, prefetch) {
+				if err != nil {
+					yield(window, windowBatch{err: windowError(window, err)})
+					return
+				}
+				if !yield(window, windowBatch{payments: resp.Payments, first: first}) {
+					return
+				}
+				first = false
+			}
+		}
+	}
+}
+
+// concurrentWindows fetches up to limit windows ahead of the consumer and
+// delivers each of them whole, in order
+func (c *Client) concurrentWindows(ctx context.Context, params ListPaymentsParams, windows []PaymentWindow, limit, prefetch int) iter.Seq2[PaymentWindow, windowBatch] {
+	return func(yield func(PaymentWindow, windowBatch) bool) {
+		results := make([]chan windowBatch, len(windows))
+		for i := range results {
+			results[i] = make(chan windowBatch, 1)
+		}
+
+		// A slot is taken when a window is fetched and released when it is
+		// consumed, bounding the windows held in memory
+		slots := make(chan struct{}, limit)
+		go func() {
+			for i, window := range windows {
+				select {
+				case slots <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				go func() {
+					results[i] <- c.fetchWindow(ctx, params, window, prefetch)
+				}()
+			}
+		}()
+
+		for i, window := range windows {
+			var batch windowBatch
+			select {
+			case batch = <-results[i]:
+				<-slots
+			case <-ctx.Done():
+				yield(window, windowBatch{err: ctx.Err()})
+				return
+			}
+
+			if !yield(window, batch) || batch.err != nil {
+				return
+			}
+		}
+	}
+}
+
+// fetchWindow lists every page of a window
+func (c *Client) fetchWindow(ctx context.Context, params ListPaymentsParams, window PaymentWindow, prefetch int) windowBatch {
+	batch := windowBatch{first: true}
+	for resp, err := range c.windowPages(ctx, params, window, prefetch) {
+		if err != nil {
+			return windowBatch{err: windowError(window, err)}
+		}
+		batch.payments = append(batch.payments, resp.Payments...)
+	}
+	return batch
+}
+
+// windowPages yields every page of a window
+func (c *Client) windowPages(ctx context.Context, params ListPaymentsParams, window PaymentWindow, prefetch int) iter.Seq2[*PaymentListResponse, error] {
+	params.StartDate, params.EndDate = window.Start, window.End
+
+	return prefetchPages(ctx, prefetch, func(ctx context.Context, page int) (*PaymentListResponse, int, error) {
+		pageParams := params
+		pageParams.Page = page
+
+		resp, err := c.ListPayments(ctx, pageParams)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp, resp.Parameters.Pagination.TotalPages, nil
+	})
+}
+
+// windowError wraps err with the window it happened in
+func windowError(window PaymentWindow, err error) error {
+	return fmt.Errorf("failed to list payments from %s to %s: %w",
+		window.Start.Format(time.RFC3339), window.End.Format(time.RFC3339), err)
+}
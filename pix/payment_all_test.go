@@ -0,0 +1,136 @@
+package pix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// paymentWindowsServer serves three 5-day windows starting at start; E2 is
+// listed by the first two windows since it happened at their boundary
+func paymentWindowsServer(t *testing.T, start time.Time, failWindow int) *httptest.Server {
+	t.Helper()
+
+	boundary := start.Add(MaxPaymentListWindow).Format(time.RFC3339)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inicio, err := time.Parse(time.RFC3339, r.URL.Query().Get("inicio"))
+		if err != nil {
+			t.Errorf("inicio = %q: %v", r.URL.Query().Get("inicio"), err)
+		}
+		window := int(inicio.Sub(start) / MaxPaymentListWindow)
+		page := r.URL.Query().Get("paginacao.paginaAtual")
+
+		if window == failWindow {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case window == 0:
+			fmt.Fprintf(w, `{"pix":[{"endToEndId":"E1"},{"endToEndId":"E2","horario":%q}]}`, boundary)
+		case window == 1 && page == "0":
+			fmt.Fprintf(w, `{"parametros":{"paginacao":{"quantidadeDePaginas":2}},"pix":[{"endToEndId":"E2","horario":%q},{"endToEndId":"E3"}]}`, boundary)
+		case window == 1 && page == "1":
+			w.Write([]byte(`{"parametros":{"paginacao":{"paginaAtual":1,"quantidadeDePaginas":2}},"pix":[{"endToEndId":"E4"}]}`))
+		case window == 2:
+			w.Write([]byte(`{"pix":[{"endToEndId":"E5"}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.RawQuery)
+		}
+	}))
+}
+
+func TestClient_ListAllPayments(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	params := ListPaymentsParams{StartDate: start, EndDate: start.Add(12 * 24 * time.Hour), CPF: "12345678909"}
+
+	tests := []struct {
+		name string
+		opts []ListAllOption
+	}{
+		{"sequential", nil},
+		{"concurrent", []ListAllOption{WithWindowConcurrency(3)}},
+		{"prefetch", []ListAllOption{WithPagePrefetch(2)}},
+		{"concurrent prefetch", []ListAllOption{WithWindowConcurrency(3), WithPagePrefetch(2)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := paymentWindowsServer(t, start, -1)
+			defer server.Close()
+
+			client := NewClient(&http.Client{}, server.URL)
+
+			var got []string
+			for payment, err := range client.ListAllPayments(context.Background(), params, tt.opts...) {
+				if err != nil {
+					t.Fatalf("ListAllPayments() error = %v", err)
+				}
+				got = append(got, payment.EndToEndID)
+			}
+
+			if fmt.Sprint(got) != "[E1 E2 E3 E4 E5]" {
+				t.Errorf("payments = %v, want [E1 E2 E3 E4 E5]", got)
+			}
+		})
+	}
+}
+
+func TestClient_ListAllPayments_Error(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	params := ListPaymentsParams{StartDate: start, EndDate: start.Add(12 * 24 * time.Hour)}
+
+	for _, concurrency := range []int{1, 3} {
+		t.Run(fmt.Sprintf("concurrency %d", concurrency), func(t *testing.T) {
+			server := paymentWindowsServer(t, start, 1)
+			defer server.Close()
+
+			client := NewClient(&http.Client{}, server.URL)
+
+			var got []string
+			var gotErr error
+			for payment, err := range client.ListAllPayments(context.Background(), params, WithWindowConcurrency(concurrency)) {
+				if err != nil {
+					gotErr = err
+					continue
+				}
+				got = append(got, payment.EndToEndID)
+			}
+
+			if gotErr == nil {
+				t.Fatal("ListAllPayments() error = nil, want the failure of the second window")
+			}
+			if fmt.Sprint(got) != "[E1 E2]" {
+				t.Errorf("payments = %v, want [E1 E2] before the error", got)
+			}
+		})
+	}
+}
+
+func TestClient_ListAllPayments_Break(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	params := ListPaymentsParams{StartDate: start, EndDate: start.Add(12 * 24 * time.Hour)}
+
+	server := paymentWindowsServer(t, start, -1)
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	for _, concurrency := range []int{1, 3} {
+		count := 0
+		for _, err := range client.ListAllPayments(context.Background(), params, WithWindowConcurrency(concurrency)) {
+			if err != nil {
+				t.Fatalf("ListAllPayments() error = %v", err)
+			}
+			count++
+			break
+		}
+		if count != 1 {
+			t.Errorf("count = %d, want 1", count)
+		}
+	}
+}
@@ -3,8 +3,11 @@ package pix
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -189,16 +192,73 @@ func TestClient_ListPayments_WithFilters(t *testing.T) {
 	}
 }
 
+func TestClient_ListPayments_PresenceFilters(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		name   string
+		params ListPaymentsParams
+		want   map[string]string
+		absent []string
+	}{
+		{
+			name:   "with refunds and charges",
+			params: ListPaymentsParams{RefundPresent: &yes, TxIDPresent: &yes},
+			want:   map[string]string{"devolucaoPresente": "true", "txIdPresente": "true"},
+			absent: []string{"loteCobVId"},
+		},
+		{
+			name:   "without charge in a batch",
+			params: ListPaymentsParams{TxIDPresent: &no, BatchID: 42},
+			want:   map[string]string{"txIdPresente": "false", "loteCobVId": "42"},
+			absent: []string{"devolucaoPresente"},
+		},
+		{
+			name:   "unset",
+			params: ListPaymentsParams{},
+			absent: []string{"devolucaoPresente", "txIdPresente", "loteCobVId"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query := r.URL.Query()
+				for name, want := range tt.want {
+					if got := query.Get(name); got != want {
+						t.Errorf("%s = %q, want %q", name, got, want)
+					}
+				}
+				for _, name := range tt.absent {
+					if query.Has(name) {
+						t.Errorf("%s should not be sent", name)
+					}
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"pix":[]}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(&http.Client{}, server.URL)
+
+			if _, err := client.ListPayments(context.Background(), tt.params); err != nil {
+				t.Fatalf("ListPayments() error = %v", err)
+			}
+		})
+	}
+}
+
 func TestClient_ListPayments_WithPagination(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		query := r.URL.Query()
 
 		// Verify pagination parameters
-		if query.Get("paginaAtual") != "2" {
-			t.Errorf("paginaAtual = %s, want 2", query.Get("paginaAtual"))
+		if query.Get("paginacao.paginaAtual") != "2" {
+			t.Errorf("paginaAtual = %s, want 2", query.Get("paginacao.paginaAtual"))
 		}
-		if query.Get("itensPorPagina") != "50" {
-			t.Errorf("itensPorPagina = %s, want 50", query.Get("itensPorPagina"))
+		if query.Get("paginacao.itensPorPagina") != "50" {
+			t.Errorf("itensPorPagina = %s, want 50", query.Get("paginacao.itensPorPagina"))
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -240,3 +300,151 @@ func TestClient_ListPayments_WithPagination(t *testing.T) {
 		t.Errorf("ItemsPerPage = %d, want 50", resp.Parameters.Pagination.ItemsPerPage)
 	}
 }
+
+func TestClient_ListPaymentsInto_ReusesResponse(t *testing.T) {
+	pages := []string{
+		`{"parametros":{"inicio":"2024-01-01T00:00:00Z","fim":"2024-01-02T00:00:00Z","paginacao":{"paginaAtual":0,"itensPorPagina":2,"quantidadeDePaginas":2,"quantidadeTotalDeItens":3}},
+		  "pix":[{"endToEndId":"E1","txid":"t1","valor":"1.00","horario":"2024-01-01T10:00:00Z","infoPagador":"first page"},
+		         {"endToEndId":"E2","txid":"t2","valor":"2.00","horario":"2024-01-01T11:00:00Z"}]}`,
+		`{"parametros":{"inicio":"2024-01-01T00:00:00Z","fim":"2024-01-02T00:00:00Z","paginacao":{"paginaAtual":1,"itensPorPagina":2,"quantidadeDePaginas":2,"quantidadeTotalDeItens":3}},
+		  "pix":[{"endToEndId":"E3","valor":"3.00","horario":"2024-01-01T12:00:00Z"}]}`,
+	}
+	call := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(pages[call]))
+		call++
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+	params := ListPaymentsParams{
+		StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	var page PaymentListResponse
+	if err := client.ListPaymentsInto(context.Background(), params, &page); err != nil {
+		t.Fatalf("ListPaymentsInto() error = %v", err)
+	}
+	if len(page.Payments) != 2 {
+		t.Fatalf("len(Payments) = %d, want 2", len(page.Payments))
+	}
+	backing := &page.Payments[:1][0]
+
+	if err := client.ListPaymentsInto(context.Background(), params, &page); err != nil {
+		t.Fatalf("ListPaymentsInto() error = %v", err)
+	}
+	if len(page.Payments) != 1 {
+		t.Fatalf("len(Payments) = %d, want 1", len(page.Payments))
+	}
+	if &page.Payments[0] != backing {
+		t.Error("Payments backing array was not reused")
+	}
+
+	// Fields absent from the second page must not carry over from the first
+	got := page.Payments[0]
+	if got.EndToEndID != "E3" || got.TxID != "" || got.PayerInfo != "" {
+		t.Errorf("Payments[0] = %+v, want only E3 fields", got)
+	}
+	if page.Parameters.Pagination.CurrentPage != 1 {
+		t.Errorf("CurrentPage = %d, want 1", page.Parameters.Pagination.CurrentPage)
+	}
+}
+
+func TestClient_ListPaymentsFunc(t *testing.T) {
+	const page = `{"parametros":{"inicio":"2024-01-01T00:00:00Z","fim":"2024-01-02T00:00:00Z","paginacao":{"paginaAtual":0,"itensPorPagina":3,"quantidadeDePaginas":2,"quantidadeTotalDeItens":4}},
+		"extra":{"ignored":[1,2]},
+		"pix":[{"endToEndId":"E1","valor":"1.00","horario":"2024-01-01T10:00:00Z"},
+		       {"endToEndId":"E2","valor":"2.00","horario":"2024-01-01T11:00:00Z"},
+		       {"endToEndId":"E3","valor":"3.00","horario":"2024-01-01T12:00:00Z"}]}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pix" || r.URL.Query().Get("cpf") != "12345678909" {
+			t.Errorf("request = %s, want /pix filtered by cpf", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+	params := ListPaymentsParams{
+		StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		CPF:       "12345678909",
+	}
+
+	t.Run("delivers every payment", func(t *testing.T) {
+		var got []string
+		resp, err := client.ListPaymentsFunc(context.Background(), params, func(p PaymentResponse) error {
+			got = append(got, p.EndToEndID+"="+p.Value)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("ListPaymentsFunc() error = %v", err)
+		}
+
+		if want := "[E1=1.00 E2=2.00 E3=3.00]"; fmt.Sprint(got) != want {
+			t.Errorf("payments = %v, want %s", got, want)
+		}
+		if len(resp.Payments) != 0 {
+			t.Errorf("len(Payments) = %d, want 0", len(resp.Payments))
+		}
+		if !resp.HasNextPage() || resp.Parameters.Pagination.TotalItems != 4 {
+			t.Errorf("Pagination = %+v, want a next page of 4 items", resp.Parameters.Pagination)
+		}
+	})
+
+	t.Run("stops at callback error", func(t *testing.T) {
+		errStop := errors.New("stop")
+		calls := 0
+		_, err := client.ListPaymentsFunc(context.Background(), params, func(p PaymentResponse) error {
+			calls++
+			if p.EndToEndID == "E2" {
+				return errStop
+			}
+			return nil
+		})
+		if !errors.Is(err, errStop) {
+			t.Errorf("ListPaymentsFunc() error = %v, want %v", err, errStop)
+		}
+		if calls != 2 {
+			t.Errorf("calls = %d, want 2", calls)
+		}
+	})
+}
+
+func TestDecodePaymentStream(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    int
+		wantErr bool
+	}{
+		{"null list", `{"pix":null}`, 0, false},
+		{"empty object", `{}`, 0, false},
+		{"not an object", `[]`, 0, true},
+		{"pix not an array", `{"pix":{}}`, 0, true},
+		{"truncated", `{"pix":[{"endToEndId":"E1"},`, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp PaymentListResponse
+			got := 0
+			err := decodePaymentStream(strings.NewReader(tt.body), &resp, func(PaymentResponse) error {
+				got++
+				return nil
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodePaymentStream() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("payments = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
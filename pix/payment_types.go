@@ -1,6 +1,9 @@
 package pix
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // PaymentResponse represents a PIX payment
 type PaymentResponse struct {
@@ -9,17 +12,80 @@ type PaymentResponse struct {
 	Value      string       `json:"valor"`
 	Time       time.Time    `json:"horario"`
 	PayerInfo  string       `json:"infoPagador,omitempty"`
+	Payer      *Payer       `json:"pagador,omitempty"`
 	Refunds    []RefundInfo `json:"devolucoes,omitempty"`
+
+	// ValueComponents breaks Value down into the original amount, withdrawal
+	// (saque), change (troco), interest, fines, rebates and discounts
+	// It is nil when the bank does not send the breakdown.
+	ValueComponents *ValueComponents `json:"componentesValor,omitempty"`
+
+	// RawExtras holds the fields not modeled by this struct, such as ones
+	// added by newer versions of the API. It is only filled for the
+	// payments of a WebhookPayload, so listings are decoded without the
+	// extra pass.
+	RawExtras map[string]json.RawMessage `json:"-"`
+}
+
+// Payer identifies who paid, when the bank discloses it (pagador)
+type Payer struct {
+	CPF  string `json:"cpf,omitempty"`
+	CNPJ string `json:"cnpj,omitempty"`
+	Name string `json:"nome,omitempty"`
+}
+
+// Document returns the CPF or, for companies, the CNPJ of the payer
+func (p Payer) Document() string {
+	if p.CPF != "" {
+		return p.CPF
+	}
+	return p.CNPJ
+}
+
+// ValueComponents represents the composition of a received payment
+// (componentesValor)
+type ValueComponents struct {
+	Original   *ValueComponent     `json:"original,omitempty"`
+	Withdrawal *CashValueComponent `json:"saque,omitempty"`
+	Change     *CashValueComponent `json:"troco,omitempty"`
+	Interest   *ValueComponent     `json:"juros,omitempty"`
+	Fine       *ValueComponent     `json:"multa,omitempty"`
+	Rebate     *ValueComponent     `json:"abatimento,omitempty"`
+	Discount   *ValueComponent     `json:"desconto,omitempty"`
+}
+
+// ValueComponent represents a single amount of the payment composition
+type ValueComponent struct {
+	Value string `json:"valor"`
+}
+
+// CashValueComponent represents the cash part of a Pix Saque or Pix Troco
+// payment and the agent that handed it out
+type CashValueComponent struct {
+	Value string `json:"valor"`
+	// AgentMode is the withdrawal agent mode (modalidadeAgente), e.g.
+	// "AGTEC", "AGTOT" or "AGPSS"
+	AgentMode string `json:"modalidadeAgente,omitempty"`
+	// ServiceProvider is the ISPB of the withdrawal service provider
+	ServiceProvider string `json:"prestadorDeServicoDeSaque,omitempty"`
 }
 
 // RefundInfo represents information about a refund
 type RefundInfo struct {
-	ID     string     `json:"id"`
-	RtrID  string     `json:"rtrId"`
-	Value  string     `json:"valor"`
-	Time   RefundTime `json:"horario"`
-	Status string     `json:"status"`
-	Reason string     `json:"motivo,omitempty"`
+	ID     string       `json:"id"`
+	RtrID  string       `json:"rtrId"`
+	Value  string       `json:"valor"`
+	Time   RefundTime   `json:"horario"`
+	Status RefundStatus `json:"status"`
+	Nature RefundNature `json:"natureza,omitempty"`
+	Reason string       `json:"motivo,omitempty"`
+
+	Description string `json:"descricao,omitempty"`
+}
+
+// IsMED reports whether the refund is a MED return
+func (r RefundInfo) IsMED() bool {
+	return r.Nature.IsMED()
 }
 
 // RefundTime represents refund timing information
@@ -29,14 +95,26 @@ type RefundTime struct {
 }
 
 // ListPaymentsParams represents parameters for listing payments
+// paginaAtual and itensPorPagina are always sent: Page is zero-based and a
+// zero PageSize requests 100 items per page.
 type ListPaymentsParams struct {
 	StartDate time.Time `json:"inicio"`
 	EndDate   time.Time `json:"fim"`
 	TxID      string    `json:"txid,omitempty"`
 	CPF       string    `json:"cpf,omitempty"`
 	CNPJ      string    `json:"cnpj,omitempty"`
-	Page      int       `json:"paginaAtual,omitempty"`
-	PageSize  int       `json:"itensPorPagina,omitempty"`
+
+	// TxIDPresent filters payments with (true) or without (false) an
+	// associated charge; nil lists both
+	TxIDPresent *bool `json:"txIdPresente,omitempty"`
+	// RefundPresent filters payments with (true) or without (false)
+	// refunds; nil lists both
+	RefundPresent *bool `json:"devolucaoPresente,omitempty"`
+	// BatchID filters payments of the charges of a lotecobv batch
+	BatchID int `json:"loteCobVId,omitempty"`
+
+	Page     int `json:"paginaAtual,omitempty"`
+	PageSize int `json:"itensPorPagina,omitempty"`
 }
 
 // PaymentListResponse represents a list of payments
@@ -48,3 +126,27 @@ type PaymentListResponse struct {
 	} `json:"parametros"`
 	Payments []PaymentResponse `json:"pix"`
 }
+
+// Reset clears the response while keeping the capacity of the Payments slice
+func (r *PaymentListResponse) Reset() {
+	payments := r.Payments[:cap(r.Payments)]
+	clear(payments)
+	*r = PaymentListResponse{Payments: payments[:0]}
+}
+
+// HasNextPage reports whether there is a page after this one
+func (r *PaymentListResponse) HasNextPage() bool {
+	return r.Parameters.Pagination.HasNextPage()
+}
+
+// NextPageParams returns params, the parameters that listed this page, set
+// to request the next page
+func (r *PaymentListResponse) NextPageParams(params ListPaymentsParams) ListPaymentsParams {
+	params.Page = r.Parameters.Pagination.CurrentPage + 1
+	return params
+}
+
+// TotalRemaining returns how many payments are listed by the next pages
+func (r *PaymentListResponse) TotalRemaining() int {
+	return r.Parameters.Pagination.TotalRemaining()
+}
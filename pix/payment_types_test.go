@@ -229,3 +229,116 @@ func TestPaymentResponse_NoRefunds(t *testing.T) {
 		t.Errorf("len(Refunds) = %d, want 0", len(resp.Refunds))
 	}
 }
+
+func TestPaymentResponse_UnmarshalValueComponents(t *testing.T) {
+	jsonData := `{
+		"endToEndId": "E12345678202401151000000000001",
+		"valor": "120.00",
+		"horario": "2024-01-15T10:30:45Z",
+		"componentesValor": {
+			"original": {"valor": "20.00"},
+			"troco": {"valor": "100.00", "modalidadeAgente": "AGTEC", "prestadorDeServicoDeSaque": "12345678"},
+			"juros": {"valor": "1.00"},
+			"desconto": {"valor": "1.00"}
+		}
+	}`
+
+	var resp PaymentResponse
+	if err := json.Unmarshal([]byte(jsonData), &resp); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	c := resp.ValueComponents
+	if c == nil {
+		t.Fatal("ValueComponents = nil")
+	}
+	if c.Original == nil || c.Original.Value != "20.00" {
+		t.Errorf("Original = %+v, want 20.00", c.Original)
+	}
+	if c.Change == nil || c.Change.Value != "100.00" || c.Change.AgentMode != "AGTEC" || c.Change.ServiceProvider != "12345678" {
+		t.Errorf("Change = %+v", c.Change)
+	}
+	if c.Interest == nil || c.Discount == nil {
+		t.Errorf("Interest = %+v, Discount = %+v, want both set", c.Interest, c.Discount)
+	}
+	if c.Withdrawal != nil || c.Fine != nil || c.Rebate != nil {
+		t.Error("absent components should be nil")
+	}
+	if _, ok := resp.RawExtras["componentesValor"]; ok {
+		t.Error("componentesValor should not be in RawExtras")
+	}
+}
+
+func TestPaymentResponse_UnmarshalPayer(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantDoc string
+		wantNil bool
+	}{
+		{"person", `{"endToEndId":"E1","pagador":{"cpf":"12345678909","nome":"Fulano de Tal"}}`, "12345678909", false},
+		{"company", `{"endToEndId":"E1","pagador":{"cnpj":"12345678000195","nome":"Empresa LTDA"}}`, "12345678000195", false},
+		{"absent", `{"endToEndId":"E1","infoPagador":"obrigado"}`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp PaymentResponse
+			if err := json.Unmarshal([]byte(tt.json), &resp); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			if (resp.Payer == nil) != tt.wantNil {
+				t.Fatalf("Payer = %+v, want nil %v", resp.Payer, tt.wantNil)
+			}
+			if resp.Payer == nil {
+				return
+			}
+			if got := resp.Payer.Document(); got != tt.wantDoc {
+				t.Errorf("Document() = %s, want %s", got, tt.wantDoc)
+			}
+			if resp.Payer.Name == "" {
+				t.Error("Name not decoded")
+			}
+			if _, ok := resp.RawExtras["pagador"]; ok {
+				t.Error("pagador should not be in RawExtras")
+			}
+		})
+	}
+}
+
+func TestPaymentResponse_UnmarshalSkipsRawExtras(t *testing.T) {
+	var resp PaymentResponse
+	if err := json.Unmarshal([]byte(`{"endToEndId":"E1","valor":"1.00","gnAttrs":{"tipo":"cob"}}`), &resp); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	// Unknown fields are only kept for webhook payloads
+	if resp.EndToEndID != "E1" || resp.RawExtras != nil {
+		t.Errorf("resp = %+v, want E1 without RawExtras", resp)
+	}
+}
+
+func TestPaymentListResponse_Pagination(t *testing.T) {
+	var resp PaymentListResponse
+	if err := json.Unmarshal([]byte(`{"parametros":{"paginacao":{"paginaAtual":0,"itensPorPagina":2,"quantidadeDePaginas":2,"quantidadeTotalDeItens":3}},"pix":[{"endToEndId":"E1"},{"endToEndId":"E2"}]}`), &resp); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if !resp.HasNextPage() {
+		t.Fatal("HasNextPage() = false, want true")
+	}
+	if got := resp.TotalRemaining(); got != 1 {
+		t.Errorf("TotalRemaining() = %d, want 1", got)
+	}
+
+	params := resp.NextPageParams(ListPaymentsParams{CPF: "12345678909", PageSize: 2})
+	if params.Page != 1 || params.CPF != "12345678909" {
+		t.Errorf("NextPageParams() = %+v, want page 1 keeping the filters", params)
+	}
+
+	resp.Parameters.Pagination.CurrentPage = 1
+	if resp.HasNextPage() || resp.TotalRemaining() != 0 {
+		t.Error("last page reports a next page")
+	}
+}
@@ -0,0 +1,104 @@
+package pix
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+// maxPages bounds the number of pages reported by the API that a listing
+// follows, so a bogus quantidadeDePaginas cannot exhaust memory
+const maxPages = 100_000
+
+// pageFetchFunc fetches a page of a listing and returns it with the total
+// number of pages reported by the API
+type pageFetchFunc[T any] func(ctx context.Context, page int) (T, int, error)
+
+// pageResult is a page fetched ahead of the consumer
+type pageResult[T any] struct {
+	page T
+	err  error
+}
+
+// prefetchPages yields every page of a listing in order, stopping after the
+// first error. The number of pages is taken from the first page; after it,
+// up to ahead pages are fetched concurrently while the consumer processes
+// the previous ones. With ahead <= 0 the pages are fetched one at a time.
+func prefetchPages[T any](ctx context.Context, ahead int, fetch pageFetchFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		first, total, err := fetch(ctx, 0)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		if !yield(first, nil) {
+			return
+		}
+
+		total = max(total, 0)
+		if total > maxPages {
+			yield(zero, fmt.Errorf("page count %d exceeds the limit of %d", total, maxPages))
+			return
+		}
+
+		if ahead <= 0 {
+			for page := 1; page < total; page++ {
+				resp, _, err := fetch(ctx, page)
+				if err != nil {
+					yield(zero, err)
+					return
+				}
+				if !yield(resp, nil) {
+					return
+				}
+			}
+			return
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		results := make([]chan pageResult[T], total)
+		for page := 1; page < total; page++ {
+			results[page] = make(chan pageResult[T], 1)
+		}
+
+		// A slot is taken when a page is fetched and released when it is
+		// consumed, bounding the pages held in memory
+		slots := make(chan struct{}, ahead)
+		go func() {
+			for page := 1; page < total; page++ {
+				select {
+				case slots <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				go func() {
+					resp, _, err := fetch(ctx, page)
+					results[page] <- pageResult[T]{page: resp, err: err}
+				}()
+			}
+		}()
+
+		for page := 1; page < total; page++ {
+			var result pageResult[T]
+			select {
+			case result = <-results[page]:
+				<-slots
+			case <-ctx.Done():
+				yield(zero, ctx.Err())
+				return
+			}
+
+			if result.err != nil {
+				yield(zero, result.err)
+				return
+			}
+			if !yield(result.page, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package pix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPrefetchPages(t *testing.T) {
+	const total = 10
+
+	tests := []struct {
+		name  string
+		ahead int
+	}{
+		{"one page at a time", 0},
+		{"one ahead", 1},
+		{"four ahead", 4},
+		{"more than the pages", 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu             sync.Mutex
+				inFlight, peak int
+			)
+			fetch := func(ctx context.Context, page int) (int, int, error) {
+				mu.Lock()
+				inFlight++
+				peak = max(peak, inFlight)
+				mu.Unlock()
+
+				// Later pages answer first to exercise the reordering
+				time.Sleep(time.Duration(total-page) * time.Millisecond)
+
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+				return page, total, nil
+			}
+
+			var got []int
+			for page, err := range prefetchPages(context.Background(), tt.ahead, fetch) {
+				if err != nil {
+					t.Fatalf("prefetchPages() error = %v", err)
+				}
+				got = append(got, page)
+			}
+
+			if fmt.Sprint(got) != "[0 1 2 3 4 5 6 7 8 9]" {
+				t.Errorf("pages = %v, want them in order", got)
+			}
+			if limit := max(tt.ahead, 1); peak > limit {
+				t.Errorf("max in flight = %d, want at most %d", peak, limit)
+			}
+		})
+	}
+}
+
+func TestPrefetchPages_StopsAtError(t *testing.T) {
+	errPage := errors.New("page failed")
+	fetch := func(ctx context.Context, page int) (int, int, error) {
+		if page == 3 {
+			return 0, 0, errPage
+		}
+		return page, 10, nil
+	}
+
+	var got []int
+	var gotErr error
+	for page, err := range prefetchPages(context.Background(), 2, fetch) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		got = append(got, page)
+	}
+
+	if !errors.Is(gotErr, errPage) {
+		t.Errorf("error = %v, want %v", gotErr, errPage)
+	}
+	if fmt.Sprint(got) != "[0 1 2]" {
+		t.Errorf("pages = %v, want [0 1 2]", got)
+	}
+}
+
+func TestPrefetchPages_BreakCancelsFetches(t *testing.T) {
+	cancelled := make(chan struct{}, 10)
+	fetch := func(ctx context.Context, page int) (int, int, error) {
+		if page <= 1 {
+			return page, 10, nil
+		}
+		<-ctx.Done()
+		cancelled <- struct{}{}
+		return 0, 0, ctx.Err()
+	}
+
+	for page := range prefetchPages(context.Background(), 3, fetch) {
+		if page == 1 {
+			break
+		}
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("pending fetches were not cancelled after break")
+	}
+}
+
+func TestPrefetchPages_InvalidPageCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int
+		wantErr bool
+	}{
+		{"negative", -5, false},
+		{"absurd", 10 * maxPages, true},
+	}
+
+	for _, tt := range tests {
+		for _, ahead := range []int{0, 4} {
+			t.Run(fmt.Sprintf("%s ahead %d", tt.name, ahead), func(t *testing.T) {
+				fetches := 0
+				fetch := func(ctx context.Context, page int) (int, int, error) {
+					fetches++
+					return page, tt.total, nil
+				}
+
+				var pages []int
+				var gotErr error
+				for page, err := range prefetchPages(context.Background(), ahead, fetch) {
+					if err != nil {
+						gotErr = err
+						break
+					}
+					pages = append(pages, page)
+				}
+
+				if (gotErr != nil) != tt.wantErr {
+					t.Errorf("error = %v, wantErr %v", gotErr, tt.wantErr)
+				}
+				if fmt.Sprint(pages) != "[0]" || fetches != 1 {
+					t.Errorf("pages = %v after %d fetches, want only the first page", pages, fetches)
+				}
+			})
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 )
 
 // CreateQRCode creates a new QR Code
@@ -13,10 +14,17 @@ func (c *Client) CreateQRCode(ctx context.Context, req CreateQRCodeRequest) (*QR
 		return nil, fmt.Errorf("txid is required")
 	}
 
+	if c.normalizer != nil {
+		req = c.normalizer.QRCodeRequest(req)
+	}
+
 	// Build path
-	path := fmt.Sprintf("/cob/%s", req.TxID)
+	path := fmt.Sprintf("/cob/%s", url.PathEscape(req.TxID))
 
 	// Create HTTP request
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.Write)
+	defer cancel()
+
 	httpReq, err := c.http.NewRequest(ctx, http.MethodPut, path, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -31,25 +39,63 @@ func (c *Client) CreateQRCode(ctx context.Context, req CreateQRCodeRequest) (*QR
 	return &resp, nil
 }
 
+// CreateOrGetQRCode creates a QR Code, or returns the existing charge when
+// the txid was already used (for example by a previous attempt of a retried
+// checkout). The existing charge must have the same value as req, otherwise
+// ErrTxIDConflict is returned.
+func (c *Client) CreateOrGetQRCode(ctx context.Context, req CreateQRCodeRequest) (*CreateOrGetQRCodeResult, error) {
+	resp, err := c.CreateQRCode(ctx, req)
+	if err == nil {
+		return &CreateOrGetQRCodeResult{Charge: resp}, nil
+	}
+	if !IsDuplicateTxIDError(err) {
+		return nil, err
+	}
+
+	existing, getErr := c.GetQRCode(ctx, req.TxID)
+	if getErr != nil {
+		return nil, fmt.Errorf("failed to get existing qr code: %w", getErr)
+	}
+
+	if want := fmt.Sprintf("%.2f", req.Value); existing.Value.Original != want {
+		return nil, fmt.Errorf("%w: txid %s has value %s, want %s", ErrTxIDConflict, req.TxID, existing.Value.Original, want)
+	}
+
+	return &CreateOrGetQRCodeResult{Charge: existing, AlreadyExisted: true}, nil
+}
+
 // GetQRCode retrieves a QR Code by TxID
 func (c *Client) GetQRCode(ctx context.Context, txID string) (*QRCodeResponse, error) {
+	var resp QRCodeResponse
+	if err := c.GetQRCodeInto(ctx, txID, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// GetQRCodeInto retrieves a QR Code by TxID, decoding it into dst
+// dst is reset before decoding, so it can be reused across calls
+func (c *Client) GetQRCodeInto(ctx context.Context, txID string, dst *QRCodeResponse) error {
 	if txID == "" {
-		return nil, fmt.Errorf("txid is required")
+		return fmt.Errorf("txid is required")
 	}
 
-	path := fmt.Sprintf("/cob/%s", txID)
+	path := fmt.Sprintf("/cob/%s", url.PathEscape(txID))
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.Read)
+	defer cancel()
 
 	httpReq, err := c.http.NewRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	var resp QRCodeResponse
-	if err := c.http.Do(httpReq, &resp); err != nil {
-		return nil, fmt.Errorf("failed to get qr code: %w", err)
+	if err := c.http.Do(httpReq, dst); err != nil {
+		return fmt.Errorf("failed to get qr code: %w", err)
 	}
 
-	return &resp, nil
+	return nil
 }
 
 // UpdateQRCode updates an existing QR Code
@@ -58,7 +104,10 @@ func (c *Client) UpdateQRCode(ctx context.Context, txID string, req UpdateQRCode
 		return nil, fmt.Errorf("txid is required")
 	}
 
-	path := fmt.Sprintf("/cob/%s", txID)
+	path := fmt.Sprintf("/cob/%s", url.PathEscape(txID))
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.Write)
+	defer cancel()
 
 	httpReq, err := c.http.NewRequest(ctx, http.MethodPatch, path, req)
 	if err != nil {
@@ -75,11 +124,26 @@ func (c *Client) UpdateQRCode(ctx context.Context, txID string, req UpdateQRCode
 
 // ListQRCodes lists QR Codes with optional filters
 func (c *Client) ListQRCodes(ctx context.Context, params ListQRCodesParams) (*QRCodeListResponse, error) {
+	var resp QRCodeListResponse
+	if err := c.ListQRCodesInto(ctx, params, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ListQRCodesInto lists QR Codes with optional filters, decoding the page into dst
+// dst is reset before decoding and keeps its slice capacity, so a single
+// value can be reused across pages and polling iterations
+func (c *Client) ListQRCodesInto(ctx context.Context, params ListQRCodesParams, dst *QRCodeListResponse) error {
 	path := "/cob"
 
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.List)
+	defer cancel()
+
 	httpReq, err := c.http.NewRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Add query parameters
@@ -96,30 +160,48 @@ func (c *Client) ListQRCodes(ctx context.Context, params ListQRCodesParams) (*QR
 	if params.Status != "" {
 		q.Set("status", params.Status)
 	}
-	if params.Page > 0 {
-		q.Set("paginaAtual", fmt.Sprintf("%d", params.Page))
-	}
-	if params.PageSize > 0 {
-		q.Set("itensPorPagina", fmt.Sprintf("%d", params.PageSize))
-	}
+	c.http.SetPagination(q, params.Page, params.PageSize)
 
 	httpReq.URL.RawQuery = q.Encode()
 
-	var resp QRCodeListResponse
-	if err := c.http.Do(httpReq, &resp); err != nil {
-		return nil, fmt.Errorf("failed to list qr codes: %w", err)
+	if err := c.http.Do(httpReq, dst); err != nil {
+		return fmt.Errorf("failed to list qr codes: %w", err)
 	}
 
-	return &resp, nil
+	return nil
+}
+
+// DeleteOption is a functional option for DeleteQRCode and RemoveQRCode
+type DeleteOption func(*deleteOptions)
+
+// deleteOptions holds the options of a charge removal
+type deleteOptions struct {
+	verifyActive bool
+}
+
+// WithVerifyActive fetches the charge before removing it and fails with
+// ErrChargeAlreadyConcluded or ErrChargeAlreadyRemoved unless it is still
+// ATIVA, so a charge paid in the meantime is not removed by accident
+func WithVerifyActive() DeleteOption {
+	return func(opts *deleteOptions) {
+		opts.verifyActive = true
+	}
 }
 
 // DeleteQRCode deletes a QR Code
-func (c *Client) DeleteQRCode(ctx context.Context, txID string) error {
+func (c *Client) DeleteQRCode(ctx context.Context, txID string, opts ...DeleteOption) error {
 	if txID == "" {
 		return fmt.Errorf("txid is required")
 	}
 
-	path := fmt.Sprintf("/cob/%s", txID)
+	if err := c.verifyChargeActive(ctx, txID, opts); err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/cob/%s", url.PathEscape(txID))
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.Write)
+	defer cancel()
 
 	httpReq, err := c.http.NewRequest(ctx, http.MethodDelete, path, nil)
 	if err != nil {
@@ -132,3 +214,61 @@ func (c *Client) DeleteQRCode(ctx context.Context, txID string) error {
 
 	return nil
 }
+
+// RemoveQRCode removes a charge by setting its status to
+// REMOVIDA_PELO_USUARIO_RECEBEDOR and returns the resulting charge
+func (c *Client) RemoveQRCode(ctx context.Context, txID string, opts ...DeleteOption) (*QRCodeResponse, error) {
+	if txID == "" {
+		return nil, fmt.Errorf("txid is required")
+	}
+
+	if err := c.verifyChargeActive(ctx, txID, opts); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/cob/%s", url.PathEscape(txID))
+
+	body := removeChargeRequest{Status: ChargeStatusRemovedByReceiver}
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.Write)
+	defer cancel()
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodPatch, path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp QRCodeResponse
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to remove qr code: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// verifyChargeActive checks that the charge is still ATIVA when
+// WithVerifyActive is set
+func (c *Client) verifyChargeActive(ctx context.Context, txID string, opts []DeleteOption) error {
+	var options deleteOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if !options.verifyActive {
+		return nil
+	}
+
+	charge, err := c.GetQRCode(ctx, txID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case charge.Status == ChargeStatusConcluded:
+		return fmt.Errorf("%w: txid %s", ErrChargeAlreadyConcluded, txID)
+	case charge.IsRemoved():
+		return fmt.Errorf("%w: txid %s has status %s", ErrChargeAlreadyRemoved, txID, charge.Status)
+	}
+
+	return nil
+}
@@ -3,8 +3,12 @@ package pix
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -193,6 +197,12 @@ func TestClient_ListQRCodes_Success(t *testing.T) {
 		if query.Get("fim") == "" {
 			t.Error("fim query parameter missing")
 		}
+		if got := query.Get("paginacao.paginaAtual"); got != "0" {
+			t.Errorf("paginaAtual = %q, want 0", got)
+		}
+		if got := query.Get("paginacao.itensPorPagina"); got != "100" {
+			t.Errorf("itensPorPagina = %q, want 100", got)
+		}
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]interface{}{
@@ -200,9 +210,9 @@ func TestClient_ListQRCodes_Success(t *testing.T) {
 				"inicio": "2024-01-01T00:00:00Z",
 				"fim":    "2024-01-31T23:59:59Z",
 				"paginacao": map[string]interface{}{
-					"paginaAtual":           0,
-					"itensPorPagina":        100,
-					"quantidadeDePaginas":   1,
+					"paginaAtual":            0,
+					"itensPorPagina":         100,
+					"quantidadeDePaginas":    1,
 					"quantidadeTotalDeItens": 2,
 				},
 			},
@@ -274,9 +284,9 @@ func TestClient_ListQRCodes_WithFilters(t *testing.T) {
 				"inicio": "2024-01-01T00:00:00Z",
 				"fim":    "2024-01-31T23:59:59Z",
 				"paginacao": map[string]interface{}{
-					"paginaAtual":           0,
-					"itensPorPagina":        100,
-					"quantidadeDePaginas":   1,
+					"paginaAtual":            0,
+					"itensPorPagina":         100,
+					"quantidadeDePaginas":    1,
 					"quantidadeTotalDeItens": 1,
 				},
 			},
@@ -322,3 +332,343 @@ func TestClient_DeleteQRCode_Success(t *testing.T) {
 		t.Fatalf("DeleteQRCode() error = %v", err)
 	}
 }
+
+func TestClient_GetQRCodeInto_ResetsDestination(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"calendario":{"criacao":"2024-01-15T10:00:00Z","expiracao":3600},"txid":"txid123","revisao":1,"status":"ATIVA","valor":{"original":"10.00"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	dst := QRCodeResponse{QRCode: "stale", Key: "stale"}
+	if err := client.GetQRCodeInto(context.Background(), "txid123", &dst); err != nil {
+		t.Fatalf("GetQRCodeInto() error = %v", err)
+	}
+	if dst.TxID != "txid123" {
+		t.Errorf("TxID = %s, want txid123", dst.TxID)
+	}
+	if dst.QRCode != "" || dst.Key != "" {
+		t.Error("stale fields were not reset")
+	}
+}
+
+func TestClient_CreateOrGetQRCode(t *testing.T) {
+	duplicate := `{"type":"https://api.bb.com.br/api/v2/error/RegraDeNegocio","title":"Entidade não processável","status":422,"detail":"Violação de regra de negócio","violacoes":[{"razao":"O txid já existe para este CPF/CNPJ","propriedade":"txid"}]}`
+	existing := `{"calendario":{"criacao":"2024-01-15T10:00:00Z","expiracao":3600},"txid":"txid1234567890123456789012","revisao":0,"status":"ATIVA","valor":{"original":"%s"}}`
+
+	tests := []struct {
+		name              string
+		putStatus         int
+		putBody           string
+		existingValue     string
+		wantErr           error
+		wantAlreadyExists bool
+		wantGet           bool
+	}{
+		{
+			name:      "created",
+			putStatus: http.StatusCreated,
+			putBody:   fmt.Sprintf(existing, "10.00"),
+		},
+		{
+			name:              "already existed",
+			putStatus:         http.StatusUnprocessableEntity,
+			putBody:           duplicate,
+			existingValue:     "10.00",
+			wantAlreadyExists: true,
+			wantGet:           true,
+		},
+		{
+			name:          "txid reused with different value",
+			putStatus:     http.StatusUnprocessableEntity,
+			putBody:       duplicate,
+			existingValue: "99.00",
+			wantErr:       ErrTxIDConflict,
+			wantGet:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotGet := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.Method == http.MethodGet {
+					gotGet = true
+					fmt.Fprintf(w, existing, tt.existingValue)
+					return
+				}
+				w.WriteHeader(tt.putStatus)
+				w.Write([]byte(tt.putBody))
+			}))
+			defer server.Close()
+
+			client := NewClient(&http.Client{}, server.URL)
+			result, err := client.CreateOrGetQRCode(context.Background(), CreateQRCodeRequest{
+				TxID:  "txid1234567890123456789012",
+				Value: 10,
+			})
+
+			if gotGet != tt.wantGet {
+				t.Errorf("GET issued = %v, want %v", gotGet, tt.wantGet)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CreateOrGetQRCode() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateOrGetQRCode() error = %v", err)
+			}
+			if result.AlreadyExisted != tt.wantAlreadyExists {
+				t.Errorf("AlreadyExisted = %v, want %v", result.AlreadyExisted, tt.wantAlreadyExists)
+			}
+			if result.Charge.Value.Original != "10.00" {
+				t.Errorf("Value = %s, want 10.00", result.Charge.Value.Original)
+			}
+		})
+	}
+}
+
+func TestClient_WithConvenio(t *testing.T) {
+	got := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got[r.Method+" "+r.URL.Path] = r.URL.Query().Get(ConvenioParam)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/cob" {
+			w.Write([]byte(`{"cobs":[]}`))
+			return
+		}
+		w.Write([]byte(`{"txid":"txid123","status":"ATIVA","valor":{"original":"10.00"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL, WithConvenio("1234567"))
+	ctx := context.Background()
+	if _, err := client.ListQRCodes(ctx, ListQRCodesParams{StartDate: time.Now().Add(-time.Hour), EndDate: time.Now()}); err != nil {
+		t.Fatalf("ListQRCodes() error = %v", err)
+	}
+	if _, err := client.GetQRCode(ctx, "txid123"); err != nil {
+		t.Fatalf("GetQRCode() error = %v", err)
+	}
+	if _, err := client.UpdateQRCode(ctx, "txid123", UpdateQRCodeRequest{Value: 10}); err != nil {
+		t.Fatalf("UpdateQRCode() error = %v", err)
+	}
+	if err := client.ConfigureWebhook(ctx, "chave", WebhookConfig{WebhookURL: "https://example.com"}); err != nil {
+		t.Fatalf("ConfigureWebhook() error = %v", err)
+	}
+
+	want := map[string]string{
+		"GET /cob":           "1234567",
+		"GET /cob/txid123":   "",
+		"PATCH /cob/txid123": "",
+		"PUT /webhook/chave": "",
+	}
+	for call, wantConvenio := range want {
+		if convenio, ok := got[call]; !ok || convenio != wantConvenio {
+			t.Errorf("%s: %s = %q (sent %v), want %q", call, ConvenioParam, convenio, ok, wantConvenio)
+		}
+	}
+}
+
+// deadlineRecorder is an http.RoundTripper that records the time left
+// before the deadline of each request
+type deadlineRecorder struct {
+	left []time.Duration // zero when the request has no deadline
+}
+
+func (d *deadlineRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var left time.Duration
+	if deadline, ok := req.Context().Deadline(); ok {
+		left = time.Until(deadline)
+	}
+	d.left = append(d.left, left)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"txid":"txid123","status":"ATIVA","valor":{"original":"10.00"}}`)),
+	}, nil
+}
+
+func TestClient_WithTimeouts(t *testing.T) {
+	timeouts := Timeouts{Read: 1 * time.Second, Write: 5 * time.Second, List: 30 * time.Second}
+
+	tests := []struct {
+		name     string
+		timeouts Timeouts
+		call     func(ctx context.Context, c *Client) error
+		want     time.Duration
+	}{
+		{
+			name:     "read",
+			timeouts: timeouts,
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetQRCode(ctx, "txid123")
+				return err
+			},
+			want: timeouts.Read,
+		},
+		{
+			name:     "write",
+			timeouts: timeouts,
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.UpdateQRCode(ctx, "txid123", UpdateQRCodeRequest{})
+				return err
+			},
+			want: timeouts.Write,
+		},
+		{
+			name:     "list",
+			timeouts: timeouts,
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.ListQRCodes(ctx, ListQRCodesParams{})
+				return err
+			},
+			want: timeouts.List,
+		},
+		{
+			name: "unset",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetQRCode(ctx, "txid123")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &deadlineRecorder{}
+			client := NewClient(&http.Client{Transport: recorder}, "http://example.com", WithTimeouts(tt.timeouts))
+
+			if err := tt.call(context.Background(), client); err != nil {
+				t.Fatalf("call error = %v", err)
+			}
+			if len(recorder.left) != 1 {
+				t.Fatalf("requests = %d, want 1", len(recorder.left))
+			}
+			if left := recorder.left[0]; left > tt.want || left < tt.want-time.Second/2 {
+				t.Errorf("deadline in %v, want %v", left, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_WithTimeouts_KeepsEarlierDeadline(t *testing.T) {
+	recorder := &deadlineRecorder{}
+	client := NewClient(&http.Client{Transport: recorder}, "http://example.com", WithTimeouts(Timeouts{Read: time.Minute}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := client.GetQRCode(ctx, "txid123"); err != nil {
+		t.Fatalf("GetQRCode() error = %v", err)
+	}
+	if left := recorder.left[0]; left > time.Second {
+		t.Errorf("deadline in %v, want at most 1s", left)
+	}
+}
+
+func TestClient_RemoveQRCode_VerifyActive(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      string
+		opts        []DeleteOption
+		wantErr     error
+		wantPatched bool
+	}{
+		{
+			name:        "active charge",
+			status:      ChargeStatusActive,
+			opts:        []DeleteOption{WithVerifyActive()},
+			wantPatched: true,
+		},
+		{
+			name:    "paid meanwhile",
+			status:  ChargeStatusConcluded,
+			opts:    []DeleteOption{WithVerifyActive()},
+			wantErr: ErrChargeAlreadyConcluded,
+		},
+		{
+			name:    "already removed",
+			status:  ChargeStatusRemovedByPSP,
+			opts:    []DeleteOption{WithVerifyActive()},
+			wantErr: ErrChargeAlreadyRemoved,
+		},
+		{
+			name:        "without verification",
+			status:      ChargeStatusConcluded,
+			wantPatched: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patched := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.Method {
+				case http.MethodGet:
+					fmt.Fprintf(w, `{"txid":"txid123","status":%q,"valor":{"original":"10.00"}}`, tt.status)
+				case http.MethodPatch:
+					patched = true
+					var body map[string]string
+					json.NewDecoder(r.Body).Decode(&body)
+					if body["status"] != ChargeStatusRemovedByReceiver {
+						t.Errorf("status = %q, want %s", body["status"], ChargeStatusRemovedByReceiver)
+					}
+					fmt.Fprintf(w, `{"txid":"txid123","status":%q,"valor":{"original":"10.00"}}`, ChargeStatusRemovedByReceiver)
+				default:
+					t.Errorf("unexpected method %s", r.Method)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(&http.Client{}, server.URL)
+			charge, err := client.RemoveQRCode(context.Background(), "txid123", tt.opts...)
+
+			if patched != tt.wantPatched {
+				t.Errorf("patched = %v, want %v", patched, tt.wantPatched)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("RemoveQRCode() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RemoveQRCode() error = %v", err)
+			}
+			if !charge.IsRemoved() {
+				t.Errorf("Status = %s, want a removed status", charge.Status)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteQRCode_VerifyActive(t *testing.T) {
+	deleted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"txid":"txid123","status":"CONCLUIDA","valor":{"original":"10.00"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+	err := client.DeleteQRCode(context.Background(), "txid123", WithVerifyActive())
+
+	if !errors.Is(err, ErrChargeAlreadyConcluded) {
+		t.Errorf("DeleteQRCode() error = %v, want ErrChargeAlreadyConcluded", err)
+	}
+	if deleted {
+		t.Error("DELETE issued for a concluded charge")
+	}
+}
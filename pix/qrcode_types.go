@@ -1,6 +1,7 @@
 package pix
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -13,18 +14,35 @@ type CreateQRCodeRequest struct {
 	PayerSolicitation     string  `json:"-"`
 	AdditionalInformation string  `json:"-"`
 	Debtor                *Debtor `json:"devedor,omitempty"`
+
+	// LocationID attaches the charge to an existing location (loc.id),
+	// typically created with CreateLocation for pre-printed QR Codes.
+	// When zero, the bank creates a new location.
+	LocationID int `json:"-"`
 }
 
 // MarshalJSON implements custom JSON marshaling for CreateQRCodeRequest
 func (r CreateQRCodeRequest) MarshalJSON() ([]byte, error) {
-	type Alias CreateQRCodeRequest
-	return []byte(fmt.Sprintf(`{
-		"calendario": {"expiracao": %d},
-		"valor": {"original": "%.2f"},
-		"chave": "",
-		"solicitacaoPagador": %q,
-		"infoAdicionais": [{"nome": "info", "valor": %q}]
-	}`, r.Expiration, r.Value, r.PayerSolicitation, r.AdditionalInformation)), nil
+	body := struct {
+		Calendar struct {
+			Expiration int `json:"expiracao"`
+		} `json:"calendario"`
+		Loc                   *locationRef     `json:"loc,omitempty"`
+		Debtor                *Debtor          `json:"devedor,omitempty"`
+		Value                 Value            `json:"valor"`
+		Key                   string           `json:"chave"`
+		PayerSolicitation     string           `json:"solicitacaoPagador"`
+		AdditionalInformation []AdditionalInfo `json:"infoAdicionais"`
+	}{
+		Loc:                   newLocationRef(r.LocationID),
+		Debtor:                r.Debtor,
+		Value:                 Value{Original: fmt.Sprintf("%.2f", r.Value)},
+		PayerSolicitation:     r.PayerSolicitation,
+		AdditionalInformation: []AdditionalInfo{{Name: "info", Value: r.AdditionalInformation}},
+	}
+	body.Calendar.Expiration = r.Expiration
+
+	return json.Marshal(body)
 }
 
 // UpdateQRCodeRequest represents a request to update a QR Code
@@ -35,10 +53,30 @@ type UpdateQRCodeRequest struct {
 
 // MarshalJSON implements custom JSON marshaling for UpdateQRCodeRequest
 func (r UpdateQRCodeRequest) MarshalJSON() ([]byte, error) {
-	return []byte(fmt.Sprintf(`{
-		"calendario": {"expiracao": %d},
-		"valor": {"original": "%.2f"}
-	}`, r.Expiration, r.Value)), nil
+	body := struct {
+		Calendar struct {
+			Expiration int `json:"expiracao"`
+		} `json:"calendario"`
+		Value Value `json:"valor"`
+	}{
+		Value: Value{Original: fmt.Sprintf("%.2f", r.Value)},
+	}
+	body.Calendar.Expiration = r.Expiration
+
+	return json.Marshal(body)
+}
+
+// Charge (cob) statuses
+const (
+	ChargeStatusActive            = "ATIVA"
+	ChargeStatusConcluded         = "CONCLUIDA"
+	ChargeStatusRemovedByReceiver = "REMOVIDA_PELO_USUARIO_RECEBEDOR"
+	ChargeStatusRemovedByPSP      = "REMOVIDA_PELO_PSP"
+)
+
+// removeChargeRequest is the PATCH body that removes a charge
+type removeChargeRequest struct {
+	Status string `json:"status"`
 }
 
 // QRCodeResponse represents a QR Code response from the API
@@ -57,6 +95,25 @@ type QRCodeResponse struct {
 	QRCode                string           `json:"pixCopiaECola,omitempty"`
 }
 
+// IsRemoved reports whether the charge was removed by the receiver or the PSP
+func (r QRCodeResponse) IsRemoved() bool {
+	return r.Status == ChargeStatusRemovedByReceiver || r.Status == ChargeStatusRemovedByPSP
+}
+
+// Reset clears the response so it can be reused by GetQRCodeInto
+func (r *QRCodeResponse) Reset() {
+	*r = QRCodeResponse{}
+}
+
+// CreateOrGetQRCodeResult is the result of CreateOrGetQRCode
+type CreateOrGetQRCodeResult struct {
+	Charge *QRCodeResponse
+
+	// AlreadyExisted is true when the charge was created by an earlier
+	// request with the same txid
+	AlreadyExisted bool
+}
+
 // Calendar represents the calendar information of a QR Code
 type Calendar struct {
 	Creation   time.Time `json:"criacao"`
@@ -65,9 +122,24 @@ type Calendar struct {
 
 // Location represents the location information of a QR Code
 type Location struct {
-	ID       int    `json:"id"`
-	Location string `json:"location"`
-	Type     string `json:"tipoCob"`
+	ID       int       `json:"id"`
+	Location string    `json:"location"`
+	Type     string    `json:"tipoCob"`
+	Creation time.Time `json:"criacao,omitzero"`
+	TxID     string    `json:"txid,omitempty"`
+}
+
+// locationRef references an existing location by its ID
+type locationRef struct {
+	ID int `json:"id"`
+}
+
+// newLocationRef returns a reference to the location, or nil when id is zero
+func newLocationRef(id int) *locationRef {
+	if id <= 0 {
+		return nil
+	}
+	return &locationRef{ID: id}
 }
 
 // Debtor represents debtor information
@@ -89,6 +161,8 @@ type AdditionalInfo struct {
 }
 
 // ListQRCodesParams represents parameters for listing QR Codes
+// paginaAtual and itensPorPagina are always sent: Page is zero-based and a
+// zero PageSize requests 100 items per page.
 type ListQRCodesParams struct {
 	StartDate time.Time `json:"inicio"`
 	EndDate   time.Time `json:"fim"`
@@ -109,6 +183,30 @@ type QRCodeListResponse struct {
 	QRCodes []QRCodeResponse `json:"cobs"`
 }
 
+// Reset clears the response while keeping the capacity of the QRCodes slice
+func (r *QRCodeListResponse) Reset() {
+	qrCodes := r.QRCodes[:cap(r.QRCodes)]
+	clear(qrCodes)
+	*r = QRCodeListResponse{QRCodes: qrCodes[:0]}
+}
+
+// HasNextPage reports whether there is a page after this one
+func (r *QRCodeListResponse) HasNextPage() bool {
+	return r.Parameters.Pagination.HasNextPage()
+}
+
+// NextPageParams returns params, the parameters that listed this page, set
+// to request the next page
+func (r *QRCodeListResponse) NextPageParams(params ListQRCodesParams) ListQRCodesParams {
+	params.Page = r.Parameters.Pagination.CurrentPage + 1
+	return params
+}
+
+// TotalRemaining returns how many QR Codes are listed by the next pages
+func (r *QRCodeListResponse) TotalRemaining() int {
+	return r.Parameters.Pagination.TotalRemaining()
+}
+
 // Pagination represents pagination information
 type Pagination struct {
 	CurrentPage  int `json:"paginaAtual"`
@@ -116,3 +214,17 @@ type Pagination struct {
 	TotalPages   int `json:"quantidadeDePaginas"`
 	TotalItems   int `json:"quantidadeTotalDeItens"`
 }
+
+// HasNextPage reports whether there is a page after the current one
+func (p Pagination) HasNextPage() bool {
+	return p.CurrentPage+1 < p.TotalPages
+}
+
+// TotalRemaining returns how many items are listed by the pages after the
+// current one
+func (p Pagination) TotalRemaining() int {
+	if !p.HasNextPage() {
+		return 0
+	}
+	return max(p.TotalItems-(p.CurrentPage+1)*p.ItemsPerPage, 0)
+}
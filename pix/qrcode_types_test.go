@@ -311,3 +311,85 @@ func TestAdditionalInfo_Marshal(t *testing.T) {
 		t.Error("Value not marshaled correctly")
 	}
 }
+
+func TestCreateQRCodeRequest_MarshalLocation(t *testing.T) {
+	tests := []struct {
+		name       string
+		locationID int
+		wantLoc    bool
+	}{
+		{name: "new location", locationID: 0, wantLoc: false},
+		{name: "existing location", locationID: 789, wantLoc: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(CreateQRCodeRequest{
+				TxID:       "txid123",
+				Value:      10,
+				Expiration: 3600,
+				LocationID: tt.locationID,
+			})
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+
+			var decoded map[string]interface{}
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Failed to unmarshal: %v", err)
+			}
+
+			loc, ok := decoded["loc"].(map[string]interface{})
+			if ok != tt.wantLoc {
+				t.Fatalf("loc present = %v, want %v", ok, tt.wantLoc)
+			}
+			if tt.wantLoc && loc["id"] != float64(tt.locationID) {
+				t.Errorf("loc.id = %v, want %d", loc["id"], tt.locationID)
+			}
+		})
+	}
+}
+
+func TestPagination_Helpers(t *testing.T) {
+	tests := []struct {
+		name          string
+		pagination    Pagination
+		wantNext      bool
+		wantRemaining int
+	}{
+		{"first of three", Pagination{CurrentPage: 0, ItemsPerPage: 100, TotalPages: 3, TotalItems: 250}, true, 150},
+		{"middle page", Pagination{CurrentPage: 1, ItemsPerPage: 100, TotalPages: 3, TotalItems: 250}, true, 50},
+		{"last page", Pagination{CurrentPage: 2, ItemsPerPage: 100, TotalPages: 3, TotalItems: 250}, false, 0},
+		{"single page", Pagination{TotalPages: 1, TotalItems: 2, ItemsPerPage: 100}, false, 0},
+		{"no pagination", Pagination{}, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pagination.HasNextPage(); got != tt.wantNext {
+				t.Errorf("HasNextPage() = %v, want %v", got, tt.wantNext)
+			}
+			if got := tt.pagination.TotalRemaining(); got != tt.wantRemaining {
+				t.Errorf("TotalRemaining() = %d, want %d", got, tt.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestQRCodeListResponse_NextPageParams(t *testing.T) {
+	var resp QRCodeListResponse
+	resp.Parameters.Pagination = Pagination{CurrentPage: 1, ItemsPerPage: 50, TotalPages: 4, TotalItems: 180}
+
+	params := ListQRCodesParams{Status: "ATIVA", Page: 1, PageSize: 50}
+	next := resp.NextPageParams(params)
+
+	if next.Page != 2 || next.Status != "ATIVA" || next.PageSize != 50 {
+		t.Errorf("NextPageParams() = %+v, want page 2 keeping the filters", next)
+	}
+	if !resp.HasNextPage() {
+		t.Error("HasNextPage() = false, want true")
+	}
+	if got := resp.TotalRemaining(); got != 80 {
+		t.Errorf("TotalRemaining() = %d, want 80", got)
+	}
+}
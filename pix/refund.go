@@ -2,11 +2,22 @@ package pix
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sync"
 )
 
+// GenerateRefundID returns a crypto-random refund id that satisfies the
+// BB rules (1 to 35 characters of [a-zA-Z0-9])
+func GenerateRefundID() string {
+	return rand.Text()
+}
+
 // CreateRefund creates a refund for a payment
+// refundID identifies the refund; keep it to retry a failed call without
+// creating a second refund, e.g. from GenerateRefundID.
 func (c *Client) CreateRefund(ctx context.Context, e2eid, refundID string, req CreateRefundRequest) (*RefundResponse, error) {
 	if e2eid == "" {
 		return nil, fmt.Errorf("e2eid is required")
@@ -15,7 +26,10 @@ func (c *Client) CreateRefund(ctx context.Context, e2eid, refundID string, req C
 		return nil, fmt.Errorf("refundID is required")
 	}
 
-	path := fmt.Sprintf("/pix/%s/devolucao/%s", e2eid, refundID)
+	path := fmt.Sprintf("/pix/%s/devolucao/%s", url.PathEscape(e2eid), url.PathEscape(refundID))
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.Write)
+	defer cancel()
 
 	httpReq, err := c.http.NewRequest(ctx, http.MethodPut, path, req)
 	if err != nil {
@@ -39,7 +53,10 @@ func (c *Client) GetRefund(ctx context.Context, e2eid, refundID string) (*Refund
 		return nil, fmt.Errorf("refundID is required")
 	}
 
-	path := fmt.Sprintf("/pix/%s/devolucao/%s", e2eid, refundID)
+	path := fmt.Sprintf("/pix/%s/devolucao/%s", url.PathEscape(e2eid), url.PathEscape(refundID))
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.Read)
+	defer cancel()
 
 	httpReq, err := c.http.NewRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
@@ -53,3 +70,75 @@ func (c *Client) GetRefund(ctx context.Context, e2eid, refundID string) (*Refund
 
 	return &resp, nil
 }
+
+// ListRefunds returns the refunds (devolucoes) of a payment, as reported by
+// GetPayment. The slice is empty when the payment has no refunds.
+func (c *Client) ListRefunds(ctx context.Context, e2eid string) ([]RefundInfo, error) {
+	payment, err := c.GetPayment(ctx, e2eid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refunds: %w", err)
+	}
+
+	if payment.Refunds == nil {
+		return []RefundInfo{}, nil
+	}
+	return payment.Refunds, nil
+}
+
+// RefundRequestItem is a refund issued by RefundMany
+type RefundRequestItem struct {
+	EndToEndID string
+	// RefundID is generated with GenerateRefundID when empty
+	RefundID string
+	Request  CreateRefundRequest
+}
+
+// RefundResult is the outcome of a RefundRequestItem
+type RefundResult struct {
+	// Item is the refund as sent, with the RefundID used, so a failed
+	// refund can be retried or queried with the same id
+	Item    RefundRequestItem
+	Success bool
+	Refund  *RefundResponse
+	Err     error
+}
+
+// RefundMany issues the refunds of items with up to concurrency requests in
+// flight and returns one result per item, in the same order. A failed
+// refund does not stop the others; items not started when ctx is done fail
+// with the context error.
+func (c *Client) RefundMany(ctx context.Context, items []RefundRequestItem, concurrency int) []RefundResult {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	results := make([]RefundResult, len(items))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, item := range items {
+		if item.RefundID == "" {
+			item.RefundID = GenerateRefundID()
+		}
+		results[i].Item = item
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			refund, err := c.CreateRefund(ctx, item.EndToEndID, item.RefundID, item.Request)
+			results[i].Refund, results[i].Err, results[i].Success = refund, err, err == nil
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -213,3 +216,203 @@ func TestClient_GetRefund_InvalidParams(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_CreateRefund_AuditHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"refund123","rtrId":"D1","valor":"50.25","status":"EM_PROCESSAMENTO","horario":{"solicitacao":"2024-01-15T11:00:00Z"}}`))
+	}))
+	defer server.Close()
+
+	var records []AuditRecord
+	client := NewClient(&http.Client{}, server.URL, WithAuditHook(func(ctx context.Context, rec AuditRecord) {
+		records = append(records, rec)
+	}))
+
+	resp, err := client.CreateRefund(context.Background(), "E12345678202401151000000000001", "refund123", CreateRefundRequest{
+		Value:  50.25,
+		Reason: "Pedido cancelado",
+	})
+	if err != nil {
+		t.Fatalf("CreateRefund() error = %v", err)
+	}
+
+	if _, err := client.GetRefund(context.Background(), "E12345678202401151000000000001", "refund123"); err != nil {
+		t.Fatalf("GetRefund() error = %v", err)
+	}
+
+	if len(records) != 1 {
+		t.Fatalf("len(records) = %d, want 1 (reads are not audited)", len(records))
+	}
+	if want := `{"valor":"50.25","motivo":"Pedido cancelado"}`; string(records[0].RequestBody) != want {
+		t.Errorf("RequestBody = %s, want %s", records[0].RequestBody, want)
+	}
+	if got, ok := records[0].Response.(*RefundResponse); !ok || got.ID != resp.ID {
+		t.Errorf("Response = %#v, want decoded *RefundResponse", records[0].Response)
+	}
+}
+
+func TestClient_ListRefunds(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantCount int
+	}{
+		{
+			name: "with refunds",
+			body: `{"endToEndId":"E1","valor":"100.00","devolucoes":[
+				{"id":"d1","rtrId":"D1","valor":"10.00","status":"DEVOLVIDO"},
+				{"id":"d2","rtrId":"D2","valor":"20.00","status":"EM_PROCESSAMENTO"}]}`,
+			wantCount: 2,
+		},
+		{
+			name:      "without refunds",
+			body:      `{"endToEndId":"E1","valor":"100.00"}`,
+			wantCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/pix/E1" {
+					t.Errorf("Path = %s, want /pix/E1", r.URL.Path)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(&http.Client{}, server.URL)
+
+			refunds, err := client.ListRefunds(context.Background(), "E1")
+			if err != nil {
+				t.Fatalf("ListRefunds() error = %v", err)
+			}
+			if refunds == nil || len(refunds) != tt.wantCount {
+				t.Fatalf("len(refunds) = %d, want %d (non-nil)", len(refunds), tt.wantCount)
+			}
+			if tt.wantCount == 2 && (!refunds[0].Status.IsTerminal() || refunds[1].Status.IsTerminal()) {
+				t.Errorf("terminal statuses = %v/%v, want true/false", refunds[0].Status.IsTerminal(), refunds[1].Status.IsTerminal())
+			}
+		})
+	}
+
+	client := NewClient(&http.Client{}, "http://example.com")
+	if _, err := client.ListRefunds(context.Background(), ""); err == nil {
+		t.Error("ListRefunds(\"\") expected error")
+	}
+}
+
+func TestGenerateRefundID(t *testing.T) {
+	valid := regexp.MustCompile(`^[a-zA-Z0-9]{1,35}$`)
+	seen := make(map[string]bool)
+
+	for i := 0; i < 100; i++ {
+		id := GenerateRefundID()
+		if !valid.MatchString(id) {
+			t.Fatalf("GenerateRefundID() = %q, want 1-35 chars of [a-zA-Z0-9]", id)
+		}
+		if seen[id] {
+			t.Fatalf("GenerateRefundID() repeated %q", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestClient_CreateRefund_GeneratedID(t *testing.T) {
+	var gotID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID = strings.TrimPrefix(r.URL.Path, "/pix/E1/devolucao/")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": gotID, "valor": "10.00", "status": "EM_PROCESSAMENTO"})
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	refundID := GenerateRefundID()
+	resp, err := client.CreateRefund(context.Background(), "E1", refundID, CreateRefundRequest{Value: 10})
+	if err != nil {
+		t.Fatalf("CreateRefund() error = %v", err)
+	}
+	if gotID != refundID || resp.ID != refundID {
+		t.Errorf("sent id = %q, resp.ID = %q, want %q", gotID, resp.ID, refundID)
+	}
+}
+
+func TestClient_RefundMany(t *testing.T) {
+	var (
+		mu             sync.Mutex
+		inFlight, peak int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		e2eid, id, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/pix/"), "/devolucao/")
+		if e2eid == "E2" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"title":"Devolução inválida","detail":"valor excede o disponível"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": id, "valor": "1.00", "status": "EM_PROCESSAMENTO"})
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	items := []RefundRequestItem{
+		{EndToEndID: "E1", RefundID: "D1", Request: CreateRefundRequest{Value: 1}},
+		{EndToEndID: "E2", RefundID: "D2", Request: CreateRefundRequest{Value: 1}},
+		{EndToEndID: "E3", Request: CreateRefundRequest{Value: 1}},
+		{EndToEndID: "", RefundID: "D4", Request: CreateRefundRequest{Value: 1}},
+		{EndToEndID: "E5", RefundID: "D5", Request: CreateRefundRequest{Value: 1}},
+	}
+
+	results := client.RefundMany(context.Background(), items, 2)
+
+	if len(results) != len(items) {
+		t.Fatalf("len(results) = %d, want %d", len(results), len(items))
+	}
+	wantSuccess := []bool{true, false, true, false, true}
+	for i, result := range results {
+		if result.Item.EndToEndID != items[i].EndToEndID {
+			t.Errorf("results[%d] is for %s, want %s", i, result.Item.EndToEndID, items[i].EndToEndID)
+		}
+		if result.Success != wantSuccess[i] || (result.Err == nil) != wantSuccess[i] {
+			t.Errorf("results[%d] = %+v, want success %v", i, result, wantSuccess[i])
+		}
+		if result.Success && result.Refund.ID != result.Item.RefundID {
+			t.Errorf("results[%d].Refund.ID = %s, want %s", i, result.Refund.ID, result.Item.RefundID)
+		}
+	}
+	if results[2].Item.RefundID == "" {
+		t.Error("RefundID of results[2] was not generated")
+	}
+	if peak > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", peak)
+	}
+}
+
+func TestClient_RefundMany_CancelledContext(t *testing.T) {
+	client := NewClient(&http.Client{}, "http://127.0.0.1:0")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := client.RefundMany(ctx, []RefundRequestItem{{EndToEndID: "E1"}, {EndToEndID: "E2"}}, 1)
+	for i, result := range results {
+		if result.Success || result.Err == nil {
+			t.Errorf("results[%d] = %+v, want a context error", i, result)
+		}
+	}
+}
@@ -1,27 +1,87 @@
 package pix
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RefundNature identifies why a refund is being made
+type RefundNature string
+
+// Refund natures
+const (
+	// RefundNatureOriginal is a regular refund of the received amount
+	RefundNatureOriginal RefundNature = "ORIGINAL"
+	// RefundNatureWithdrawal refunds the cash part (saque or troco) of a
+	// Pix Saque or Pix Troco payment
+	RefundNatureWithdrawal RefundNature = "RETIRADA"
+	// RefundNatureMEDOperational is a MED (Mecanismo Especial de Devolução)
+	// return caused by an operational failure of the PSP
+	RefundNatureMEDOperational RefundNature = "MED_OPERACIONAL"
+	// RefundNatureMEDFraud is a MED return caused by a well-founded
+	// suspicion of fraud
+	RefundNatureMEDFraud RefundNature = "MED_FRAUDE"
+)
+
+// IsMED reports whether the nature is one of the MED return natures
+func (n RefundNature) IsMED() bool {
+	return n == RefundNatureMEDOperational || n == RefundNatureMEDFraud
+}
+
+// RefundStatus is the status of a refund (devolução)
+type RefundStatus string
+
+// Refund statuses
+const (
+	RefundStatusProcessing   RefundStatus = "EM_PROCESSAMENTO"
+	RefundStatusReturned     RefundStatus = "DEVOLVIDO"
+	RefundStatusNotPerformed RefundStatus = "NAO_REALIZADO"
+)
+
+// IsTerminal reports whether the refund can no longer change status
+func (s RefundStatus) IsTerminal() bool {
+	return s == RefundStatusReturned || s == RefundStatusNotPerformed
+}
 
 // CreateRefundRequest represents a request to create a refund
 type CreateRefundRequest struct {
-	Value  float64 `json:"-"`
-	Reason string  `json:"motivo,omitempty"`
+	Value  float64      `json:"-"`
+	Nature RefundNature `json:"natureza,omitempty"`
+	Reason string       `json:"motivo,omitempty"`
+
+	// Description is shown to the payer (descricao, up to 140 characters)
+	Description string `json:"descricao,omitempty"`
 }
 
 // MarshalJSON implements custom JSON marshaling for CreateRefundRequest
 func (r CreateRefundRequest) MarshalJSON() ([]byte, error) {
-	if r.Reason != "" {
-		return []byte(fmt.Sprintf(`{"valor":"%.2f","motivo":%q}`, r.Value, r.Reason)), nil
-	}
-	return []byte(fmt.Sprintf(`{"valor":"%.2f"}`, r.Value)), nil
+	return json.Marshal(struct {
+		Value       string       `json:"valor"`
+		Nature      RefundNature `json:"natureza,omitempty"`
+		Reason      string       `json:"motivo,omitempty"`
+		Description string       `json:"descricao,omitempty"`
+	}{
+		Value:       fmt.Sprintf("%.2f", r.Value),
+		Nature:      r.Nature,
+		Reason:      r.Reason,
+		Description: r.Description,
+	})
 }
 
 // RefundResponse represents a refund response
 type RefundResponse struct {
-	ID     string     `json:"id"`
-	RtrID  string     `json:"rtrId"`
-	Value  string     `json:"valor"`
-	Time   RefundTime `json:"horario"`
-	Status string     `json:"status"`
-	Reason string     `json:"motivo,omitempty"`
+	ID     string       `json:"id"`
+	RtrID  string       `json:"rtrId"`
+	Value  string       `json:"valor"`
+	Time   RefundTime   `json:"horario"`
+	Status RefundStatus `json:"status"`
+	Nature RefundNature `json:"natureza,omitempty"`
+	Reason string       `json:"motivo,omitempty"`
+
+	Description string `json:"descricao,omitempty"`
+}
+
+// IsMED reports whether the refund is a MED return
+func (r RefundResponse) IsMED() bool {
+	return r.Nature.IsMED()
 }
@@ -129,3 +129,88 @@ func TestCreateRefundRequest_ZeroValue(t *testing.T) {
 		t.Errorf("valor = %v, want 0.00", decoded["valor"])
 	}
 }
+
+func TestCreateRefundRequest_MarshalNature(t *testing.T) {
+	tests := []struct {
+		name string
+		req  CreateRefundRequest
+		want string
+	}{
+		{
+			name: "without nature",
+			req:  CreateRefundRequest{Value: 10},
+			want: `{"valor":"10.00"}`,
+		},
+		{
+			name: "MED fraud",
+			req:  CreateRefundRequest{Value: 10, Nature: RefundNatureMEDFraud, Reason: "Golpe"},
+			want: `{"valor":"10.00","natureza":"MED_FRAUDE","motivo":"Golpe"}`,
+		},
+		{
+			name: "withdrawal with description",
+			req:  CreateRefundRequest{Value: 50, Nature: RefundNatureWithdrawal, Description: "Saque não entregue"},
+			want: `{"valor":"50.00","natureza":"RETIRADA","descricao":"Saque não entregue"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.req)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefundNature_IsMED(t *testing.T) {
+	tests := []struct {
+		nature RefundNature
+		want   bool
+	}{
+		{RefundNatureOriginal, false},
+		{RefundNatureWithdrawal, false},
+		{RefundNatureMEDOperational, true},
+		{RefundNatureMEDFraud, true},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.nature.IsMED(); got != tt.want {
+			t.Errorf("RefundNature(%q).IsMED() = %v, want %v", tt.nature, got, tt.want)
+		}
+	}
+}
+
+func TestRefundStatus_IsTerminal(t *testing.T) {
+	tests := []struct {
+		status RefundStatus
+		want   bool
+	}{
+		{RefundStatusProcessing, false},
+		{RefundStatusReturned, true},
+		{RefundStatusNotPerformed, true},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.status.IsTerminal(); got != tt.want {
+			t.Errorf("RefundStatus(%q).IsTerminal() = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestRefundResponse_UnmarshalDescription(t *testing.T) {
+	data := `{"id":"d1","rtrId":"D1","valor":"50.00","status":"DEVOLVIDO","natureza":"RETIRADA","descricao":"Saque não entregue"}`
+
+	var resp RefundResponse
+	if err := json.Unmarshal([]byte(data), &resp); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if resp.Nature != RefundNatureWithdrawal || resp.Description != "Saque não entregue" {
+		t.Errorf("resp = %+v, want RETIRADA with descricao", resp)
+	}
+}
@@ -287,7 +287,7 @@ func TestPaymentListResponseSchema(t *testing.T) {
 
 		// Validate refund statuses
 		for j, refund := range pmt.Refunds {
-			validRefundStatuses := map[string]bool{
+			validRefundStatuses := map[RefundStatus]bool{
 				"DEVOLVIDO":        true,
 				"EM_PROCESSAMENTO": true,
 				"NAO_REALIZADO":    true,
@@ -0,0 +1,90 @@
+package pix
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultWatchInterval is the polling interval used by WatchQRCode when the
+// given interval is not positive
+const DefaultWatchInterval = 5 * time.Second
+
+// QRCodeEvent is delivered by WatchQRCode when the charge status changes or
+// a poll fails
+type QRCodeEvent struct {
+	// QRCode is the charge as returned by the latest poll; nil when Err is set
+	QRCode *QRCodeResponse
+	// Err is the error of a failed poll; polling continues after it
+	Err error
+}
+
+// IsTerminal reports whether the charge can no longer change status, i.e.
+// it was paid (CONCLUIDA) or removed
+func (r QRCodeResponse) IsTerminal() bool {
+	return r.Status == ChargeStatusConcluded || r.IsRemoved()
+}
+
+// WatchQRCode polls GetQRCode every interval and delivers an event with the
+// first status seen and with every status change, for integrations without
+// a public webhook endpoint. Failed polls are delivered with Err set and
+// polling continues.
+// The channel is closed after the charge reaches a terminal status
+// (CONCLUIDA or REMOVIDA_*) or when ctx is done.
+func (c *Client) WatchQRCode(ctx context.Context, txID string, interval time.Duration) (<-chan QRCodeEvent, error) {
+	if txID == "" {
+		return nil, fmt.Errorf("txid is required")
+	}
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	events := make(chan QRCodeEvent, 1)
+
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var lastStatus string
+		for {
+			qrCode, err := c.GetQRCode(ctx, txID)
+			switch {
+			case err != nil && ctx.Err() != nil:
+				return
+			case err != nil:
+				if !sendEvent(ctx, events, QRCodeEvent{Err: err}) {
+					return
+				}
+			case qrCode.Status != lastStatus:
+				lastStatus = qrCode.Status
+				if !sendEvent(ctx, events, QRCodeEvent{QRCode: qrCode}) {
+					return
+				}
+			}
+
+			if qrCode != nil && qrCode.IsTerminal() {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// sendEvent delivers event unless ctx is done first
+func sendEvent(ctx context.Context, events chan<- QRCodeEvent, event QRCodeEvent) bool {
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,114 @@
+package pix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_WatchQRCode(t *testing.T) {
+	// ATIVA twice, a failed poll, then CONCLUIDA
+	responses := []struct {
+		status int
+		body   string
+	}{
+		{http.StatusOK, `{"txid":"tx1","status":"ATIVA"}`},
+		{http.StatusOK, `{"txid":"tx1","status":"ATIVA"}`},
+		{http.StatusServiceUnavailable, `{"detail":"indisponível"}`},
+		{http.StatusOK, `{"txid":"tx1","status":"CONCLUIDA"}`},
+	}
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1)) - 1
+		if n >= len(responses) {
+			t.Errorf("unexpected poll %d after terminal status", n+1)
+			n = len(responses) - 1
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(responses[n].status)
+		w.Write([]byte(responses[n].body))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	events, err := client.WatchQRCode(context.Background(), "tx1", time.Millisecond)
+	if err != nil {
+		t.Fatalf("WatchQRCode() error = %v", err)
+	}
+
+	var got []string
+	for event := range events {
+		if event.Err != nil {
+			got = append(got, "error")
+			continue
+		}
+		got = append(got, event.QRCode.Status)
+	}
+
+	want := []string{"ATIVA", "error", "CONCLUIDA"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+}
+
+func TestClient_WatchQRCode_ContextDone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"txid":"tx1","status":"ATIVA"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := client.WatchQRCode(ctx, "tx1", time.Millisecond)
+	if err != nil {
+		t.Fatalf("WatchQRCode() error = %v", err)
+	}
+
+	if event := <-events; event.QRCode == nil || event.QRCode.Status != ChargeStatusActive {
+		t.Fatalf("first event = %+v, want ATIVA", event)
+	}
+	cancel()
+
+	// The channel is closed once the watcher notices the cancellation
+	closed := make(chan struct{})
+	go func() {
+		for range events {
+		}
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+
+	if _, err := client.WatchQRCode(context.Background(), "", 0); err == nil {
+		t.Error("WatchQRCode(\"\") expected error")
+	}
+}
+
+func TestQRCodeResponse_IsTerminal(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{ChargeStatusActive, false},
+		{ChargeStatusConcluded, true},
+		{ChargeStatusRemovedByReceiver, true},
+		{ChargeStatusRemovedByPSP, true},
+	}
+
+	for _, tt := range tests {
+		if got := (QRCodeResponse{Status: tt.status}).IsTerminal(); got != tt.want {
+			t.Errorf("IsTerminal(%s) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
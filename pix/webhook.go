@@ -0,0 +1,128 @@
+package pix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ConfigureWebhook configures the webhook URL that receives notifications
+// for payments to the given PIX key
+func (c *Client) ConfigureWebhook(ctx context.Context, key string, config WebhookConfig) error {
+	if key == "" {
+		return fmt.Errorf("key is required")
+	}
+	if config.WebhookURL == "" {
+		return fmt.Errorf("webhook url is required")
+	}
+
+	path := fmt.Sprintf("/webhook/%s", url.PathEscape(key))
+
+	body := WebhookConfig{WebhookURL: config.WebhookURL}
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.Write)
+	defer cancel()
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodPut, path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := c.http.Do(httpReq, nil); err != nil {
+		return fmt.Errorf("failed to configure webhook: %w", err)
+	}
+
+	return nil
+}
+
+// GetWebhook retrieves the webhook configuration of a PIX key
+func (c *Client) GetWebhook(ctx context.Context, key string) (*WebhookConfig, error) {
+	if key == "" {
+		return nil, fmt.Errorf("key is required")
+	}
+
+	path := fmt.Sprintf("/webhook/%s", url.PathEscape(key))
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.Read)
+	defer cancel()
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp WebhookConfig
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// DeleteWebhook removes the webhook configuration of a PIX key
+func (c *Client) DeleteWebhook(ctx context.Context, key string) error {
+	if key == "" {
+		return fmt.Errorf("key is required")
+	}
+
+	path := fmt.Sprintf("/webhook/%s", url.PathEscape(key))
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.Write)
+	defer cancel()
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := c.http.Do(httpReq, nil); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	return nil
+}
+
+// ListWebhooks lists the webhook configurations of all PIX keys
+func (c *Client) ListWebhooks(ctx context.Context, params ListWebhooksParams) (*WebhookListResponse, error) {
+	path := "/webhook"
+
+	ctx, cancel := c.withTimeout(ctx, c.timeouts.List)
+	defer cancel()
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Add query parameters
+	q := httpReq.URL.Query()
+	if !params.StartDate.IsZero() {
+		q.Set("inicio", params.StartDate.Format("2006-01-02T15:04:05Z07:00"))
+	}
+	if !params.EndDate.IsZero() {
+		q.Set("fim", params.EndDate.Format("2006-01-02T15:04:05Z07:00"))
+	}
+	c.http.SetPagination(q, params.Page, params.PageSize)
+
+	httpReq.URL.RawQuery = q.Encode()
+
+	var resp WebhookListResponse
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// ParseWebhookPayload decodes a webhook callback body
+func ParseWebhookPayload(r io.Reader) (*WebhookPayload, error) {
+	var payload WebhookPayload
+	if err := json.NewDecoder(r).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook payload: %w", err)
+	}
+
+	return &payload, nil
+}
@@ -0,0 +1,68 @@
+package pix
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/pericles-luz/go-bb-pix/internal/callback"
+)
+
+// DefaultWebhookMaxBodySize is the largest callback body accepted by
+// NewWebhookHandler unless overridden with WithWebhookMaxBodySize
+const DefaultWebhookMaxBodySize = callback.DefaultMaxBodySize
+
+// WebhookFunc processes the payments notified in a webhook callback.
+// Returning an error makes the handler answer with 500 so BB retries the
+// notification.
+type WebhookFunc func(ctx context.Context, payments []PaymentResponse) error
+
+// WebhookHandlerOption is a functional option for NewWebhookHandler
+type WebhookHandlerOption func(*callback.Options)
+
+// WithWebhookMaxBodySize sets the largest accepted callback body in bytes
+// Default: 1 MiB
+func WithWebhookMaxBodySize(n int64) WebhookHandlerOption {
+	return func(opts *callback.Options) {
+		if n > 0 {
+			opts.MaxBodySize = n
+		}
+	}
+}
+
+// WithWebhookLogger sets the logger used to report rejected callbacks and
+// processing errors
+func WithWebhookLogger(logger *slog.Logger) WebhookHandlerOption {
+	return func(opts *callback.Options) {
+		opts.Logger = logger
+	}
+}
+
+// NewWebhookHandler returns an http.Handler that receives PIX webhook
+// callbacks, validates and decodes the payload and passes the payments to fn.
+//
+// Responses:
+//   - 200 when fn succeeds (or the payload has no payments)
+//   - 400 for malformed payloads or payments without endToEndId
+//   - 405 for methods other than POST
+//   - 413 when the body exceeds the maximum size
+//   - 500 when fn returns an error
+func NewWebhookHandler(fn WebhookFunc, opts ...WebhookHandlerOption) http.Handler {
+	options := callback.DefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return callback.NewHandler(options, webhookPayments, fn)
+}
+
+// webhookPayments validates and returns the payments of a payload
+func webhookPayments(payload WebhookPayload) ([]PaymentResponse, error) {
+	for _, payment := range payload.Pix {
+		if payment.EndToEndID == "" {
+			return nil, errors.New("payment without endToEndId")
+		}
+	}
+	return payload.Pix, nil
+}
@@ -0,0 +1,101 @@
+package pix
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewWebhookHandler(t *testing.T) {
+	payloadData, err := os.ReadFile(filepath.Join("..", "testdata", "webhook", "callback_payload.json"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		maxBody    int64
+		fnErr      error
+		wantStatus int
+		wantCalled bool
+	}{
+		{
+			name:       "valid payload",
+			method:     http.MethodPost,
+			body:       string(payloadData),
+			wantStatus: http.StatusOK,
+			wantCalled: true,
+		},
+		{
+			name:       "empty payment list",
+			method:     http.MethodPost,
+			body:       `{"pix":[]}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "malformed JSON",
+			method:     http.MethodPost,
+			body:       `{"pix":`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "payment without endToEndId",
+			method:     http.MethodPost,
+			body:       `{"pix":[{"valor":"10.00"}]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "body too large",
+			method:     http.MethodPost,
+			body:       `{"pix":[{"endToEndId":"E1","infoPagador":"` + strings.Repeat("x", 256) + `"}]}`,
+			maxBody:    200,
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "callback error",
+			method:     http.MethodPost,
+			body:       string(payloadData),
+			fnErr:      errors.New("database unavailable"),
+			wantStatus: http.StatusInternalServerError,
+			wantCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			fn := func(ctx context.Context, payments []PaymentResponse) error {
+				called = true
+				if len(payments) == 0 {
+					t.Error("callback received no payments")
+				}
+				return tt.fnErr
+			}
+
+			handler := NewWebhookHandler(fn, WithWebhookMaxBodySize(tt.maxBody))
+
+			req := httptest.NewRequest(tt.method, "/webhook/pix", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("callback called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
@@ -1,26 +1,18 @@
 package pix
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
 
-// WebhookConfig represents webhook configuration
-type WebhookConfig struct {
-	WebhookURL string    `json:"webhookUrl"`
-	Creation   time.Time `json:"criacao,omitempty"`
-}
-
-// WebhookPayload represents a webhook callback payload
-type WebhookPayload struct {
-	Pix []PaymentResponse `json:"pix"`
-}
-
 // TestWebhookConfiguration tests webhook setup operations
 func TestWebhookConfiguration(t *testing.T) {
 	configData, err := os.ReadFile(filepath.Join("..", "testdata", "webhook", "config_response.json"))
@@ -106,30 +98,148 @@ func TestWebhookConfiguration(t *testing.T) {
 			}))
 			defer server.Close()
 
-			// Since we're testing the webhook endpoints conceptually,
-			// we'll verify the server behavior directly
-			// In a real client implementation, this would use the client methods
-
-			if tt.statusCode != http.StatusOK && tt.statusCode != http.StatusNoContent && tt.wantErr {
-				// For error cases, just verify the setup
-				return
-			}
-
-			// For success cases, verify response can be decoded
-			if tt.statusCode == http.StatusOK {
-				var config WebhookConfig
-				if err := json.Unmarshal(configData, &config); err != nil {
-					t.Errorf("Failed to unmarshal config data: %v", err)
-				}
-
-				if config.WebhookURL == "" {
+			client := NewClient(&http.Client{}, server.URL)
+			ctx := context.Background()
+
+			var err error
+			switch tt.method {
+			case http.MethodPut:
+				err = client.ConfigureWebhook(ctx, tt.key, *tt.config)
+			case http.MethodGet:
+				var config *WebhookConfig
+				config, err = client.GetWebhook(ctx, tt.key)
+				if err == nil && config.WebhookURL == "" {
 					t.Error("WebhookURL should not be empty in response")
 				}
+			case http.MethodDelete:
+				err = client.DeleteWebhook(ctx, tt.key)
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
+func TestClient_GetWebhook_DecodesCreation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"webhookUrl":"https://pix.example.com/api/webhook/","chave":"pix.example.com","criacao":"2024-01-15T10:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	config, err := client.GetWebhook(context.Background(), "pix.example.com")
+	if err != nil {
+		t.Fatalf("GetWebhook() error = %v", err)
+	}
+	if config.Key != "pix.example.com" {
+		t.Errorf("Key = %s, want pix.example.com", config.Key)
+	}
+	if !config.Creation.Equal(time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Creation = %v, want 2024-01-15T10:00:00Z", config.Creation)
+	}
+}
+
+func TestClient_ConfigureWebhook_SendsOnlyURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if len(body) != 1 || body["webhookUrl"] != "https://pix.example.com/api/webhook/" {
+			t.Errorf("body = %v, want only webhookUrl", body)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	err := client.ConfigureWebhook(context.Background(), "pix.example.com", WebhookConfig{
+		WebhookURL: "https://pix.example.com/api/webhook/",
+		Creation:   time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("ConfigureWebhook() error = %v", err)
+	}
+}
+
+func TestClient_Webhook_Validation(t *testing.T) {
+	client := NewClient(&http.Client{}, "http://localhost")
+	ctx := context.Background()
+
+	if err := client.ConfigureWebhook(ctx, "", WebhookConfig{WebhookURL: "https://example.com"}); err == nil {
+		t.Error("ConfigureWebhook() expected error for empty key")
+	}
+	if err := client.ConfigureWebhook(ctx, "chave", WebhookConfig{}); err == nil {
+		t.Error("ConfigureWebhook() expected error for empty webhook url")
+	}
+	if _, err := client.GetWebhook(ctx, ""); err == nil {
+		t.Error("GetWebhook() expected error for empty key")
+	}
+	if err := client.DeleteWebhook(ctx, ""); err == nil {
+		t.Error("DeleteWebhook() expected error for empty key")
+	}
+}
+
+func TestClient_ListWebhooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Method = %s, want GET", r.Method)
+		}
+		if r.URL.Path != "/webhook" {
+			t.Errorf("Path = %s, want /webhook", r.URL.Path)
+		}
+
+		query := r.URL.Query()
+		if query.Get("inicio") != "2024-01-01T00:00:00Z" {
+			t.Errorf("inicio = %s, want 2024-01-01T00:00:00Z", query.Get("inicio"))
+		}
+		if query.Has("fim") {
+			t.Error("fim should not be sent when EndDate is zero")
+		}
+		if query.Get("paginacao.itensPorPagina") != "10" {
+			t.Errorf("itensPorPagina = %s, want 10", query.Get("paginacao.itensPorPagina"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"parametros": {
+				"inicio": "2024-01-01T00:00:00Z",
+				"fim": "2024-01-31T23:59:59Z",
+				"paginacao": {"paginaAtual": 0, "itensPorPagina": 10, "quantidadeDePaginas": 1, "quantidadeTotalDeItens": 2}
+			},
+			"webhooks": [
+				{"webhookUrl": "https://pix.example.com/api/webhook/", "chave": "chave-1", "criacao": "2024-01-15T10:00:00Z"},
+				{"webhookUrl": "https://pix.example.com/api/webhook/", "chave": "chave-2", "criacao": "2024-01-16T10:00:00Z"}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	resp, err := client.ListWebhooks(context.Background(), ListWebhooksParams{
+		StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		PageSize:  10,
+	})
+	if err != nil {
+		t.Fatalf("ListWebhooks() error = %v", err)
+	}
+	if len(resp.Webhooks) != 2 {
+		t.Fatalf("len(Webhooks) = %d, want 2", len(resp.Webhooks))
+	}
+	if resp.Webhooks[1].Key != "chave-2" {
+		t.Errorf("Key = %s, want chave-2", resp.Webhooks[1].Key)
+	}
+	if resp.Parameters.Pagination.TotalItems != 2 {
+		t.Errorf("TotalItems = %d, want 2", resp.Parameters.Pagination.TotalItems)
+	}
+}
+
 // TestWebhookPayloadParsing tests parsing of webhook callback payloads
 func TestWebhookPayloadParsing(t *testing.T) {
 	payloadData, err := os.ReadFile(filepath.Join("..", "testdata", "webhook", "callback_payload.json"))
@@ -165,6 +275,100 @@ func TestWebhookPayloadParsing(t *testing.T) {
 	}
 }
 
+func TestParseWebhookPayload(t *testing.T) {
+	payloadData, err := os.ReadFile(filepath.Join("..", "testdata", "webhook", "callback_payload.json"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	payload, err := ParseWebhookPayload(bytes.NewReader(payloadData))
+	if err != nil {
+		t.Fatalf("ParseWebhookPayload() error = %v", err)
+	}
+	if len(payload.Pix) != 1 {
+		t.Fatalf("len(Pix) = %d, want 1", len(payload.Pix))
+	}
+	if payload.Pix[0].EndToEndID != "E12345678202406201221abcdef12345" {
+		t.Errorf("EndToEndID = %s, want E12345678202406201221abcdef12345", payload.Pix[0].EndToEndID)
+	}
+
+	invalid := []string{"", "not json", `{"pix": "not a list"}`}
+	for _, body := range invalid {
+		if _, err := ParseWebhookPayload(strings.NewReader(body)); err == nil {
+			t.Errorf("ParseWebhookPayload(%q) expected error", body)
+		}
+	}
+}
+
+func TestWebhookPayload_MEDReturns(t *testing.T) {
+	body := `{"pix":[
+		{"endToEndId":"E1","valor":"100.00","horario":"2024-01-15T10:00:00Z","devolucoes":[
+			{"id":"d1","rtrId":"D1","valor":"10.00","horario":{"solicitacao":"2024-01-16T10:00:00Z"},"status":"DEVOLVIDO","natureza":"ORIGINAL"},
+			{"id":"d2","rtrId":"D2","valor":"90.00","horario":{"solicitacao":"2024-01-17T10:00:00Z"},"status":"EM_PROCESSAMENTO","natureza":"MED_FRAUDE"}
+		]},
+		{"endToEndId":"E2","valor":"50.00","horario":"2024-01-15T11:00:00Z"}
+	]}`
+
+	payload, err := ParseWebhookPayload(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("ParseWebhookPayload() error = %v", err)
+	}
+
+	returns := payload.MEDReturns()
+	if len(returns) != 1 {
+		t.Fatalf("len(MEDReturns()) = %d, want 1", len(returns))
+	}
+	if returns[0].Payment.EndToEndID != "E1" || returns[0].Refund.ID != "d2" {
+		t.Errorf("MEDReturns()[0] = %s/%s, want E1/d2", returns[0].Payment.EndToEndID, returns[0].Refund.ID)
+	}
+	if returns[0].Refund.Status != RefundStatusProcessing {
+		t.Errorf("Status = %s, want %s", returns[0].Refund.Status, RefundStatusProcessing)
+	}
+}
+
+func TestParseWebhookPayload_KeepsUnknownFields(t *testing.T) {
+	body := `{
+		"pix": [{
+			"endToEndId": "E12345678202406201221abcdef12345",
+			"txid": "txid123",
+			"valor": "110.00",
+			"horario": "2024-06-20T12:21:00Z",
+			"campoFuturo": {"versao": 3},
+			"gnAttrs": {"tipo": "cob"}
+		}],
+		"versao": "2.1"
+	}`
+
+	payload, err := ParseWebhookPayload(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("ParseWebhookPayload() error = %v", err)
+	}
+
+	if string(payload.RawExtras["versao"]) != `"2.1"` {
+		t.Errorf("RawExtras[versao] = %s, want \"2.1\"", payload.RawExtras["versao"])
+	}
+
+	payment := payload.Pix[0]
+	if payment.Value != "110.00" || payment.TxID != "txid123" {
+		t.Errorf("known fields not decoded: %+v", payment)
+	}
+	if len(payment.RawExtras) != 2 {
+		t.Fatalf("len(RawExtras) = %d, want 2: %v", len(payment.RawExtras), payment.RawExtras)
+	}
+	if string(payment.RawExtras["gnAttrs"]) != `{"tipo": "cob"}` {
+		t.Errorf("RawExtras[gnAttrs] = %s", payment.RawExtras["gnAttrs"])
+	}
+	if _, ok := payment.RawExtras["valor"]; ok {
+		t.Error("known field valor should not be in RawExtras")
+	}
+
+	// Payloads without unknown fields do not allocate RawExtras
+	plain, _ := ParseWebhookPayload(strings.NewReader(`{"pix":[{"endToEndId":"E1","valor":"1.00"}]}`))
+	if plain.RawExtras != nil || plain.Pix[0].RawExtras != nil {
+		t.Error("RawExtras should be nil when there are no unknown fields")
+	}
+}
+
 // TestWebhookCallbackHandler tests a webhook callback handler
 func TestWebhookCallbackHandler(t *testing.T) {
 	payloadData, err := os.ReadFile(filepath.Join("..", "testdata", "webhook", "callback_payload.json"))
@@ -0,0 +1,96 @@
+package pix
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookConfig represents the webhook configuration of a PIX key
+type WebhookConfig struct {
+	WebhookURL string    `json:"webhookUrl"`
+	Key        string    `json:"chave,omitempty"`
+	Creation   time.Time `json:"criacao,omitzero"`
+}
+
+// WebhookPayload represents the payload BB posts to the configured webhook
+// URL when payments are received
+type WebhookPayload struct {
+	Pix []PaymentResponse `json:"pix"`
+
+	// RawExtras holds the top-level fields not modeled by this struct
+	RawExtras map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields in
+// RawExtras, its own and those of each payment
+func (p *WebhookPayload) UnmarshalJSON(data []byte) error {
+	type alias WebhookPayload
+	var decoded alias
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	extras, err := unknownFields(data, decoded)
+	if err != nil {
+		return err
+	}
+
+	var raw struct {
+		Pix []json.RawMessage `json:"pix"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for i := range min(len(decoded.Pix), len(raw.Pix)) {
+		payment := &decoded.Pix[i]
+		if payment.RawExtras, err = unknownFields(raw.Pix[i], *payment); err != nil {
+			return err
+		}
+	}
+
+	*p = WebhookPayload(decoded)
+	p.RawExtras = extras
+	return nil
+}
+
+// MEDReturn is a MED return found in a webhook payload, together with the
+// payment it debits
+type MEDReturn struct {
+	Payment PaymentResponse
+	Refund  RefundInfo
+}
+
+// MEDReturns returns the MED (Mecanismo Especial de Devolução) returns
+// notified in the payload. These are driven by infraction reports rather
+// than requested by the receiver, so they usually need separate handling.
+func (p WebhookPayload) MEDReturns() []MEDReturn {
+	var returns []MEDReturn
+	for _, payment := range p.Pix {
+		for _, refund := range payment.Refunds {
+			if refund.IsMED() {
+				returns = append(returns, MEDReturn{Payment: payment, Refund: refund})
+			}
+		}
+	}
+
+	return returns
+}
+
+// ListWebhooksParams represents parameters for listing webhook configurations
+// All fields are optional
+type ListWebhooksParams struct {
+	StartDate time.Time `json:"inicio,omitzero"`
+	EndDate   time.Time `json:"fim,omitzero"`
+	Page      int       `json:"paginaAtual,omitempty"`
+	PageSize  int       `json:"itensPorPagina,omitempty"`
+}
+
+// WebhookListResponse represents a list of webhook configurations
+type WebhookListResponse struct {
+	Parameters struct {
+		Start      time.Time  `json:"inicio"`
+		End        time.Time  `json:"fim"`
+		Pagination Pagination `json:"paginacao"`
+	} `json:"parametros"`
+	Webhooks []WebhookConfig `json:"webhooks"`
+}
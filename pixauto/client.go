@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	httpclient "github.com/pericles-luz/go-bb-pix/internal/http"
+	"github.com/pericles-luz/go-bb-pix/pix"
+	"github.com/pericles-luz/go-bb-pix/redact"
 )
 
 // Client is the PIX Automático API client
@@ -11,9 +13,69 @@ type Client struct {
 	http *httpclient.Client
 }
 
+// ClientOption is a functional option for configuring the PIX Automático client
+type ClientOption func(*clientOptions)
+
+// clientOptions holds the options used to build the HTTP client
+type clientOptions struct {
+	http []httpclient.Option
+}
+
+// WithAuditHook sets a function that receives the exact marshaled request
+// body and the response of every mutating operation
+func WithAuditHook(fn pix.AuditFunc) ClientOption {
+	return func(opts *clientOptions) {
+		if fn != nil {
+			opts.http = append(opts.http, httpclient.WithAuditFunc(fn))
+		}
+	}
+}
+
+// WithHooks sets functions called before and after every API call, with
+// its method, path, status, duration and error
+func WithHooks(h pix.Hooks) ClientOption {
+	return func(opts *clientOptions) {
+		opts.http = append(opts.http, httpclient.WithHooks(h))
+	}
+}
+
+// WithConvenio sends the BB agreement (convênio) number as the
+// numeroConvenio query parameter of the listings, the endpoints that take it
+func WithConvenio(numero string) ClientOption {
+	return func(opts *clientOptions) {
+		if numero != "" {
+			opts.http = append(opts.http, httpclient.WithListParam(pix.ConvenioParam, numero))
+		}
+	}
+}
+
+// WithPaginationStyle sets the names of the paging query parameters sent by
+// the list operations
+// Default: pix.PaginationNested
+func WithPaginationStyle(style pix.PaginationStyle) ClientOption {
+	return func(opts *clientOptions) {
+		opts.http = append(opts.http, httpclient.WithPaginationStyle(style))
+	}
+}
+
+// WithRedactor applies the pattern rules of r to the messages of the
+// errors returned by the client, including API error details
+func WithRedactor(r *redact.Redactor) ClientOption {
+	return func(opts *clientOptions) {
+		if r != nil {
+			opts.http = append(opts.http, httpclient.WithRedactor(r))
+		}
+	}
+}
+
 // NewClient creates a new PIX Automático client
-func NewClient(httpClient *http.Client, apiURL string) *Client {
+func NewClient(httpClient *http.Client, apiURL string, opts ...ClientOption) *Client {
+	var options clientOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	return &Client{
-		http: httpclient.NewClient(httpClient, apiURL),
+		http: httpclient.NewClient(httpClient, apiURL, options.http...),
 	}
 }
@@ -0,0 +1,146 @@
+package pixauto
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// CreateCobR creates a recurring charge (cobr) for an approved recurrence
+// When txID is empty the bank assigns the txid.
+func (c *Client) CreateCobR(ctx context.Context, txID string, req CobRRequest) (*RecurringCharge, error) {
+	if req.RecID == "" {
+		return nil, fmt.Errorf("idRec is required")
+	}
+	if req.Calendar.DueDate == "" {
+		return nil, fmt.Errorf("calendario.dataDeVencimento is required")
+	}
+
+	method, path := http.MethodPost, "/cobr"
+	if txID != "" {
+		method, path = http.MethodPut, fmt.Sprintf("/cobr/%s", url.PathEscape(txID))
+	}
+
+	httpReq, err := c.http.NewRequest(ctx, method, path, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp RecurringCharge
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to create cobr: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// GetCobR retrieves a recurring charge by txid, including its attempts
+func (c *Client) GetCobR(ctx context.Context, txID string) (*RecurringCharge, error) {
+	if txID == "" {
+		return nil, fmt.Errorf("txid is required")
+	}
+
+	path := fmt.Sprintf("/cobr/%s", url.PathEscape(txID))
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp RecurringCharge
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get cobr: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// UpdateCobR partially updates a recurring charge, e.g. to cancel it
+func (c *Client) UpdateCobR(ctx context.Context, txID string, req UpdateCobRRequest) (*RecurringCharge, error) {
+	if txID == "" {
+		return nil, fmt.Errorf("txid is required")
+	}
+	if req.Status == "" {
+		return nil, fmt.Errorf("status is required")
+	}
+
+	path := fmt.Sprintf("/cobr/%s", url.PathEscape(txID))
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodPatch, path, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp RecurringCharge
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to update cobr: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// ListCobR lists recurring charges with optional filters
+func (c *Client) ListCobR(ctx context.Context, params ListCobRParams) (*CobRListResponse, error) {
+	path := "/cobr"
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Add query parameters
+	q := httpReq.URL.Query()
+	q.Set("inicio", params.StartDate.Format("2006-01-02T15:04:05Z07:00"))
+	q.Set("fim", params.EndDate.Format("2006-01-02T15:04:05Z07:00"))
+
+	if params.RecID != "" {
+		q.Set("idRec", params.RecID)
+	}
+	if params.CPF != "" {
+		q.Set("cpf", params.CPF)
+	}
+	if params.CNPJ != "" {
+		q.Set("cnpj", params.CNPJ)
+	}
+	if params.Status != "" {
+		q.Set("status", string(params.Status))
+	}
+	c.http.SetPagination(q, params.Page, params.PageSize)
+
+	httpReq.URL.RawQuery = q.Encode()
+
+	var resp CobRListResponse
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list cobr: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// RetryCobR requests a new settlement attempt of a recurring charge on the
+// given date, as allowed by the recurrence retry policy. The returned charge
+// includes the scheduled attempt.
+func (c *Client) RetryCobR(ctx context.Context, txID string, date time.Time) (*RecurringCharge, error) {
+	if txID == "" {
+		return nil, fmt.Errorf("txid is required")
+	}
+	if date.IsZero() {
+		return nil, fmt.Errorf("retry date is required")
+	}
+
+	path := fmt.Sprintf("/cobr/%s/retentativa/%s", url.PathEscape(txID), date.Format("2006-01-02"))
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp RecurringCharge
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to retry cobr: %w", err)
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,239 @@
+package pixauto
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const cobrJSON = `{
+	"idRec": "RR1234567820240115abcdefghijk",
+	"txid": "cobr1234567890",
+	"infoAdicional": "Mensalidade",
+	"calendario": {"criacao": "2024-03-20T10:00:00Z", "dataDeVencimento": "2024-04-15"},
+	"valor": {"original": "106.07"},
+	"status": "ATIVA",
+	"politicaRetentativa": "PERMITE_3R_7D",
+	"ajusteDiaUtil": true,
+	"recebedor": {"agencia": "0001", "conta": "123456", "tipoConta": "CORRENTE"},
+	"atualizacao": [{"status": "CRIADA", "data": "2024-03-20T10:00:00Z"}, {"status": "ATIVA", "data": "2024-03-20T10:00:05Z"}],
+	"tentativas": [
+		{"dataLiquidacao": "2024-04-15", "tipo": "AGND", "endToEndId": "E1", "status": "REJEITADA"},
+		{"dataLiquidacao": "2024-04-16", "tipo": "NTAG", "endToEndId": "E2", "status": "AGENDADA"}
+	]
+}`
+
+func TestClient_CreateCobR(t *testing.T) {
+	tests := []struct {
+		name       string
+		txID       string
+		wantMethod string
+		wantPath   string
+	}{
+		{name: "with txid", txID: "cobr1234567890", wantMethod: http.MethodPut, wantPath: "/cobr/cobr1234567890"},
+		{name: "bank assigned txid", wantMethod: http.MethodPost, wantPath: "/cobr"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tt.wantMethod {
+					t.Errorf("Method = %s, want %s", r.Method, tt.wantMethod)
+				}
+				if r.URL.Path != tt.wantPath {
+					t.Errorf("Path = %s, want %s", r.URL.Path, tt.wantPath)
+				}
+
+				var body map[string]interface{}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("Failed to decode request: %v", err)
+				}
+				if body["ajusteDiaUtil"] != true {
+					t.Errorf("ajusteDiaUtil = %v, want true", body["ajusteDiaUtil"])
+				}
+				calendar, _ := body["calendario"].(map[string]interface{})
+				if _, ok := calendar["criacao"]; ok {
+					t.Error("criacao should be omitted in requests")
+				}
+				if calendar["dataDeVencimento"] != "2024-04-15" {
+					t.Errorf("dataDeVencimento = %v, want 2024-04-15", calendar["dataDeVencimento"])
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(cobrJSON))
+			}))
+			defer server.Close()
+
+			client := NewClient(&http.Client{}, server.URL)
+
+			charge, err := client.CreateCobR(context.Background(), tt.txID, CobRRequest{
+				RecID:                 "RR1234567820240115abcdefghijk",
+				Calendar:              CobRCalendar{DueDate: "2024-04-15"},
+				Value:                 CobRValue{Original: "106.07"},
+				BusinessDayAdjustment: true,
+			})
+			if err != nil {
+				t.Fatalf("CreateCobR() error = %v", err)
+			}
+			if charge.TxID != "cobr1234567890" {
+				t.Errorf("TxID = %s, want cobr1234567890", charge.TxID)
+			}
+		})
+	}
+}
+
+func TestClient_CreateCobR_Validation(t *testing.T) {
+	client := NewClient(&http.Client{}, "http://unused.invalid")
+
+	if _, err := client.CreateCobR(context.Background(), "", CobRRequest{Calendar: CobRCalendar{DueDate: "2024-04-15"}}); err == nil {
+		t.Error("CreateCobR() without idRec should fail")
+	}
+	if _, err := client.CreateCobR(context.Background(), "", CobRRequest{RecID: "RR1"}); err == nil {
+		t.Error("CreateCobR() without due date should fail")
+	}
+}
+
+func TestClient_GetCobR_Attempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Method = %s, want GET", r.Method)
+		}
+		if r.URL.Path != "/cobr/cobr1234567890" {
+			t.Errorf("Path = %s, want /cobr/cobr1234567890", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(cobrJSON))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	charge, err := client.GetCobR(context.Background(), "cobr1234567890")
+	if err != nil {
+		t.Fatalf("GetCobR() error = %v", err)
+	}
+	if charge.Calendar.Creation.IsZero() {
+		t.Error("Calendar.Creation should not be zero")
+	}
+	if charge.Receiver == nil || charge.Receiver.Account != "123456" {
+		t.Errorf("Receiver = %+v, want account 123456", charge.Receiver)
+	}
+	if len(charge.Attempts) != 2 {
+		t.Fatalf("len(Attempts) = %d, want 2", len(charge.Attempts))
+	}
+	if !charge.Attempts[0].Failed() || charge.Attempts[0].IsSettled() {
+		t.Errorf("Attempts[0] = %+v, want a failed attempt", charge.Attempts[0])
+	}
+
+	last, ok := charge.LastAttempt()
+	if !ok || last.Type != AttemptTypeNotScheduled || last.Status != AttemptStatusScheduled {
+		t.Errorf("LastAttempt() = %+v, %v, want the scheduled NTAG attempt", last, ok)
+	}
+	if _, ok := (RecurringCharge{}).LastAttempt(); ok {
+		t.Error("LastAttempt() on a charge without attempts should report false")
+	}
+}
+
+func TestClient_UpdateCobR(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("Method = %s, want PATCH", r.Method)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if body["status"] != "CANCELADA" {
+			t.Errorf("status = %v, want CANCELADA", body["status"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"txid":"cobr1234567890","status":"CANCELADA"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	charge, err := client.UpdateCobR(context.Background(), "cobr1234567890", UpdateCobRRequest{Status: "CANCELADA"})
+	if err != nil {
+		t.Fatalf("UpdateCobR() error = %v", err)
+	}
+	if charge.Status != "CANCELADA" {
+		t.Errorf("Status = %s, want CANCELADA", charge.Status)
+	}
+}
+
+func TestClient_ListCobR_QueryParams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cobr" {
+			t.Errorf("Path = %s, want /cobr", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("inicio") != "2024-04-01T00:00:00Z" {
+			t.Errorf("inicio = %s, want 2024-04-01T00:00:00Z", query.Get("inicio"))
+		}
+		if query.Get("idRec") != "RR1234567820240115abcdefghijk" {
+			t.Errorf("idRec = %s, want RR1234567820240115abcdefghijk", query.Get("idRec"))
+		}
+		if query.Get("status") != "ATIVA" {
+			t.Errorf("status = %s, want ATIVA", query.Get("status"))
+		}
+		if query.Has("cpf") {
+			t.Error("cpf should not be sent when empty")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"parametros":{"inicio":"2024-04-01T00:00:00Z","fim":"2024-04-30T23:59:59Z","paginacao":{"paginaAtual":0,"itensPorPagina":100,"quantidadeDePaginas":1,"quantidadeTotalDeItens":1}},"cobsr":[` + cobrJSON + `]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	resp, err := client.ListCobR(context.Background(), ListCobRParams{
+		StartDate: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 4, 30, 23, 59, 59, 0, time.UTC),
+		RecID:     "RR1234567820240115abcdefghijk",
+		Status:    "ATIVA",
+	})
+	if err != nil {
+		t.Fatalf("ListCobR() error = %v", err)
+	}
+	if len(resp.Charges) != 1 {
+		t.Errorf("len(Charges) = %d, want 1", len(resp.Charges))
+	}
+}
+
+func TestClient_RetryCobR(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Method = %s, want POST", r.Method)
+		}
+		if r.URL.Path != "/cobr/cobr1234567890/retentativa/2024-04-16" {
+			t.Errorf("Path = %s, want /cobr/cobr1234567890/retentativa/2024-04-16", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(cobrJSON))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	charge, err := client.RetryCobR(context.Background(), "cobr1234567890", time.Date(2024, 4, 16, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("RetryCobR() error = %v", err)
+	}
+	if last, _ := charge.LastAttempt(); last.SettlementDate != "2024-04-16" {
+		t.Errorf("LastAttempt().SettlementDate = %s, want 2024-04-16", last.SettlementDate)
+	}
+
+	if _, err := client.RetryCobR(context.Background(), "cobr1234567890", time.Time{}); err == nil {
+		t.Error("RetryCobR() without date should fail")
+	}
+}
@@ -0,0 +1,119 @@
+package pixauto
+
+import (
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/pix"
+)
+
+// CobRRequest represents a recurring charge (cobr) of an approved recurrence
+type CobRRequest struct {
+	RecID          string       `json:"idRec"`
+	AdditionalInfo string       `json:"infoAdicional,omitempty"`
+	Calendar       CobRCalendar `json:"calendario"`
+	Value          CobRValue    `json:"valor"`
+	// BusinessDayAdjustment moves a due date that falls on a non-business
+	// day to the next business day
+	BusinessDayAdjustment bool          `json:"ajusteDiaUtil"`
+	Debtor                *CobRDebtor   `json:"devedor,omitempty"`
+	Receiver              *CobRReceiver `json:"recebedor,omitempty"`
+}
+
+// CobRCalendar represents the due date of a recurring charge
+type CobRCalendar struct {
+	Creation time.Time `json:"criacao,omitzero"`
+	DueDate  string    `json:"dataDeVencimento"` // YYYY-MM-DD
+}
+
+// CobRValue represents the amount of a recurring charge
+type CobRValue struct {
+	Original string `json:"original"`
+}
+
+// CobRDebtor represents the debtor contact data of a recurring charge
+type CobRDebtor struct {
+	Email      string `json:"email,omitempty"`
+	Street     string `json:"logradouro,omitempty"`
+	City       string `json:"cidade,omitempty"`
+	State      string `json:"uf,omitempty"`
+	PostalCode string `json:"cep,omitempty"`
+}
+
+// CobRReceiver represents the account credited by a recurring charge
+type CobRReceiver struct {
+	Branch      string `json:"agencia,omitempty"`
+	Account     string `json:"conta,omitempty"`
+	AccountType string `json:"tipoConta,omitempty"`
+}
+
+// UpdateCobRRequest represents a partial update of a recurring charge,
+// e.g. Status "CANCELADA"
+type UpdateCobRRequest struct {
+	Status ChargeStatus `json:"status"`
+}
+
+// RecurringCharge is a recurring charge (cobr) returned by the API or
+// notified through the webhook
+type RecurringCharge struct {
+	RecID                 string          `json:"idRec"`
+	TxID                  string          `json:"txid"`
+	AdditionalInfo        string          `json:"infoAdicional,omitempty"`
+	Calendar              CobRCalendar    `json:"calendario"`
+	Value                 CobRValue       `json:"valor"`
+	Status                ChargeStatus    `json:"status"`
+	RetryPolicy           string          `json:"politicaRetentativa,omitempty"`
+	BusinessDayAdjustment bool            `json:"ajusteDiaUtil"`
+	Debtor                *CobRDebtor     `json:"devedor,omitempty"`
+	Receiver              *CobRReceiver   `json:"recebedor,omitempty"`
+	Updates               []StatusUpdate  `json:"atualizacao,omitempty"`
+	Attempts              []ChargeAttempt `json:"tentativas,omitempty"`
+}
+
+// LastAttempt returns the most recent settlement attempt of the charge
+func (c RecurringCharge) LastAttempt() (ChargeAttempt, bool) {
+	if len(c.Attempts) == 0 {
+		return ChargeAttempt{}, false
+	}
+	return c.Attempts[len(c.Attempts)-1], true
+}
+
+// ChargeAttempt is a settlement attempt of a recurring charge
+type ChargeAttempt struct {
+	SettlementDate string         `json:"dataLiquidacao"`
+	Type           AttemptType    `json:"tipo"`
+	EndToEndID     string         `json:"endToEndId,omitempty"`
+	Status         AttemptStatus  `json:"status,omitempty"`
+	Updates        []StatusUpdate `json:"atualizacao,omitempty"`
+}
+
+// IsSettled reports whether the attempt settled the charge
+func (a ChargeAttempt) IsSettled() bool {
+	return a.Status == AttemptStatusSettled
+}
+
+// Failed reports whether the attempt was rejected or failed
+func (a ChargeAttempt) Failed() bool {
+	return a.Status == AttemptStatusRejected || a.Status == AttemptStatusOperationalFailure
+}
+
+// ListCobRParams represents parameters for listing recurring charges
+type ListCobRParams struct {
+	StartDate time.Time    `json:"inicio"`
+	EndDate   time.Time    `json:"fim"`
+	RecID     string       `json:"idRec,omitempty"`
+	CPF       string       `json:"cpf,omitempty"`
+	CNPJ      string       `json:"cnpj,omitempty"`
+	Status    ChargeStatus `json:"status,omitempty"`
+	Page      int          `json:"paginaAtual,omitempty"`
+	PageSize  int          `json:"itensPorPagina,omitempty"`
+}
+
+// CobRListResponse represents a list of recurring charges
+type CobRListResponse struct {
+	Parameters struct {
+		Start      time.Time      `json:"inicio"`
+		End        time.Time      `json:"fim"`
+		Pagination pix.Pagination `json:"paginacao"`
+	} `json:"parametros"`
+	Charges []RecurringCharge `json:"cobsr"`
+}
@@ -0,0 +1,53 @@
+package pixauto
+
+import (
+	"encoding/json"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/pericles-luz/go-bb-pix/pix"
+)
+
+func FuzzCreateRecurrenceRequest_MarshalJSON(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		"João da Silva",
+		`Maria "Mary" O'Brien`,
+		"linha 1\nlinha 2\r\n",
+		"academia 🏋️ mensal",
+		"\x00\x1f\x7f",
+		"\xff\xfe invalid utf-8",
+	} {
+		f.Add(seed, seed)
+	}
+
+	f.Fuzz(func(t *testing.T, name, text string) {
+		req := CreateRecurrenceRequest{
+			Link: RecurrenceLink{
+				Object:   text,
+				Contract: text,
+				Debtor:   pix.Debtor{CPF: "45164632481", Name: name},
+			},
+			Calendar: RecurrenceCalendar{StartDate: "2024-04-01", Periodicity: PeriodicityMonthly},
+		}
+
+		data, err := json.Marshal(req)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		if !json.Valid(data) {
+			t.Fatalf("Marshal() produced invalid JSON: %s", data)
+		}
+
+		var decoded CreateRecurrenceRequest
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if !utf8.ValidString(name) || !utf8.ValidString(text) {
+			return
+		}
+		if decoded.Link.Debtor.Name != name || decoded.Link.Object != text || decoded.Link.Contract != text {
+			t.Errorf("strings did not round-trip: %+v", decoded.Link)
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package pixauto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/pix"
+)
+
+// JourneyType identifies a PIX Automático approval journey
+type JourneyType int
+
+// Approval journeys defined by the PIX Automático rules
+const (
+	// Journey1 sends a confirmation request (solicrec) to the payer's PSP,
+	// without QR Code nor initial payment
+	Journey1 JourneyType = iota + 1
+	// Journey2 presents a QR Code of the recurrence location (locrec),
+	// without initial payment
+	Journey2
+	// Journey3 presents a composed QR Code: an immediate charge (cob) paid
+	// together with the recurrence approval
+	Journey3
+	// Journey4 presents a composed QR Code: a charge with due date (cobv)
+	// paid together with the recurrence approval
+	Journey4
+)
+
+// String returns the journey name
+func (t JourneyType) String() string {
+	switch t {
+	case Journey1, Journey2, Journey3, Journey4:
+		return fmt.Sprintf("Jornada %d", int(t))
+	default:
+		return fmt.Sprintf("JourneyType(%d)", int(t))
+	}
+}
+
+// JourneyParams holds the recurrence data common to every journey
+type JourneyParams struct {
+	Contract    string
+	Object      string
+	Debtor      pix.Debtor
+	StartDate   string // YYYY-MM-DD
+	EndDate     string // YYYY-MM-DD, optional
+	Periodicity string
+
+	// Amount is the fixed value of each charge; MinimumAmount is the floor
+	// of a variable value. At most one of them may be set.
+	Amount        string
+	MinimumAmount string
+
+	// RetryPolicy defaults to RetryPolicyNotAllowed
+	RetryPolicy string
+}
+
+// Journey holds the payloads of an approval journey, in the order they must
+// be sent:
+//
+//   - Journey 1: Recurrence, then Solicitation with the idRec returned
+//   - Journey 2: Recurrence, attached to the locrec rendered as QR Code
+//   - Journey 3: Charge (PUT /cob/{txid}), then Recurrence
+//   - Journey 4: DueCharge (PUT /cobv/{txid}), then Recurrence
+type Journey struct {
+	Type       JourneyType
+	Recurrence CreateRecurrenceRequest
+
+	// Solicitation is set for Journey 1; use SolicitationFor to fill idRec
+	Solicitation *CreateSolicitationRequest
+
+	// Charge is the immediate charge of Journey 3
+	Charge *pix.CreateQRCodeRequest
+
+	// DueCharge and DueChargeTxID are the charge with due date of Journey 4
+	DueCharge     *pix.CobVRequest
+	DueChargeTxID string
+}
+
+// SolicitationFor returns the Journey 1 solicitation bound to the recurrence
+// created by the bank
+func (j *Journey) SolicitationFor(recID string) (CreateSolicitationRequest, error) {
+	if j.Solicitation == nil {
+		return CreateSolicitationRequest{}, fmt.Errorf("%s has no solicitation", j.Type)
+	}
+	if recID == "" {
+		return CreateSolicitationRequest{}, fmt.Errorf("idRec is required")
+	}
+
+	req := *j.Solicitation
+	req.RecID = recID
+	return req, nil
+}
+
+// NewJourney1 builds a journey where the payer approves the recurrence in a
+// confirmation request sent to their PSP until expiration
+func NewJourney1(params JourneyParams, recipient SolicitationRecipient, expiration time.Time) (*Journey, error) {
+	rec, err := params.recurrence(0)
+	if err != nil {
+		return nil, err
+	}
+	if recipient.ISPB == "" {
+		return nil, fmt.Errorf("destinatario.ispbParticipante is required")
+	}
+	if recipient.CPF == "" && recipient.CNPJ == "" {
+		return nil, fmt.Errorf("destinatario.cpf or destinatario.cnpj is required")
+	}
+	if expiration.IsZero() {
+		return nil, fmt.Errorf("calendario.dataExpiracaoSolicitacao is required")
+	}
+
+	return &Journey{
+		Type:       Journey1,
+		Recurrence: rec,
+		Solicitation: &CreateSolicitationRequest{
+			Recipient: recipient,
+			Calendar:  SolicitationCalendar{Expiration: expiration},
+		},
+	}, nil
+}
+
+// NewJourney2 builds a journey where the payer scans the QR Code of the
+// recurrence location locationID (see CreateLocRec)
+func NewJourney2(params JourneyParams, locationID int) (*Journey, error) {
+	if locationID <= 0 {
+		return nil, fmt.Errorf("loc is required")
+	}
+
+	rec, err := params.recurrence(locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Journey{Type: Journey2, Recurrence: rec}, nil
+}
+
+// NewJourney3 builds a journey where the payer pays charge immediately and
+// approves the recurrence of location locationID with the same QR Code
+// The charge debtor defaults to the recurrence debtor.
+func NewJourney3(params JourneyParams, locationID int, charge pix.CreateQRCodeRequest) (*Journey, error) {
+	if locationID <= 0 {
+		return nil, fmt.Errorf("loc is required")
+	}
+	if charge.TxID == "" {
+		return nil, fmt.Errorf("cob txid is required")
+	}
+	if charge.Value <= 0 {
+		return nil, fmt.Errorf("cob valor must be positive")
+	}
+
+	rec, err := params.recurrence(locationID)
+	if err != nil {
+		return nil, err
+	}
+	rec.Activation = &RecurrenceActivation{JourneyData: &JourneyData{TxID: charge.TxID}}
+
+	if charge.Debtor == nil {
+		debtor := params.Debtor
+		charge.Debtor = &debtor
+	}
+
+	return &Journey{Type: Journey3, Recurrence: rec, Charge: &charge}, nil
+}
+
+// NewJourney4 builds a journey where the payer pays the charge with due
+// date txID and approves the recurrence of location locationID with the
+// same QR Code
+// The charge debtor defaults to the recurrence debtor.
+func NewJourney4(params JourneyParams, locationID int, txID string, charge pix.CobVRequest) (*Journey, error) {
+	if locationID <= 0 {
+		return nil, fmt.Errorf("loc is required")
+	}
+	if txID == "" {
+		return nil, fmt.Errorf("cobv txid is required")
+	}
+	if charge.Calendar.DueDate == "" {
+		return nil, fmt.Errorf("cobv calendario.dataDeVencimento is required")
+	}
+	if charge.Value.Original == "" {
+		return nil, fmt.Errorf("cobv valor.original is required")
+	}
+
+	rec, err := params.recurrence(locationID)
+	if err != nil {
+		return nil, err
+	}
+	rec.Activation = &RecurrenceActivation{JourneyData: &JourneyData{TxID: txID}}
+
+	if charge.Debtor == nil {
+		debtor := params.Debtor
+		charge.Debtor = &debtor
+	}
+
+	return &Journey{Type: Journey4, Recurrence: rec, DueCharge: &charge, DueChargeTxID: txID}, nil
+}
+
+// recurrence validates params and builds the rec payload
+func (p JourneyParams) recurrence(locationID int) (CreateRecurrenceRequest, error) {
+	if p.Contract == "" {
+		return CreateRecurrenceRequest{}, fmt.Errorf("vinculo.contrato is required")
+	}
+	if p.Debtor.CPF == "" && p.Debtor.CNPJ == "" {
+		return CreateRecurrenceRequest{}, fmt.Errorf("vinculo.devedor.cpf or vinculo.devedor.cnpj is required")
+	}
+	if p.Debtor.CPF != "" && p.Debtor.CNPJ != "" {
+		return CreateRecurrenceRequest{}, fmt.Errorf("vinculo.devedor must have either cpf or cnpj, not both")
+	}
+	if p.StartDate == "" {
+		return CreateRecurrenceRequest{}, fmt.Errorf("calendario.dataInicial is required")
+	}
+	if p.Periodicity == "" {
+		return CreateRecurrenceRequest{}, fmt.Errorf("calendario.periodicidade is required")
+	}
+	if p.EndDate != "" && p.EndDate < p.StartDate {
+		return CreateRecurrenceRequest{}, fmt.Errorf("calendario.dataFinal must not be before dataInicial")
+	}
+	if p.Amount != "" && p.MinimumAmount != "" {
+		return CreateRecurrenceRequest{}, fmt.Errorf("valor must have either valorRec or valorMinimoRecebedor, not both")
+	}
+
+	rec := CreateRecurrenceRequest{
+		Link: RecurrenceLink{
+			Object:   p.Object,
+			Contract: p.Contract,
+			Debtor:   p.Debtor,
+		},
+		Calendar: RecurrenceCalendar{
+			StartDate:   p.StartDate,
+			EndDate:     p.EndDate,
+			Periodicity: p.Periodicity,
+		},
+		RetryPolicy: p.RetryPolicy,
+		LocationID:  locationID,
+	}
+	if rec.RetryPolicy == "" {
+		rec.RetryPolicy = RetryPolicyNotAllowed
+	}
+	if p.Amount != "" || p.MinimumAmount != "" {
+		rec.Value = &RecurrenceValue{Amount: p.Amount, MinimumAmount: p.MinimumAmount}
+	}
+
+	return rec, nil
+}
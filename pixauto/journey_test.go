@@ -0,0 +1,171 @@
+package pixauto
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/pix"
+)
+
+func journeyParams() JourneyParams {
+	return JourneyParams{
+		Contract:    "63100862",
+		Object:      "Academia",
+		Debtor:      pix.Debtor{CPF: "45164632481", Name: "Fulano de Tal"},
+		StartDate:   "2024-04-01",
+		Periodicity: PeriodicityMonthly,
+		Amount:      "35.00",
+	}
+}
+
+func TestNewJourney1(t *testing.T) {
+	expiration := time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC)
+	recipient := SolicitationRecipient{ISPB: "99999008", CPF: "45164632481", Account: "12345"}
+
+	j, err := NewJourney1(journeyParams(), recipient, expiration)
+	if err != nil {
+		t.Fatalf("NewJourney1() error = %v", err)
+	}
+
+	if j.Type != Journey1 || j.Recurrence.LocationID != 0 || j.Recurrence.Activation != nil {
+		t.Errorf("journey = %+v, want a rec without loc nor ativacao", j)
+	}
+	if j.Recurrence.RetryPolicy != RetryPolicyNotAllowed {
+		t.Errorf("RetryPolicy = %s, want default %s", j.Recurrence.RetryPolicy, RetryPolicyNotAllowed)
+	}
+	if j.Recurrence.Value == nil || j.Recurrence.Value.Amount != "35.00" {
+		t.Errorf("Value = %+v, want valorRec 35.00", j.Recurrence.Value)
+	}
+
+	solic, err := j.SolicitationFor("RR1234567820240115abcdefghijk")
+	if err != nil {
+		t.Fatalf("SolicitationFor() error = %v", err)
+	}
+	if solic.RecID != "RR1234567820240115abcdefghijk" || !solic.Calendar.Expiration.Equal(expiration) {
+		t.Errorf("solicitation = %+v", solic)
+	}
+	if j.Solicitation.RecID != "" {
+		t.Error("SolicitationFor() should not modify the journey")
+	}
+	if _, err := j.SolicitationFor(""); err == nil {
+		t.Error("SolicitationFor(\"\") expected error")
+	}
+}
+
+func TestNewJourney2(t *testing.T) {
+	j, err := NewJourney2(journeyParams(), 108)
+	if err != nil {
+		t.Fatalf("NewJourney2() error = %v", err)
+	}
+	if j.Recurrence.LocationID != 108 || j.Solicitation != nil || j.Charge != nil {
+		t.Errorf("journey = %+v, want only a rec with loc 108", j)
+	}
+	if _, err := j.SolicitationFor("RR1"); err == nil {
+		t.Error("SolicitationFor() expected error outside Journey 1")
+	}
+}
+
+func TestNewJourney3(t *testing.T) {
+	charge := pix.CreateQRCodeRequest{TxID: "3136957d93134f2184b369e8f1c0729d", Value: 35, Expiration: 3600}
+
+	j, err := NewJourney3(journeyParams(), 108, charge)
+	if err != nil {
+		t.Fatalf("NewJourney3() error = %v", err)
+	}
+
+	data, err := json.Marshal(j.Recurrence)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"ativacao":{"dadosJornada":{"txid":"3136957d93134f2184b369e8f1c0729d"}}`) {
+		t.Errorf("rec = %s, want ativacao.dadosJornada.txid of the cob", data)
+	}
+	if j.Charge.Debtor == nil || j.Charge.Debtor.CPF != "45164632481" {
+		t.Errorf("cob devedor = %+v, want the recurrence debtor", j.Charge.Debtor)
+	}
+}
+
+func TestNewJourney4(t *testing.T) {
+	charge := pix.CobVRequest{
+		Calendar: pix.CobVCalendar{DueDate: "2024-04-01"},
+		Debtor:   &pix.Debtor{CPF: "12345678909", Name: "Outro Pagador"},
+		Value:    pix.CobVValue{Original: "35.00"},
+	}
+
+	j, err := NewJourney4(journeyParams(), 108, "4136957d93134f2184b369e8f1c0729d", charge)
+	if err != nil {
+		t.Fatalf("NewJourney4() error = %v", err)
+	}
+	if j.DueChargeTxID != "4136957d93134f2184b369e8f1c0729d" || j.Recurrence.Activation.JourneyData.TxID != j.DueChargeTxID {
+		t.Errorf("journey = %+v, want rec bound to the cobv txid", j)
+	}
+	if j.DueCharge.Debtor.CPF != "12345678909" {
+		t.Errorf("cobv devedor = %+v, want the explicit debtor kept", j.DueCharge.Debtor)
+	}
+}
+
+func TestNewJourney_Validation(t *testing.T) {
+	valid := journeyParams()
+	recipient := SolicitationRecipient{ISPB: "99999008", CPF: "45164632481"}
+	expiration := time.Now().Add(time.Hour)
+	cob := pix.CreateQRCodeRequest{TxID: "3136957d93134f2184b369e8f1c0729d", Value: 35}
+	cobv := pix.CobVRequest{Calendar: pix.CobVCalendar{DueDate: "2024-04-01"}, Value: pix.CobVValue{Original: "35.00"}}
+
+	with := func(fn func(*JourneyParams)) JourneyParams {
+		p := valid
+		fn(&p)
+		return p
+	}
+
+	tests := []struct {
+		name  string
+		build func() (*Journey, error)
+	}{
+		{"missing contract", func() (*Journey, error) {
+			return NewJourney2(with(func(p *JourneyParams) { p.Contract = "" }), 108)
+		}},
+		{"missing debtor document", func() (*Journey, error) {
+			return NewJourney2(with(func(p *JourneyParams) { p.Debtor.CPF = "" }), 108)
+		}},
+		{"cpf and cnpj", func() (*Journey, error) {
+			return NewJourney2(with(func(p *JourneyParams) { p.Debtor.CNPJ = "12345678000195" }), 108)
+		}},
+		{"end before start", func() (*Journey, error) {
+			return NewJourney2(with(func(p *JourneyParams) { p.EndDate = "2024-01-01" }), 108)
+		}},
+		{"fixed and minimum amount", func() (*Journey, error) {
+			return NewJourney2(with(func(p *JourneyParams) { p.MinimumAmount = "10.00" }), 108)
+		}},
+		{"journey 1 without ispb", func() (*Journey, error) {
+			return NewJourney1(valid, SolicitationRecipient{CPF: "45164632481"}, expiration)
+		}},
+		{"journey 1 without expiration", func() (*Journey, error) {
+			return NewJourney1(valid, recipient, time.Time{})
+		}},
+		{"journey 2 without loc", func() (*Journey, error) {
+			return NewJourney2(valid, 0)
+		}},
+		{"journey 3 without txid", func() (*Journey, error) {
+			return NewJourney3(valid, 108, pix.CreateQRCodeRequest{Value: 35})
+		}},
+		{"journey 3 without value", func() (*Journey, error) {
+			return NewJourney3(valid, 108, pix.CreateQRCodeRequest{TxID: cob.TxID})
+		}},
+		{"journey 4 without txid", func() (*Journey, error) {
+			return NewJourney4(valid, 108, "", cobv)
+		}},
+		{"journey 4 without due date", func() (*Journey, error) {
+			return NewJourney4(valid, 108, "txid", pix.CobVRequest{Value: cobv.Value})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.build(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package pixauto
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// CreateLocRec creates a recurrence payload location that can later be
+// attached to a recurrence
+func (c *Client) CreateLocRec(ctx context.Context) (*RecurrenceLocation, error) {
+	path := "/locrec"
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp RecurrenceLocation
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to create locrec: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// GetLocRec retrieves a recurrence payload location by ID
+func (c *Client) GetLocRec(ctx context.Context, id int) (*RecurrenceLocation, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("location id is required")
+	}
+
+	path := fmt.Sprintf("/locrec/%d", id)
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp RecurrenceLocation
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get locrec: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// ListLocRec lists recurrence payload locations with optional filters
+func (c *Client) ListLocRec(ctx context.Context, params ListLocRecParams) (*LocRecListResponse, error) {
+	path := "/locrec"
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Add query parameters
+	q := httpReq.URL.Query()
+	q.Set("inicio", params.StartDate.Format("2006-01-02T15:04:05Z07:00"))
+	q.Set("fim", params.EndDate.Format("2006-01-02T15:04:05Z07:00"))
+
+	if params.RecIDPresent != nil {
+		q.Set("idRecPresente", strconv.FormatBool(*params.RecIDPresent))
+	}
+	c.http.SetPagination(q, params.Page, params.PageSize)
+
+	httpReq.URL.RawQuery = q.Encode()
+
+	var resp LocRecListResponse
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list locrec: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// AttachLocRec attaches the recurrence payload location to a recurrence
+// The API has no locrec endpoint for this; the location is set on the
+// recurrence through PATCH /rec/{idRec}.
+func (c *Client) AttachLocRec(ctx context.Context, id int, recID string) (*RecurrenceResponse, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("location id is required")
+	}
+
+	return c.UpdateRecurrence(ctx, recID, UpdateRecurrenceRequest{LocationID: id})
+}
+
+// DetachLocRec detaches the recurrence (idRec) from a payload location so
+// the location can be attached to another recurrence
+func (c *Client) DetachLocRec(ctx context.Context, id int) (*RecurrenceLocation, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("location id is required")
+	}
+
+	path := fmt.Sprintf("/locrec/%d/idRec", id)
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp RecurrenceLocation
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to detach locrec: %w", err)
+	}
+
+	return &resp, nil
+}
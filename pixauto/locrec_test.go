@@ -0,0 +1,161 @@
+package pixauto
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_CreateLocRec_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Method = %s, want POST", r.Method)
+		}
+		if r.URL.Path != "/locrec" {
+			t.Errorf("Path = %s, want /locrec", r.URL.Path)
+		}
+		if body, _ := io.ReadAll(r.Body); len(body) != 0 {
+			t.Errorf("body = %s, want empty", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":12,"location":"pix.example.com/qr/v2/rec/12","criacao":"2024-01-15T10:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	loc, err := client.CreateLocRec(context.Background())
+	if err != nil {
+		t.Fatalf("CreateLocRec() error = %v", err)
+	}
+	if loc.ID != 12 || loc.Creation.IsZero() {
+		t.Errorf("loc = %+v, want id 12 with creation", loc)
+	}
+	if loc.RecID != "" {
+		t.Errorf("RecID = %s, want empty", loc.RecID)
+	}
+}
+
+func TestClient_GetLocRec_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/locrec/12" {
+			t.Errorf("Path = %s, want /locrec/12", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":12,"location":"pix.example.com/qr/v2/rec/12","criacao":"2024-01-15T10:00:00Z","idRec":"RR1234567820240115abcdefghijk"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	loc, err := client.GetLocRec(context.Background(), 12)
+	if err != nil {
+		t.Fatalf("GetLocRec() error = %v", err)
+	}
+	if loc.RecID != "RR1234567820240115abcdefghijk" {
+		t.Errorf("RecID = %s, want RR1234567820240115abcdefghijk", loc.RecID)
+	}
+
+	if _, err := client.GetLocRec(context.Background(), 0); err == nil {
+		t.Error("GetLocRec() with zero id should fail")
+	}
+}
+
+func TestClient_ListLocRec_QueryParams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("inicio") != "2024-01-01T00:00:00Z" {
+			t.Errorf("inicio = %s, want 2024-01-01T00:00:00Z", query.Get("inicio"))
+		}
+		if query.Get("idRecPresente") != "false" {
+			t.Errorf("idRecPresente = %s, want false", query.Get("idRecPresente"))
+		}
+		if query.Get("paginacao.itensPorPagina") != "50" {
+			t.Errorf("itensPorPagina = %s, want 50", query.Get("paginacao.itensPorPagina"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"parametros":{"inicio":"2024-01-01T00:00:00Z","fim":"2024-01-31T23:59:59Z","paginacao":{"paginaAtual":0,"itensPorPagina":50,"quantidadeDePaginas":1,"quantidadeTotalDeItens":1}},"loc":[{"id":12,"location":"pix.example.com/qr/v2/rec/12","criacao":"2024-01-15T10:00:00Z"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	present := false
+	resp, err := client.ListLocRec(context.Background(), ListLocRecParams{
+		StartDate:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:      time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC),
+		RecIDPresent: &present,
+		PageSize:     50,
+	})
+	if err != nil {
+		t.Fatalf("ListLocRec() error = %v", err)
+	}
+	if len(resp.Locations) != 1 || resp.Locations[0].ID != 12 {
+		t.Errorf("Locations = %+v, want location 12", resp.Locations)
+	}
+}
+
+func TestClient_AttachLocRec(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("Method = %s, want PATCH", r.Method)
+		}
+		if r.URL.Path != "/rec/RR1234567820240115abcdefghijk" {
+			t.Errorf("Path = %s, want /rec/RR1234567820240115abcdefghijk", r.URL.Path)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if len(body) != 1 || body["loc"] != float64(12) {
+			t.Errorf("body = %v, want only loc 12", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(recurrenceJSON))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	if _, err := client.AttachLocRec(context.Background(), 12, "RR1234567820240115abcdefghijk"); err != nil {
+		t.Fatalf("AttachLocRec() error = %v", err)
+	}
+	if _, err := client.AttachLocRec(context.Background(), 0, "RR1234567820240115abcdefghijk"); err == nil {
+		t.Error("AttachLocRec() with zero id should fail")
+	}
+}
+
+func TestClient_DetachLocRec(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("Method = %s, want DELETE", r.Method)
+		}
+		if r.URL.Path != "/locrec/12/idRec" {
+			t.Errorf("Path = %s, want /locrec/12/idRec", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":12,"location":"pix.example.com/qr/v2/rec/12","criacao":"2024-01-15T10:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	loc, err := client.DetachLocRec(context.Background(), 12)
+	if err != nil {
+		t.Fatalf("DetachLocRec() error = %v", err)
+	}
+	if loc.RecID != "" {
+		t.Errorf("RecID = %s, want empty after detach", loc.RecID)
+	}
+}
@@ -0,0 +1,37 @@
+package pixauto
+
+import (
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/pix"
+)
+
+// RecurrenceLocation represents the payload location of a recurrence (locrec)
+type RecurrenceLocation struct {
+	ID       int       `json:"id"`
+	Location string    `json:"location"`
+	Creation time.Time `json:"criacao"`
+	// RecID is the recurrence attached to the location, if any
+	RecID string `json:"idRec,omitempty"`
+}
+
+// ListLocRecParams represents parameters for listing recurrence locations
+type ListLocRecParams struct {
+	StartDate time.Time `json:"inicio"`
+	EndDate   time.Time `json:"fim"`
+	// RecIDPresent filters locations with (true) or without (false) an
+	// attached idRec; nil lists both
+	RecIDPresent *bool `json:"idRecPresente,omitempty"`
+	Page         int   `json:"paginaAtual,omitempty"`
+	PageSize     int   `json:"itensPorPagina,omitempty"`
+}
+
+// LocRecListResponse represents a list of recurrence locations
+type LocRecListResponse struct {
+	Parameters struct {
+		Start      time.Time      `json:"inicio"`
+		End        time.Time      `json:"fim"`
+		Pagination pix.Pagination `json:"paginacao"`
+	} `json:"parametros"`
+	Locations []RecurrenceLocation `json:"loc"`
+}
@@ -0,0 +1,116 @@
+package pixauto
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// CreateRecurrence creates a recurrence (rec) that the debtor must approve
+func (c *Client) CreateRecurrence(ctx context.Context, req CreateRecurrenceRequest) (*RecurrenceResponse, error) {
+	if req.Link.Contract == "" {
+		return nil, fmt.Errorf("vinculo.contrato is required")
+	}
+	if req.Calendar.StartDate == "" {
+		return nil, fmt.Errorf("calendario.dataInicial is required")
+	}
+	if req.Calendar.Periodicity == "" {
+		return nil, fmt.Errorf("calendario.periodicidade is required")
+	}
+
+	path := "/rec"
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodPost, path, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp RecurrenceResponse
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to create recurrence: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// GetRecurrence retrieves a recurrence by its idRec
+func (c *Client) GetRecurrence(ctx context.Context, recID string) (*RecurrenceResponse, error) {
+	if recID == "" {
+		return nil, fmt.Errorf("idRec is required")
+	}
+
+	path := fmt.Sprintf("/rec/%s", url.PathEscape(recID))
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp RecurrenceResponse
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get recurrence: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// UpdateRecurrence partially updates a recurrence, e.g. to cancel it
+func (c *Client) UpdateRecurrence(ctx context.Context, recID string, req UpdateRecurrenceRequest) (*RecurrenceResponse, error) {
+	if recID == "" {
+		return nil, fmt.Errorf("idRec is required")
+	}
+
+	path := fmt.Sprintf("/rec/%s", url.PathEscape(recID))
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodPatch, path, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp RecurrenceResponse
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to update recurrence: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// ListRecurrences lists recurrences created within a time window with
+// optional filters
+func (c *Client) ListRecurrences(ctx context.Context, params ListRecurrencesParams) (*RecurrenceListResponse, error) {
+	path := "/rec"
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Add query parameters
+	q := httpReq.URL.Query()
+	q.Set("inicio", params.StartDate.Format("2006-01-02T15:04:05Z07:00"))
+	q.Set("fim", params.EndDate.Format("2006-01-02T15:04:05Z07:00"))
+
+	if params.CPF != "" {
+		q.Set("cpf", params.CPF)
+	}
+	if params.CNPJ != "" {
+		q.Set("cnpj", params.CNPJ)
+	}
+	if params.Status != "" {
+		q.Set("status", string(params.Status))
+	}
+	if params.Convenio != "" {
+		q.Set("convenio", params.Convenio)
+	}
+	c.http.SetPagination(q, params.Page, params.PageSize)
+
+	httpReq.URL.RawQuery = q.Encode()
+
+	var resp RecurrenceListResponse
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list recurrences: %w", err)
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,300 @@
+package pixauto
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/pix"
+)
+
+const recurrenceJSON = `{
+	"idRec": "RR1234567820240115abcdefghijk",
+	"vinculo": {"objeto": "Academia", "contrato": "63100862", "devedor": {"cpf": "45164632481", "nome": "Fulano de Tal"}},
+	"calendario": {"dataInicial": "2024-04-01", "periodicidade": "MENSAL"},
+	"valor": {"valorRec": "35.00"},
+	"recebedor": {"cnpj": "00000000000191", "nome": "Banco do Brasil", "convenio": "1234567"},
+	"politicaRetentativa": "PERMITE_3R_7D",
+	"loc": {"id": 108, "location": "pix.example.com/qr/v2/rec/108", "criacao": "2024-01-15T10:00:00Z"},
+	"status": "CRIADA",
+	"atualizacao": [{"status": "CRIADA", "data": "2024-01-15T10:00:00Z"}]
+}`
+
+func TestClient_CreateRecurrence_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Method = %s, want POST", r.Method)
+		}
+		if r.URL.Path != "/rec" {
+			t.Errorf("Path = %s, want /rec", r.URL.Path)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if body["politicaRetentativa"] != RetryPolicyThreeInSevenDays {
+			t.Errorf("politicaRetentativa = %v, want %s", body["politicaRetentativa"], RetryPolicyThreeInSevenDays)
+		}
+		if body["loc"] != float64(108) {
+			t.Errorf("loc = %v, want 108", body["loc"])
+		}
+		calendar, _ := body["calendario"].(map[string]interface{})
+		if calendar["periodicidade"] != PeriodicityMonthly {
+			t.Errorf("periodicidade = %v, want MENSAL", calendar["periodicidade"])
+		}
+		if _, ok := calendar["dataFinal"]; ok {
+			t.Error("dataFinal should be omitted when empty")
+		}
+		value, _ := body["valor"].(map[string]interface{})
+		if value["valorRec"] != "35.00" {
+			t.Errorf("valorRec = %v, want 35.00", value["valorRec"])
+		}
+		link, _ := body["vinculo"].(map[string]interface{})
+		debtor, _ := link["devedor"].(map[string]interface{})
+		if debtor["cpf"] != "45164632481" {
+			t.Errorf("devedor.cpf = %v, want 45164632481", debtor["cpf"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(recurrenceJSON))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	rec, err := client.CreateRecurrence(context.Background(), CreateRecurrenceRequest{
+		Link: RecurrenceLink{
+			Object:   "Academia",
+			Contract: "63100862",
+			Debtor:   pix.Debtor{CPF: "45164632481", Name: "Fulano de Tal"},
+		},
+		Calendar:    RecurrenceCalendar{StartDate: "2024-04-01", Periodicity: PeriodicityMonthly},
+		Value:       &RecurrenceValue{Amount: "35.00"},
+		RetryPolicy: RetryPolicyThreeInSevenDays,
+		LocationID:  108,
+	})
+	if err != nil {
+		t.Fatalf("CreateRecurrence() error = %v", err)
+	}
+	if rec.RecID != "RR1234567820240115abcdefghijk" {
+		t.Errorf("RecID = %s, want RR1234567820240115abcdefghijk", rec.RecID)
+	}
+	if rec.Receiver == nil || rec.Receiver.Convenio != "1234567" {
+		t.Errorf("Receiver = %+v, want convenio 1234567", rec.Receiver)
+	}
+	if rec.Loc == nil || rec.Loc.ID != 108 {
+		t.Errorf("Loc = %+v, want id 108", rec.Loc)
+	}
+	if len(rec.Updates) != 1 || rec.Updates[0].Date.IsZero() {
+		t.Errorf("Updates = %+v, want one dated update", rec.Updates)
+	}
+}
+
+func TestClient_CreateRecurrence_Validation(t *testing.T) {
+	valid := CreateRecurrenceRequest{
+		Link:     RecurrenceLink{Contract: "63100862"},
+		Calendar: RecurrenceCalendar{StartDate: "2024-04-01", Periodicity: PeriodicityMonthly},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*CreateRecurrenceRequest)
+	}{
+		{name: "missing contract", modify: func(r *CreateRecurrenceRequest) { r.Link.Contract = "" }},
+		{name: "missing start date", modify: func(r *CreateRecurrenceRequest) { r.Calendar.StartDate = "" }},
+		{name: "missing periodicity", modify: func(r *CreateRecurrenceRequest) { r.Calendar.Periodicity = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Error("Server should not be called when validation fails")
+			}))
+			defer server.Close()
+
+			req := valid
+			tt.modify(&req)
+
+			client := NewClient(&http.Client{}, server.URL)
+			if _, err := client.CreateRecurrence(context.Background(), req); err == nil {
+				t.Error("CreateRecurrence() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestClient_GetRecurrence_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Method = %s, want GET", r.Method)
+		}
+		if r.URL.Path != "/rec/RR1234567820240115abcdefghijk" {
+			t.Errorf("Path = %s, want /rec/RR1234567820240115abcdefghijk", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(recurrenceJSON))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	rec, err := client.GetRecurrence(context.Background(), "RR1234567820240115abcdefghijk")
+	if err != nil {
+		t.Fatalf("GetRecurrence() error = %v", err)
+	}
+	if rec.Status != "CRIADA" {
+		t.Errorf("Status = %s, want CRIADA", rec.Status)
+	}
+	if rec.Link.Debtor.Name != "Fulano de Tal" {
+		t.Errorf("Debtor.Name = %s, want Fulano de Tal", rec.Link.Debtor.Name)
+	}
+
+	if _, err := client.GetRecurrence(context.Background(), ""); err == nil {
+		t.Error("GetRecurrence() with empty idRec should fail")
+	}
+}
+
+func TestClient_UpdateRecurrence_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("Method = %s, want PATCH", r.Method)
+		}
+		if r.URL.Path != "/rec/RR1234567820240115abcdefghijk" {
+			t.Errorf("Path = %s, want /rec/RR1234567820240115abcdefghijk", r.URL.Path)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if body["status"] != "CANCELADA" {
+			t.Errorf("status = %v, want CANCELADA", body["status"])
+		}
+		for _, field := range []string{"vinculo", "calendario", "loc", "ativacao"} {
+			if _, ok := body[field]; ok {
+				t.Errorf("%s should be omitted when not set", field)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"idRec":"RR1234567820240115abcdefghijk","status":"CANCELADA"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	rec, err := client.UpdateRecurrence(context.Background(), "RR1234567820240115abcdefghijk", UpdateRecurrenceRequest{Status: "CANCELADA"})
+	if err != nil {
+		t.Fatalf("UpdateRecurrence() error = %v", err)
+	}
+	if rec.Status != "CANCELADA" {
+		t.Errorf("Status = %s, want CANCELADA", rec.Status)
+	}
+}
+
+func TestClient_ListRecurrences_QueryParams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rec" {
+			t.Errorf("Path = %s, want /rec", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("inicio") != "2024-01-01T00:00:00Z" {
+			t.Errorf("inicio = %s, want 2024-01-01T00:00:00Z", query.Get("inicio"))
+		}
+		if query.Get("fim") != "2024-01-31T23:59:59Z" {
+			t.Errorf("fim = %s, want 2024-01-31T23:59:59Z", query.Get("fim"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"parametros":{"inicio":"2024-01-01T00:00:00Z","fim":"2024-01-31T23:59:59Z","paginacao":{"paginaAtual":0,"itensPorPagina":100,"quantidadeDePaginas":1,"quantidadeTotalDeItens":1}},"recs":[` + recurrenceJSON + `]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	resp, err := client.ListRecurrences(context.Background(), ListRecurrencesParams{
+		StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("ListRecurrences() error = %v", err)
+	}
+	if len(resp.Recurrences) != 1 {
+		t.Fatalf("len(Recurrences) = %d, want 1", len(resp.Recurrences))
+	}
+	if resp.Parameters.Pagination.TotalItems != 1 {
+		t.Errorf("TotalItems = %d, want 1", resp.Parameters.Pagination.TotalItems)
+	}
+}
+
+func TestClient_ListRecurrences_Filters(t *testing.T) {
+	tests := []struct {
+		name   string
+		params ListRecurrencesParams
+		want   map[string]string
+		absent []string
+	}{
+		{
+			name: "all filters",
+			params: ListRecurrencesParams{
+				CPF:      "45164632481",
+				Status:   RecurrenceStatusApproved,
+				Convenio: "7654321",
+				Page:     2,
+				PageSize: 50,
+			},
+			want: map[string]string{
+				"cpf":                      "45164632481",
+				"status":                   "APROVADA",
+				"convenio":                 "7654321",
+				"paginacao.paginaAtual":    "2",
+				"paginacao.itensPorPagina": "50",
+			},
+			absent: []string{"cnpj"},
+		},
+		{
+			name:   "no filters",
+			params: ListRecurrencesParams{},
+			want:   map[string]string{"paginacao.paginaAtual": "0", "paginacao.itensPorPagina": "100"},
+			absent: []string{"cpf", "cnpj", "status", "convenio"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query := r.URL.Query()
+				for name, want := range tt.want {
+					if got := query.Get(name); got != want {
+						t.Errorf("%s = %q, want %q", name, got, want)
+					}
+				}
+				for _, name := range tt.absent {
+					if query.Has(name) {
+						t.Errorf("%s should not be sent", name)
+					}
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"parametros":{"paginacao":{"paginaAtual":2,"itensPorPagina":50,"quantidadeDePaginas":3,"quantidadeTotalDeItens":120}},"recs":[]}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(&http.Client{}, server.URL)
+
+			resp, err := client.ListRecurrences(context.Background(), tt.params)
+			if err != nil {
+				t.Fatalf("ListRecurrences() error = %v", err)
+			}
+			if resp.Parameters.Pagination.CurrentPage != 2 || resp.Parameters.Pagination.TotalPages != 3 {
+				t.Errorf("Pagination = %+v", resp.Parameters.Pagination)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package pixauto
+
+import (
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/pix"
+)
+
+// Recurrence periodicities (periodicidade)
+const (
+	PeriodicityWeekly     = "SEMANAL"
+	PeriodicityMonthly    = "MENSAL"
+	PeriodicityQuarterly  = "TRIMESTRAL"
+	PeriodicitySemiannual = "SEMESTRAL"
+	PeriodicityAnnual     = "ANUAL"
+)
+
+// Retry policies (politicaRetentativa)
+const (
+	// RetryPolicyNotAllowed disallows retries after a failed settlement
+	RetryPolicyNotAllowed = "NAO_PERMITE"
+	// RetryPolicyThreeInSevenDays allows up to 3 retries within 7 days
+	RetryPolicyThreeInSevenDays = "PERMITE_3R_7D"
+)
+
+// CreateRecurrenceRequest represents a request to create a recurrence (rec)
+type CreateRecurrenceRequest struct {
+	Link        RecurrenceLink        `json:"vinculo"`
+	Calendar    RecurrenceCalendar    `json:"calendario"`
+	Value       *RecurrenceValue      `json:"valor,omitempty"`
+	RetryPolicy string                `json:"politicaRetentativa"`
+	LocationID  int                   `json:"loc,omitempty"`
+	Activation  *RecurrenceActivation `json:"ativacao,omitempty"`
+}
+
+// RecurrenceLink binds the recurrence to the contract with the debtor
+type RecurrenceLink struct {
+	Object   string     `json:"objeto,omitempty"`
+	Contract string     `json:"contrato"`
+	Debtor   pix.Debtor `json:"devedor"`
+}
+
+// RecurrenceCalendar represents the recurrence schedule
+type RecurrenceCalendar struct {
+	StartDate   string `json:"dataInicial"`         // YYYY-MM-DD
+	EndDate     string `json:"dataFinal,omitempty"` // YYYY-MM-DD
+	Periodicity string `json:"periodicidade"`
+}
+
+// RecurrenceValue represents the recurrence amount
+// Set Amount for a fixed value or MinimumAmount for a variable value
+// with a floor defined by the receiver.
+type RecurrenceValue struct {
+	Amount        string `json:"valorRec,omitempty"`
+	MinimumAmount string `json:"valorMinimoRecebedor,omitempty"`
+}
+
+// RecurrenceActivation represents how the debtor approves the recurrence
+type RecurrenceActivation struct {
+	JourneyData *JourneyData `json:"dadosJornada,omitempty"`
+}
+
+// JourneyData references the immediate charge used by the approval journey
+type JourneyData struct {
+	TxID string `json:"txid"`
+}
+
+// UpdateRecurrenceRequest represents a partial update of a recurrence
+// Only non-zero fields are sent.
+type UpdateRecurrenceRequest struct {
+	Status     RecurrenceStatus          `json:"status,omitempty"`
+	Link       *RecurrenceLinkUpdate     `json:"vinculo,omitempty"`
+	Calendar   *RecurrenceCalendarUpdate `json:"calendario,omitempty"`
+	LocationID int                       `json:"loc,omitempty"`
+	Activation *RecurrenceActivation     `json:"ativacao,omitempty"`
+}
+
+// RecurrenceLinkUpdate represents the updatable fields of the link
+type RecurrenceLinkUpdate struct {
+	Debtor *pix.Debtor `json:"devedor,omitempty"`
+}
+
+// RecurrenceCalendarUpdate represents the updatable fields of the calendar
+type RecurrenceCalendarUpdate struct {
+	EndDate string `json:"dataFinal,omitempty"` // YYYY-MM-DD
+}
+
+// Receiver identifies the receiver of the recurrence
+type Receiver struct {
+	CNPJ     string `json:"cnpj,omitempty"`
+	Name     string `json:"nome,omitempty"`
+	Convenio string `json:"convenio,omitempty"`
+}
+
+// RecurrenceResponse represents a recurrence returned by the API
+type RecurrenceResponse struct {
+	RecID       string                `json:"idRec"`
+	Link        RecurrenceLink        `json:"vinculo"`
+	Calendar    RecurrenceCalendar    `json:"calendario"`
+	Value       *RecurrenceValue      `json:"valor,omitempty"`
+	Receiver    *Receiver             `json:"recebedor,omitempty"`
+	RetryPolicy string                `json:"politicaRetentativa"`
+	Loc         *RecurrenceLocation   `json:"loc,omitempty"`
+	Activation  *RecurrenceActivation `json:"ativacao,omitempty"`
+	Status      RecurrenceStatus      `json:"status"`
+	Updates     []StatusUpdate        `json:"atualizacao,omitempty"`
+}
+
+// ListRecurrencesParams represents parameters for listing recurrences
+type ListRecurrencesParams struct {
+	StartDate time.Time        `json:"inicio"`
+	EndDate   time.Time        `json:"fim"`
+	CPF       string           `json:"cpf,omitempty"`
+	CNPJ      string           `json:"cnpj,omitempty"`
+	Status    RecurrenceStatus `json:"status,omitempty"`
+	Page      int              `json:"paginaAtual,omitempty"`
+	PageSize  int              `json:"itensPorPagina,omitempty"`
+
+	// Convenio filters the recurrences of a receiver agreement; it takes
+	// precedence over the client-wide WithConvenio for this request
+	Convenio string `json:"convenio,omitempty"`
+}
+
+// RecurrenceListResponse represents a list of recurrences
+type RecurrenceListResponse struct {
+	Parameters struct {
+		Start      time.Time      `json:"inicio"`
+		End        time.Time      `json:"fim"`
+		Pagination pix.Pagination `json:"paginacao"`
+	} `json:"parametros"`
+	Recurrences []RecurrenceResponse `json:"recs"`
+}
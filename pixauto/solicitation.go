@@ -0,0 +1,82 @@
+package pixauto
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// CreateSolicitation sends a confirmation request for a recurrence to the
+// payer's PSP, starting the payer authorization journey
+func (c *Client) CreateSolicitation(ctx context.Context, req CreateSolicitationRequest) (*SolicitationResponse, error) {
+	if req.RecID == "" {
+		return nil, fmt.Errorf("idRec is required")
+	}
+	if req.Recipient.ISPB == "" {
+		return nil, fmt.Errorf("destinatario.ispbParticipante is required")
+	}
+	if req.Calendar.Expiration.IsZero() {
+		return nil, fmt.Errorf("calendario.dataExpiracaoSolicitacao is required")
+	}
+
+	path := "/solicrec"
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodPost, path, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp SolicitationResponse
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to create solicitation: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// GetSolicitation retrieves a recurrence solicitation by its idSolicRec
+func (c *Client) GetSolicitation(ctx context.Context, solicitationID string) (*SolicitationResponse, error) {
+	if solicitationID == "" {
+		return nil, fmt.Errorf("idSolicRec is required")
+	}
+
+	path := fmt.Sprintf("/solicrec/%s", url.PathEscape(solicitationID))
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp SolicitationResponse
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get solicitation: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// UpdateSolicitation partially updates a recurrence solicitation, e.g. to
+// cancel it before the payer answers
+func (c *Client) UpdateSolicitation(ctx context.Context, solicitationID string, req UpdateSolicitationRequest) (*SolicitationResponse, error) {
+	if solicitationID == "" {
+		return nil, fmt.Errorf("idSolicRec is required")
+	}
+	if req.Status == "" {
+		return nil, fmt.Errorf("status is required")
+	}
+
+	path := fmt.Sprintf("/solicrec/%s", url.PathEscape(solicitationID))
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodPatch, path, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp SolicitationResponse
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, fmt.Errorf("failed to update solicitation: %w", err)
+	}
+
+	return &resp, nil
+}
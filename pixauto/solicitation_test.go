@@ -0,0 +1,179 @@
+package pixauto
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const solicitationJSON = `{
+	"idSolicRec": "SC0000000020240115abcdefghijk",
+	"idRec": "RR1234567820240115abcdefghijk",
+	"calendario": {"dataExpiracaoSolicitacao": "2024-01-20T12:00:00Z"},
+	"destinatario": {"contaCorrente": "12345678", "ispbParticipante": "00000000", "agencia": "0001", "cpf": "45164632481"},
+	"status": "CRIADA",
+	"atualizacao": [{"status": "CRIADA", "data": "2024-01-15T10:00:00Z"}]
+}`
+
+func TestClient_CreateSolicitation_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Method = %s, want POST", r.Method)
+		}
+		if r.URL.Path != "/solicrec" {
+			t.Errorf("Path = %s, want /solicrec", r.URL.Path)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if body["idRec"] != "RR1234567820240115abcdefghijk" {
+			t.Errorf("idRec = %v, want RR1234567820240115abcdefghijk", body["idRec"])
+		}
+		calendar, _ := body["calendario"].(map[string]interface{})
+		if calendar["dataExpiracaoSolicitacao"] != "2024-01-20T12:00:00Z" {
+			t.Errorf("dataExpiracaoSolicitacao = %v, want 2024-01-20T12:00:00Z", calendar["dataExpiracaoSolicitacao"])
+		}
+		recipient, _ := body["destinatario"].(map[string]interface{})
+		if recipient["ispbParticipante"] != "00000000" {
+			t.Errorf("ispbParticipante = %v, want 00000000", recipient["ispbParticipante"])
+		}
+		if _, ok := recipient["cnpj"]; ok {
+			t.Error("cnpj should be omitted when empty")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(solicitationJSON))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	sol, err := client.CreateSolicitation(context.Background(), CreateSolicitationRequest{
+		RecID: "RR1234567820240115abcdefghijk",
+		Recipient: SolicitationRecipient{
+			Account: "12345678",
+			ISPB:    "00000000",
+			Branch:  "0001",
+			CPF:     "45164632481",
+		},
+		Calendar: SolicitationCalendar{Expiration: time.Date(2024, 1, 20, 12, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("CreateSolicitation() error = %v", err)
+	}
+	if sol.SolicitationID != "SC0000000020240115abcdefghijk" {
+		t.Errorf("SolicitationID = %s, want SC0000000020240115abcdefghijk", sol.SolicitationID)
+	}
+	if sol.Status != "CRIADA" {
+		t.Errorf("Status = %s, want CRIADA", sol.Status)
+	}
+}
+
+func TestClient_CreateSolicitation_Validation(t *testing.T) {
+	valid := CreateSolicitationRequest{
+		RecID:     "RR1234567820240115abcdefghijk",
+		Recipient: SolicitationRecipient{ISPB: "00000000"},
+		Calendar:  SolicitationCalendar{Expiration: time.Now().Add(time.Hour)},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*CreateSolicitationRequest)
+	}{
+		{name: "missing idRec", modify: func(r *CreateSolicitationRequest) { r.RecID = "" }},
+		{name: "missing ispb", modify: func(r *CreateSolicitationRequest) { r.Recipient.ISPB = "" }},
+		{name: "missing expiration", modify: func(r *CreateSolicitationRequest) { r.Calendar.Expiration = time.Time{} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Error("Server should not be called when validation fails")
+			}))
+			defer server.Close()
+
+			req := valid
+			tt.modify(&req)
+
+			client := NewClient(&http.Client{}, server.URL)
+			if _, err := client.CreateSolicitation(context.Background(), req); err == nil {
+				t.Error("CreateSolicitation() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestClient_GetSolicitation_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Method = %s, want GET", r.Method)
+		}
+		if r.URL.Path != "/solicrec/SC0000000020240115abcdefghijk" {
+			t.Errorf("Path = %s, want /solicrec/SC0000000020240115abcdefghijk", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(solicitationJSON))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	sol, err := client.GetSolicitation(context.Background(), "SC0000000020240115abcdefghijk")
+	if err != nil {
+		t.Fatalf("GetSolicitation() error = %v", err)
+	}
+	if sol.Recipient.CPF != "45164632481" {
+		t.Errorf("Recipient.CPF = %s, want 45164632481", sol.Recipient.CPF)
+	}
+	if len(sol.Updates) != 1 {
+		t.Errorf("len(Updates) = %d, want 1", len(sol.Updates))
+	}
+
+	if _, err := client.GetSolicitation(context.Background(), ""); err == nil {
+		t.Error("GetSolicitation() with empty idSolicRec should fail")
+	}
+}
+
+func TestClient_UpdateSolicitation_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("Method = %s, want PATCH", r.Method)
+		}
+		if r.URL.Path != "/solicrec/SC0000000020240115abcdefghijk" {
+			t.Errorf("Path = %s, want /solicrec/SC0000000020240115abcdefghijk", r.URL.Path)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if body["status"] != "CANCELADA" {
+			t.Errorf("status = %v, want CANCELADA", body["status"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"idSolicRec":"SC0000000020240115abcdefghijk","status":"CANCELADA"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&http.Client{}, server.URL)
+
+	sol, err := client.UpdateSolicitation(context.Background(), "SC0000000020240115abcdefghijk", UpdateSolicitationRequest{Status: "CANCELADA"})
+	if err != nil {
+		t.Fatalf("UpdateSolicitation() error = %v", err)
+	}
+	if sol.Status != "CANCELADA" {
+		t.Errorf("Status = %s, want CANCELADA", sol.Status)
+	}
+
+	if _, err := client.UpdateSolicitation(context.Background(), "SC0000000020240115abcdefghijk", UpdateSolicitationRequest{}); err == nil {
+		t.Error("UpdateSolicitation() without status should fail")
+	}
+}
@@ -0,0 +1,42 @@
+package pixauto
+
+import "time"
+
+// CreateSolicitationRequest represents a request to send a recurrence
+// confirmation request (solicrec) to the payer's PSP
+type CreateSolicitationRequest struct {
+	RecID     string                `json:"idRec"`
+	Recipient SolicitationRecipient `json:"destinatario"`
+	Calendar  SolicitationCalendar  `json:"calendario"`
+}
+
+// SolicitationRecipient identifies the payer account that must authorize
+// the recurrence
+type SolicitationRecipient struct {
+	Account string `json:"contaCorrente,omitempty"`
+	ISPB    string `json:"ispbParticipante"`
+	Branch  string `json:"agencia,omitempty"`
+	CPF     string `json:"cpf,omitempty"`
+	CNPJ    string `json:"cnpj,omitempty"`
+}
+
+// SolicitationCalendar represents when the solicitation expires
+type SolicitationCalendar struct {
+	Expiration time.Time `json:"dataExpiracaoSolicitacao"`
+}
+
+// UpdateSolicitationRequest represents a partial update of a solicitation,
+// e.g. Status "CANCELADA"
+type UpdateSolicitationRequest struct {
+	Status string `json:"status"`
+}
+
+// SolicitationResponse represents a recurrence solicitation
+type SolicitationResponse struct {
+	SolicitationID string                `json:"idSolicRec"`
+	RecID          string                `json:"idRec"`
+	Calendar       SolicitationCalendar  `json:"calendario"`
+	Recipient      SolicitationRecipient `json:"destinatario"`
+	Status         string                `json:"status"`
+	Updates        []StatusUpdate        `json:"atualizacao,omitempty"`
+}
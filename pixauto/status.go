@@ -0,0 +1,94 @@
+package pixauto
+
+// RecurrenceStatus is the status of a recurrence (rec)
+type RecurrenceStatus string
+
+// Recurrence statuses
+const (
+	// RecurrenceStatusCreated is a recurrence waiting for the payer approval
+	RecurrenceStatusCreated RecurrenceStatus = "CRIADA"
+	// RecurrenceStatusApproved is a recurrence approved by the payer
+	RecurrenceStatusApproved RecurrenceStatus = "APROVADA"
+	// RecurrenceStatusRejected is a recurrence rejected by the payer
+	RecurrenceStatusRejected RecurrenceStatus = "REJEITADA"
+	// RecurrenceStatusExpired is a recurrence not approved in time
+	RecurrenceStatusExpired RecurrenceStatus = "EXPIRADA"
+	// RecurrenceStatusCancelled is a recurrence cancelled by the payer or
+	// the receiver
+	RecurrenceStatusCancelled RecurrenceStatus = "CANCELADA"
+)
+
+// IsTerminal reports whether the recurrence can no longer change status
+func (s RecurrenceStatus) IsTerminal() bool {
+	switch s {
+	case RecurrenceStatusRejected, RecurrenceStatusExpired, RecurrenceStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// ChargeStatus is the status of a recurring charge (cobr)
+type ChargeStatus string
+
+// Recurring charge statuses
+const (
+	ChargeStatusCreated   ChargeStatus = "CRIADA"
+	ChargeStatusActive    ChargeStatus = "ATIVA"
+	ChargeStatusConcluded ChargeStatus = "CONCLUIDA"
+	ChargeStatusExpired   ChargeStatus = "EXPIRADA"
+	ChargeStatusRejected  ChargeStatus = "REJEITADA"
+	ChargeStatusCancelled ChargeStatus = "CANCELADA"
+)
+
+// IsTerminal reports whether the charge can no longer change status
+func (s ChargeStatus) IsTerminal() bool {
+	switch s {
+	case ChargeStatusConcluded, ChargeStatusExpired, ChargeStatusRejected, ChargeStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// AttemptType is the type of a settlement attempt of a recurring charge
+type AttemptType string
+
+// Charge attempt types
+const (
+	// AttemptTypeScheduled is the attempt scheduled when the charge is created
+	AttemptTypeScheduled AttemptType = "AGND"
+	// AttemptTypeNotScheduled is an attempt outside the original schedule
+	AttemptTypeNotScheduled AttemptType = "NTAG"
+	// AttemptTypeIntraday is a retry within the settlement day
+	AttemptTypeIntraday AttemptType = "RIFL"
+)
+
+// IsRetry reports whether the attempt retries a failed settlement instead
+// of being the originally scheduled one
+func (t AttemptType) IsRetry() bool {
+	return t == AttemptTypeNotScheduled || t == AttemptTypeIntraday
+}
+
+// AttemptStatus is the status of a settlement attempt
+type AttemptStatus string
+
+// Charge attempt statuses
+const (
+	AttemptStatusRequested          AttemptStatus = "SOLICITADA"
+	AttemptStatusScheduled          AttemptStatus = "AGENDADA"
+	AttemptStatusSettled            AttemptStatus = "LIQUIDADA"
+	AttemptStatusCancelled          AttemptStatus = "CANCELADA"
+	AttemptStatusRejected           AttemptStatus = "REJEITADA"
+	AttemptStatusOperationalFailure AttemptStatus = "FALHA_OPERACIONAL"
+)
+
+// IsTerminal reports whether the attempt can no longer change status
+func (s AttemptStatus) IsTerminal() bool {
+	switch s {
+	case AttemptStatusSettled, AttemptStatusCancelled, AttemptStatusRejected, AttemptStatusOperationalFailure:
+		return true
+	default:
+		return false
+	}
+}
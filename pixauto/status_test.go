@@ -0,0 +1,49 @@
+package pixauto
+
+import "testing"
+
+func TestStatuses_IsTerminal(t *testing.T) {
+	tests := []struct {
+		name   string
+		status interface{ IsTerminal() bool }
+		want   bool
+	}{
+		{name: "recurrence created", status: RecurrenceStatusCreated, want: false},
+		{name: "recurrence approved", status: RecurrenceStatusApproved, want: false},
+		{name: "recurrence rejected", status: RecurrenceStatusRejected, want: true},
+		{name: "recurrence expired", status: RecurrenceStatusExpired, want: true},
+		{name: "recurrence cancelled", status: RecurrenceStatusCancelled, want: true},
+		{name: "recurrence unknown", status: RecurrenceStatus("NOVA"), want: false},
+		{name: "charge active", status: ChargeStatusActive, want: false},
+		{name: "charge concluded", status: ChargeStatusConcluded, want: true},
+		{name: "charge cancelled", status: ChargeStatusCancelled, want: true},
+		{name: "attempt scheduled", status: AttemptStatusScheduled, want: false},
+		{name: "attempt settled", status: AttemptStatusSettled, want: true},
+		{name: "attempt operational failure", status: AttemptStatusOperationalFailure, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.status.IsTerminal(); got != tt.want {
+				t.Errorf("IsTerminal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttemptType_IsRetry(t *testing.T) {
+	tests := []struct {
+		typ  AttemptType
+		want bool
+	}{
+		{typ: AttemptTypeScheduled, want: false},
+		{typ: AttemptTypeNotScheduled, want: true},
+		{typ: AttemptTypeIntraday, want: true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.typ.IsRetry(); got != tt.want {
+			t.Errorf("%s.IsRetry() = %v, want %v", tt.typ, got, tt.want)
+		}
+	}
+}
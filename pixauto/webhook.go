@@ -0,0 +1,106 @@
+package pixauto
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/pericles-luz/go-bb-pix/internal/callback"
+	"github.com/pericles-luz/go-bb-pix/pix"
+)
+
+// ParseChargeWebhookPayload decodes a recurring charge webhook callback body
+func ParseChargeWebhookPayload(r io.Reader) (*ChargeWebhookPayload, error) {
+	var payload ChargeWebhookPayload
+	if err := json.NewDecoder(r).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook payload: %w", err)
+	}
+
+	return &payload, nil
+}
+
+// ChargeWebhookFunc processes the recurring charges notified in a webhook
+// callback. Returning an error makes the handler answer with 500 so BB
+// retries the notification.
+type ChargeWebhookFunc func(ctx context.Context, charges []RecurringCharge) error
+
+// NewChargeWebhookHandler returns an http.Handler that receives recurring
+// charge callbacks and passes the charges to fn. It accepts the same options
+// and answers with the same status codes as pix.NewWebhookHandler.
+func NewChargeWebhookHandler(fn ChargeWebhookFunc, opts ...pix.WebhookHandlerOption) http.Handler {
+	options := callback.DefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return callback.NewHandler(options, webhookCharges, fn)
+}
+
+// webhookCharges validates and returns the charges of a payload
+func webhookCharges(payload ChargeWebhookPayload) ([]RecurringCharge, error) {
+	for _, charge := range payload.Charges {
+		if charge.TxID == "" || charge.RecID == "" {
+			return nil, errors.New("charge without txid or idRec")
+		}
+	}
+	return payload.Charges, nil
+}
+
+// ChargeFunc processes a single recurring charge
+type ChargeFunc func(ctx context.Context, charge RecurringCharge) error
+
+// ChargeDispatcher routes notified charges to functions registered by
+// status. Its Dispatch method can be passed to NewChargeWebhookHandler.
+type ChargeDispatcher struct {
+	mu       sync.RWMutex
+	handlers map[ChargeStatus]ChargeFunc
+	fallback ChargeFunc
+}
+
+// NewChargeDispatcher creates an empty ChargeDispatcher
+func NewChargeDispatcher() *ChargeDispatcher {
+	return &ChargeDispatcher{handlers: make(map[ChargeStatus]ChargeFunc)}
+}
+
+// On registers fn for charges with the given status
+func (d *ChargeDispatcher) On(status ChargeStatus, fn ChargeFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlers[status] = fn
+}
+
+// Default registers fn for charges whose status has no function
+func (d *ChargeDispatcher) Default(fn ChargeFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.fallback = fn
+}
+
+// Dispatch calls the registered function for every charge, in order, and
+// stops at the first error. Charges without a matching function are skipped.
+func (d *ChargeDispatcher) Dispatch(ctx context.Context, charges []RecurringCharge) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, charge := range charges {
+		fn, ok := d.handlers[charge.Status]
+		if !ok {
+			fn = d.fallback
+		}
+		if fn == nil {
+			continue
+		}
+
+		if err := fn(ctx, charge); err != nil {
+			return fmt.Errorf("failed to process charge %s: %w", charge.TxID, err)
+		}
+	}
+
+	return nil
+}
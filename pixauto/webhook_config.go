@@ -0,0 +1,108 @@
+package pixauto
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/pericles-luz/go-bb-pix/pix"
+)
+
+// Webhook configuration paths
+const (
+	recurrenceWebhookPath = "/webhookrec"
+	chargeWebhookPath     = "/webhookcobr"
+)
+
+// ConfigureRecurrenceWebhook configures the webhook URL notified when
+// recurrences (rec) change status
+func (c *Client) ConfigureRecurrenceWebhook(ctx context.Context, config pix.WebhookConfig) error {
+	if err := c.configureWebhook(ctx, recurrenceWebhookPath, config); err != nil {
+		return fmt.Errorf("failed to configure recurrence webhook: %w", err)
+	}
+	return nil
+}
+
+// GetRecurrenceWebhook retrieves the recurrence webhook configuration
+func (c *Client) GetRecurrenceWebhook(ctx context.Context) (*pix.WebhookConfig, error) {
+	config, err := c.getWebhook(ctx, recurrenceWebhookPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recurrence webhook: %w", err)
+	}
+	return config, nil
+}
+
+// DeleteRecurrenceWebhook removes the recurrence webhook configuration
+func (c *Client) DeleteRecurrenceWebhook(ctx context.Context) error {
+	if err := c.deleteWebhook(ctx, recurrenceWebhookPath); err != nil {
+		return fmt.Errorf("failed to delete recurrence webhook: %w", err)
+	}
+	return nil
+}
+
+// ConfigureChargeWebhook configures the webhook URL notified when recurring
+// charges (cobr) are created or change status; see NewChargeWebhookHandler
+func (c *Client) ConfigureChargeWebhook(ctx context.Context, config pix.WebhookConfig) error {
+	if err := c.configureWebhook(ctx, chargeWebhookPath, config); err != nil {
+		return fmt.Errorf("failed to configure charge webhook: %w", err)
+	}
+	return nil
+}
+
+// GetChargeWebhook retrieves the recurring charge webhook configuration
+func (c *Client) GetChargeWebhook(ctx context.Context) (*pix.WebhookConfig, error) {
+	config, err := c.getWebhook(ctx, chargeWebhookPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get charge webhook: %w", err)
+	}
+	return config, nil
+}
+
+// DeleteChargeWebhook removes the recurring charge webhook configuration
+func (c *Client) DeleteChargeWebhook(ctx context.Context) error {
+	if err := c.deleteWebhook(ctx, chargeWebhookPath); err != nil {
+		return fmt.Errorf("failed to delete charge webhook: %w", err)
+	}
+	return nil
+}
+
+// configureWebhook sets the webhook URL at path
+func (c *Client) configureWebhook(ctx context.Context, path string, config pix.WebhookConfig) error {
+	if config.WebhookURL == "" {
+		return fmt.Errorf("webhook url is required")
+	}
+
+	body := pix.WebhookConfig{WebhookURL: config.WebhookURL}
+
+	httpReq, err := c.http.NewRequest(ctx, http.MethodPut, path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	return c.http.Do(httpReq, nil)
+}
+
+// getWebhook retrieves the webhook configuration at path
+func (c *Client) getWebhook(ctx context.Context, path string) (*pix.WebhookConfig, error) {
+	httpReq, err := c.http.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp pix.WebhookConfig
+	if err := c.http.Do(httpReq, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// deleteWebhook removes the webhook configuration at path
+func (c *Client) deleteWebhook(ctx context.Context, path string) error {
+	httpReq, err := c.http.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	return c.http.Do(httpReq, nil)
+}
@@ -0,0 +1,100 @@
+package pixauto
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/pix"
+)
+
+func TestClient_WebhookConfiguration(t *testing.T) {
+	type webhookAPI struct {
+		configure func(*Client, context.Context, pix.WebhookConfig) error
+		get       func(*Client, context.Context) (*pix.WebhookConfig, error)
+		delete    func(*Client, context.Context) error
+	}
+
+	tests := []struct {
+		name string
+		path string
+		api  webhookAPI
+	}{
+		{
+			name: "recurrence",
+			path: "/webhookrec",
+			api:  webhookAPI{(*Client).ConfigureRecurrenceWebhook, (*Client).GetRecurrenceWebhook, (*Client).DeleteRecurrenceWebhook},
+		},
+		{
+			name: "recurring charge",
+			path: "/webhookcobr",
+			api:  webhookAPI{(*Client).ConfigureChargeWebhook, (*Client).GetChargeWebhook, (*Client).DeleteChargeWebhook},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var methods []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				methods = append(methods, r.Method)
+				if r.URL.Path != tt.path {
+					t.Errorf("Path = %s, want %s", r.URL.Path, tt.path)
+				}
+
+				switch r.Method {
+				case http.MethodPut:
+					var body map[string]interface{}
+					if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+						t.Fatalf("Failed to decode request: %v", err)
+					}
+					if len(body) != 1 || body["webhookUrl"] != "https://app.example.com/pixauto" {
+						t.Errorf("body = %v, want only webhookUrl", body)
+					}
+					w.WriteHeader(http.StatusNoContent)
+				case http.MethodGet:
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"webhookUrl":"https://app.example.com/pixauto","criacao":"2024-01-15T10:00:00Z"}`))
+				case http.MethodDelete:
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(&http.Client{}, server.URL)
+			ctx := context.Background()
+
+			if err := tt.api.configure(client, ctx, pix.WebhookConfig{WebhookURL: "https://app.example.com/pixauto", Creation: time.Now()}); err != nil {
+				t.Fatalf("configure error = %v", err)
+			}
+
+			config, err := tt.api.get(client, ctx)
+			if err != nil {
+				t.Fatalf("get error = %v", err)
+			}
+			if config.WebhookURL != "https://app.example.com/pixauto" || config.Creation.IsZero() {
+				t.Errorf("config = %+v, want URL and creation", config)
+			}
+
+			if err := tt.api.delete(client, ctx); err != nil {
+				t.Fatalf("delete error = %v", err)
+			}
+
+			if err := tt.api.configure(client, ctx, pix.WebhookConfig{}); err == nil {
+				t.Error("configure without URL should fail")
+			}
+
+			want := []string{http.MethodPut, http.MethodGet, http.MethodDelete}
+			if len(methods) != len(want) {
+				t.Fatalf("methods = %v, want %v", methods, want)
+			}
+			for i := range want {
+				if methods[i] != want[i] {
+					t.Errorf("methods = %v, want %v", methods, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package pixauto
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const chargePayload = `{"cobsr":[
+	{"idRec":"RR1234567820240115abcdefghijk","txid":"3136957d93134f2184b369e8f1c0729d","status":"CONCLUIDA",
+	 "atualizacao":[{"status":"CRIADA","data":"2024-01-15T10:00:00Z"},{"status":"CONCLUIDA","data":"2024-01-20T08:00:00Z"}],
+	 "tentativas":[{"dataLiquidacao":"2024-01-20","tipo":"AGND","endToEndId":"E12345678202401200800abcdef12345","status":"LIQUIDADA"}]},
+	{"idRec":"RR1234567820240115abcdefghijk","txid":"4136957d93134f2184b369e8f1c0729d","status":"REJEITADA"}
+]}`
+
+func TestParseChargeWebhookPayload(t *testing.T) {
+	payload, err := ParseChargeWebhookPayload(strings.NewReader(chargePayload))
+	if err != nil {
+		t.Fatalf("ParseChargeWebhookPayload() error = %v", err)
+	}
+
+	if len(payload.Charges) != 2 {
+		t.Fatalf("len(Charges) = %d, want 2", len(payload.Charges))
+	}
+
+	charge := payload.Charges[0]
+	if charge.Status != "CONCLUIDA" || len(charge.Updates) != 2 {
+		t.Errorf("charge = %+v", charge)
+	}
+	if len(charge.Attempts) != 1 || charge.Attempts[0].Type != AttemptTypeScheduled {
+		t.Errorf("Attempts = %+v, want one AGND attempt", charge.Attempts)
+	}
+	if charge.Updates[1].Date.IsZero() {
+		t.Error("update date not decoded")
+	}
+}
+
+func TestNewChargeWebhookHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		fnErr      error
+		wantStatus int
+		wantCalled bool
+	}{
+		{"valid payload", chargePayload, nil, http.StatusOK, true},
+		{"missing idRec", `{"cobsr":[{"txid":"abc","status":"ATIVA"}]}`, nil, http.StatusBadRequest, false},
+		{"malformed", `{"cobsr":`, nil, http.StatusBadRequest, false},
+		{"callback error", chargePayload, errors.New("boom"), http.StatusInternalServerError, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := NewChargeWebhookHandler(func(ctx context.Context, charges []RecurringCharge) error {
+				called = true
+				return tt.fnErr
+			})
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook/cobr", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+func TestChargeDispatcher(t *testing.T) {
+	var (
+		concluded []string
+		other     []ChargeStatus
+	)
+
+	d := NewChargeDispatcher()
+	d.On(ChargeStatusConcluded, func(ctx context.Context, charge RecurringCharge) error {
+		concluded = append(concluded, charge.TxID)
+		return nil
+	})
+	d.Default(func(ctx context.Context, charge RecurringCharge) error {
+		other = append(other, charge.Status)
+		return nil
+	})
+
+	payload, _ := ParseChargeWebhookPayload(strings.NewReader(chargePayload))
+	if err := d.Dispatch(context.Background(), payload.Charges); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+
+	if len(concluded) != 1 || concluded[0] != "3136957d93134f2184b369e8f1c0729d" {
+		t.Errorf("concluded = %v", concluded)
+	}
+	if len(other) != 1 || other[0] != "REJEITADA" {
+		t.Errorf("other = %v, want [REJEITADA]", other)
+	}
+
+	failing := NewChargeDispatcher()
+	failing.On("REJEITADA", func(ctx context.Context, charge RecurringCharge) error {
+		return errors.New("notify failed")
+	})
+	if err := failing.Dispatch(context.Background(), payload.Charges); err == nil {
+		t.Error("Dispatch() expected error")
+	}
+}
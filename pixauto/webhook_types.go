@@ -0,0 +1,15 @@
+package pixauto
+
+import "time"
+
+// ChargeWebhookPayload is the payload BB posts to the webhook URL when
+// recurring charges (cobr) are created or change status
+type ChargeWebhookPayload struct {
+	Charges []RecurringCharge `json:"cobsr"`
+}
+
+// StatusUpdate records when a recurrence, charge or attempt reached a status
+type StatusUpdate struct {
+	Status string    `json:"status"`
+	Date   time.Time `json:"data"`
+}
@@ -0,0 +1,175 @@
+// Package reconcile compares the payments recorded by an application ledger
+// against the payments BB received in a period, reporting missing,
+// mismatched-value and unexpected payments
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/pix"
+)
+
+// Entry is a payment expected by the application
+// At least one of TxID and EndToEndID must be set. Value is a decimal
+// amount such as "10.50".
+type Entry interface {
+	TxID() string
+	EndToEndID() string
+	Value() string
+}
+
+// Ledger provides the entries the application recorded for a period
+type Ledger interface {
+	Entries(ctx context.Context, start, end time.Time) ([]Entry, error)
+}
+
+// PaymentSource lists the payments received in a period; *pix.Client
+// implements it
+type PaymentSource interface {
+	ListAllPayments(ctx context.Context, params pix.ListPaymentsParams, opts ...pix.ListAllOption) iter.Seq2[pix.PaymentResponse, error]
+}
+
+// Match is a ledger entry paired with the payment received for it
+type Match struct {
+	Entry   Entry
+	Payment pix.PaymentResponse
+}
+
+// Report is the result of a reconciliation
+type Report struct {
+	// Matched are the entries paid with the expected value
+	Matched []Match
+	// Mismatched are the entries paid with a different value
+	Mismatched []Match
+	// Missing are the entries without a payment
+	Missing []Entry
+	// Unexpected are the payments without a ledger entry
+	Unexpected []pix.PaymentResponse
+}
+
+// OK reports whether every entry was paid with the expected value and no
+// unexpected payment was received
+func (r *Report) OK() bool {
+	return len(r.Mismatched) == 0 && len(r.Missing) == 0 && len(r.Unexpected) == 0
+}
+
+// Run reconciles the ledger entries of [start, end] against every payment
+// listed by source for the same period
+func Run(ctx context.Context, source PaymentSource, ledger Ledger, start, end time.Time) (*Report, error) {
+	entries, err := ledger.Entries(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ledger entries: %w", err)
+	}
+
+	var payments []pix.PaymentResponse
+	for payment, err := range source.ListAllPayments(ctx, pix.ListPaymentsParams{StartDate: start, EndDate: end}) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list payments: %w", err)
+		}
+		payments = append(payments, payment)
+	}
+
+	report := Compare(entries, payments)
+	return &report, nil
+}
+
+// Compare pairs entries with payments
+// An entry with an endToEndId matches the payment with the same id;
+// otherwise it matches the first unpaired payment of its txid. Values are
+// compared as amounts, so "10.5" matches "10.50".
+func Compare(entries []Entry, payments []pix.PaymentResponse) Report {
+	var report Report
+
+	byE2E := make(map[string]int, len(payments))
+	byTxID := make(map[string][]int)
+	for i, payment := range payments {
+		byE2E[payment.EndToEndID] = i
+		if payment.TxID != "" {
+			byTxID[payment.TxID] = append(byTxID[payment.TxID], i)
+		}
+	}
+
+	paired := make([]bool, len(payments))
+	for _, entry := range entries {
+		i, ok := findPayment(entry, byE2E, byTxID, paired)
+		if !ok {
+			report.Missing = append(report.Missing, entry)
+			continue
+		}
+		paired[i] = true
+
+		match := Match{Entry: entry, Payment: payments[i]}
+		if sameValue(entry.Value(), payments[i].Value) {
+			report.Matched = append(report.Matched, match)
+		} else {
+			report.Mismatched = append(report.Mismatched, match)
+		}
+	}
+
+	for i, payment := range payments {
+		if !paired[i] {
+			report.Unexpected = append(report.Unexpected, payment)
+		}
+	}
+
+	return report
+}
+
+// findPayment returns the index of the unpaired payment of entry
+func findPayment(entry Entry, byE2E map[string]int, byTxID map[string][]int, paired []bool) (int, bool) {
+	if e2eid := entry.EndToEndID(); e2eid != "" {
+		i, ok := byE2E[e2eid]
+		return i, ok && !paired[i]
+	}
+
+	for _, i := range byTxID[entry.TxID()] {
+		if !paired[i] {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// sameValue reports whether two decimal amounts are equal
+// Amounts that cannot be parsed are compared as text.
+func sameValue(a, b string) bool {
+	ca, okA := parseCents(a)
+	cb, okB := parseCents(b)
+	if !okA || !okB {
+		return strings.TrimSpace(a) == strings.TrimSpace(b)
+	}
+	return ca == cb
+}
+
+// parseCents parses a decimal amount with up to two decimal places into
+// cents
+func parseCents(s string) (int64, bool) {
+	units, frac, _ := strings.Cut(strings.TrimSpace(s), ".")
+	if units == "" || len(frac) > 2 {
+		return 0, false
+	}
+
+	u, err := strconv.ParseUint(units, 10, 63)
+	if err != nil || u > math.MaxInt64/100-1 {
+		return 0, false
+	}
+
+	var f uint64
+	if frac != "" {
+		f, err = strconv.ParseUint(frac, 10, 8)
+		if err != nil {
+			return 0, false
+		}
+		if len(frac) == 1 {
+			f *= 10
+		}
+	}
+
+	return int64(u*100 + f), true
+}
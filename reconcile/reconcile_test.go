@@ -0,0 +1,149 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/pix"
+)
+
+// entry is a test ledger entry
+type entry struct {
+	txID, e2eID, value string
+}
+
+func (e entry) TxID() string       { return e.txID }
+func (e entry) EndToEndID() string { return e.e2eID }
+func (e entry) Value() string      { return e.value }
+
+// ledgerFunc adapts a function to Ledger
+type ledgerFunc func(ctx context.Context, start, end time.Time) ([]Entry, error)
+
+func (f ledgerFunc) Entries(ctx context.Context, start, end time.Time) ([]Entry, error) {
+	return f(ctx, start, end)
+}
+
+func TestCompare(t *testing.T) {
+	entries := []Entry{
+		entry{e2eID: "E1", value: "10.00"},
+		entry{txID: "tx2", value: "20.5"},
+		entry{txID: "tx3", value: "30.00"},
+		entry{e2eID: "E9", value: "90.00"},
+		entry{txID: "tx4", value: "5.00"},
+		entry{txID: "tx4", value: "5.00"},
+	}
+	payments := []pix.PaymentResponse{
+		{EndToEndID: "E1", Value: "10.00"},
+		{EndToEndID: "E2", TxID: "tx2", Value: "20.50"},
+		{EndToEndID: "E3", TxID: "tx3", Value: "29.99"},
+		{EndToEndID: "E4", TxID: "tx4", Value: "5.00"},
+		{EndToEndID: "E5", Value: "1.00"},
+	}
+
+	report := Compare(entries, payments)
+
+	if got := matchedIDs(report.Matched); got != "[E1 E2 E4]" {
+		t.Errorf("Matched = %s, want [E1 E2 E4]", got)
+	}
+	if got := matchedIDs(report.Mismatched); got != "[E3]" {
+		t.Errorf("Mismatched = %s, want [E3]", got)
+	}
+	if len(report.Missing) != 2 || report.Missing[0].EndToEndID() != "E9" || report.Missing[1].TxID() != "tx4" {
+		t.Errorf("Missing = %v, want E9 and the second tx4", report.Missing)
+	}
+	if len(report.Unexpected) != 1 || report.Unexpected[0].EndToEndID != "E5" {
+		t.Errorf("Unexpected = %v, want [E5]", report.Unexpected)
+	}
+	if report.OK() {
+		t.Error("OK() = true, want false")
+	}
+}
+
+func TestCompare_AllMatched(t *testing.T) {
+	report := Compare(
+		[]Entry{entry{e2eID: "E1", value: "10"}},
+		[]pix.PaymentResponse{{EndToEndID: "E1", Value: "10.00"}},
+	)
+	if !report.OK() {
+		t.Errorf("report = %+v, want OK", report)
+	}
+}
+
+func TestSameValue(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"10.50", "10.5", true},
+		{"10", "10.00", true},
+		{" 1.00", "1.00", true},
+		{"10.50", "10.05", false},
+		{"10.501", "10.50", false},
+		{"abc", "abc", true},
+		{"-1.00", "1.00", false},
+	}
+
+	for _, tt := range tests {
+		if got := sameValue(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameValue(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pix" {
+			t.Errorf("Path = %s, want /pix", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"pix":[{"endToEndId":"E1","txid":"tx1","valor":"10.00"},{"endToEndId":"E2","valor":"2.00"}]}`))
+	}))
+	defer server.Close()
+
+	client := pix.NewClient(&http.Client{}, server.URL)
+
+	t.Run("report", func(t *testing.T) {
+		ledger := ledgerFunc(func(ctx context.Context, gotStart, gotEnd time.Time) ([]Entry, error) {
+			if !gotStart.Equal(start) || !gotEnd.Equal(end) {
+				t.Errorf("Entries(%v, %v), want the reconciled period", gotStart, gotEnd)
+			}
+			return []Entry{entry{txID: "tx1", value: "10.00"}}, nil
+		})
+
+		report, err := Run(context.Background(), client, ledger, start, end)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if len(report.Matched) != 1 || len(report.Unexpected) != 1 || report.Unexpected[0].EndToEndID != "E2" {
+			t.Errorf("report = %+v, want tx1 matched and E2 unexpected", report)
+		}
+	})
+
+	t.Run("ledger error", func(t *testing.T) {
+		errLedger := errors.New("database down")
+		ledger := ledgerFunc(func(ctx context.Context, start, end time.Time) ([]Entry, error) {
+			return nil, errLedger
+		})
+
+		if _, err := Run(context.Background(), client, ledger, start, end); !errors.Is(err, errLedger) {
+			t.Errorf("Run() error = %v, want %v", err, errLedger)
+		}
+	})
+}
+
+// matchedIDs returns the endToEndIds of matches
+func matchedIDs(matches []Match) string {
+	ids := make([]string, len(matches))
+	for i, m := range matches {
+		ids[i] = m.Payment.EndToEndID
+	}
+	return fmt.Sprint(ids)
+}
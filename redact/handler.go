@@ -0,0 +1,102 @@
+package redact
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+// handler is a slog.Handler that redacts records before passing them on
+type handler struct {
+	next     slog.Handler
+	redactor *Redactor
+}
+
+// Handler wraps next so every record has its message and attributes
+// redacted before being handled
+func (r *Redactor) Handler(next slog.Handler) slog.Handler {
+	return &handler{next: next, redactor: r}
+}
+
+// Enabled implements slog.Handler
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, h.redactor.String(record.Message), record.PC)
+	record.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.attr(a))
+		return true
+	})
+
+	return h.next.Handle(ctx, redacted)
+}
+
+// WithAttrs implements slog.Handler
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.attr(a)
+	}
+
+	return &handler{next: h.next.WithAttrs(redacted), redactor: h.redactor}
+}
+
+// WithGroup implements slog.Handler
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{next: h.next.WithGroup(name), redactor: h.redactor}
+}
+
+// attr redacts a single attribute
+func (h *handler) attr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			redacted[i] = h.attr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+	}
+
+	if rule, ok := h.redactor.field(a.Key); ok {
+		return slog.String(a.Key, rule.replace(a.Value.String()))
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, h.redactor.String(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, h.redactor.String(err.Error()))
+		}
+		if redacted, ok := h.anyValue(a.Value.Any()); ok {
+			return slog.String(a.Key, redacted)
+		}
+	}
+
+	return a
+}
+
+// anyValue redacts a value of kind Any such as a struct, map or slice
+// The value is checked in the forms the JSON and text handlers print, and is
+// replaced by the first redacted text only when one of them holds personal
+// data; otherwise ok is false and the value is logged as is.
+func (h *handler) anyValue(v any) (redacted string, ok bool) {
+	var texts []string
+	if b, err := json.Marshal(v); err == nil {
+		texts = append(texts, string(b))
+	}
+	texts = append(texts, fmt.Sprintf("%+v", v))
+
+	for _, text := range texts {
+		if redacted := h.redactor.String(text); redacted != text {
+			return redacted, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,141 @@
+// Package redact masks personal data (CPF, names, keys...) in log records
+// and error messages, so applications can meet LGPD data-minimization
+// requirements without post-processing their logs
+package redact
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Mask replaces values redacted without a custom replacement
+const Mask = "[REDACTED]"
+
+// Rule is a redaction rule
+// Pattern rules rewrite every match found in messages, string attributes and
+// error texts. Field rules rewrite the whole value of log attributes whose
+// key is in Fields (compared case-insensitively).
+type Rule struct {
+	Pattern *regexp.Regexp
+	Fields  []string
+
+	// Replace returns the replacement of a match or field value; nil
+	// replaces it with Mask
+	Replace func(s string) string
+}
+
+// replace applies the rule replacement to s
+func (r Rule) replace(s string) string {
+	if r.Replace == nil {
+		return Mask
+	}
+	return r.Replace(s)
+}
+
+// Pattern returns a rule that rewrites every match of re
+// A nil replace replaces the match with Mask.
+func Pattern(re *regexp.Regexp, replace func(match string) string) Rule {
+	return Rule{Pattern: re, Replace: replace}
+}
+
+// Fields returns a rule that rewrites the value of log attributes with the
+// given keys. A nil replace replaces the value with Mask.
+func Fields(replace func(value string) string, keys ...string) Rule {
+	return Rule{Fields: keys, Replace: replace}
+}
+
+// cpfPattern matches CPFs with or without punctuation
+var cpfPattern = regexp.MustCompile(`\b\d{3}\.?\d{3}\.?\d{3}-?\d{2}\b`)
+
+// CPF returns a rule that masks CPFs except for the last two digits,
+// e.g. 123.456.789-09 becomes ***.***.***-09
+func CPF() Rule {
+	return Pattern(cpfPattern, MaskDigits(2))
+}
+
+// MaskDigits returns a replacement that masks every digit with '*' except
+// for the last keep digits, preserving punctuation
+func MaskDigits(keep int) func(string) string {
+	return func(s string) string {
+		b := []byte(s)
+		seen := 0
+		for i := len(b) - 1; i >= 0; i-- {
+			if b[i] < '0' || b[i] > '9' {
+				continue
+			}
+			seen++
+			if seen > keep {
+				b[i] = '*'
+			}
+		}
+		return string(b)
+	}
+}
+
+// Redactor applies a set of rules to strings, errors and log records
+type Redactor struct {
+	patterns []Rule
+	fields   map[string]Rule
+}
+
+// New creates a Redactor with the given rules
+// Rules are applied in order; for field rules the last rule registered for
+// a key wins.
+func New(rules ...Rule) *Redactor {
+	r := &Redactor{fields: make(map[string]Rule)}
+	for _, rule := range rules {
+		if rule.Pattern != nil {
+			r.patterns = append(r.patterns, rule)
+		}
+		for _, key := range rule.Fields {
+			r.fields[strings.ToLower(key)] = rule
+		}
+	}
+	return r
+}
+
+// String applies the pattern rules to s
+func (r *Redactor) String(s string) string {
+	for _, rule := range r.patterns {
+		s = rule.Pattern.ReplaceAllStringFunc(s, rule.replace)
+	}
+	return s
+}
+
+// field returns the field rule registered for key
+func (r *Redactor) field(key string) (Rule, bool) {
+	rule, ok := r.fields[strings.ToLower(key)]
+	return rule, ok
+}
+
+// Error returns an error whose message has the pattern rules applied
+// The original error stays reachable through errors.Is and errors.As.
+func (r *Redactor) Error(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := err.Error()
+	redacted := r.String(msg)
+	if redacted == msg {
+		return err
+	}
+
+	return &redactedError{err: err, msg: redacted}
+}
+
+// redactedError is an error with a redacted message
+type redactedError struct {
+	err error
+	msg string
+}
+
+// Error implements the error interface
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the original error
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,172 @@
+package redact
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRedactor_String(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []Rule
+		input string
+		want  string
+	}{
+		{
+			name:  "formatted cpf",
+			rules: []Rule{CPF()},
+			input: "devedor 123.456.789-09 não encontrado",
+			want:  "devedor ***.***.***-09 não encontrado",
+		},
+		{
+			name:  "plain cpf in url",
+			rules: []Rule{CPF()},
+			input: "GET /cob?cpf=12345678909&status=ATIVA",
+			want:  "GET /cob?cpf=*********09&status=ATIVA",
+		},
+		{
+			name:  "cnpj is not a cpf",
+			rules: []Rule{CPF()},
+			input: "cnpj 12345678000195",
+			want:  "cnpj 12345678000195",
+		},
+		{
+			name:  "custom pattern with default mask",
+			rules: []Rule{Pattern(regexp.MustCompile(`[\w.]+@[\w.]+`), nil)},
+			input: "chave fulano@example.com",
+			want:  "chave " + Mask,
+		},
+		{
+			name:  "rules applied in order",
+			rules: []Rule{CPF(), Pattern(regexp.MustCompile(`\*+`), func(string) string { return "#" })},
+			input: "12345678909",
+			want:  "#09",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(tt.rules...).String(tt.input)
+			if got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+var errSentinel = errors.New("cpf 12345678909 inválido")
+
+func TestRedactor_Error(t *testing.T) {
+	r := New(CPF())
+
+	err := r.Error(fmt.Errorf("failed to create qr code: %w", errSentinel))
+	if got := err.Error(); got != "failed to create qr code: cpf *********09 inválido" {
+		t.Errorf("Error() = %q", got)
+	}
+	if !errors.Is(err, errSentinel) {
+		t.Error("redacted error should wrap the original error")
+	}
+
+	plain := errors.New("nothing to hide")
+	if r.Error(plain) != plain {
+		t.Error("errors without matches should be returned unchanged")
+	}
+	if r.Error(nil) != nil {
+		t.Error("Error(nil) should be nil")
+	}
+}
+
+func TestRedactor_Handler(t *testing.T) {
+	var buf bytes.Buffer
+	r := New(CPF(), Fields(nil, "nome"), Fields(MaskDigits(4), "chave"))
+	logger := slog.New(r.Handler(slog.NewTextHandler(&buf, nil)))
+
+	logger.With("chave", "+5561999998888").
+		WithGroup("devedor").
+		Info("cobrança para 123.456.789-09",
+			"nome", "Fulano de Tal",
+			"cpf", "12345678909",
+			"error", errors.New("cpf 12345678909 bloqueado"),
+			slog.Group("endereco", "NOME", "Fulano"),
+			"valor", 10.5,
+		)
+
+	out := buf.String()
+	for _, leaked := range []string{"123.456.789", "1234567890", "Fulano", "99999"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("output leaks %q: %s", leaked, out)
+		}
+	}
+	for _, want := range []string{
+		"***.***.***-09",
+		"chave=+*********8888",
+		"devedor.nome=" + Mask,
+		"devedor.cpf=*********09",
+		`devedor.error="cpf *********09 bloqueado"`,
+		"devedor.endereco.NOME=" + Mask,
+		"devedor.valor=10.5",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q: %s", want, out)
+		}
+	}
+}
+
+func TestRedactor_Handler_AnyValues(t *testing.T) {
+	type devedor struct {
+		Nome string
+		CPF  string
+	}
+	type cobranca struct {
+		TxID  string
+		Valor float64
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(New(CPF()).Handler(slog.NewJSONHandler(&buf, nil)))
+
+	logger.Info("cobrança criada",
+		"devedor", devedor{Nome: "Fulano", CPF: "123.456.789-09"},
+		"devedores", map[string]*devedor{"a": {CPF: "12345678909"}},
+		"cobranca", cobranca{TxID: "abc", Valor: 10.5},
+	)
+
+	out := buf.String()
+	for _, leaked := range []string{"123.456.789", "1234567890"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("output leaks %q: %s", leaked, out)
+		}
+	}
+	for _, want := range []string{
+		`"devedor":"{\"Nome\":\"Fulano\",\"CPF\":\"***.***.***-09\"}"`,
+		// Values without personal data keep their original form
+		`"cobranca":{"TxID":"abc","Valor":10.5}`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q: %s", want, out)
+		}
+	}
+}
+
+func TestMaskDigits(t *testing.T) {
+	tests := []struct {
+		keep  int
+		input string
+		want  string
+	}{
+		{keep: 2, input: "123.456.789-09", want: "***.***.***-09"},
+		{keep: 0, input: "12-34", want: "**-**"},
+		{keep: 5, input: "123", want: "123"},
+	}
+
+	for _, tt := range tests {
+		if got := MaskDigits(tt.keep)(tt.input); got != tt.want {
+			t.Errorf("MaskDigits(%d)(%q) = %q, want %q", tt.keep, tt.input, got, tt.want)
+		}
+	}
+}
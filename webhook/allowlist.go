@@ -0,0 +1,160 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// AllowlistOption is a functional option for IPAllowlist
+type AllowlistOption func(*allowlistOptions)
+
+// allowlistOptions holds the unparsed allowlist settings
+type allowlistOptions struct {
+	trustedProxies []string
+}
+
+// WithTrustedProxies sets the CIDR ranges (or single addresses) of the
+// reverse proxies in front of the receiver. X-Forwarded-For is only honored
+// for requests coming from these addresses.
+func WithTrustedProxies(cidrs ...string) AllowlistOption {
+	return func(opts *allowlistOptions) {
+		opts.trustedProxies = append(opts.trustedProxies, cidrs...)
+	}
+}
+
+// ipAllowlist rejects requests whose client address is not allowed
+type ipAllowlist struct {
+	next    http.Handler
+	allowed []netip.Prefix
+	proxies []netip.Prefix
+}
+
+// IPAllowlist returns a middleware that rejects with 403 the requests whose
+// client address is outside cidrs, for example the ranges BB publishes for
+// webhook delivery. It is meant as defense in depth when mTLS is terminated
+// upstream.
+//
+// The client address is the connection's remote address. When the request
+// comes from a trusted proxy, X-Forwarded-For is read from right to left and
+// the first address that is not a trusted proxy is used instead. In-process
+// replays of HandlerDispatcher are let through.
+func IPAllowlist(next http.Handler, cidrs []string, opts ...AllowlistOption) (http.Handler, error) {
+	var options allowlistOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if len(cidrs) == 0 {
+		return nil, errors.New("at least one allowed range is required")
+	}
+
+	allowed, err := parsePrefixes(cidrs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid allowed range: %w", err)
+	}
+
+	proxies, err := parsePrefixes(options.trustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxy: %w", err)
+	}
+
+	return &ipAllowlist{next: next, allowed: allowed, proxies: proxies}, nil
+}
+
+// ServeHTTP implements http.Handler
+func (a *ipAllowlist) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, replay := ReplayedEvent(r.Context()); replay {
+		a.next.ServeHTTP(w, r)
+		return
+	}
+
+	addr, ok := a.clientAddr(r)
+	if !ok || !containsAddr(a.allowed, addr) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	a.next.ServeHTTP(w, r)
+}
+
+// clientAddr returns the address of the client that originated the request
+func (a *ipAllowlist) clientAddr(r *http.Request) (netip.Addr, bool) {
+	addr, ok := parseAddr(r.RemoteAddr)
+	if !ok {
+		return netip.Addr{}, false
+	}
+
+	if !containsAddr(a.proxies, addr) {
+		return addr, true
+	}
+
+	// Walk the proxy chain from the closest hop outwards
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, ok := parseAddr(strings.TrimSpace(hops[i]))
+		if !ok {
+			return netip.Addr{}, false
+		}
+		addr = hop
+		if !containsAddr(a.proxies, hop) {
+			break
+		}
+	}
+
+	return addr, true
+}
+
+// parsePrefixes parses CIDR ranges; single addresses become /32 or /128
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+
+		if strings.Contains(value, "/") {
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return prefixes, nil
+}
+
+// parseAddr parses an address with or without a port
+func parseAddr(value string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+// containsAddr reports whether any prefix contains addr
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIPAllowlist(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := IPAllowlist(ok, []string{"170.66.0.0/16", "2001:db8::1"}, WithTrustedProxies("10.0.0.0/8"))
+	if err != nil {
+		t.Fatalf("IPAllowlist() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		wantStatus   int
+	}{
+		{"allowed direct", "170.66.1.2:443", nil, http.StatusOK},
+		{"allowed IPv6", "[2001:db8::1]:443", nil, http.StatusOK},
+		{"denied direct", "203.0.113.5:443", nil, http.StatusForbidden},
+		{"spoofed header from untrusted peer", "203.0.113.5:443", []string{"170.66.1.2"}, http.StatusForbidden},
+		{"allowed behind proxy", "10.0.0.2:5000", []string{"170.66.1.2"}, http.StatusOK},
+		{"denied behind proxy", "10.0.0.2:5000", []string{"203.0.113.5"}, http.StatusForbidden},
+		{"client spoofs leftmost hop", "10.0.0.2:5000", []string{"170.66.1.2, 203.0.113.5"}, http.StatusForbidden},
+		{"proxy chain", "10.0.0.2:5000", []string{"170.66.1.2", "10.0.0.3"}, http.StatusOK},
+		{"malformed header", "10.0.0.2:5000", []string{"not-an-ip"}, http.StatusForbidden},
+		{"proxy without header", "10.0.0.2:5000", nil, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/webhook", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestIPAllowlist_InvalidRanges(t *testing.T) {
+	ok := http.NotFoundHandler()
+
+	if _, err := IPAllowlist(ok, nil); err == nil {
+		t.Error("expected error without ranges")
+	}
+	if _, err := IPAllowlist(ok, []string{"300.0.0.0/8"}); err == nil {
+		t.Error("expected error for invalid range")
+	}
+	if _, err := IPAllowlist(ok, []string{"10.0.0.0/8"}, WithTrustedProxies("proxy")); err == nil {
+		t.Error("expected error for invalid proxy")
+	}
+}
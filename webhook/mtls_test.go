@@ -0,0 +1,209 @@
+package webhook
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testCA issues certificates for mTLS tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create CA: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return &testCA{cert: cert, key: key, pool: pool}
+}
+
+// issue creates a certificate signed by the CA
+func (ca *testCA) issue(t *testing.T, commonName string, usage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	leaf, _ := x509.ParseCertificate(der)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestRequireClientCert(t *testing.T) {
+	ca := newTestCA(t)
+	other := newTestCA(t)
+
+	bbCert := ca.issue(t, "webhook.bb.com.br", x509.ExtKeyUsageClientAuth)
+	foreignCert := other.issue(t, "webhook.bb.com.br", x509.ExtKeyUsageClientAuth)
+	wrongName := ca.issue(t, "attacker.example.com", x509.ExtKeyUsageClientAuth)
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name       string
+		state      *tls.ConnectionState
+		opts       []ClientCertOption
+		wantStatus int
+	}{
+		{
+			name:       "plain HTTP",
+			opts:       []ClientCertOption{WithClientCertPool(ca.pool)},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "no client certificate",
+			state:      &tls.ConnectionState{},
+			opts:       []ClientCertOption{WithClientCertPool(ca.pool)},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "valid certificate",
+			state:      &tls.ConnectionState{PeerCertificates: []*x509.Certificate{bbCert.Leaf}},
+			opts:       []ClientCertOption{WithClientCertPool(ca.pool), WithAllowedCommonNames("webhook.bb.com.br")},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "untrusted issuer",
+			state:      &tls.ConnectionState{PeerCertificates: []*x509.Certificate{foreignCert.Leaf}},
+			opts:       []ClientCertOption{WithClientCertPool(ca.pool)},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "common name not allowed",
+			state:      &tls.ConnectionState{PeerCertificates: []*x509.Certificate{wrongName.Leaf}},
+			opts:       []ClientCertOption{WithClientCertPool(ca.pool), WithAllowedCommonNames("webhook.bb.com.br")},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "unverified without pool",
+			state:      &tls.ConnectionState{PeerCertificates: []*x509.Certificate{bbCert.Leaf}},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "verified during handshake",
+			state: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{bbCert.Leaf},
+				VerifiedChains:   [][]*x509.Certificate{{bbCert.Leaf, ca.cert}},
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/webhook", nil)
+			req.TLS = tt.state
+			rec := httptest.NewRecorder()
+
+			RequireClientCert(ok, tt.opts...).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestServer_WithClientCAs(t *testing.T) {
+	ca := newTestCA(t)
+	serverCert := ca.issue(t, "127.0.0.1", x509.ExtKeyUsageServerAuth)
+	clientCert := ca.issue(t, "webhook.bb.com.br", x509.ExtKeyUsageClientAuth)
+
+	handler := RequireClientCert(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	srv, err := NewServer("", handler,
+		WithTLSConfig(&tls.Config{Certificates: []tls.Certificate{serverCert}}),
+		WithClientCAs(ca.pool),
+	)
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.Serve(ctx, ln)
+
+	url := "https://" + ln.Addr().String() + "/webhook"
+	newClient := func(certs ...tls.Certificate) *http.Client {
+		return &http.Client{
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: ca.pool, Certificates: certs}},
+			Timeout:   5 * time.Second,
+		}
+	}
+
+	if resp, err := newClient().Post(url, "application/json", nil); err == nil {
+		resp.Body.Close()
+		t.Error("request without client certificate should fail the handshake")
+	}
+
+	resp, err := newClient(clientCert).Post(url, "application/json", nil)
+	if err != nil {
+		t.Fatalf("request with client certificate failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+}
@@ -0,0 +1,260 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Default queue settings
+const (
+	DefaultQueueWorkers     = 4
+	DefaultQueueSize        = 100
+	DefaultQueueMaxAttempts = 5
+	DefaultQueueBackoff     = time.Second
+	DefaultQueueMaxBackoff  = time.Minute
+)
+
+var (
+	// ErrQueueFull is returned by Enqueue when the buffer is full. Returning
+	// it from the webhook handler makes BB retry the notification later.
+	ErrQueueFull = errors.New("webhook queue is full")
+
+	// ErrQueueClosed is returned by Enqueue after Close was called
+	ErrQueueClosed = errors.New("webhook queue is closed")
+)
+
+// ProcessFunc processes a queued item. Returning an error schedules a retry.
+type ProcessFunc[T any] func(ctx context.Context, item T) error
+
+// DeadLetterFunc receives items that failed every attempt, together with the
+// last error
+type DeadLetterFunc[T any] func(ctx context.Context, item T, err error)
+
+// QueueOption is a functional option for configuring a Queue
+type QueueOption func(*queueOptions)
+
+// queueOptions holds the settings of a Queue
+type queueOptions struct {
+	workers     int
+	size        int
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	logger      *slog.Logger
+}
+
+// WithWorkers sets how many items are processed concurrently
+// Default: 4
+func WithWorkers(n int) QueueOption {
+	return func(opts *queueOptions) {
+		if n > 0 {
+			opts.workers = n
+		}
+	}
+}
+
+// WithQueueSize sets how many items can wait for a worker
+// Default: 100
+func WithQueueSize(n int) QueueOption {
+	return func(opts *queueOptions) {
+		if n > 0 {
+			opts.size = n
+		}
+	}
+}
+
+// WithMaxAttempts sets how many times an item is processed before it is
+// sent to the dead-letter function
+// Default: 5
+func WithMaxAttempts(n int) QueueOption {
+	return func(opts *queueOptions) {
+		if n > 0 {
+			opts.maxAttempts = n
+		}
+	}
+}
+
+// WithBackoff sets the delay before the first retry and its upper bound.
+// The delay doubles after every failed attempt.
+// Default: 1s, capped at 1 minute
+func WithBackoff(initial, max time.Duration) QueueOption {
+	return func(opts *queueOptions) {
+		if initial > 0 {
+			opts.backoff = initial
+		}
+		if max > 0 {
+			opts.maxBackoff = max
+		}
+	}
+}
+
+// WithQueueLogger sets the logger used to report failed attempts
+func WithQueueLogger(logger *slog.Logger) QueueOption {
+	return func(opts *queueOptions) {
+		opts.logger = logger
+	}
+}
+
+// Queue processes webhook payloads asynchronously with bounded concurrency.
+// Enqueue has the signature expected by pix.NewWebhookHandler, so BB gets an
+// immediate acknowledgement while payloads are processed in the background:
+//
+//	queue := webhook.NewQueue(process, deadLetter)
+//	handler := pix.NewWebhookHandler(queue.Enqueue)
+type Queue[T any] struct {
+	process    ProcessFunc[T]
+	deadLetter DeadLetterFunc[T]
+	opts       queueOptions
+
+	items  chan T
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewQueue creates a queue and starts its workers. deadLetter may be nil,
+// in which case permanently failed items are only logged.
+func NewQueue[T any](process ProcessFunc[T], deadLetter DeadLetterFunc[T], opts ...QueueOption) *Queue[T] {
+	options := queueOptions{
+		workers:     DefaultQueueWorkers,
+		size:        DefaultQueueSize,
+		maxAttempts: DefaultQueueMaxAttempts,
+		backoff:     DefaultQueueBackoff,
+		maxBackoff:  DefaultQueueMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	q := &Queue[T]{
+		process:    process,
+		deadLetter: deadLetter,
+		opts:       options,
+		items:      make(chan T, options.size),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+
+	q.wg.Add(options.workers)
+	for i := 0; i < options.workers; i++ {
+		go q.work()
+	}
+
+	return q
+}
+
+// Enqueue adds an item without blocking. It returns ErrQueueFull when the
+// buffer is full and ErrQueueClosed after Close.
+func (q *Queue[T]) Enqueue(ctx context.Context, item T) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	select {
+	case q.items <- item:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Len returns the number of items waiting for a worker
+func (q *Queue[T]) Len() int {
+	return len(q.items)
+}
+
+// Close stops accepting items and waits for the queued ones to be processed.
+// If ctx expires first, pending retries are abandoned and every unprocessed
+// item is sent to the dead-letter function with context.Canceled.
+func (q *Queue[T]) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.items)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// work processes items until the queue is closed and drained
+func (q *Queue[T]) work() {
+	defer q.wg.Done()
+
+	for item := range q.items {
+		q.handle(item)
+	}
+}
+
+// handle processes an item, retrying with backoff until it succeeds or the
+// attempts are exhausted
+func (q *Queue[T]) handle(item T) {
+	backoff := q.opts.backoff
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = q.ctx.Err(); err != nil {
+			break
+		}
+
+		if err = q.process(q.ctx, item); err == nil {
+			return
+		}
+
+		if q.opts.logger != nil {
+			q.opts.logger.Warn("webhook processing failed",
+				slog.Int("attempt", attempt),
+				slog.String("error", err.Error()),
+			)
+		}
+
+		if attempt >= q.opts.maxAttempts {
+			break
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-q.ctx.Done():
+			timer.Stop()
+		}
+
+		backoff *= 2
+		if backoff > q.opts.maxBackoff {
+			backoff = q.opts.maxBackoff
+		}
+	}
+
+	if q.opts.logger != nil {
+		q.opts.logger.Error("webhook sent to dead letter", slog.String("error", err.Error()))
+	}
+	if q.deadLetter != nil {
+		// The queue context may already be canceled during shutdown
+		q.deadLetter(context.WithoutCancel(q.ctx), item, err)
+	}
+}
@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestQueue_ProcessesWithRetry(t *testing.T) {
+	var attempts atomic.Int32
+	process := func(ctx context.Context, item string) error {
+		if attempts.Add(1) < 3 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	}
+
+	var deadLetters atomic.Int32
+	deadLetter := func(ctx context.Context, item string, err error) {
+		deadLetters.Add(1)
+	}
+
+	q := NewQueue(process, deadLetter, WithWorkers(1), WithBackoff(time.Millisecond, 5*time.Millisecond))
+
+	if err := q.Enqueue(context.Background(), "payload"); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	if err := q.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+	if got := deadLetters.Load(); got != 0 {
+		t.Errorf("dead letters = %d, want 0", got)
+	}
+}
+
+func TestQueue_DeadLetter(t *testing.T) {
+	failure := errors.New("permanent failure")
+
+	var attempts atomic.Int32
+	process := func(ctx context.Context, item int) error {
+		attempts.Add(1)
+		return failure
+	}
+
+	var mu sync.Mutex
+	var dead []int
+	var lastErr error
+	deadLetter := func(ctx context.Context, item int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		dead = append(dead, item)
+		lastErr = err
+	}
+
+	q := NewQueue(process, deadLetter, WithMaxAttempts(2), WithBackoff(time.Millisecond, time.Millisecond))
+	q.Enqueue(context.Background(), 42)
+
+	if err := q.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+	if len(dead) != 1 || dead[0] != 42 {
+		t.Errorf("dead letters = %v, want [42]", dead)
+	}
+	if !errors.Is(lastErr, failure) {
+		t.Errorf("dead letter error = %v, want %v", lastErr, failure)
+	}
+}
+
+func TestQueue_FullAndClosed(t *testing.T) {
+	release := make(chan struct{})
+	process := func(ctx context.Context, item int) error {
+		<-release
+		return nil
+	}
+
+	q := NewQueue(process, nil, WithWorkers(1), WithQueueSize(1))
+
+	// The first item is picked up by the worker, the second fills the buffer
+	q.Enqueue(context.Background(), 1)
+	deadline := time.Now().Add(time.Second)
+	for q.Len() != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if err := q.Enqueue(context.Background(), 2); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	if err := q.Enqueue(context.Background(), 3); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Enqueue() error = %v, want ErrQueueFull", err)
+	}
+
+	close(release)
+	q.Close(context.Background())
+
+	if err := q.Enqueue(context.Background(), 4); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Enqueue() after Close error = %v, want ErrQueueClosed", err)
+	}
+}
+
+func TestQueue_CloseTimeoutDeadLetters(t *testing.T) {
+	process := func(ctx context.Context, item int) error {
+		return errors.New("always failing")
+	}
+
+	var dead atomic.Int32
+	deadLetter := func(ctx context.Context, item int, err error) {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("dead letter error = %v, want context.Canceled", err)
+		}
+		dead.Add(1)
+	}
+
+	q := NewQueue(process, deadLetter, WithWorkers(1), WithBackoff(time.Hour, time.Hour))
+	q.Enqueue(context.Background(), 1)
+	q.Enqueue(context.Background(), 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := q.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Close() error = %v, want DeadlineExceeded", err)
+	}
+	if got := dead.Load(); got != 2 {
+		t.Errorf("dead letters = %d, want 2", got)
+	}
+}
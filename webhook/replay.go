@@ -0,0 +1,224 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	httpclient "github.com/pericles-luz/go-bb-pix/internal/http"
+	"github.com/pericles-luz/go-bb-pix/pix"
+)
+
+// ReplayHeader is set on replayed requests to the ID of the stored event
+// It is informational only: any sender can set it, so it is not trusted by
+// Record; use ReplayedEvent to recognize in-process replays.
+const ReplayHeader = "X-Webhook-Replay"
+
+// replayContextKey is the context key of the event replayed by
+// HandlerDispatcher; being unexported, senders cannot set it
+type replayContextKey struct{}
+
+// ReplayedEvent returns the ID of the stored event a request replays, when
+// it was dispatched in-process by HandlerDispatcher
+func ReplayedEvent(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(replayContextKey{}).(string)
+	return id, ok
+}
+
+// Record returns a middleware that stores every POSTed callback in store
+// before passing it to next. If the event cannot be stored the request is
+// answered with 500 so BB delivers it again.
+// Place it behind RequireClientCert and IPAllowlist so only authenticated
+// callbacks are stored: the in-process replays of HandlerDispatcher pass
+// those middlewares, as their events already did when received.
+func Record(store EventStore, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, replay := ReplayedEvent(r.Context()); r.Method != http.MethodPost || replay {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, pix.DefaultWebhookMaxBodySize))
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "failed to read payload", http.StatusBadRequest)
+			return
+		}
+
+		event := NewEvent(body, time.Now())
+		event.Path = r.URL.Path
+		event.RemoteAddr = r.RemoteAddr
+		if err := store.Save(r.Context(), event); err != nil {
+			http.Error(w, "failed to store notification", http.StatusInternalServerError)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Dispatcher delivers a stored event again
+type Dispatcher interface {
+	Dispatch(ctx context.Context, event Event) error
+}
+
+// DispatcherFunc adapts a function to the Dispatcher interface
+type DispatcherFunc func(ctx context.Context, event Event) error
+
+// Dispatch implements Dispatcher
+func (f DispatcherFunc) Dispatch(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+// HandlerDispatcher dispatches events in-process to handler, typically the
+// same handler chain that serves BB callbacks
+// The requests carry the address the event was received from and are
+// marked as replays (see ReplayedEvent), so Record does not store them again
+// and RequireClientCert and IPAllowlist let them through.
+func HandlerDispatcher(handler http.Handler) Dispatcher {
+	return DispatcherFunc(func(ctx context.Context, event Event) error {
+		ctx = context.WithValue(ctx, replayContextKey{}, event.ID)
+		req, err := newReplayRequest(ctx, eventPath(event), event)
+		if err != nil {
+			return err
+		}
+		req.RemoteAddr = event.RemoteAddr
+
+		rec := &statusRecorder{header: http.Header{}, status: http.StatusOK}
+		handler.ServeHTTP(rec, req)
+
+		if rec.status < 200 || rec.status >= 300 {
+			return fmt.Errorf("handler responded with status %d", rec.status)
+		}
+		return nil
+	})
+}
+
+// HTTPDispatcher dispatches events by POSTing them to url. The stored path
+// is not appended; url must point at the receiving endpoint.
+// The receiver cannot tell these requests from new callbacks: they must
+// pass its mTLS and allowlist checks, and Record stores them again.
+// If client is nil, http.DefaultClient is used.
+func HTTPDispatcher(url string, client *http.Client) Dispatcher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return DispatcherFunc(func(ctx context.Context, event Event) error {
+		req, err := newReplayRequest(ctx, url, event)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to send event: %w", err)
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+		}
+		return nil
+	})
+}
+
+// ReplayFailure is an event that could not be dispatched
+type ReplayFailure struct {
+	Event Event
+	Err   error
+}
+
+// ReplayResult summarizes a replay
+type ReplayResult struct {
+	Dispatched int
+	Failed     []ReplayFailure
+}
+
+// Replay dispatches the stored events matching filter, in the order they
+// were received. Failed events are reported in the result and do not stop
+// the replay; an error is only returned when the store cannot be read or
+// ctx is canceled.
+func Replay(ctx context.Context, store EventStore, filter EventFilter, d Dispatcher) (ReplayResult, error) {
+	var result ReplayResult
+
+	events, err := store.List(ctx, filter)
+	if err != nil {
+		return result, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	for _, event := range events {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		if err := d.Dispatch(ctx, event); err != nil {
+			result.Failed = append(result.Failed, ReplayFailure{Event: event, Err: err})
+			continue
+		}
+		result.Dispatched++
+	}
+
+	return result, nil
+}
+
+// newReplayRequest builds the POST request used to redeliver an event
+func newReplayRequest(ctx context.Context, url string, event Event) (*http.Request, error) {
+	if err := httpclient.CheckHeaderValue(ReplayHeader, event.ID); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(event.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(ReplayHeader, event.ID)
+
+	return req, nil
+}
+
+// eventPath returns the path the event was received at
+func eventPath(event Event) string {
+	if event.Path == "" {
+		return "/"
+	}
+	return event.Path
+}
+
+// statusRecorder is a minimal http.ResponseWriter that keeps the status code
+type statusRecorder struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+}
+
+// Header implements http.ResponseWriter
+func (r *statusRecorder) Header() http.Header {
+	return r.header
+}
+
+// Write implements http.ResponseWriter
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return len(b), nil
+}
+
+// WriteHeader implements http.ResponseWriter
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+}
@@ -0,0 +1,165 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testTime = time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+func TestRecordAndReplay(t *testing.T) {
+	store := NewMemoryStore()
+
+	var received []string
+	failE2 := true
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, string(body))
+
+		if failE2 && strings.Contains(string(body), "E2") {
+			http.Error(w, "downstream bug", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	recorder := Record(store, handler)
+
+	for _, payload := range []string{
+		`{"pix":[{"endToEndId":"E1","valor":"10.00"}]}`,
+		`{"pix":[{"endToEndId":"E2","valor":"20.00"}]}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/webhook/pix", strings.NewReader(payload))
+		rec := httptest.NewRecorder()
+		recorder.ServeHTTP(rec, req)
+	}
+
+	events, _ := store.List(context.Background(), EventFilter{})
+	if len(events) != 2 {
+		t.Fatalf("stored %d events, want 2", len(events))
+	}
+	if events[0].Path != "/webhook/pix" {
+		t.Errorf("Path = %q, want /webhook/pix", events[0].Path)
+	}
+
+	// The downstream bug is fixed; replay the failed payment through the
+	// same handler chain, which must not record it again
+	failE2 = false
+	received = nil
+
+	result, err := Replay(context.Background(), store, EventFilter{EndToEndID: "E2"}, HandlerDispatcher(recorder))
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if result.Dispatched != 1 || len(result.Failed) != 0 {
+		t.Errorf("result = %+v, want 1 dispatched", result)
+	}
+	if len(received) != 1 || !strings.Contains(received[0], "E2") {
+		t.Errorf("received = %v, want the E2 payload", received)
+	}
+
+	events, _ = store.List(context.Background(), EventFilter{})
+	if len(events) != 2 {
+		t.Errorf("stored %d events after replay, want 2", len(events))
+	}
+}
+
+func TestRecord_IgnoresReplayHeader(t *testing.T) {
+	store := NewMemoryStore()
+	handler := Record(store, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, replay := ReplayedEvent(r.Context()); replay {
+			t.Error("request from the network treated as a replay")
+		}
+	}))
+
+	// A sender cannot skip the recording with the header
+	req := httptest.NewRequest(http.MethodPost, "/webhook/pix", strings.NewReader(`{"pix":[{"endToEndId":"E1"}]}`))
+	req.Header.Set(ReplayHeader, "forged")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	events, _ := store.List(context.Background(), EventFilter{})
+	if len(events) != 1 {
+		t.Errorf("stored %d events, want 1", len(events))
+	}
+}
+
+func TestHandlerDispatcher_PassesPeerChecks(t *testing.T) {
+	store := NewMemoryStore()
+
+	var replayedID, remoteAddr string
+	handler := Record(store, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		replayedID, _ = ReplayedEvent(r.Context())
+		remoteAddr = r.RemoteAddr
+	}))
+	allowlist, err := IPAllowlist(handler, []string{"192.0.2.0/24"})
+	if err != nil {
+		t.Fatalf("IPAllowlist() error = %v", err)
+	}
+	chain := RequireClientCert(allowlist)
+
+	// Received over mTLS from an allowed address
+	req := httptest.NewRequest(http.MethodPost, "/webhook/pix", strings.NewReader(`{"pix":[{"endToEndId":"E1"}]}`))
+	req.RemoteAddr = "192.0.2.10:4321"
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	events, _ := store.List(context.Background(), EventFilter{})
+	if len(events) != 1 || events[0].RemoteAddr != "192.0.2.10:4321" {
+		t.Fatalf("events = %+v, want one from 192.0.2.10:4321", events)
+	}
+
+	// The replay has no TLS state, yet it passes the checks of the chain
+	if err := HandlerDispatcher(chain).Dispatch(context.Background(), events[0]); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	if replayedID != events[0].ID || remoteAddr != "192.0.2.10:4321" {
+		t.Errorf("replay = %q from %q, want %q from 192.0.2.10:4321", replayedID, remoteAddr, events[0].ID)
+	}
+	if events, _ := store.List(context.Background(), EventFilter{}); len(events) != 1 {
+		t.Errorf("stored %d events after replay, want 1", len(events))
+	}
+
+	// Requests from the network still need the certificate
+	rec := httptest.NewRecorder()
+	chain.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook/pix", strings.NewReader(`{}`)))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+}
+
+func TestReplay_ReportsFailures(t *testing.T) {
+	store := NewMemoryStore()
+	store.Save(context.Background(), NewEvent([]byte(`{"pix":[{"endToEndId":"E1"}]}`), testTime))
+	store.Save(context.Background(), NewEvent([]byte(`{"pix":[{"endToEndId":"E2"}]}`), testTime))
+
+	var headers []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Get(ReplayHeader))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	result, err := Replay(context.Background(), store, EventFilter{}, HTTPDispatcher(server.URL, nil))
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if result.Dispatched != 0 || len(result.Failed) != 2 {
+		t.Errorf("result = %+v, want 2 failures", result)
+	}
+	for _, h := range headers {
+		if h == "" {
+			t.Errorf("%s header not set", ReplayHeader)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Replay(ctx, store, EventFilter{}, HTTPDispatcher(server.URL, nil)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Replay() with canceled context error = %v, want context.Canceled", err)
+	}
+}
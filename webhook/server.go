@@ -0,0 +1,285 @@
+// Package webhook provides helpers to receive BB PIX webhook callbacks
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Default server timeouts
+const (
+	DefaultReadHeaderTimeout = 10 * time.Second
+	DefaultReadTimeout       = 30 * time.Second
+	DefaultWriteTimeout      = 30 * time.Second
+	DefaultIdleTimeout       = 120 * time.Second
+	DefaultShutdownTimeout   = 15 * time.Second
+)
+
+// acmeTLSALPNProtocol is the ALPN protocol used by the ACME tls-alpn-01 challenge
+const acmeTLSALPNProtocol = "acme-tls/1"
+
+// CertManager provides certificates obtained through ACME.
+// *autocert.Manager from golang.org/x/crypto/acme/autocert satisfies this
+// interface, so it can be used without this package depending on it.
+type CertManager interface {
+	GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error)
+	HTTPHandler(fallback http.Handler) http.Handler
+}
+
+// ServerOption is a functional option for configuring the Server
+type ServerOption func(*Server)
+
+// Server runs a webhook receiver over HTTPS with sane timeouts and
+// graceful shutdown
+type Server struct {
+	addr    string
+	handler http.Handler
+
+	certFile       string
+	keyFile        string
+	getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)
+	certManager    CertManager
+	tlsConfig      *tls.Config
+	challengeAddr  string
+	clientCAs      *x509.CertPool
+
+	readHeaderTimeout time.Duration
+	readTimeout       time.Duration
+	writeTimeout      time.Duration
+	idleTimeout       time.Duration
+	shutdownTimeout   time.Duration
+}
+
+// WithCertificateFiles serves the certificate and key stored in PEM files
+func WithCertificateFiles(certFile, keyFile string) ServerOption {
+	return func(s *Server) {
+		s.certFile = certFile
+		s.keyFile = keyFile
+	}
+}
+
+// WithGetCertificate sets a callback that returns the certificate for each
+// TLS handshake
+func WithGetCertificate(fn func(*tls.ClientHelloInfo) (*tls.Certificate, error)) ServerOption {
+	return func(s *Server) {
+		s.getCertificate = fn
+	}
+}
+
+// WithCertManager obtains certificates automatically through ACME, for
+// example with an *autocert.Manager. The tls-alpn-01 challenge is answered
+// on the HTTPS listener; use WithHTTPChallengeAddr to also answer http-01.
+func WithCertManager(m CertManager) ServerOption {
+	return func(s *Server) {
+		s.certManager = m
+	}
+}
+
+// WithHTTPChallengeAddr starts a plain HTTP listener on addr (usually ":80")
+// that answers ACME http-01 challenges and redirects everything else to HTTPS.
+// Requires WithCertManager.
+func WithHTTPChallengeAddr(addr string) ServerOption {
+	return func(s *Server) {
+		s.challengeAddr = addr
+	}
+}
+
+// WithTLSConfig sets the base TLS configuration. It is cloned before use.
+func WithTLSConfig(cfg *tls.Config) ServerOption {
+	return func(s *Server) {
+		s.tlsConfig = cfg
+	}
+}
+
+// WithTimeouts sets the read header, read, write and idle timeouts.
+// Zero values keep the defaults.
+func WithTimeouts(readHeader, read, write, idle time.Duration) ServerOption {
+	return func(s *Server) {
+		if readHeader > 0 {
+			s.readHeaderTimeout = readHeader
+		}
+		if read > 0 {
+			s.readTimeout = read
+		}
+		if write > 0 {
+			s.writeTimeout = write
+		}
+		if idle > 0 {
+			s.idleTimeout = idle
+		}
+	}
+}
+
+// WithShutdownTimeout sets how long in-flight requests are given to finish
+// once the context is canceled
+// Default: 15 seconds
+func WithShutdownTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.shutdownTimeout = timeout
+	}
+}
+
+// NewServer creates an HTTPS server for handler listening on addr.
+// A certificate source is required: certificate files, a GetCertificate
+// callback, a CertManager or a TLS config with certificates.
+func NewServer(addr string, handler http.Handler, opts ...ServerOption) (*Server, error) {
+	if handler == nil {
+		return nil, errors.New("handler is required")
+	}
+
+	s := &Server{
+		addr:              addr,
+		handler:           handler,
+		readHeaderTimeout: DefaultReadHeaderTimeout,
+		readTimeout:       DefaultReadTimeout,
+		writeTimeout:      DefaultWriteTimeout,
+		idleTimeout:       DefaultIdleTimeout,
+		shutdownTimeout:   DefaultShutdownTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.challengeAddr != "" && s.certManager == nil {
+		return nil, errors.New("HTTP challenge address requires a certificate manager")
+	}
+
+	if (s.certFile == "") != (s.keyFile == "") {
+		return nil, errors.New("both certificate and key files are required")
+	}
+
+	if !s.hasCertificateSource() {
+		return nil, errors.New("a certificate source is required")
+	}
+
+	return s, nil
+}
+
+// hasCertificateSource reports whether any certificate source is configured
+func (s *Server) hasCertificateSource() bool {
+	if s.certFile != "" || s.getCertificate != nil || s.certManager != nil {
+		return true
+	}
+
+	return s.tlsConfig != nil && (len(s.tlsConfig.Certificates) > 0 || s.tlsConfig.GetCertificate != nil)
+}
+
+// buildTLSConfig returns the TLS configuration used by the HTTPS listener
+func (s *Server) buildTLSConfig() (*tls.Config, error) {
+	var cfg *tls.Config
+	if s.tlsConfig != nil {
+		cfg = s.tlsConfig.Clone()
+	} else {
+		cfg = &tls.Config{}
+	}
+
+	if cfg.MinVersion == 0 {
+		cfg.MinVersion = tls.VersionTLS12
+	}
+
+	if s.certFile != "" {
+		cert, err := tls.LoadX509KeyPair(s.certFile, s.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load certificate: %w", err)
+		}
+		cfg.Certificates = append(cfg.Certificates, cert)
+	}
+
+	if s.clientCAs != nil {
+		cfg.ClientCAs = s.clientCAs
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	switch {
+	case s.getCertificate != nil:
+		cfg.GetCertificate = s.getCertificate
+	case s.certManager != nil:
+		cfg.GetCertificate = s.certManager.GetCertificate
+		cfg.NextProtos = append(cfg.NextProtos, acmeTLSALPNProtocol)
+	}
+
+	return cfg, nil
+}
+
+// newHTTPServer creates an http.Server with the configured timeouts
+func (s *Server) newHTTPServer(handler http.Handler) *http.Server {
+	return &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: s.readHeaderTimeout,
+		ReadTimeout:       s.readTimeout,
+		WriteTimeout:      s.writeTimeout,
+		IdleTimeout:       s.idleTimeout,
+	}
+}
+
+// ListenAndServe listens on the configured address and serves HTTPS until
+// ctx is canceled, then shuts down gracefully
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.addr, err)
+	}
+
+	return s.Serve(ctx, ln)
+}
+
+// Serve serves HTTPS on ln until ctx is canceled, then shuts down
+// gracefully. The listener is closed when Serve returns.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	tlsConfig, err := s.buildTLSConfig()
+	if err != nil {
+		ln.Close()
+		return err
+	}
+
+	servers := []*http.Server{s.newHTTPServer(s.handler)}
+	servers[0].TLSConfig = tlsConfig
+
+	errCh := make(chan error, 2)
+	go func() {
+		// ServeTLS adds the h2 and http/1.1 protocols to the config
+		errCh <- servers[0].ServeTLS(ln, "", "")
+	}()
+
+	if s.challengeAddr != "" {
+		challengeLn, err := net.Listen("tcp", s.challengeAddr)
+		if err != nil {
+			servers[0].Close()
+			return fmt.Errorf("failed to listen on %s: %w", s.challengeAddr, err)
+		}
+
+		challenge := s.newHTTPServer(s.certManager.HTTPHandler(nil))
+		servers = append(servers, challenge)
+		go func() {
+			errCh <- challenge.Serve(challengeLn)
+		}()
+	}
+
+	select {
+	case err := <-errCh:
+		for _, srv := range servers {
+			srv.Close()
+		}
+		return fmt.Errorf("webhook server stopped: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	var shutdownErr error
+	for _, srv := range servers {
+		if err := srv.Shutdown(shutdownCtx); err != nil && shutdownErr == nil {
+			shutdownErr = fmt.Errorf("failed to shut down webhook server: %w", err)
+		}
+	}
+
+	return shutdownErr
+}
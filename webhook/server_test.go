@@ -0,0 +1,186 @@
+package webhook
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// generateCertificate creates a self-signed certificate for 127.0.0.1
+func generateCertificate(t *testing.T) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM
+}
+
+func TestNewServer_Validation(t *testing.T) {
+	handler := http.NotFoundHandler()
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		opts    []ServerOption
+		wantErr bool
+	}{
+		{
+			name:    "no certificate source",
+			handler: handler,
+			wantErr: true,
+		},
+		{
+			name:    "nil handler",
+			opts:    []ServerOption{WithCertificateFiles("cert.pem", "key.pem")},
+			wantErr: true,
+		},
+		{
+			name:    "missing key file",
+			handler: handler,
+			opts:    []ServerOption{WithCertificateFiles("cert.pem", "")},
+			wantErr: true,
+		},
+		{
+			name:    "challenge without manager",
+			handler: handler,
+			opts:    []ServerOption{WithCertificateFiles("cert.pem", "key.pem"), WithHTTPChallengeAddr(":80")},
+			wantErr: true,
+		},
+		{
+			name:    "certificate files",
+			handler: handler,
+			opts:    []ServerOption{WithCertificateFiles("cert.pem", "key.pem")},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewServer(":8443", tt.handler, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewServer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServer_ServeAndShutdown(t *testing.T) {
+	certPEM, keyPEM := generateCertificate(t)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+
+	srv, err := NewServer("", handler, WithCertificateFiles(certFile, keyFile), WithShutdownTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(ctx, ln)
+	}()
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(certPEM)
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}},
+		Timeout:   5 * time.Second,
+	}
+
+	resp, err := client.Get("https://" + ln.Addr().String() + "/webhook")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" {
+		t.Errorf("response = %d %q, want 200 \"ok\"", resp.StatusCode, body)
+	}
+	if resp.TLS == nil || resp.TLS.Version < tls.VersionTLS12 {
+		t.Error("expected a TLS 1.2+ connection")
+	}
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve() did not return after cancel")
+	}
+}
+
+func TestServer_Serve_InvalidCertificate(t *testing.T) {
+	srv, err := NewServer("", http.NotFoundHandler(), WithCertificateFiles("missing.pem", "missing.key"))
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	if err := srv.Serve(context.Background(), ln); err == nil {
+		t.Error("Serve() expected error for missing certificate files")
+	}
+}
@@ -0,0 +1,205 @@
+package webhook
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pericles-luz/go-bb-pix/pix"
+)
+
+// Event is a webhook callback as received from BB
+type Event struct {
+	ID         string          `json:"id"`
+	ReceivedAt time.Time       `json:"receivedAt"`
+	Path       string          `json:"path,omitempty"`
+	RemoteAddr string          `json:"remoteAddr,omitempty"`
+	Payload    json.RawMessage `json:"payload"`
+
+	// EndToEndIDs are the payments notified in the payload
+	EndToEndIDs []string `json:"endToEndIds,omitempty"`
+}
+
+// NewEvent creates an event for a payload received at receivedAt
+func NewEvent(payload []byte, receivedAt time.Time) Event {
+	event := Event{
+		ID:         newEventID(),
+		ReceivedAt: receivedAt,
+		Payload:    json.RawMessage(slices.Clone(payload)),
+	}
+
+	var parsed pix.WebhookPayload
+	if err := json.Unmarshal(payload, &parsed); err == nil {
+		for _, payment := range parsed.Pix {
+			event.EndToEndIDs = append(event.EndToEndIDs, payment.EndToEndID)
+		}
+	}
+
+	return event
+}
+
+// newEventID returns a random event identifier
+func newEventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// EventFilter selects stored events. Zero fields match everything.
+type EventFilter struct {
+	// Since and Until bound ReceivedAt (inclusive)
+	Since time.Time
+	Until time.Time
+
+	// EndToEndID selects events that notified the payment
+	EndToEndID string
+}
+
+// Matches reports whether the event satisfies the filter
+func (f EventFilter) Matches(e Event) bool {
+	if !f.Since.IsZero() && e.ReceivedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.ReceivedAt.After(f.Until) {
+		return false
+	}
+	if f.EndToEndID != "" && !slices.Contains(e.EndToEndIDs, f.EndToEndID) {
+		return false
+	}
+	return true
+}
+
+// EventStore persists received webhook events
+type EventStore interface {
+	// Save stores an event
+	Save(ctx context.Context, event Event) error
+
+	// List returns the stored events matching filter in the order they
+	// were saved
+	List(ctx context.Context, filter EventFilter) ([]Event, error)
+}
+
+// MemoryStore is an in-memory EventStore, useful for tests and short-lived
+// processes
+type MemoryStore struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Save implements EventStore
+func (s *MemoryStore) Save(ctx context.Context, event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, event)
+	return nil
+}
+
+// List implements EventStore
+func (s *MemoryStore) List(ctx context.Context, filter EventFilter) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []Event
+	for _, event := range s.events {
+		if filter.Matches(event) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// maxStoredEventSize is the longest line FileStore reads back
+const maxStoredEventSize = 16 << 20
+
+// FileStore is an EventStore that appends events to a JSON Lines file
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore creates a FileStore backed by the file at path. The file is
+// created on the first Save.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Save implements EventStore
+func (s *FileStore) Save(ctx context.Context, event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open event store: %w", err)
+	}
+
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close event store: %w", err)
+	}
+
+	return nil
+}
+
+// List implements EventStore
+func (s *FileStore) List(ctx context.Context, filter EventFilter) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event store: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxStoredEventSize)
+
+	var events []Event
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("failed to decode event on line %d: %w", line, err)
+		}
+		if filter.Matches(event) {
+			events = append(events, event)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read event store: %w", err)
+	}
+
+	return events, nil
+}
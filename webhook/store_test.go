@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEventStores(t *testing.T) {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	stores := map[string]EventStore{
+		"memory": NewMemoryStore(),
+		"file":   NewFileStore(filepath.Join(t.TempDir(), "events.jsonl")),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			events, err := store.List(ctx, EventFilter{})
+			if err != nil || len(events) != 0 {
+				t.Fatalf("List() on empty store = %v, %v", events, err)
+			}
+
+			payloads := []string{
+				`{"pix":[{"endToEndId":"E1","valor":"10.00"}]}`,
+				`{"pix":[{"endToEndId":"E2","valor":"20.00"},{"endToEndId":"E3","valor":"30.00"}]}`,
+				`{"pix":[{"endToEndId":"E4","valor":"40.00"}]}`,
+			}
+			for i, payload := range payloads {
+				event := NewEvent([]byte(payload), base.Add(time.Duration(i)*time.Hour))
+				if err := store.Save(ctx, event); err != nil {
+					t.Fatalf("Save() error = %v", err)
+				}
+			}
+
+			tests := []struct {
+				name   string
+				filter EventFilter
+				want   []string
+			}{
+				{"all", EventFilter{}, []string{"E1", "E2", "E4"}},
+				{"since", EventFilter{Since: base.Add(time.Hour)}, []string{"E2", "E4"}},
+				{"until", EventFilter{Until: base.Add(time.Hour)}, []string{"E1", "E2"}},
+				{"e2eid", EventFilter{EndToEndID: "E3"}, []string{"E2"}},
+				{"no match", EventFilter{EndToEndID: "E9"}, nil},
+			}
+
+			for _, tt := range tests {
+				events, err := store.List(ctx, tt.filter)
+				if err != nil {
+					t.Fatalf("%s: List() error = %v", tt.name, err)
+				}
+
+				var got []string
+				for _, event := range events {
+					got = append(got, event.EndToEndIDs[0])
+				}
+				if len(got) != len(tt.want) {
+					t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+					continue
+				}
+				for i := range got {
+					if got[i] != tt.want[i] {
+						t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+						break
+					}
+				}
+			}
+		})
+	}
+}